@@ -9,6 +9,8 @@ import (
 
 	_ "github.com/8thgencore/passfort/docs"
 	mailGrpc "github.com/8thgencore/passfort/internal/clients/mail/grpc"
+	mailSmtp "github.com/8thgencore/passfort/internal/clients/mail/smtp"
+	oidcClient "github.com/8thgencore/passfort/internal/clients/oidc"
 	"github.com/8thgencore/passfort/internal/config"
 	"github.com/8thgencore/passfort/internal/database"
 	"github.com/8thgencore/passfort/internal/delivery/http"
@@ -17,13 +19,27 @@ import (
 	"github.com/8thgencore/passfort/internal/repository/storage/postgres"
 	authSvc "github.com/8thgencore/passfort/internal/service/auth"
 	collectionSvc "github.com/8thgencore/passfort/internal/service/collection"
+	deviceSvc "github.com/8thgencore/passfort/internal/service/device"
+	folderSvc "github.com/8thgencore/passfort/internal/service/folder"
+	inviteSvc "github.com/8thgencore/passfort/internal/service/invite"
+	lockoutSvc "github.com/8thgencore/passfort/internal/service/lockout"
 	masterPasswordSvc "github.com/8thgencore/passfort/internal/service/master_password"
+	mfaSvc "github.com/8thgencore/passfort/internal/service/mfa"
 	otpSvc "github.com/8thgencore/passfort/internal/service/otp"
+	passwordPolicySvc "github.com/8thgencore/passfort/internal/service/password_policy"
+	accessTokenSvc "github.com/8thgencore/passfort/internal/service/personal_access_token"
+	roleSvc "github.com/8thgencore/passfort/internal/service/role"
+	scimSvc "github.com/8thgencore/passfort/internal/service/scim"
 	secretSvc "github.com/8thgencore/passfort/internal/service/secret"
+	sessionSvc "github.com/8thgencore/passfort/internal/service/session"
+	stepUpSvc "github.com/8thgencore/passfort/internal/service/step_up"
+	tagSvc "github.com/8thgencore/passfort/internal/service/tag"
 	tokenSvc "github.com/8thgencore/passfort/internal/service/token"
 	userSvc "github.com/8thgencore/passfort/internal/service/user"
+	"github.com/8thgencore/passfort/pkg/cipherkit"
 	"github.com/8thgencore/passfort/pkg/logger/sl"
 	"github.com/8thgencore/passfort/pkg/logger/slogpretty"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/hibiken/asynq"
 )
 
@@ -46,6 +62,11 @@ import (
 // @in							header
 // @name						Authorization
 // @description				Type "Bearer" followed by a space and the access token.
+//
+// @securityDefinitions.apikey	SCIMAuth
+// @in							header
+// @name						Authorization
+// @description				Type "Bearer" followed by a space and the SCIM provisioning token.
 func Run(configPath string) {
 	// Load configuration
 	cfg, err := config.NewConfig(configPath)
@@ -91,10 +112,20 @@ func Run(configPath string) {
 	log.Info("Successfully connected to the cache server")
 
 	// Init token service
-	tokenService := tokenSvc.NewTokenService(log, cfg.Token.SigningKey, cfg.Token.AccessTokenTTL, cfg.Token.RefreshTokenTTL, cache)
+	signingKeyRepo := postgres.NewSigningKeyRepository(db)
+	keyring := tokenSvc.NewKeyring(log, signingKeyRepo, cfg.Token.SigningAlgorithm, cfg.Token.KeyRotationInterval, cfg.Token.RefreshTokenTTL)
+	if err := keyring.Load(ctx); err != nil {
+		log.Error("Error loading token signing keys", sl.Err(err))
+		os.Exit(1)
+	}
+	go keyring.Run(ctx)
+
+	tokenRepo := postgres.NewTokenRepository(db)
+	tokenService := tokenSvc.NewTokenService(log, keyring, cfg.Token.SigningKey, cfg.Token.AccessTokenTTL, cfg.Token.RefreshTokenTTL, tokenRepo, cache)
+	jwksHandler := handler.NewJWKSHandler(tokenService)
 
 	// Otp service
-	otpService := otpSvc.NewOtpService(log, cache)
+	otpService := otpSvc.NewOtpService(log, cache, cfg.OTP.TTL, cfg.OTP.MaxAttempts)
 
 	// Register external microservices
 	mailClient, err := mailGrpc.New(ctx,
@@ -110,6 +141,20 @@ func Run(configPath string) {
 
 	log.Info("Successfully initializing the mail client")
 
+	smtpClient, err := mailSmtp.New(
+		cfg.Clients.SMTP.Host,
+		cfg.Clients.SMTP.Port,
+		cfg.Clients.SMTP.Username,
+		cfg.Clients.SMTP.Password,
+		cfg.Clients.SMTP.From,
+		cfg.App.Name,
+		cfg.Clients.SMTP.Timeout,
+	)
+	if err != nil {
+		log.Error("Error initializing SMTP client", sl.Err(err))
+		os.Exit(1)
+	}
+
 	// Init asynq client and server and register task handlers
 	asynqCfg := asynq.RedisClientOpt{Addr: cfg.Cache.Addr, Password: cfg.Cache.Password}
 	asynqClient := asynq.NewClient(asynqCfg)
@@ -118,25 +163,103 @@ func Run(configPath string) {
 	// Dependency injection
 	// User
 	userRepo := postgres.NewUserRepository(db)
-	userService := userSvc.NewUserService(log, userRepo, cache)
+	collectionRepo := postgres.NewCollectionRepository(db)
+	lockoutService := lockoutSvc.NewLockoutService(log, userRepo, cache, smtpClient, &cfg.Lockout)
+	passwordPolicyService, err := passwordPolicySvc.NewPasswordPolicyService(log, &cfg.PasswordPolicy)
+	if err != nil {
+		log.Error("Error initializing password policy", sl.Err(err))
+		os.Exit(1)
+	}
+	userService := userSvc.NewUserService(log, userRepo, cache, lockoutService, tokenService, cfg.Account.DeletedUserCollections)
 	userHandler := handler.NewUserHandler(userService)
 
+	// MFA
+	if len(cfg.MFA.EncryptionKey) < cipherkit.MinTOTPSecretLength {
+		log.Error("MFA encryption key is too short", "min_length", cipherkit.MinTOTPSecretLength)
+		os.Exit(1)
+	}
+	mfaRepo := postgres.NewMFARepository(db)
+	mfaService := mfaSvc.NewMFAService(log, mfaRepo, userRepo, cache, lockoutService, cfg.App.Name, cipherkit.TOTPKey(cfg.MFA.EncryptionKey))
+	mfaHandler := handler.NewMFAHandler(mfaService)
+
+	// Personal access token
+	accessTokenRepo := postgres.NewPersonalAccessTokenRepository(db)
+	accessTokenService := accessTokenSvc.NewPersonalAccessTokenService(log, accessTokenRepo, collectionRepo)
+	accessTokenHandler := handler.NewPersonalAccessTokenHandler(accessTokenService)
+
+	// Session
+	sessionService := sessionSvc.NewSessionService(log, tokenRepo, tokenService, cfg.Token.RefreshTokenTTL)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+
+	// Step-up authentication
+	stepUpService := stepUpSvc.NewStepUpService(log, userRepo, tokenRepo, cache, mfaService, lockoutService, cfg.StepUp.MaxAge)
+	stepUpHandler := handler.NewStepUpHandler(stepUpService)
+
 	// Auth
-	authService := authSvc.NewAuthService(log, userRepo, cache, tokenService, otpService, mailClient)
+	webAuthnRepo := postgres.NewWebAuthnRepository(db)
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: cfg.WebAuthn.RPDisplayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+	})
+	if err != nil {
+		log.Error("Error initializing WebAuthn", sl.Err(err))
+		os.Exit(1)
+	}
+	identityRepo := postgres.NewOIDCRepository(db)
+	inviteRepo := postgres.NewInviteRepository(db)
+	var oidc *oidcClient.Client
+	if cfg.OIDC.Enabled {
+		oidc = oidcClient.New(cfg.OIDC.IssuerURL, cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, cfg.OIDC.RedirectURL, cfg.OIDC.Scopes)
+	}
+	authService := authSvc.NewAuthService(log, userRepo, webAuthnRepo, identityRepo, inviteRepo, cache, tokenService, otpService, mfaService, lockoutService,
+		passwordPolicyService, mailClient, smtpClient, webAuthn, oidc, cfg.OIDC.AllowSignup,
+		cfg.Account.RegistrationMode, cfg.Account.RegistrationDomains, cfg.Token.MFATokenTTL)
 	authHandler := handler.NewAuthHandler(authService)
 
+	// Device
+	deviceService := deviceSvc.NewDeviceService(log, userRepo, cache, tokenService, lockoutService, &cfg.Device)
+	deviceHandler := handler.NewDeviceHandler(deviceService)
+
+	// Invite
+	inviteService := inviteSvc.NewInviteService(log, inviteRepo)
+	inviteHandler := handler.NewInviteHandler(inviteService)
+
+	// Role
+	roleRepo := postgres.NewRoleRepository(db)
+	roleService := roleSvc.NewRoleService(log, roleRepo, userRepo, cache)
+	roleHandler := handler.NewRoleHandler(roleService)
+
+	// SCIM
+	if cfg.SCIM.Enabled && cfg.SCIM.Token == "" {
+		log.Error("SCIM is enabled without a provisioning token")
+		os.Exit(1)
+	}
+	scimService := scimSvc.NewSCIMService(log, userRepo, roleRepo, cache, userService, roleService)
+	scimHandler := handler.NewSCIMHandler(scimService)
+
 	// Collection
-	collectionRepo := postgres.NewCollectionRepository(db)
 	collectionService := collectionSvc.NewCollectionService(log, collectionRepo)
 	collectionHandler := handler.NewCollectionHandler(collectionService)
 
+	// Folder
+	folderRepo := postgres.NewFolderRepository(db)
+	folderService := folderSvc.NewFolderService(log, folderRepo, collectionRepo)
+	folderHandler := handler.NewFolderHandler(folderService)
+
+	// Tag
+	tagRepo := postgres.NewTagRepository(db)
+	tagService := tagSvc.NewTagService(log, tagRepo)
+	tagHandler := handler.NewTagHandler(tagService)
+
 	// Secret
 	secretRepo := postgres.NewSecretRepository(db)
-	secretService := secretSvc.NewSecretService(log, secretRepo, collectionRepo, cache, asynqClient)
+	secretService := secretSvc.NewSecretService(log, secretRepo, collectionRepo, folderRepo, tagRepo, cache, asynqClient, cfg.Secret.EncryptMetadata)
 	secretHandler := handler.NewSecretHandler(secretService)
 
 	// MasterPassword
-	masterPasswordService := masterPasswordSvc.NewMasterPasswordService(log, userRepo, cache, *secretService, cfg.MasterPassword.MasterPasswordTTL)
+	masterPasswordService := masterPasswordSvc.NewMasterPasswordService(log, userRepo, accessTokenRepo, cache, *secretService, lockoutService,
+		passwordPolicyService, cfg.MasterPassword.MasterPasswordTTL)
 	masterPasswordHandler := handler.NewMasterPasswordHandler(masterPasswordService)
 
 	mux := asynq.NewServeMux()
@@ -155,12 +278,27 @@ func Run(configPath string) {
 		log,
 		cfg,
 		tokenService,
+		accessTokenService,
 		masterPasswordService,
+		stepUpService,
+		userService,
+		roleService,
 		*userHandler,
 		*authHandler,
+		*mfaHandler,
+		*sessionHandler,
+		*accessTokenHandler,
 		*collectionHandler,
+		*folderHandler,
+		*tagHandler,
 		*secretHandler,
 		*masterPasswordHandler,
+		*jwksHandler,
+		*inviteHandler,
+		*deviceHandler,
+		*stepUpHandler,
+		*roleHandler,
+		*scimHandler,
 	)
 	if err != nil {
 		log.Error("Error initializing router", sl.Err(err))
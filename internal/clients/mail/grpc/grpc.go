@@ -53,6 +53,7 @@ func New(
 
 	return &Client{
 		api: mailv1.NewMailServiceClient(cc),
+		log: log,
 	}, nil
 }
 
@@ -0,0 +1,203 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// Notice is a security notice sent to the owner of an account
+type Notice string
+
+// Notice enum values
+const (
+	// LockoutNotice tells the owner their account was locked after too many failed attempts
+	LockoutNotice Notice = "account_locked"
+	// EmailChangeNotice tells the owner a change of the email of their account was requested
+	EmailChangeNotice Notice = "email_change_requested"
+)
+
+// message is the template of an email, its data is the application name and the code sent, if any
+type message struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func newMessage(subject, body string) message {
+	return message{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+var notices = map[Notice]message{
+	LockoutNotice: newMessage("Your {{.AppName}} account was locked", `Hello,
+
+Your {{.AppName}} account was locked after too many failed attempts to sign in.
+It unlocks by itself after a while, the attempts made in the meantime are refused.
+
+If these attempts were not yours, someone may know your password: change it once you can sign in again.
+`),
+	EmailChangeNotice: newMessage("A new email was requested for your {{.AppName}} account", `Hello,
+
+A change of the email of your {{.AppName}} account to another address was requested.
+It applies only once confirmed with the code sent to the new address.
+
+If you did not request it, reset your password now: this cancels the change.
+Then review the sessions of your account and sign out the ones you do not know.
+`),
+}
+
+// emailChangeCode is the message sending the code confirming a new email to this email
+var emailChangeCode = newMessage("Confirm the new email of your {{.AppName}} account", `Hello,
+
+Use this code to confirm the new email of your {{.AppName}} account:
+
+{{.Code}}
+
+If you did not request it, ignore this email, the email of the account does not change.
+`)
+
+// Client sends the security notices and the email change codes directly to a mail server.
+// The mail service only has templates for the registration and password reset codes.
+type Client struct {
+	addr    string
+	host    string
+	auth    smtp.Auth
+	from    *mail.Address
+	appName string
+	timeout time.Duration
+}
+
+// New creates a new SMTP client sending from the address, e.g. "PassFort <no-reply@example.com>".
+// The credentials are sent only over TLS, a mail server on localhost may be used without.
+func New(host string, port int, username, password, from, appName string, timeout time.Duration) (*Client, error) {
+	const op = "smtp.New"
+
+	fromAddress, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid sender address: %w", op, err)
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &Client{
+		addr:    net.JoinHostPort(host, strconv.Itoa(port)),
+		host:    host,
+		auth:    auth,
+		from:    fromAddress,
+		appName: appName,
+		timeout: timeout,
+	}, nil
+}
+
+// SendNotice sends a security notice to the owner of an account
+func (c *Client) SendNotice(ctx context.Context, email string, notice Notice) error {
+	const op = "smtp.SendNotice"
+
+	msg, ok := notices[notice]
+	if !ok {
+		return fmt.Errorf("%s: unknown notice %q", op, notice)
+	}
+
+	if err := c.send(ctx, email, msg, ""); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SendEmailChangeCode sends the code confirming the new email of an account to this email
+func (c *Client) SendEmailChangeCode(ctx context.Context, email, otpCode string) error {
+	const op = "smtp.SendEmailChangeCode"
+
+	if err := c.send(ctx, email, emailChangeCode, otpCode); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// send renders the message and sends it to the email, over TLS if the server supports it
+func (c *Client) send(ctx context.Context, email string, msg message, code string) error {
+	data := struct {
+		AppName string
+		Code    string
+	}{c.appName, code}
+
+	var subject, body bytes.Buffer
+	if err := msg.subject.Execute(&subject, data); err != nil {
+		return err
+	}
+	if err := msg.body.Execute(&body, data); err != nil {
+		return err
+	}
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "From: %s\r\n", c.from.String())
+	fmt.Fprintf(&content, "To: %s\r\n", email)
+	fmt.Fprintf(&content, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject.String()))
+	fmt.Fprintf(&content, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	content.WriteString("MIME-Version: 1.0\r\n")
+	content.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	content.Write(bytes.ReplaceAll(body.Bytes(), []byte("\n"), []byte("\r\n")))
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.host}); err != nil {
+			return err
+		}
+	}
+	if c.auth != nil {
+		if err := client.Auth(c.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(c.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(email); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(content.Bytes()); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
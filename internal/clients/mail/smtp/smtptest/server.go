@@ -0,0 +1,135 @@
+package smtptest
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Message is an email received by the server
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Server is a mail server for the tests keeping the emails it receives.
+// It offers no TLS and no authentication, the client must not send credentials.
+type Server struct {
+	Host string
+	Port int
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewServer starts a mail server closed at the end of the test
+func NewServer(t *testing.T) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	addr := listener.Addr().(*net.TCPAddr)
+	server := &Server{Host: addr.IP.String(), Port: addr.Port}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+// Messages returns the emails received by the server
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// MessagesTo returns the emails received by the server for the address
+func (s *Server) MessagesTo(email string) []Message {
+	var messages []Message
+	for _, message := range s.Messages() {
+		if message.To == email {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// serve answers the commands of a single SMTP session
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+
+	var to string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			to = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.receive(to, data.String())
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *Server) receive(to, data string) {
+	message := Message{To: to, Body: data}
+	if parsed, err := mail.ReadMessage(strings.NewReader(data)); err == nil {
+		message.Subject, _ = new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		body := new(strings.Builder)
+		_, _ = io.Copy(body, parsed.Body)
+		message.Body = strings.ReplaceAll(body.String(), "\r\n", "\n")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, message)
+}
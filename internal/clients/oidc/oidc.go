@@ -0,0 +1,139 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Identity is the identity of a user confirmed by the provider
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Client is an OpenID Connect relying party using the authorization code flow with PKCE.
+// The provider configuration is discovered on first use, so the application starts while the provider is down.
+type Client struct {
+	issuerURL    string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+
+	mu       sync.Mutex
+	provider *gooidc.Provider
+}
+
+// New creates a new OpenID Connect client
+func New(issuerURL, clientID, clientSecret, redirectURL string, scopes []string) *Client {
+	return &Client{
+		issuerURL:    issuerURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       scopes,
+	}
+}
+
+// AuthCodeURL returns the URL of the provider to send the user to.
+// The state and the nonce bind the response to this request, the verifier is the PKCE secret of the code exchange.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	const op = "oidc.AuthCodeURL"
+
+	config, _, err := c.config(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return config.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange exchanges an authorization code for an ID token and returns the verified identity
+func (c *Client) Exchange(ctx context.Context, code, nonce, verifier string) (*Identity, error) {
+	const op = "oidc.Exchange"
+
+	config, verifierOIDC, err := c.config(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, errors.New("no id_token in the token response"))
+	}
+
+	idToken, err := verifierOIDC.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%s: %w", op, errors.New("nonce does not match"))
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Identity{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+// config returns the OAuth 2.0 configuration and the ID token verifier of the provider
+func (c *Client) config(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	provider, err := c.discover(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := &oauth2.Config{
+		ClientID:     c.clientID,
+		ClientSecret: c.clientSecret,
+		RedirectURL:  c.redirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       c.scopes,
+	}
+
+	return config, provider.Verifier(&gooidc.Config{ClientID: c.clientID}), nil
+}
+
+// discover fetches the provider configuration once
+func (c *Client) discover(ctx context.Context) (*gooidc.Provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.provider != nil {
+		return c.provider, nil
+	}
+
+	// The provider keeps the context to fetch its signing keys later, it must outlive the request
+	provider, err := gooidc.NewProvider(context.WithoutCancel(ctx), c.issuerURL)
+	if err != nil {
+		return nil, err
+	}
+	c.provider = provider
+
+	return provider, nil
+}
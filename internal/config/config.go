@@ -6,6 +6,7 @@ import (
 	"path"
 	"time"
 
+	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/pkg/logger/slogpretty"
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -26,7 +27,18 @@ type (
 		Database       Database       `yaml:"database"`
 		Cache          Cache          `yaml:"cache"`
 		Token          Token          `yaml:"token"`
+		WebAuthn       WebAuthn       `yaml:"webauthn"`
+		MFA            MFA            `yaml:"mfa"`
+		OIDC           OIDC           `yaml:"oidc"`
+		SCIM           SCIM           `yaml:"scim"`
+		Device         Device         `yaml:"device"`
+		OTP            OTP            `yaml:"otp"`
+		Lockout        Lockout        `yaml:"lockout"`
+		StepUp         StepUp         `yaml:"step_up"`
+		PasswordPolicy PasswordPolicy `yaml:"password_policy"`
 		MasterPassword MasterPassword `yaml:"master_password"`
+		Account        Account        `yaml:"account"`
+		Secret         Secret         `yaml:"secret"`
 		Clients        ClientConfig   `yaml:"clients"`
 		Log            Log            `yaml:"log"`
 	}
@@ -62,9 +74,116 @@ type (
 
 	// Token contains all the environment variables for the token service
 	Token struct {
+		// SigningAlgorithm signs the tokens with RS256 or EdDSA keys, generated and stored in the database.
+		// Other services verify the tokens with the public keys published at /.well-known/jwks.json.
+		SigningAlgorithm domain.SigningAlgorithm `yaml:"signing_algorithm" env:"TOKEN_SIGNING_ALGORITHM" env-default:"EdDSA"`
+		// KeyRotationInterval is the time a key signs new tokens before it is replaced,
+		// a retired key still verifies the tokens it signed until they expire
+		KeyRotationInterval time.Duration `yaml:"key_rotation_interval" env-default:"720h"`
+		// SigningKey is the HS256 secret of the tokens issued before the asymmetric keys, these tokens are still accepted.
+		// No new token is signed with it, remove it once the refresh tokens it signed have expired.
 		SigningKey      string        `yaml:"signing_key"       env:"TOKEN_SIGNING_KEY"`
 		AccessTokenTTL  time.Duration `yaml:"access_token_ttl"  env-default:"30m"`
 		RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+		MFATokenTTL     time.Duration `yaml:"mfa_token_ttl"     env-default:"5m"`
+	}
+
+	// WebAuthn contains the relying party settings for passkeys
+	WebAuthn struct {
+		// RPID is the domain the passkeys are bound to, it must match the domain of the origins
+		RPID          string   `yaml:"rp_id"           env:"WEBAUTHN_RP_ID"           env-default:"localhost"`
+		RPDisplayName string   `yaml:"rp_display_name" env:"WEBAUTHN_RP_DISPLAY_NAME" env-default:"PassFort"`
+		RPOrigins     []string `yaml:"rp_origins"      env:"WEBAUTHN_RP_ORIGINS"      env-default:"http://localhost:8080"`
+	}
+
+	// MFA contains the settings of the second factors
+	MFA struct {
+		// EncryptionKey encrypts the TOTP secrets stored in the database, a database read alone does not reveal them.
+		// It must have at least 32 characters, changing it makes the enrolled authenticators unusable.
+		EncryptionKey string `yaml:"encryption_key" env:"MFA_ENCRYPTION_KEY" env-required:"true"`
+	}
+
+	// OIDC contains the settings of the single sign-on with an OpenID Connect provider
+	OIDC struct {
+		Enabled bool `yaml:"enabled" env:"OIDC_ENABLED" env-default:"false"`
+		// IssuerURL is the issuer of the provider, its configuration is discovered from /.well-known/openid-configuration
+		IssuerURL    string `yaml:"issuer_url"    env:"OIDC_ISSUER_URL"`
+		ClientID     string `yaml:"client_id"     env:"OIDC_CLIENT_ID"`
+		ClientSecret string `yaml:"client_secret" env:"OIDC_CLIENT_SECRET"`
+		// RedirectURL is the callback registered at the provider, the client there posts the code and the state to /auth/oidc/login/finish
+		RedirectURL string   `yaml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+		Scopes      []string `yaml:"scopes"       env:"OIDC_SCOPES"       env-default:"openid,email,profile"`
+		// AllowSignup creates an account on the first login of an unknown user the registration mode lets register
+		// without an invite code, e.g. only the emails of the registration domains. Otherwise only existing users can log in.
+		AllowSignup bool `yaml:"allow_signup" env:"OIDC_ALLOW_SIGNUP" env-default:"true"`
+	}
+
+	// SCIM contains the settings of the SCIM 2.0 API at /scim/v2, provisioning the users and the groups from an identity provider
+	SCIM struct {
+		Enabled bool `yaml:"enabled" env:"SCIM_ENABLED" env-default:"false"`
+		// Token is the bearer token the identity provider authenticates with, required when SCIM is enabled
+		Token string `yaml:"token" env:"SCIM_TOKEN"`
+	}
+
+	// Device contains the settings of the device authorization grant logging in CLI and headless clients (RFC 8628)
+	Device struct {
+		// VerificationURI is the page where a logged in user enters the user code shown by the device and approves it
+		VerificationURI string        `yaml:"verification_uri" env:"DEVICE_VERIFICATION_URI" env-default:"http://localhost:3000/device"`
+		CodeTTL         time.Duration `yaml:"code_ttl"         env-default:"10m"`
+		// PollInterval is the minimum time between two token requests of a device
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"5s"`
+	}
+
+	// OTP contains the settings of the one-time passwords sent by email
+	OTP struct {
+		TTL time.Duration `yaml:"ttl" env-default:"10m"`
+		// MaxAttempts is the number of wrong codes after which an OTP is invalidated, a new one must be requested
+		MaxAttempts int `yaml:"max_attempts" env-default:"5"`
+	}
+
+	// Lockout contains the limits of the failed attempts to log in, activate the master password or enter an OTP.
+	// Failed attempts are counted per account and per IP address for each action.
+	Lockout struct {
+		// AttemptWindow is the time the failed attempts are counted for, counting restarts after it
+		AttemptWindow time.Duration `yaml:"attempt_window" env-default:"15m"`
+		// FreeAttempts is the number of failed attempts of an account before each attempt is delayed,
+		// the delay starts at BaseDelay and doubles with each failed attempt
+		FreeAttempts int           `yaml:"free_attempts" env-default:"3"`
+		BaseDelay    time.Duration `yaml:"base_delay"    env-default:"1s"`
+		// MaxAccountAttempts is the number of failed attempts locking an account, its owner is notified by email.
+		// The lockout lasts LockoutDuration and doubles with each lockout up to MaxLockoutDuration.
+		MaxAccountAttempts int           `yaml:"max_account_attempts" env-default:"10"`
+		LockoutDuration    time.Duration `yaml:"lockout_duration"     env-default:"15m"`
+		MaxLockoutDuration time.Duration `yaml:"max_lockout_duration" env-default:"24h"`
+		// MaxIPAttempts is the number of failed attempts of an IP address, on any account, blocking it for AttemptWindow
+		MaxIPAttempts int `yaml:"max_ip_attempts" env-default:"100"`
+	}
+
+	// StepUp contains the settings of the recent authentication required by sensitive operations
+	StepUp struct {
+		// MaxAge is the time a login or a confirmation of the password or second factor counts as recent for the session
+		MaxAge time.Duration `yaml:"max_age" env:"STEP_UP_MAX_AGE" env-default:"5m"`
+	}
+
+	// PasswordPolicy contains the rules of the new account passwords and master passwords
+	PasswordPolicy struct {
+		// DenylistFile is a file of common passwords, one per line, refused in addition to the built-in list
+		DenylistFile   string        `yaml:"denylist_file" env:"PASSWORD_DENYLIST_FILE"`
+		Account        PasswordRules `yaml:"account"`
+		MasterPassword PasswordRules `yaml:"master_password"`
+	}
+
+	// PasswordRules are the rules a new password must follow, passwords are limited to 72 bytes in any case
+	PasswordRules struct {
+		MinLength int `yaml:"min_length" env-default:"8"`
+		// MinCharacterClasses is the number of classes among lowercase letters, uppercase letters, digits and symbols to use
+		MinCharacterClasses int `yaml:"min_character_classes" env-default:"2"`
+		// MinEntropy is the minimum estimated strength in bits, repeated and sequential characters count little
+		MinEntropy float64 `yaml:"min_entropy" env-default:"40"`
+		// Denylist refuses the common passwords, also with digits or symbols around them
+		Denylist bool `yaml:"denylist" env-default:"true"`
+		// DifferFromAccountPassword refuses a master password equal to the account password, unused for account passwords
+		DifferFromAccountPassword bool `yaml:"differ_from_account_password" env-default:"true"`
 	}
 
 	// MasterPassword contains all the environment variables for the master password service
@@ -72,6 +191,23 @@ type (
 		MasterPasswordTTL time.Duration `yaml:"master_password_ttl" env-default:"MasterPassword"`
 	}
 
+	// Account contains the settings of the account management
+	Account struct {
+		// DeletedUserCollections is the policy for the collections owned by a deleted user: transfer, delete or block
+		DeletedUserCollections domain.OwnedCollectionsPolicy `yaml:"deleted_user_collections" env:"ACCOUNT_DELETED_USER_COLLECTIONS" env-default:"transfer"`
+		// RegistrationMode defines who can register at /auth/register: open, invite or domain.
+		// In the domain mode the emails of RegistrationDomains register freely, the others need an invite code.
+		RegistrationMode    domain.RegistrationMode `yaml:"registration_mode"    env:"ACCOUNT_REGISTRATION_MODE"    env-default:"open"`
+		RegistrationDomains []string                `yaml:"registration_domains" env:"ACCOUNT_REGISTRATION_DOMAINS"`
+	}
+
+	// Secret contains the settings of the secret storage
+	Secret struct {
+		// EncryptMetadata encrypts the names, descriptions, URLs and logins of secrets with the user's key.
+		// Existing secrets are encrypted when they are updated or when the master password changes.
+		EncryptMetadata bool `yaml:"encrypt_metadata" env:"SECRET_ENCRYPT_METADATA" env-default:"false"`
+	}
+
 	//  Clients
 	Client struct {
 		Address      string        `yaml:"address"        env:"CLIENT_MAIL_ADDRESS"`
@@ -81,6 +217,17 @@ type (
 	}
 	ClientConfig struct {
 		Mail Client `yaml:"mail"`
+		SMTP SMTP   `yaml:"smtp"`
+	}
+	// SMTP contains the mail server sending the security notices, which the mail service has no template for.
+	// The credentials are only sent over TLS, unless the server is on localhost.
+	SMTP struct {
+		Host     string        `yaml:"host"     env:"SMTP_HOST"     env-default:"localhost"`
+		Port     int           `yaml:"port"     env:"SMTP_PORT"     env-default:"587"`
+		Username string        `yaml:"username" env:"SMTP_USERNAME"`
+		Password string        `yaml:"password" env:"SMTP_PASSWORD"`
+		From     string        `yaml:"from"     env:"SMTP_FROM"     env-default:"PassFort <no-reply@localhost>"`
+		Timeout  time.Duration `yaml:"timeout"                      env-default:"10s"`
 	}
 
 	// Logger settings
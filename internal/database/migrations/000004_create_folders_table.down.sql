@@ -0,0 +1,10 @@
+-- Drop trigger and function
+DROP TRIGGER IF EXISTS trigger_check_folder_parent ON folders;
+
+DROP FUNCTION IF EXISTS check_folder_parent;
+
+-- Unlink secrets from folders
+ALTER TABLE secrets DROP COLUMN IF EXISTS folder_id;
+
+-- Drop folders table
+DROP TABLE IF EXISTS folders;
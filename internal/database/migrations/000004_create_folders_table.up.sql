@@ -0,0 +1,59 @@
+-- Create folders table
+CREATE TABLE
+    folders (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        collection_id UUID NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+        parent_id UUID REFERENCES folders (id) ON DELETE CASCADE,
+        name VARCHAR NOT NULL,
+        created_by UUID,
+        updated_by UUID,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+    );
+
+-- Create indexes
+CREATE INDEX folders_collection_id ON folders (collection_id);
+
+CREATE INDEX folders_parent_id ON folders (parent_id);
+
+-- Link secrets to folders
+ALTER TABLE secrets ADD COLUMN folder_id UUID REFERENCES folders (id) ON DELETE CASCADE;
+
+CREATE INDEX secrets_folder_id ON secrets (folder_id);
+
+-- Function to check the folder hierarchy
+CREATE OR REPLACE FUNCTION check_folder_parent() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM 1 FROM folders WHERE id = NEW.parent_id AND collection_id = NEW.collection_id;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Parent folder belongs to another collection'
+            USING ERRCODE = 'foreign_key_violation';
+    END IF;
+
+    -- Walk up from the new parent; reaching the folder itself means a cycle
+    PERFORM 1 FROM (
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_id FROM folders WHERE id = NEW.parent_id
+            UNION
+            SELECT f.id, f.parent_id FROM folders f JOIN ancestors a ON f.id = a.parent_id
+        )
+        SELECT id FROM ancestors
+    ) AS a WHERE a.id = NEW.id;
+    IF FOUND THEN
+        RAISE EXCEPTION 'Folder cannot be moved into itself or its descendants'
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger for checking the folder hierarchy
+CREATE TRIGGER trigger_check_folder_parent
+BEFORE INSERT OR UPDATE OF parent_id ON folders
+FOR EACH ROW
+EXECUTE FUNCTION check_folder_parent();
@@ -0,0 +1,12 @@
+-- Restore the second foreign key of secrets
+ALTER TABLE secrets ADD CONSTRAINT fk_collection_id FOREIGN KEY (collection_id) REFERENCES collections (id);
+
+-- Restore the memberships foreign key
+ALTER TABLE users_collections DROP CONSTRAINT fk_user_id;
+
+ALTER TABLE users_collections ADD CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id);
+
+-- Drop the owner foreign key
+DROP INDEX IF EXISTS collections_created_by;
+
+ALTER TABLE collections DROP CONSTRAINT IF EXISTS fk_created_by;
@@ -0,0 +1,26 @@
+-- Clear owners that no longer exist before adding the foreign key
+UPDATE collections
+SET
+    created_by = NULL
+WHERE
+    created_by IS NOT NULL
+    AND created_by NOT IN (
+        SELECT
+            id
+        FROM
+            users
+    );
+
+-- Collections keep no dangling owner when a user is deleted
+ALTER TABLE collections ADD CONSTRAINT fk_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX collections_created_by ON collections (created_by);
+
+-- Memberships are removed together with the user
+ALTER TABLE users_collections DROP CONSTRAINT fk_user_id;
+
+ALTER TABLE users_collections ADD CONSTRAINT fk_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+-- The secrets table references collections twice, the second constraint
+-- has no ON DELETE CASCADE and prevents deleting collections that contain secrets
+ALTER TABLE secrets DROP CONSTRAINT fk_collection_id;
@@ -0,0 +1,5 @@
+-- Drop secrets_tags table
+DROP TABLE IF EXISTS secrets_tags;
+
+-- Drop tags table
+DROP TABLE IF EXISTS tags;
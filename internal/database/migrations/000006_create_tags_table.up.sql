@@ -0,0 +1,21 @@
+-- Create tags table
+CREATE TABLE
+    tags (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        name VARCHAR NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        UNIQUE (user_id, name)
+    );
+
+-- Create secrets_tags table
+CREATE TABLE
+    secrets_tags (
+        secret_id UUID REFERENCES secrets (id) ON DELETE CASCADE,
+        tag_id UUID REFERENCES tags (id) ON DELETE CASCADE,
+        PRIMARY KEY (secret_id, tag_id)
+    );
+
+-- Create indexes
+CREATE INDEX secrets_tags_tag_id ON secrets_tags (tag_id);
@@ -0,0 +1,13 @@
+-- Drop triggers and functions
+DROP TRIGGER IF EXISTS trigger_refresh_secret_search_on_password_secret ON password_secrets;
+
+DROP TRIGGER IF EXISTS trigger_refresh_secret_search ON secrets;
+
+DROP FUNCTION IF EXISTS refresh_secret_search_on_password_secret;
+
+DROP FUNCTION IF EXISTS refresh_secret_search_on_secret;
+
+DROP FUNCTION IF EXISTS refresh_secret_search;
+
+-- Drop secrets_search table
+DROP TABLE IF EXISTS secrets_search;
@@ -0,0 +1,60 @@
+-- Create secrets_search table holding the full-text search document of each secret.
+-- It is kept apart from the secrets table so the secrets columns do not change.
+CREATE TABLE
+    secrets_search (
+        secret_id UUID PRIMARY KEY REFERENCES secrets (id) ON DELETE CASCADE,
+        document TSVECTOR NOT NULL
+    );
+
+CREATE INDEX secrets_search_document ON secrets_search USING GIN (document);
+
+-- Function to build the search document of a secret.
+-- URLs and logins are also split into words so that "github" matches "https://github.com".
+CREATE OR REPLACE FUNCTION refresh_secret_search(target_secret_id UUID) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO secrets_search (secret_id, document)
+    SELECT
+        s.id,
+        setweight(to_tsvector('simple', coalesce(s.name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(s.description, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(ps.url, '') || ' ' || coalesce(ps.login, '')), 'C') ||
+        setweight(to_tsvector('simple', regexp_replace(coalesce(ps.url, '') || ' ' || coalesce(ps.login, ''), '[^[:alnum:]]+', ' ', 'g')), 'C')
+    FROM secrets s
+    LEFT JOIN password_secrets ps ON s.secret_type = 'password' AND ps.id = s.linked_secret_id
+    WHERE s.id = target_secret_id
+    ON CONFLICT (secret_id) DO UPDATE SET document = EXCLUDED.document;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger function for changes of secrets
+CREATE OR REPLACE FUNCTION refresh_secret_search_on_secret() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_secret_search(NEW.id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger function for changes of password secrets
+CREATE OR REPLACE FUNCTION refresh_secret_search_on_password_secret() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_secret_search(s.id) FROM secrets s WHERE s.secret_type = 'password' AND s.linked_secret_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Triggers for maintaining the search documents
+CREATE TRIGGER trigger_refresh_secret_search
+AFTER INSERT OR UPDATE OF name, description, linked_secret_id ON secrets
+FOR EACH ROW
+EXECUTE FUNCTION refresh_secret_search_on_secret();
+
+CREATE TRIGGER trigger_refresh_secret_search_on_password_secret
+AFTER UPDATE OF url, login ON password_secrets
+FOR EACH ROW
+EXECUTE FUNCTION refresh_secret_search_on_password_secret();
+
+-- Index the existing secrets
+SELECT
+    refresh_secret_search(id)
+FROM
+    secrets;
@@ -0,0 +1,2 @@
+-- Drop secret_blind_indexes table
+DROP TABLE IF EXISTS secret_blind_indexes;
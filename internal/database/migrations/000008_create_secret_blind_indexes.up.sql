@@ -0,0 +1,11 @@
+-- Create secret_blind_indexes table holding keyed hashes of secret fields for exact-match lookups
+CREATE TABLE
+    secret_blind_indexes (
+        secret_id UUID REFERENCES secrets (id) ON DELETE CASCADE,
+        field VARCHAR NOT NULL,
+        value BYTEA NOT NULL,
+        PRIMARY KEY (secret_id, field)
+    );
+
+-- Create indexes
+CREATE INDEX secret_blind_indexes_field_value ON secret_blind_indexes (field, value);
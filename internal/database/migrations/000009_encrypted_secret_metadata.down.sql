@@ -0,0 +1,18 @@
+-- Restore the search document of all metadata values
+CREATE OR REPLACE FUNCTION refresh_secret_search(target_secret_id UUID) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO secrets_search (secret_id, document)
+    SELECT
+        s.id,
+        setweight(to_tsvector('simple', coalesce(s.name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(s.description, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(ps.url, '') || ' ' || coalesce(ps.login, '')), 'C') ||
+        setweight(to_tsvector('simple', regexp_replace(coalesce(ps.url, '') || ' ' || coalesce(ps.login, ''), '[^[:alnum:]]+', ' ', 'g')), 'C')
+    FROM secrets s
+    LEFT JOIN password_secrets ps ON s.secret_type = 'password' AND ps.id = s.linked_secret_id
+    WHERE s.id = target_secret_id
+    ON CONFLICT (secret_id) DO UPDATE SET document = EXCLUDED.document;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS secret_search_text;
@@ -0,0 +1,27 @@
+-- Function returning the text to index for a secret metadata value.
+-- Encrypted values are prefixed with "enc:v1:" and are not indexed, their ciphertext means nothing to search.
+CREATE OR REPLACE FUNCTION secret_search_text(value TEXT) RETURNS TEXT AS $$
+BEGIN
+    IF value IS NULL OR value LIKE 'enc:v1:%' THEN
+        RETURN '';
+    END IF;
+    RETURN value;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Rebuild the search document without the encrypted metadata
+CREATE OR REPLACE FUNCTION refresh_secret_search(target_secret_id UUID) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO secrets_search (secret_id, document)
+    SELECT
+        s.id,
+        setweight(to_tsvector('simple', secret_search_text(s.name)), 'A') ||
+        setweight(to_tsvector('simple', secret_search_text(s.description)), 'B') ||
+        setweight(to_tsvector('simple', secret_search_text(ps.url) || ' ' || secret_search_text(ps.login)), 'C') ||
+        setweight(to_tsvector('simple', regexp_replace(secret_search_text(ps.url) || ' ' || secret_search_text(ps.login), '[^[:alnum:]]+', ' ', 'g')), 'C')
+    FROM secrets s
+    LEFT JOIN password_secrets ps ON s.secret_type = 'password' AND ps.id = s.linked_secret_id
+    WHERE s.id = target_secret_id
+    ON CONFLICT (secret_id) DO UPDATE SET document = EXCLUDED.document;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,21 @@
+-- Create users_totp table holding the TOTP secret of each user.
+-- A secret stays pending until the user confirms it with a first code.
+CREATE TABLE
+    users_totp (
+        user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+        secret VARCHAR NOT NULL,
+        enabled BOOLEAN NOT NULL DEFAULT false,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+    );
+
+-- Create users_backup_codes table holding the hashed one-time backup codes
+CREATE TABLE
+    users_backup_codes (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        code_hash VARCHAR NOT NULL,
+        used_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        UNIQUE (user_id, code_hash)
+    );
@@ -0,0 +1,19 @@
+-- Create webauthn_credentials table holding the passkeys and security keys of users
+CREATE TABLE
+    webauthn_credentials (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        credential_id BYTEA NOT NULL UNIQUE,
+        public_key BYTEA NOT NULL,
+        attestation_type VARCHAR NOT NULL,
+        aaguid BYTEA,
+        sign_count BIGINT NOT NULL DEFAULT 0,
+        transports TEXT[] NOT NULL DEFAULT '{}',
+        backup_eligible BOOLEAN NOT NULL DEFAULT false,
+        backup_state BOOLEAN NOT NULL DEFAULT false,
+        name VARCHAR NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        last_used_at TIMESTAMPTZ
+    );
+
+CREATE INDEX webauthn_credentials_user_id ON webauthn_credentials (user_id);
@@ -0,0 +1,13 @@
+-- Create refresh_token_families table holding the refresh token chain of each login.
+-- Only the current refresh token of a family can be exchanged, presenting an older one revokes the family.
+CREATE TABLE
+    refresh_token_families (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        current_token_id UUID NOT NULL,
+        revoked_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+    );
+
+CREATE INDEX refresh_token_families_user_id ON refresh_token_families (user_id);
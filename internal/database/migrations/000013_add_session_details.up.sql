@@ -0,0 +1,6 @@
+-- Each refresh token family is a login session, store where it was created and when it was last used
+ALTER TABLE refresh_token_families
+    ADD COLUMN device_name VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN user_agent VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN ip_address VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN last_used_at TIMESTAMPTZ NOT NULL DEFAULT now ();
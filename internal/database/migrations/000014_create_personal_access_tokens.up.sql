@@ -0,0 +1,17 @@
+-- Create personal_access_tokens table holding the long-lived tokens of users for non-interactive access.
+-- The token itself is only stored hashed, the vault key is stored wrapped with a key derived from the token.
+CREATE TABLE
+    personal_access_tokens (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        name VARCHAR NOT NULL,
+        token_hash VARCHAR NOT NULL UNIQUE,
+        scopes TEXT[] NOT NULL,
+        collection_ids UUID[] NOT NULL DEFAULT '{}',
+        wrapped_key BYTEA NOT NULL,
+        expires_at TIMESTAMPTZ,
+        last_used_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+    );
+
+CREATE INDEX personal_access_tokens_user_id ON personal_access_tokens (user_id);
@@ -0,0 +1,14 @@
+-- Create oidc_identities table linking the accounts at OpenID Connect providers to users
+CREATE TABLE
+    oidc_identities (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        issuer VARCHAR NOT NULL,
+        subject VARCHAR NOT NULL,
+        email VARCHAR NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        last_login_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        UNIQUE (issuer, subject)
+    );
+
+CREATE INDEX oidc_identities_user_id ON oidc_identities (user_id);
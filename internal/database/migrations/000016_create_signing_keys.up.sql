@@ -0,0 +1,16 @@
+-- Create signing_keys table holding the key pairs signing the JWT tokens
+CREATE TABLE
+    signing_keys (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        algorithm VARCHAR(16) NOT NULL,
+        private_key BYTEA NOT NULL,
+        public_key BYTEA NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        retired_at TIMESTAMPTZ,
+        expires_at TIMESTAMPTZ
+    );
+
+-- Only one key signs new tokens
+CREATE UNIQUE INDEX signing_keys_active ON signing_keys ((retired_at IS NULL))
+WHERE
+    retired_at IS NULL;
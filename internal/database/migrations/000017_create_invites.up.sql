@@ -0,0 +1,13 @@
+-- Create invites table holding the codes admins create to register accounts when sign-ups are restricted.
+-- The code itself is only stored hashed.
+CREATE TABLE
+    invites (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        code_hash VARCHAR NOT NULL UNIQUE,
+        created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+        role users_role_enum NOT NULL DEFAULT 'user',
+        max_uses INTEGER NOT NULL CHECK (max_uses > 0),
+        uses INTEGER NOT NULL DEFAULT 0,
+        expires_at TIMESTAMPTZ NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+    );
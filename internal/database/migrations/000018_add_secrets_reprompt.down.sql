@@ -0,0 +1 @@
+ALTER TABLE secrets DROP COLUMN IF EXISTS reprompt;
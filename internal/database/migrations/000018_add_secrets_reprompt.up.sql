@@ -0,0 +1,2 @@
+-- Flagged secrets only return their payload with a fresh master password proof
+ALTER TABLE secrets ADD COLUMN reprompt BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS force_mfa_enrollment,
+    DROP COLUMN IF EXISTS force_password_reset,
+    DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,5 @@
+-- Suspended users cannot log in, forced actions are resolved at the next login
+ALTER TABLE users
+    ADD COLUMN suspended_at TIMESTAMPTZ,
+    ADD COLUMN force_password_reset BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN force_mfa_enrollment BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,21 @@
+-- Create roles table holding the custom roles admins define on top of the built-in admin and user roles.
+-- The permissions of a role are formatted as "<resource>:<action>".
+CREATE TABLE
+    roles (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+        name VARCHAR NOT NULL UNIQUE,
+        description VARCHAR NOT NULL DEFAULT '',
+        permissions TEXT[] NOT NULL DEFAULT '{}',
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+    );
+
+-- Create user_roles table assigning the custom roles to users
+CREATE TABLE
+    user_roles (
+        user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+        role_id UUID NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+        PRIMARY KEY (user_id, role_id)
+    );
+
+CREATE INDEX user_roles_role_id ON user_roles (role_id);
@@ -0,0 +1 @@
+ALTER TABLE refresh_token_families DROP COLUMN IF EXISTS authenticated_at;
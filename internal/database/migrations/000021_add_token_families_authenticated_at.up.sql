@@ -0,0 +1,2 @@
+-- A session started by a login records when the user authenticated, the sessions of approved devices do not
+ALTER TABLE refresh_token_families ADD COLUMN authenticated_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+-- The roles created as groups by the identity provider, SCIM only lists and modifies these
+ALTER TABLE roles ADD COLUMN scim_managed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -34,13 +34,20 @@ type loginRequest struct {
 //
 //	@Summary		Login and get an access token
 //	@Description	Logs in a registered user and returns an access token if the credentials are valid.
+//	@Description	If two-factor authentication is enabled, an MFA challenge token is returned instead (mfa_required=true),
+//	@Description	to be sent with a code to /auth/login/mfa.
+//	@Description	A suspended user is refused. A user with a password reset or a second factor enrollment forced by an admin
+//	@Description	is logged in, but can only complete these actions until done.
 //	@Tags			Authentication
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		loginRequest			true	"Login request body"
+//	@Param			X-Device-Name	header		string					false	"Device name shown in the sessions, derived from the user agent if empty"
 //	@Success		200		{object}	response.AuthResponse	"Succesfully logged in"
 //	@Failure		400		{object}	response.ErrorResponse	"Validation error"
 //	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse	"User suspended"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
 //	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/auth/login [post]
 func (ah *AuthHandler) Login(ctx *gin.Context) {
@@ -50,7 +57,51 @@ func (ah *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := ah.svc.Login(ctx, req.Email, req.Password)
+	accessToken, refreshToken, mfaToken, err := ah.svc.Login(ctx, req.Email, req.Password, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	if mfaToken != "" {
+		response.HandleSuccess(ctx, response.NewMFAChallengeResponse(mfaToken))
+		return
+	}
+
+	rsp := response.NewAuthResponse(accessToken, refreshToken)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// loginMFARequest represents the request body for completing a login with two-factor authentication
+type loginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"Zm9vYmFyYmF6cXV4Zm9vYmFyYmF6cXV4Zm9vYmFyYmE"`
+	Code     string `json:"code" binding:"required,max=32" example:"123456"`
+}
+
+// LoginMFA godoc
+//
+//	@Summary		Complete a login with two-factor authentication
+//	@Description	Exchanges the MFA challenge token returned by the login and a TOTP code or a backup code for an access token.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		loginMFARequest			true	"Login MFA request body"
+//	@Param			X-Device-Name	header		string					false	"Device name shown in the sessions, derived from the user agent if empty"
+//	@Success		200		{object}	response.AuthResponse	"Succesfully logged in"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/login/mfa [post]
+func (ah *AuthHandler) LoginMFA(ctx *gin.Context) {
+	var req loginMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	accessToken, refreshToken, err := ah.svc.LoginMFA(ctx, req.MFAToken, req.Code, helper.GetClientInfo(ctx))
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -63,15 +114,17 @@ func (ah *AuthHandler) Login(ctx *gin.Context) {
 
 // registerRequet represents the request body for creating a user
 type registerRequest struct {
-	Name     string `json:"name" binding:"required" example:"John Doe"`
-	Email    string `json:"email" binding:"required,email" example:"test@example.com"`
-	Password string `json:"password" binding:"required,min=8" example:"12345678"`
+	Name       string `json:"name" binding:"required" example:"John Doe"`
+	Email      string `json:"email" binding:"required,email" example:"test@example.com"`
+	Password   string `json:"password" binding:"required" example:"Correct-Horse-42"` // checked against the password policy
+	InviteCode string `json:"invite_code" example:"Zm9vYmFyYmF6cXV4Zm9vYmFy"`         // required unless the registration mode lets the email register
 }
 
 // Register godoc
 //
 //	@Summary		Register a new user
 //	@Description	create a new user account with default role "user"
+//	@Description	Depending on the registration mode an invite code created by an admin is required, the account gets the role of the invite.
 //	@Tags			Authentication
 //	@Accept			json
 //	@Produce		json
@@ -79,8 +132,10 @@ type registerRequest struct {
 //	@Success		200				{object}	response.UserResponse	"User created"
 //	@Failure		400				{object}	response.ErrorResponse	"Validation error"
 //	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403				{object}	response.ErrorResponse	"Invite code required or invalid"
 //	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
 //	@Failure		409				{object}	response.ErrorResponse	"Data conflict error"
+//	@Failure		422				{object}	response.ErrorResponse	"Password policy error"
 //	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/auth/register [post]
 func (ah *AuthHandler) Register(ctx *gin.Context) {
@@ -96,7 +151,7 @@ func (ah *AuthHandler) Register(ctx *gin.Context) {
 		Password: req.Password,
 	}
 
-	_, err := ah.svc.Register(ctx, &user)
+	_, err := ah.svc.Register(ctx, &user, req.InviteCode)
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -124,6 +179,7 @@ type confirmRegistrationRequest struct {
 //	@Success		200		{object}	response.Response			"Successfully confirmed registration"
 //	@Failure		400		{object}	response.ErrorResponse		"Validation error"
 //	@Failure		404		{object}	response.ErrorResponse		"Data not found error"
+//	@Failure		429		{object}	response.ErrorResponse		"Too many failed attempts, retry after the Retry-After header"
 //	@Failure		500		{object}	response.ErrorResponse		"Internal server error"
 //	@Router			/auth/register/confirm [post]
 func (ah *AuthHandler) ConfirmRegistration(ctx *gin.Context) {
@@ -133,7 +189,7 @@ func (ah *AuthHandler) ConfirmRegistration(ctx *gin.Context) {
 		return
 	}
 
-	err := ah.svc.ConfirmRegistration(ctx, req.Email, req.OTP)
+	err := ah.svc.ConfirmRegistration(ctx, req.Email, req.OTP, helper.GetClientInfo(ctx))
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -182,7 +238,8 @@ func (ah *AuthHandler) ResendOTPCode(ctx *gin.Context) {
 // RefreshToken godoc
 //
 //	@Summary		Refresh an access token
-//	@Description	Refreshes an access token by providing the refresh token
+//	@Description	Refreshes an access token by providing the refresh token, which is replaced by a new one.
+//	@Description	A refresh token can be used only once, using it again logs out the session it belongs to.
 //	@Tags			Authentication
 //	@Accept			json
 //	@Produce		json
@@ -216,7 +273,7 @@ func (ah *AuthHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := ah.svc.RefreshToken(ctx, fields[1])
+	accessToken, refreshToken, err := ah.svc.RefreshToken(ctx, fields[1], helper.GetClientInfo(ctx))
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -230,7 +287,7 @@ func (ah *AuthHandler) RefreshToken(ctx *gin.Context) {
 // Logout godoc
 //
 //	@Summary		Logout a user
-//	@Description	Logs out a user by invalidating the access token
+//	@Description	Logs out a user by invalidating the access token and the refresh token of the session
 //	@Tags			Authentication
 //	@Accept			json
 //	@Produce		json
@@ -253,7 +310,7 @@ func (ah *AuthHandler) Logout(ctx *gin.Context) {
 
 type changePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required,min=8" example:"oldpassword"`
-	NewPassword string `json:"new_password" binding:"required,min=8" example:"newpassword"`
+	NewPassword string `json:"new_password" binding:"required" example:"Correct-Horse-42"` // checked against the password policy
 }
 
 // ResetPassword godoc
@@ -270,6 +327,7 @@ type changePasswordRequest struct {
 //	@Failure		403						{object}	response.ErrorResponse	"Forbidden error"
 //	@Failure		404						{object}	response.ErrorResponse	"Data not found error"
 //	@Failure		422						{object}	response.ErrorResponse	"Passwords do not match"
+//	@Failure		429						{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
 //	@Failure		500						{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/auth/change-password [put]
 //	@Security		BearerAuth
@@ -282,7 +340,50 @@ func (ah *AuthHandler) ChangePassword(ctx *gin.Context) {
 
 	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
 
-	err := ah.svc.ChangePassword(ctx, authPayload.UserID, req.OldPassword, req.NewPassword)
+	err := ah.svc.ChangePassword(ctx, authPayload.UserID, req.OldPassword, req.NewPassword, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
+
+// changeEmailRequest represents the request body for changing the email of the authenticated user
+type changeEmailRequest struct {
+	Password string `json:"password" binding:"required" example:"12345678"`
+	NewEmail string `json:"new_email" binding:"required,email" example:"new@example.com"`
+}
+
+// ChangeEmail godoc
+//
+//	@Summary		Request to change own email
+//	@Description	Send a code to the new email of the authenticated user, who confirms the change with the password.
+//	@Description	The email changes only once the code is confirmed. The current email gets a notice of the request,
+//	@Description	resetting the password cancels the change.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		changeEmailRequest		true	"Change email request"
+//	@Success		200		{object}	response.Response		"Confirmation code sent to the new email"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.ErrorResponse	"Email already in use"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/change-email [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) ChangeEmail(ctx *gin.Context) {
+	var req changeEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err := ah.svc.RequestEmailChange(ctx, authPayload.UserID, req.Password, req.NewEmail, helper.GetClientInfo(ctx))
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -291,6 +392,50 @@ func (ah *AuthHandler) ChangePassword(ctx *gin.Context) {
 	response.HandleSuccess(ctx, nil)
 }
 
+// confirmEmailChangeRequest represents the request body for confirming an email change with the OTP code
+type confirmEmailChangeRequest struct {
+	OTP                 string `json:"otp" binding:"required" example:"123456"`
+	RevokeOtherSessions bool   `json:"revoke_other_sessions" example:"true"`
+}
+
+// ConfirmEmailChange godoc
+//
+//	@Summary		Confirm own email change
+//	@Description	Change the email of the authenticated user to the one the code was sent to.
+//	@Description	The other sessions of the user are signed out if asked, the session making the request stays.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		confirmEmailChangeRequest	true	"Confirm email change request"
+//	@Success		200		{object}	response.UserResponse		"Email changed"
+//	@Failure		400		{object}	response.ErrorResponse		"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse		"Unauthorized error or invalid OTP"
+//	@Failure		404		{object}	response.ErrorResponse		"Data not found error"
+//	@Failure		409		{object}	response.ErrorResponse		"Email already in use"
+//	@Failure		429		{object}	response.ErrorResponse		"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500		{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/auth/change-email/confirm [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) ConfirmEmailChange(ctx *gin.Context) {
+	var req confirmEmailChangeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	user, err := ah.svc.ConfirmEmailChange(ctx, authPayload.UserID, authPayload.FamilyID, req.OTP, req.RevokeOtherSessions, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewUserResponse(user)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
 // forgotPasswordRequest represents the request body for requesting a reset of a forgotten password
 type forgotPasswordRequest struct {
 	Email string `json:"email" binding:"required,email" example:"user@example.com"`
@@ -328,7 +473,7 @@ func (ah *AuthHandler) ForgotPassword(ctx *gin.Context) {
 // resetPasswordRequest represents the request body for resetting password
 type resetPasswordRequest struct {
 	Email       string `json:"email" binding:"required,email" example:"test@example.com"`
-	NewPassword string `json:"new_password" binding:"required,min=8" example:"new_password" minLength:"8"`
+	NewPassword string `json:"new_password" binding:"required" example:"Correct-Horse-42"` // checked against the password policy
 	OTP         string `json:"otp" binding:"required" example:"123456"`
 }
 
@@ -346,6 +491,7 @@ type resetPasswordRequest struct {
 //	@Failure		400		{string}	string					"Invalid email or password format"
 //	@Failure		401		{string}	string					"Invalid OTP code"
 //	@Failure		404		{string}	string					"User not found"
+//	@Failure		429		{string}	string					"Too many failed attempts, retry after the Retry-After header"
 //	@Failure		500		{string}	string					"Internal server error"
 //	@Router			/auth/reset-password [post]
 func (ah *AuthHandler) ResetPassword(ctx *gin.Context) {
@@ -355,7 +501,7 @@ func (ah *AuthHandler) ResetPassword(ctx *gin.Context) {
 		return
 	}
 
-	err := ah.svc.ResetPassword(ctx, req.Email, req.NewPassword, req.OTP)
+	err := ah.svc.ResetPassword(ctx, req.Email, req.NewPassword, req.OTP, helper.GetClientInfo(ctx))
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -221,6 +221,60 @@ func (ch *CollectionHandler) UpdateCollection(ctx *gin.Context) {
 	response.HandleSuccess(ctx, rsp)
 }
 
+// transferCollectionRequest represents the request body for transferring a collection
+type transferCollectionRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// TransferCollection godoc
+//
+//	@Summary		Transfer collection ownership
+//	@Description	Make another user the owner of a collection, the new owner becomes a member of the collection
+//	@Tags			Collections
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string						true	"Collection ID"
+//	@Param			request			body		transferCollectionRequest	true	"Transfer collection request"
+//	@Success		200				{object}	response.CollectionResponse	"Collection transferred"
+//	@Failure		400				{object}	response.ErrorResponse		"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse		"Unauthorized error"
+//	@Failure		403				{object}	response.ErrorResponse		"Forbidden error"
+//	@Failure		404				{object}	response.ErrorResponse		"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/collections/{collection_id}/transfer [post]
+//	@Security		BearerAuth
+func (ch *CollectionHandler) TransferCollection(ctx *gin.Context) {
+	var req transferCollectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	newOwnerID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	collection, err := ch.svc.TransferOwnership(ctx, authPayload.UserID, collectionID, newOwnerID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewCollectionResponse(collection)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
 // deleteCollectionRequest represents the request body for deleting a collection
 type deleteCollectionRequest struct {
 	ID string `uri:"collection_id" binding:"required" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"`
@@ -230,6 +284,7 @@ type deleteCollectionRequest struct {
 //
 //	@Summary		Delete a collection
 //	@Description	Delete a collection by id
+//	@Description	Requires a recent authentication, see /auth/step-up, so personal access tokens cannot delete collections.
 //	@Tags			Collections
 //	@Accept			json
 //	@Produce		json
@@ -237,7 +292,7 @@ type deleteCollectionRequest struct {
 //	@Success		200				{object}	response.Response		"Collection deleted"
 //	@Failure		400				{object}	response.ErrorResponse	"Validation error"
 //	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
-//	@Failure		403				{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		403				{object}	response.ErrorResponse	"Forbidden error or recent authentication required"
 //	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
 //	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/collections/{collection_id} [delete]
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceHandler represents the HTTP handler for the device authorization grant of CLI and headless clients
+type DeviceHandler struct {
+	svc service.DeviceService
+}
+
+// NewDeviceHandler creates a new DeviceHandler instance
+func NewDeviceHandler(svc service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{
+		svc,
+	}
+}
+
+// RequestDeviceCode godoc
+//
+//	@Summary		Start a device login
+//	@Description	Start the login of a CLI or headless client with the device authorization grant (RFC 8628).
+//	@Description	The client shows the user code and the verification URI, where a logged in user approves it, and polls /auth/device/token with the device code.
+//	@Description	The response is not wrapped in the response body format, so standard OAuth 2.0 clients can use it.
+//	@Tags			Authentication
+//	@Accept			x-www-form-urlencoded,json
+//	@Produce		json
+//	@Param			X-Device-Name	header		string								false	"Device name shown in the sessions, derived from the user agent if empty"
+//	@Success		200				{object}	response.DeviceAuthorizationResponse	"Device authorization started"
+//	@Failure		500				{object}	response.OAuthErrorResponse			"Internal server error"
+//	@Router			/auth/device/code [post]
+func (dh *DeviceHandler) RequestDeviceCode(ctx *gin.Context) {
+	deviceCode, authorization, err := dh.svc.RequestDeviceAuthorization(ctx, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleOAuthError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, response.NewDeviceAuthorizationResponse(deviceCode, authorization))
+}
+
+// deviceTokenRequest represents the request body for polling the tokens of a device
+type deviceTokenRequest struct {
+	GrantType  string `form:"grant_type" json:"grant_type" binding:"required" example:"urn:ietf:params:oauth:grant-type:device_code"`
+	DeviceCode string `form:"device_code" json:"device_code" binding:"required" example:"Zm9vYmFyYmF6cXV4Zm9vYmFyYmF6cXV4Zm9vYmFyYmE"`
+}
+
+// RequestDeviceToken godoc
+//
+//	@Summary		Poll the tokens of a device
+//	@Description	Exchange the device code for a token pair once the user approved the device, as in RFC 8628.
+//	@Description	Until then the error is authorization_pending, or slow_down if the client polls more often than the interval.
+//	@Description	The response is not wrapped in the response body format, so standard OAuth 2.0 clients can use it.
+//	@Tags			Authentication
+//	@Accept			x-www-form-urlencoded,json
+//	@Produce		json
+//	@Param			request	body		deviceTokenRequest				true	"Device token request"
+//	@Success		200		{object}	response.DeviceTokenResponse	"Device logged in"
+//	@Failure		400		{object}	response.OAuthErrorResponse		"authorization_pending, slow_down, access_denied, expired_token or invalid_request"
+//	@Failure		500		{object}	response.OAuthErrorResponse		"Internal server error"
+//	@Router			/auth/device/token [post]
+func (dh *DeviceHandler) RequestDeviceToken(ctx *gin.Context) {
+	var req deviceTokenRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, response.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: err.Error()})
+		return
+	}
+
+	if req.GrantType != domain.DeviceCodeGrantType {
+		response.HandleOAuthError(ctx, domain.ErrUnsupportedGrantType)
+		return
+	}
+
+	accessToken, refreshToken, err := dh.svc.ExchangeDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		response.HandleOAuthError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, response.NewDeviceTokenResponse(accessToken, refreshToken))
+}
+
+// getDeviceRequest represents the request query for getting a pending device authorization
+type getDeviceRequest struct {
+	UserCode string `form:"user_code" binding:"required,max=16" example:"BDWP-HQTK"`
+}
+
+// GetDevice godoc
+//
+//	@Summary		Get a pending device login
+//	@Description	Get the device waiting for approval with the user code, so the user can check it before approving it.
+//	@Description	Wrong user codes are limited like the login.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			user_code	query		string					true	"User code shown by the device"
+//	@Success		200			{object}	response.DeviceResponse	"Pending device login"
+//	@Failure		400			{object}	response.ErrorResponse	"Validation error or invalid user code"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		429			{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/device [get]
+//	@Security		BearerAuth
+func (dh *DeviceHandler) GetDevice(ctx *gin.Context) {
+	var req getDeviceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	authorization, err := dh.svc.GetDeviceAuthorization(ctx, authPayload.UserID, req.UserCode, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, response.NewDeviceResponse(authorization))
+}
+
+// verifyDeviceRequest represents the request body for approving or denying a device login
+type verifyDeviceRequest struct {
+	UserCode string `json:"user_code" binding:"required,max=16" example:"BDWP-HQTK"`
+}
+
+// ApproveDevice godoc
+//
+//	@Summary		Approve a device login
+//	@Description	Log the device showing the user code in as the current user, the device gets its tokens on its next poll.
+//	@Description	Requires a recent authentication, see /auth/step-up.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		verifyDeviceRequest		true	"Approve device request"
+//	@Success		200		{object}	response.Response		"Device approved"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error or invalid user code"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse	"Recent authentication required"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/device/approve [post]
+//	@Security		BearerAuth
+func (dh *DeviceHandler) ApproveDevice(ctx *gin.Context) {
+	var req verifyDeviceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err := dh.svc.ApproveDeviceAuthorization(ctx, authPayload.UserID, req.UserCode, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
+
+// DenyDevice godoc
+//
+//	@Summary		Deny a device login
+//	@Description	Refuse the login of the device showing the user code, the device stops polling on its next poll.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		verifyDeviceRequest		true	"Deny device request"
+//	@Success		200		{object}	response.Response		"Device denied"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error or invalid user code"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/device/deny [post]
+//	@Security		BearerAuth
+func (dh *DeviceHandler) DenyDevice(ctx *gin.Context) {
+	var req verifyDeviceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err := dh.svc.DenyDeviceAuthorization(ctx, authPayload.UserID, req.UserCode, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FolderHandler represents the HTTP handler for folder-related requests
+type FolderHandler struct {
+	svc service.FolderService
+}
+
+// NewFolderHandler creates a new FolderHandler instance
+func NewFolderHandler(svc service.FolderService) *FolderHandler {
+	return &FolderHandler{
+		svc,
+	}
+}
+
+// createFolderRequest represents the request body for creating a folder
+type createFolderRequest struct {
+	Name     string `json:"name" binding:"required" example:"Databases"`
+	ParentID string `json:"parent_id,omitempty" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"` // Empty for the collection root
+}
+
+// CreateFolder godoc
+//
+//	@Summary		Create a new folder
+//	@Description	Create a new folder in the collection root or inside another folder
+//	@Tags			Folders
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			request			body		createFolderRequest		true	"Create Folder Request"
+//	@Success		200				{object}	response.FolderResponse	"Folder created"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/folders [post]
+//	@Security		BearerAuth
+func (fh *FolderHandler) CreateFolder(ctx *gin.Context) {
+	var req createFolderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	parentID, err := helper.ParseOptionalUUID(req.ParentID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	folder := domain.Folder{
+		CollectionID: collectionID,
+		ParentID:     parentID,
+		Name:         req.Name,
+	}
+
+	createdFolder, err := fh.svc.CreateFolder(ctx, authPayload.UserID, &folder)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewFolderResponse(createdFolder)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// listFoldersRequest represents the request body for listing folders
+type listFoldersRequest struct {
+	ParentID string `form:"parent_id" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"`
+}
+
+// ListFolders godoc
+//
+//	@Summary		List folders
+//	@Description	List the direct children of a folder, or the folders of the collection root if no parent is given
+//	@Tags			Folders
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			parent_id		query		string					false	"Parent folder ID"
+//	@Success		200				{object}	response.Meta			"Folders displayed"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/folders [get]
+//	@Security		BearerAuth
+func (fh *FolderHandler) ListFolders(ctx *gin.Context) {
+	var req listFoldersRequest
+	var foldersList []response.FolderResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	parentID, err := helper.ParseOptionalUUID(req.ParentID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	folders, err := fh.svc.ListFolders(ctx, authPayload.UserID, collectionID, parentID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, folder := range folders {
+		foldersList = append(foldersList, response.NewFolderResponse(&folder))
+	}
+
+	total := uint64(len(foldersList))
+	meta := response.NewMeta(total, total, 0)
+	rsp := helper.ToMap(meta, foldersList, "folders")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// folderRequest represents the request uri for working with a single folder
+type folderRequest struct {
+	CollectionID string `uri:"collection_id" binding:"required"`
+	FolderID     string `uri:"folder_id" binding:"required"`
+}
+
+// GetFolder godoc
+//
+//	@Summary		Get a folder
+//	@Description	Get a folder by id
+//	@Tags			Folders
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			folder_id		path		string					true	"Folder ID"
+//	@Success		200				{object}	response.FolderResponse	"Folder displayed"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/folders/{folder_id} [get]
+//	@Security		BearerAuth
+func (fh *FolderHandler) GetFolder(ctx *gin.Context) {
+	var req folderRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(req.CollectionID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	folderID, err := uuid.Parse(req.FolderID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	folder, err := fh.svc.GetFolder(ctx, authPayload.UserID, collectionID, folderID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewFolderResponse(folder)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// updateFolderRequest represents the request body for renaming a folder
+type updateFolderRequest struct {
+	Name string `json:"name" binding:"required" example:"Databases"`
+}
+
+// UpdateFolder godoc
+//
+//	@Summary		Rename a folder
+//	@Description	Rename a folder by id
+//	@Tags			Folders
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			folder_id		path		string					true	"Folder ID"
+//	@Param			request			body		updateFolderRequest		true	"Update Folder Request"
+//	@Success		200				{object}	response.FolderResponse	"Folder updated"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/folders/{folder_id} [put]
+//	@Security		BearerAuth
+func (fh *FolderHandler) UpdateFolder(ctx *gin.Context) {
+	var req updateFolderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	folderID, err := uuid.Parse(ctx.Param("folder_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	folder := domain.Folder{
+		ID:           folderID,
+		CollectionID: collectionID,
+		Name:         req.Name,
+	}
+
+	updatedFolder, err := fh.svc.UpdateFolder(ctx, authPayload.UserID, &folder)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewFolderResponse(updatedFolder)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// moveFolderRequest represents the request body for moving a folder
+type moveFolderRequest struct {
+	ParentID string `json:"parent_id,omitempty" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"` // Empty for the collection root
+}
+
+// MoveFolder godoc
+//
+//	@Summary		Move a folder
+//	@Description	Move a folder with its content under another folder or to the collection root
+//	@Tags			Folders
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			folder_id		path		string					true	"Folder ID"
+//	@Param			request			body		moveFolderRequest		true	"Move Folder Request"
+//	@Success		200				{object}	response.FolderResponse	"Folder moved"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		409				{object}	response.ErrorResponse	"Folder cycle error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/folders/{folder_id}/move [post]
+//	@Security		BearerAuth
+func (fh *FolderHandler) MoveFolder(ctx *gin.Context) {
+	var req moveFolderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	folderID, err := uuid.Parse(ctx.Param("folder_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	parentID, err := helper.ParseOptionalUUID(req.ParentID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	movedFolder, err := fh.svc.MoveFolder(ctx, authPayload.UserID, collectionID, folderID, parentID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewFolderResponse(movedFolder)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// DeleteFolder godoc
+//
+//	@Summary		Delete a folder
+//	@Description	Delete a folder by id together with all nested folders and secrets
+//	@Tags			Folders
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			folder_id		path		string					true	"Folder ID"
+//	@Success		200				{object}	response.Response		"Folder deleted"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/folders/{folder_id} [delete]
+//	@Security		BearerAuth
+func (fh *FolderHandler) DeleteFolder(ctx *gin.Context) {
+	var req folderRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(req.CollectionID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	folderID, err := uuid.Parse(req.FolderID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err = fh.svc.DeleteFolder(ctx, authPayload.UserID, collectionID, folderID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InviteHandler represents the HTTP handler for invite-related requests
+type InviteHandler struct {
+	svc service.InviteService
+}
+
+// NewInviteHandler creates a new InviteHandler instance
+func NewInviteHandler(svc service.InviteService) *InviteHandler {
+	return &InviteHandler{
+		svc,
+	}
+}
+
+// createInviteRequest represents the request body for creating an invite
+type createInviteRequest struct {
+	Role      domain.UserRoleEnum `json:"role" binding:"omitempty,user_role" example:"user"` // "user" if empty
+	MaxUses   int                 `json:"max_uses" binding:"omitempty,min=1" example:"1"`    // 1 if empty, for a single-use code
+	ExpiresAt time.Time           `json:"expires_at" binding:"required,gt" example:"2030-01-01T00:00:00Z"`
+}
+
+// CreateInvite godoc
+//
+//	@Summary		Create an invite
+//	@Description	Create an invite code to register accounts with the given role when sign-ups are restricted.
+//	@Description	The code registers up to max_uses accounts until it expires, it is returned only once.
+//	@Description	Inviting admins requires the roles:write permission.
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createInviteRequest				true	"Create invite request"
+//	@Success		200		{object}	response.CreatedInviteResponse	"Invite created"
+//	@Failure		400		{object}	response.ErrorResponse			"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse			"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse			"Forbidden error"
+//	@Failure		500		{object}	response.ErrorResponse			"Internal server error"
+//	@Router			/invites [post]
+//	@Security		BearerAuth
+func (ih *InviteHandler) CreateInvite(ctx *gin.Context) {
+	var req createInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	invite := &domain.Invite{
+		Role:      req.Role,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if invite.Role == "" {
+		invite.Role = domain.UserRole
+	}
+
+	// An admin of the invites cannot invite more permissions than an admin of the roles
+	if invite.Role != domain.UserRole && !helper.HasPermission(ctx, middleware.PermissionsKey, domain.RolesWritePermission) {
+		response.HandleError(ctx, domain.ErrForbidden)
+		return
+	}
+	if invite.MaxUses == 0 {
+		invite.MaxUses = 1
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	code, createdInvite, err := ih.svc.CreateInvite(ctx, authPayload.UserID, invite)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewCreatedInviteResponse(code, createdInvite)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// ListInvites godoc
+//
+//	@Summary		List invites
+//	@Description	List all invites with their uses, including the expired and used up ones
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Meta			"Invites displayed"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/invites [get]
+//	@Security		BearerAuth
+func (ih *InviteHandler) ListInvites(ctx *gin.Context) {
+	var invitesList []response.InviteResponse
+
+	invites, err := ih.svc.ListInvites(ctx)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, invite := range invites {
+		invitesList = append(invitesList, response.NewInviteResponse(&invite))
+	}
+
+	total := uint64(len(invitesList))
+	meta := response.NewMeta(total, total, 0)
+	rsp := helper.ToMap(meta, invitesList, "invites")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// inviteRequest represents the request path of an invite
+type inviteRequest struct {
+	InviteID string `uri:"invite_id" binding:"required,uuid" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// DeleteInvite godoc
+//
+//	@Summary		Revoke an invite
+//	@Description	Delete an invite, its code can no longer register accounts. The accounts it registered are kept.
+//	@Tags			Invites
+//	@Accept			json
+//	@Produce		json
+//	@Param			invite_id	path		string					true	"Invite ID"
+//	@Success		200			{object}	response.Response		"Invite revoked"
+//	@Failure		400			{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403			{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404			{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/invites/{invite_id} [delete]
+//	@Security		BearerAuth
+func (ih *InviteHandler) DeleteInvite(ctx *gin.Context) {
+	var req inviteRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	inviteID, err := uuid.Parse(req.InviteID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	err = ih.svc.DeleteInvite(ctx, inviteID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler represents the HTTP handler publishing the keys verifying the tokens
+type JWKSHandler struct {
+	svc service.TokenService
+}
+
+// NewJWKSHandler creates a new JWKSHandler instance
+func NewJWKSHandler(svc service.TokenService) *JWKSHandler {
+	return &JWKSHandler{
+		svc,
+	}
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Get the token verification keys
+//	@Description	Get the public keys verifying the access tokens as a JSON Web Key Set, for other services to verify them.
+//	@Description	The tokens name their key in the kid header, retired keys stay published until the tokens they signed expire.
+//	@Description	The set is served at the root of the server, not under the API path, and is not wrapped in the response body format.
+//	@Tags			Authentication
+//	@Produce		json
+//	@Success		200	{object}	response.JWKSResponse	"JSON Web Key Set"
+//	@Router			/.well-known/jwks.json [get]
+func (jh *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	rsp := response.NewJWKSResponse(jh.svc.ListVerificationKeys())
+
+	// Verifiers may cache the keys, they fetch them again for a token signed with an unknown key
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, rsp)
+}
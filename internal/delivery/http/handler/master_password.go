@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/8thgencore/passfort/internal/delivery/http/helper"
 	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
 	"github.com/8thgencore/passfort/internal/delivery/http/response"
@@ -24,7 +25,7 @@ func NewMasterPasswordHandler(svc service.MasterPasswordService) *MasterPassword
 
 // createMasterPasswordRequest represents the request body for creating a master password
 type createMasterPasswordRequest struct {
-	Password string `json:"password" binding:"required,min=8" example:"masterpassword"`
+	Password string `json:"password" binding:"required" example:"Battery-Staple-Vault-7"` // checked against the master password policy
 }
 
 // CreateMasterPassword godoc
@@ -75,13 +76,15 @@ func (h *MasterPasswordHandler) CreateMasterPassword(ctx *gin.Context) {
 // changeMasterPasswordRequest represents the request body for changing a master password
 type changeMasterPasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required,min=8" example:"currentmasterpassword"`
-	NewPassword     string `json:"new_password" binding:"required,min=8" example:"newmasterpassword"`
+	NewPassword     string `json:"new_password" binding:"required" example:"Battery-Staple-Vault-7"` // checked against the master password policy
 }
 
 // ChangeMasterPassword godoc
 //
 //	@Summary		Change master password
-//	@Description	Change the master password for the authenticated user
+//	@Description	Change the master password for the authenticated user.
+//	@Description	The personal access tokens of the user are revoked, since they hold the old vault key.
+//	@Description	Requires a recent authentication, see /auth/step-up.
 //	@Tags			MasterPassword
 //	@Accept			json
 //	@Produce		json
@@ -89,6 +92,8 @@ type changeMasterPasswordRequest struct {
 //	@Success		200		{object}	response.Response			"Master password changed successfully"
 //	@Failure		400		{object}	response.ErrorResponse		"Validation error"
 //	@Failure		401		{object}	response.ErrorResponse		"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse		"Recent authentication required"
+//	@Failure		429		{object}	response.ErrorResponse		"Too many failed attempts, retry after the Retry-After header"
 //	@Failure		500		{object}	response.ErrorResponse		"Internal server error"
 //	@Router			/master-password [put]
 //	@Security		BearerAuth
@@ -103,13 +108,13 @@ func (h *MasterPasswordHandler) ChangeMasterPassword(ctx *gin.Context) {
 	userID := authPayload.UserID
 
 	// Save the new master password (hashed)
-	if err := h.svc.ChangeMasterPassword(ctx, userID, req.CurrentPassword, req.NewPassword); err != nil {
+	if err := h.svc.ChangeMasterPassword(ctx, userID, req.CurrentPassword, req.NewPassword, helper.GetClientInfo(ctx)); err != nil {
 		response.HandleError(ctx, err)
 		return
 	}
 
 	// Activate current master password
-	if err := h.svc.ActivateMasterPassword(ctx, userID, req.NewPassword); err != nil {
+	if err := h.svc.ActivateMasterPassword(ctx, userID, req.NewPassword, helper.GetClientInfo(ctx)); err != nil {
 		response.HandleError(ctx, err)
 		return
 	}
@@ -133,6 +138,7 @@ type activateMasterPasswordRequest struct {
 //	@Success		200		{object}	response.Response				"Master password is activated"
 //	@Failure		400		{object}	response.ErrorResponse			"Validation error"
 //	@Failure		401		{object}	response.ErrorResponse			"Invalid master password"
+//	@Failure		429		{object}	response.ErrorResponse			"Too many failed attempts, retry after the Retry-After header"
 //	@Failure		500		{object}	response.ErrorResponse			"Internal server error"
 //	@Router			/master-password/activate [post]
 //	@Security		BearerAuth
@@ -147,8 +153,12 @@ func (h *MasterPasswordHandler) ActivateMasterPassword(ctx *gin.Context) {
 	userID := authPayload.UserID
 
 	// Activate current master password
-	if err := h.svc.ActivateMasterPassword(ctx, userID, req.Password); err != nil {
-		response.HandleError(ctx, domain.ErrInvalidMasterPassword)
+	if err := h.svc.ActivateMasterPassword(ctx, userID, req.Password, helper.GetClientInfo(ctx)); err != nil {
+		var retryErr *domain.RetryAfterError
+		if !errors.As(err, &retryErr) {
+			err = domain.ErrInvalidMasterPassword
+		}
+		response.HandleError(ctx, err)
 		return
 	}
 
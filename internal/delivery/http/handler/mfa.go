@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler represents the HTTP handler for two-factor authentication-related requests
+type MFAHandler struct {
+	svc service.MFAService
+}
+
+// NewMFAHandler creates a new MFAHandler instance
+func NewMFAHandler(svc service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		svc,
+	}
+}
+
+// GetMFAStatus godoc
+//
+//	@Summary		Get the two-factor authentication status
+//	@Description	Get whether TOTP is enabled for the current user and how many backup codes are left
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.MFAStatusResponse	"Two-factor authentication status"
+//	@Failure		401	{object}	response.ErrorResponse		"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/auth/mfa [get]
+//	@Security		BearerAuth
+func (mh *MFAHandler) GetMFAStatus(ctx *gin.Context) {
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	status, err := mh.svc.GetStatus(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewMFAStatusResponse(status)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// EnrollTOTP godoc
+//
+//	@Summary		Start a TOTP enrollment
+//	@Description	Generate a new TOTP secret with its otpauth URI and a QR code PNG (base64) for authenticator apps.
+//	@Description	TOTP is enabled once the secret is confirmed with a first code, starting again replaces a pending secret.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.TOTPEnrollmentResponse	"TOTP enrollment started"
+//	@Failure		401	{object}	response.ErrorResponse			"Unauthorized error"
+//	@Failure		409	{object}	response.ErrorResponse			"Data conflict error"
+//	@Failure		500	{object}	response.ErrorResponse			"Internal server error"
+//	@Router			/auth/mfa/totp [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) EnrollTOTP(ctx *gin.Context) {
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	enrollment, err := mh.svc.EnrollTOTP(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewTOTPEnrollmentResponse(enrollment)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// mfaCodeRequest represents the request body with a two-factor authentication code
+type mfaCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32" example:"123456"`
+}
+
+// ConfirmTOTP godoc
+//
+//	@Summary		Confirm a TOTP enrollment
+//	@Description	Enable TOTP with a first code from the authenticator app.
+//	@Description	The one-time backup codes are returned only once, they can be used instead of a TOTP code.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		mfaCodeRequest					true	"Confirm TOTP request"
+//	@Success		200		{object}	response.BackupCodesResponse	"TOTP enabled"
+//	@Failure		400		{object}	response.ErrorResponse			"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse			"Unauthorized error"
+//	@Failure		409		{object}	response.ErrorResponse			"Data conflict error"
+//	@Failure		500		{object}	response.ErrorResponse			"Internal server error"
+//	@Router			/auth/mfa/totp/confirm [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) ConfirmTOTP(ctx *gin.Context) {
+	var req mfaCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	codes, err := mh.svc.ConfirmTOTP(ctx, authPayload.UserID, req.Code)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewBackupCodesResponse(codes)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// DisableTOTP godoc
+//
+//	@Summary		Disable TOTP
+//	@Description	Disable two-factor authentication with a current TOTP code or a backup code, the backup codes are deleted
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		mfaCodeRequest			true	"Disable TOTP request"
+//	@Success		200		{object}	response.Response		"TOTP disabled"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/mfa/totp/disable [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) DisableTOTP(ctx *gin.Context) {
+	var req mfaCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err := mh.svc.DisableTOTP(ctx, authPayload.UserID, req.Code, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+// BeginOIDCLogin godoc
+//
+//	@Summary		Start a single sign-on
+//	@Description	Get the URL of the OpenID Connect provider to send the user to.
+//	@Description	The provider redirects the user back with a code and the state, to be sent to /auth/oidc/login/finish within 10 minutes.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.OIDCLoginResponse	"Authorization URL of the provider"
+//	@Failure		404	{object}	response.ErrorResponse		"Single sign-on not configured"
+//	@Failure		500	{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/auth/oidc/login/begin [post]
+func (ah *AuthHandler) BeginOIDCLogin(ctx *gin.Context) {
+	authURL, state, err := ah.svc.BeginOIDCLogin(ctx)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewOIDCLoginResponse(authURL, state)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// finishOIDCLoginRequest represents the request body for finishing a single sign-on
+type finishOIDCLoginRequest struct {
+	Code  string `json:"code" binding:"required" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	State string `json:"state" binding:"required" example:"Zm9vYmFyYmF6cXV4Zm9vYmFyYmF6cXV4Zm9vYmFyYmE"`
+}
+
+// FinishOIDCLogin godoc
+//
+//	@Summary		Finish a single sign-on
+//	@Description	Exchange the code passed by the OpenID Connect provider to the redirect URL for an access token.
+//	@Description	The account is linked by the email verified by the provider and created on the first login if the sign-up and the registration mode allow it.
+//	@Description	If two-factor authentication is enabled, an MFA challenge token is returned instead (mfa_required=true).
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		finishOIDCLoginRequest	true	"Finish single sign-on request body"
+//	@Param			X-Device-Name	header		string					false	"Device name shown in the sessions, derived from the user agent if empty"
+//	@Success		200				{object}	response.AuthResponse	"Succesfully logged in"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403				{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404				{object}	response.ErrorResponse	"Single sign-on not configured"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/oidc/login/finish [post]
+func (ah *AuthHandler) FinishOIDCLogin(ctx *gin.Context) {
+	var req finishOIDCLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	accessToken, refreshToken, mfaToken, err := ah.svc.FinishOIDCLogin(ctx, req.Code, req.State, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	if mfaToken != "" {
+		response.HandleSuccess(ctx, response.NewMFAChallengeResponse(mfaToken))
+		return
+	}
+
+	rsp := response.NewAuthResponse(accessToken, refreshToken)
+
+	response.HandleSuccess(ctx, rsp)
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/8thgencore/passfort/pkg/base64_util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenHandler represents the HTTP handler for personal access token-related requests
+type PersonalAccessTokenHandler struct {
+	svc service.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler creates a new PersonalAccessTokenHandler instance
+func NewPersonalAccessTokenHandler(svc service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		svc,
+	}
+}
+
+// createPersonalAccessTokenRequest represents the request body for creating a personal access token
+type createPersonalAccessTokenRequest struct {
+	Name          string     `json:"name" binding:"required,max=64" example:"CI deploy"`
+	Scopes        []string   `json:"scopes" binding:"required,min=1,dive,oneof=collections:read collections:write secrets:read secrets:write" example:"secrets:read"`
+	CollectionIDs []string   `json:"collection_ids" binding:"omitempty,dive,uuid" example:"c2a06b0c-3e1b-4a7b-9b8a-6d6f1b6f3c1a"` // Empty for all collections
+	ExpiresAt     *time.Time `json:"expires_at" binding:"omitempty,gt" example:"2030-01-01T00:00:00Z"`                            // Empty for no expiry
+}
+
+// CreatePersonalAccessToken godoc
+//
+//	@Summary		Create a personal access token
+//	@Description	Create a long-lived token for non-interactive access, e.g. from CI jobs, sent as a bearer token like a JWT.
+//	@Description	The token can decrypt secrets by itself, so the master password must be activated to create it.
+//	@Description	The token is returned only once, it is revoked when the master password is changed.
+//	@Description	Requires a recent authentication, see /auth/step-up.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createPersonalAccessTokenRequest			true	"Create personal access token request"
+//	@Success		200		{object}	response.CreatedPersonalAccessTokenResponse	"Personal access token created"
+//	@Failure		400		{object}	response.ErrorResponse						"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse						"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse						"Recent authentication required"
+//	@Failure		404		{object}	response.ErrorResponse						"Data not found error"
+//	@Failure		500		{object}	response.ErrorResponse						"Internal server error"
+//	@Router			/auth/tokens [post]
+//	@Security		BearerAuth
+func (th *PersonalAccessTokenHandler) CreatePersonalAccessToken(ctx *gin.Context) {
+	var req createPersonalAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	token := &domain.PersonalAccessToken{
+		Name: req.Name,
+	}
+	for _, scope := range req.Scopes {
+		token.Scopes = append(token.Scopes, domain.TokenScope(scope))
+	}
+	for _, id := range req.CollectionIDs {
+		collectionID, err := uuid.Parse(id)
+		if err != nil {
+			response.ValidationError(ctx, err)
+			return
+		}
+		token.CollectionIDs = append(token.CollectionIDs, collectionID)
+	}
+	if req.ExpiresAt != nil {
+		token.ExpiresAt = *req.ExpiresAt
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	encryptionKey, err := base64_util.Base64ToBytes(helper.GetEncryptionKey(ctx, middleware.EncryptionKey))
+	if err != nil {
+		response.HandleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	rawToken, createdToken, err := th.svc.CreatePersonalAccessToken(ctx, authPayload.UserID, token, encryptionKey)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewCreatedPersonalAccessTokenResponse(rawToken, createdToken)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// ListPersonalAccessTokens godoc
+//
+//	@Summary		List personal access tokens
+//	@Description	List the personal access tokens of the current user with their last use
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Meta			"Personal access tokens displayed"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/tokens [get]
+//	@Security		BearerAuth
+func (th *PersonalAccessTokenHandler) ListPersonalAccessTokens(ctx *gin.Context) {
+	var tokensList []response.PersonalAccessTokenResponse
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	tokens, err := th.svc.ListPersonalAccessTokens(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, token := range tokens {
+		tokensList = append(tokensList, response.NewPersonalAccessTokenResponse(&token))
+	}
+
+	total := uint64(len(tokensList))
+	meta := response.NewMeta(total, total, 0)
+	rsp := helper.ToMap(meta, tokensList, "tokens")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// personalAccessTokenRequest represents the request path of a personal access token
+type personalAccessTokenRequest struct {
+	TokenID string `uri:"token_id" binding:"required,uuid" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// DeletePersonalAccessToken godoc
+//
+//	@Summary		Revoke a personal access token
+//	@Description	Delete a personal access token of the current user, it can no longer be used.
+//	@Description	Requires a recent authentication, see /auth/step-up.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			token_id	path		string					true	"Personal access token ID"
+//	@Success		200			{object}	response.Response		"Personal access token revoked"
+//	@Failure		400			{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403			{object}	response.ErrorResponse	"Recent authentication required"
+//	@Failure		404			{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/tokens/{token_id} [delete]
+//	@Security		BearerAuth
+func (th *PersonalAccessTokenHandler) DeletePersonalAccessToken(ctx *gin.Context) {
+	var req personalAccessTokenRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	tokenID, err := uuid.Parse(req.TokenID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err = th.svc.DeletePersonalAccessToken(ctx, authPayload.UserID, tokenID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
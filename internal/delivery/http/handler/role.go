@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RoleHandler represents the HTTP handler for role-related requests
+type RoleHandler struct {
+	svc service.RoleService
+}
+
+// NewRoleHandler creates a new RoleHandler instance
+func NewRoleHandler(svc service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		svc,
+	}
+}
+
+// roleBodyRequest represents the request body for creating or updating a role
+type roleBodyRequest struct {
+	Name        string              `json:"name" binding:"required,max=64" example:"support"`
+	Description string              `json:"description" binding:"max=255" example:"Helps the users with their accounts"`
+	Permissions []domain.Permission `json:"permissions" binding:"required,dive,permission" example:"users:read"`
+}
+
+// CreateRole godoc
+//
+//	@Summary		Create a role
+//	@Description	Create a custom role granting permissions to the users it is assigned to, on top of their built-in role
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		roleBodyRequest			true	"Create role request"
+//	@Success		200		{object}	response.RoleResponse	"Role created"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		409		{object}	response.ErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/roles [post]
+//	@Security		BearerAuth
+func (rh *RoleHandler) CreateRole(ctx *gin.Context) {
+	var req roleBodyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	role := &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	createdRole, err := rh.svc.CreateRole(ctx, role)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewRoleResponse(createdRole)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// ListRoles godoc
+//
+//	@Summary		List roles
+//	@Description	List all custom roles with their permissions
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Meta			"Roles displayed"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/roles [get]
+//	@Security		BearerAuth
+func (rh *RoleHandler) ListRoles(ctx *gin.Context) {
+	roles, err := rh.svc.ListRoles(ctx)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := newRolesResponse(roles)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// ListPermissions godoc
+//
+//	@Summary		List permissions
+//	@Description	List all the permissions a role can grant
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.PermissionsResponse	"Permissions displayed"
+//	@Failure		401	{object}	response.ErrorResponse			"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse			"Forbidden error"
+//	@Router			/roles/permissions [get]
+//	@Security		BearerAuth
+func (rh *RoleHandler) ListPermissions(ctx *gin.Context) {
+	rsp := response.NewPermissionsResponse(domain.Permissions)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// roleRequest represents the request path of a role
+type roleRequest struct {
+	RoleID string `uri:"role_id" binding:"required,uuid" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// UpdateRole godoc
+//
+//	@Summary		Update a role
+//	@Description	Update the name, the description and the permissions of a custom role.
+//	@Description	The users of the role get its new permissions on their next request, without logging in again.
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			role_id	path		string					true	"Role ID"
+//	@Param			request	body		roleBodyRequest			true	"Update role request"
+//	@Success		200		{object}	response.RoleResponse	"Role updated"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404		{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.ErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/roles/{role_id} [put]
+//	@Security		BearerAuth
+func (rh *RoleHandler) UpdateRole(ctx *gin.Context) {
+	var uri roleRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	roleID, err := uuid.Parse(uri.RoleID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	var req roleBodyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	role := &domain.Role{
+		ID:          roleID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+
+	updatedRole, err := rh.svc.UpdateRole(ctx, role)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewRoleResponse(updatedRole)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// DeleteRole godoc
+//
+//	@Summary		Delete a role
+//	@Description	Delete a custom role, its users lose its permissions on their next request
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			role_id	path		string					true	"Role ID"
+//	@Success		200		{object}	response.Response		"Role deleted"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404		{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/roles/{role_id} [delete]
+//	@Security		BearerAuth
+func (rh *RoleHandler) DeleteRole(ctx *gin.Context) {
+	var req roleRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	if err := rh.svc.DeleteRole(ctx, roleID); err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
+
+// userRolesRequest represents the request path of the roles of a user
+type userRolesRequest struct {
+	ID string `uri:"id" binding:"required,uuid" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"`
+}
+
+// ListUserRoles godoc
+//
+//	@Summary		List the roles of a user
+//	@Description	List the custom roles assigned to a user
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string					true	"User ID"
+//	@Success		200	{object}	response.Meta			"Roles displayed"
+//	@Failure		400	{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/roles [get]
+//	@Security		BearerAuth
+func (rh *RoleHandler) ListUserRoles(ctx *gin.Context) {
+	var req userRolesRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	roles, err := rh.svc.ListUserRoles(ctx, userID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := newRolesResponse(roles)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// setUserRolesRequest represents the request body for assigning roles to a user
+type setUserRolesRequest struct {
+	RoleIDs []uuid.UUID `json:"role_ids" binding:"required" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// SetUserRoles godoc
+//
+//	@Summary		Assign roles to a user
+//	@Description	Replace the custom roles assigned to a user, an empty list removes them all.
+//	@Description	The user gets the new permissions on their next request, without logging in again.
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"User ID"
+//	@Param			request	body		setUserRolesRequest		true	"Assign roles request"
+//	@Success		200		{object}	response.Meta			"Roles assigned"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403		{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404		{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/roles [put]
+//	@Security		BearerAuth
+func (rh *RoleHandler) SetUserRoles(ctx *gin.Context) {
+	var uri userRolesRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	userID, err := uuid.Parse(uri.ID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	var req setUserRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	roles, err := rh.svc.SetUserRoles(ctx, userID, req.RoleIDs)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := newRolesResponse(roles)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// GetMePermissions godoc
+//
+//	@Summary		Get the permissions of the authenticated user
+//	@Description	Get the permissions granted to the authenticated user by their built-in role and their custom roles
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.PermissionsResponse	"Permissions displayed"
+//	@Failure		401	{object}	response.ErrorResponse			"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse			"Internal server error"
+//	@Router			/users/me/permissions [get]
+//	@Security		BearerAuth
+func (rh *RoleHandler) GetMePermissions(ctx *gin.Context) {
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	permissions, err := rh.svc.GetUserPermissions(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewPermissionsResponse(permissions)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// newRolesResponse creates the response body of a list of roles
+func newRolesResponse(roles []domain.Role) map[string]any {
+	rolesList := make([]response.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		rolesList = append(rolesList, response.NewRoleResponse(&role))
+	}
+
+	total := uint64(len(rolesList))
+	meta := response.NewMeta(total, total, 0)
+
+	return helper.ToMap(meta, rolesList, "roles")
+}
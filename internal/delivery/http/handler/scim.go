@@ -0,0 +1,795 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// scimFilterRegexp matches the SCIM filters comparing an attribute with a string, e.g. userName eq "john@example.com"
+var scimFilterRegexp = regexp.MustCompile(`^\s*([A-Za-z][\w.]*)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// SCIMHandler represents the HTTP handler of the SCIM 2.0 API provisioning the users and the groups (RFC 7644)
+type SCIMHandler struct {
+	svc service.SCIMService
+}
+
+// NewSCIMHandler creates a new SCIMHandler instance
+func NewSCIMHandler(svc service.SCIMService) *SCIMHandler {
+	return &SCIMHandler{
+		svc,
+	}
+}
+
+// scimListRequest represents the query of a SCIM list request
+type scimListRequest struct {
+	Filter     string  `form:"filter" example:"userName eq \"john@example.com\""`
+	StartIndex uint64  `form:"startIndex" binding:"omitempty,min=1" example:"1"`
+	Count      *uint64 `form:"count" example:"100"`
+}
+
+// scimUserRequest represents a SCIM user sent by the identity provider, its userName is the email of the user
+type scimUserRequest struct {
+	Schemas     []string             `json:"schemas" example:"urn:ietf:params:scim:schemas:core:2.0:User"`
+	UserName    string               `json:"userName" binding:"required,email" example:"john@example.com"`
+	Name        response.SCIMName    `json:"name"`
+	DisplayName string               `json:"displayName" example:"John Doe"`
+	Emails      []response.SCIMEmail `json:"emails"`
+	Active      *bool                `json:"active" example:"true"` // true if empty
+}
+
+// scimMemberRequest represents a member of a SCIM group sent by the identity provider
+type scimMemberRequest struct {
+	Value string `json:"value" binding:"required,uuid" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// scimGroupRequest represents a SCIM group sent by the identity provider
+type scimGroupRequest struct {
+	Schemas     []string            `json:"schemas" example:"urn:ietf:params:scim:schemas:core:2.0:Group"`
+	DisplayName string              `json:"displayName" binding:"required,max=64" example:"support"`
+	Members     []scimMemberRequest `json:"members" binding:"dive"`
+}
+
+// scimPatchOperation represents an operation of a SCIM patch request
+type scimPatchOperation struct {
+	Op    string          `json:"op" binding:"required" example:"replace"`
+	Path  string          `json:"path" example:"active"`
+	Value json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+// scimPatchRequest represents a SCIM patch request
+type scimPatchRequest struct {
+	Schemas    []string             `json:"schemas" example:"urn:ietf:params:scim:api:messages:2.0:PatchOp"`
+	Operations []scimPatchOperation `json:"Operations" binding:"required,min=1,dive"`
+}
+
+// ListUsers godoc
+//
+//	@Summary		List SCIM users
+//	@Description	List the users for the identity provider, filtered by id or userName with eq.
+//	@Description	The userName of a user is their email.
+//	@Tags			SCIM
+//	@Produce		json
+//	@Param			filter		query		string						false	"Filter"
+//	@Param			startIndex	query		uint64						false	"1-based index of the first user"
+//	@Param			count		query		uint64						false	"Page size, up to 200"
+//	@Success		200			{object}	response.SCIMListResponse	"Users displayed"
+//	@Failure		400			{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401			{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		500			{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Users [get]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) ListUsers(ctx *gin.Context) {
+	filter, startIndex, count, ok := bindSCIMList(ctx)
+	if !ok {
+		return
+	}
+
+	users, total, err := sh.svc.ListUsers(ctx, filter, startIndex, count)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	resources := make([]response.SCIMUserResponse, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, response.NewSCIMUserResponse(&user, scimBaseURL(ctx)))
+	}
+
+	response.HandleSCIMSuccess(ctx, http.StatusOK, response.NewSCIMListResponse(resources, total, startIndex))
+}
+
+// GetUser godoc
+//
+//	@Summary		Get a SCIM user
+//	@Description	Get a user for the identity provider by id
+//	@Tags			SCIM
+//	@Produce		json
+//	@Param			id	path		string						true	"User ID"
+//	@Success		200	{object}	response.SCIMUserResponse	"User displayed"
+//	@Failure		401	{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404	{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Users/{id} [get]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) GetUser(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := sh.svc.GetUser(ctx, id)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	response.HandleSCIMSuccess(ctx, http.StatusOK, response.NewSCIMUserResponse(user, scimBaseURL(ctx)))
+}
+
+// CreateUser godoc
+//
+//	@Summary		Provision a SCIM user
+//	@Description	Create a verified account for the identity provider, an inactive user is created suspended.
+//	@Description	The account gets a random password, its user logs in with single sign-on or resets the password.
+//	@Tags			SCIM
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		scimUserRequest				true	"SCIM user"
+//	@Success		201		{object}	response.SCIMUserResponse	"User provisioned"
+//	@Failure		400		{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		409		{object}	response.SCIMErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Users [post]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) CreateUser(ctx *gin.Context) {
+	var req scimUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	user, active := req.toUser()
+
+	createdUser, err := sh.svc.CreateUser(ctx, user, active)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	rsp := response.NewSCIMUserResponse(createdUser, scimBaseURL(ctx))
+	ctx.Header("Location", rsp.Meta.Location)
+
+	response.HandleSCIMSuccess(ctx, http.StatusCreated, rsp)
+}
+
+// ReplaceUser godoc
+//
+//	@Summary		Replace a SCIM user
+//	@Description	Update the name and the email of a user, and suspend or unsuspend them to match the active attribute
+//	@Tags			SCIM
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"User ID"
+//	@Param			request	body		scimUserRequest				true	"SCIM user"
+//	@Success		200		{object}	response.SCIMUserResponse	"User replaced"
+//	@Failure		400		{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.SCIMErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Users/{id} [put]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) ReplaceUser(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	var req scimUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	sh.replaceUser(ctx, id, &req)
+}
+
+// PatchUser godoc
+//
+//	@Summary		Patch a SCIM user
+//	@Description	Apply add and replace operations to the userName, the name, the displayName and the active attributes of a user.
+//	@Description	Replacing active with false deactivates the user. The attributes not stored, e.g. externalId, are ignored.
+//	@Tags			SCIM
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"User ID"
+//	@Param			request	body		scimPatchRequest			true	"SCIM patch"
+//	@Success		200		{object}	response.SCIMUserResponse	"User patched"
+//	@Failure		400		{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.SCIMErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Users/{id} [patch]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) PatchUser(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	var patch scimPatchRequest
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	user, err := sh.svc.GetUser(ctx, id)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	// The operations apply to the current user, which then replaces it
+	active := !user.IsSuspended()
+	req := scimUserRequest{UserName: user.Email, DisplayName: user.Name, Active: &active}
+	for _, op := range patch.Operations {
+		if err := req.applyPatch(op); err != nil {
+			response.HandleSCIMError(ctx, err)
+			return
+		}
+	}
+
+	// The patched given and family names take precedence over the current name
+	if req.DisplayName == user.Name && req.Name.Formatted == "" && (req.Name.GivenName != "" || req.Name.FamilyName != "") {
+		req.DisplayName = ""
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	sh.replaceUser(ctx, id, &req)
+}
+
+// DeleteUser godoc
+//
+//	@Summary		Deprovision a SCIM user
+//	@Description	Deactivate a user, the user is suspended and their sessions are revoked.
+//	@Description	The account and its vault are kept, an admin deletes them once the collections are handed over.
+//	@Tags			SCIM
+//	@Param			id	path	string	true	"User ID"
+//	@Success		204	"User deactivated"
+//	@Failure		401	{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404	{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Users/{id} [delete]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) DeleteUser(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := sh.svc.DeactivateUser(ctx, id); err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListGroups godoc
+//
+//	@Summary		List SCIM groups
+//	@Description	List the groups for the identity provider, filtered by id or displayName with eq.
+//	@Description	A group is a custom role, its members are the users the role is assigned to.
+//	@Tags			SCIM
+//	@Produce		json
+//	@Param			filter		query		string						false	"Filter"
+//	@Param			startIndex	query		uint64						false	"1-based index of the first group"
+//	@Param			count		query		uint64						false	"Page size, up to 200"
+//	@Success		200			{object}	response.SCIMListResponse	"Groups displayed"
+//	@Failure		400			{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401			{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		500			{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Groups [get]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) ListGroups(ctx *gin.Context) {
+	filter, startIndex, count, ok := bindSCIMList(ctx)
+	if !ok {
+		return
+	}
+
+	groups, total, err := sh.svc.ListGroups(ctx, filter, startIndex, count)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	resources := make([]response.SCIMGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, response.NewSCIMGroupResponse(&group, scimBaseURL(ctx)))
+	}
+
+	response.HandleSCIMSuccess(ctx, http.StatusOK, response.NewSCIMListResponse(resources, total, startIndex))
+}
+
+// GetGroup godoc
+//
+//	@Summary		Get a SCIM group
+//	@Description	Get a group for the identity provider by id
+//	@Tags			SCIM
+//	@Produce		json
+//	@Param			id	path		string						true	"Group ID"
+//	@Success		200	{object}	response.SCIMGroupResponse	"Group displayed"
+//	@Failure		401	{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404	{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Groups/{id} [get]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) GetGroup(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	group, err := sh.svc.GetGroup(ctx, id)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	response.HandleSCIMSuccess(ctx, http.StatusOK, response.NewSCIMGroupResponse(group, scimBaseURL(ctx)))
+}
+
+// CreateGroup godoc
+//
+//	@Summary		Provision a SCIM group
+//	@Description	Create a custom role without permissions for the group and assign it to the members.
+//	@Description	An admin grants the permissions of the group through the roles.
+//	@Tags			SCIM
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		scimGroupRequest			true	"SCIM group"
+//	@Success		201		{object}	response.SCIMGroupResponse	"Group provisioned"
+//	@Failure		400		{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.SCIMErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Groups [post]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) CreateGroup(ctx *gin.Context) {
+	var req scimGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	group, err := sh.svc.CreateGroup(ctx, req.DisplayName, req.memberIDs())
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	rsp := response.NewSCIMGroupResponse(group, scimBaseURL(ctx))
+	ctx.Header("Location", rsp.Meta.Location)
+
+	response.HandleSCIMSuccess(ctx, http.StatusCreated, rsp)
+}
+
+// ReplaceGroup godoc
+//
+//	@Summary		Replace a SCIM group
+//	@Description	Rename a group and replace its members, the permissions of its role are kept
+//	@Tags			SCIM
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Group ID"
+//	@Param			request	body		scimGroupRequest			true	"SCIM group"
+//	@Success		200		{object}	response.SCIMGroupResponse	"Group replaced"
+//	@Failure		400		{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.SCIMErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Groups/{id} [put]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) ReplaceGroup(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	var req scimGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	sh.replaceGroup(ctx, id, &req)
+}
+
+// PatchGroup godoc
+//
+//	@Summary		Patch a SCIM group
+//	@Description	Apply operations to the displayName and the members of a group,
+//	@Description	e.g. adding members or removing the member of the path members[value eq "<user id>"]
+//	@Tags			SCIM
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Group ID"
+//	@Param			request	body		scimPatchRequest			true	"SCIM patch"
+//	@Success		200		{object}	response.SCIMGroupResponse	"Group patched"
+//	@Failure		400		{object}	response.SCIMErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.SCIMErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Groups/{id} [patch]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) PatchGroup(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	var patch scimPatchRequest
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	group, err := sh.svc.GetGroup(ctx, id)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	// The operations apply to the current group, which then replaces it
+	req := scimGroupRequest{DisplayName: group.Role.Name}
+	for _, memberID := range group.MemberIDs {
+		req.Members = append(req.Members, scimMemberRequest{Value: memberID.String()})
+	}
+	for _, op := range patch.Operations {
+		if err := req.applyPatch(op); err != nil {
+			response.HandleSCIMError(ctx, err)
+			return
+		}
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return
+	}
+
+	sh.replaceGroup(ctx, id, &req)
+}
+
+// DeleteGroup godoc
+//
+//	@Summary		Delete a SCIM group
+//	@Description	Delete the role of a group, its members lose its permissions
+//	@Tags			SCIM
+//	@Param			id	path	string	true	"Group ID"
+//	@Success		204	"Group deleted"
+//	@Failure		401	{object}	response.SCIMErrorResponse	"Unauthorized error"
+//	@Failure		404	{object}	response.SCIMErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.SCIMErrorResponse	"Internal server error"
+//	@Router			/scim/v2/Groups/{id} [delete]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) DeleteGroup(ctx *gin.Context) {
+	id, ok := bindSCIMID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := sh.svc.DeleteGroup(ctx, id); err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetServiceProviderConfig godoc
+//
+//	@Summary		Get the SCIM service provider configuration
+//	@Description	Get the SCIM features supported by the API: patch and filtering, no bulk operations, sorting nor ETags
+//	@Tags			SCIM
+//	@Produce		json
+//	@Success		200	{object}	response.SCIMServiceProviderConfigResponse	"Configuration displayed"
+//	@Failure		401	{object}	response.SCIMErrorResponse					"Unauthorized error"
+//	@Router			/scim/v2/ServiceProviderConfig [get]
+//	@Security		SCIMAuth
+func (sh *SCIMHandler) GetServiceProviderConfig(ctx *gin.Context) {
+	response.HandleSCIMSuccess(ctx, http.StatusOK, response.NewSCIMServiceProviderConfigResponse())
+}
+
+// replaceUser replaces a user with the SCIM user and sends it back
+func (sh *SCIMHandler) replaceUser(ctx *gin.Context, id uuid.UUID, req *scimUserRequest) {
+	user, active := req.toUser()
+	user.ID = id
+
+	updatedUser, err := sh.svc.ReplaceUser(ctx, user, active)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	response.HandleSCIMSuccess(ctx, http.StatusOK, response.NewSCIMUserResponse(updatedUser, scimBaseURL(ctx)))
+}
+
+// replaceGroup replaces a group with the SCIM group and sends it back
+func (sh *SCIMHandler) replaceGroup(ctx *gin.Context, id uuid.UUID, req *scimGroupRequest) {
+	group, err := sh.svc.ReplaceGroup(ctx, id, req.DisplayName, req.memberIDs())
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return
+	}
+
+	response.HandleSCIMSuccess(ctx, http.StatusOK, response.NewSCIMGroupResponse(group, scimBaseURL(ctx)))
+}
+
+// toUser returns the user of a SCIM user with its active state.
+// The name is the formatted name, else the display name, else the given and family names, else the start of the email.
+func (req *scimUserRequest) toUser() (*domain.User, bool) {
+	name := req.Name.Formatted
+	if name == "" {
+		name = req.DisplayName
+	}
+	if name == "" {
+		name = strings.TrimSpace(req.Name.GivenName + " " + req.Name.FamilyName)
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(req.UserName, "@")
+	}
+
+	active := req.Active == nil || *req.Active
+
+	return &domain.User{Name: name, Email: req.UserName}, active
+}
+
+// applyPatch applies an add or replace operation to the SCIM user, the attributes not stored are ignored
+func (req *scimUserRequest) applyPatch(op scimPatchOperation) error {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+	default:
+		return domain.ErrInvalidSCIMPatch
+	}
+
+	// Without a path the value holds the attributes to set
+	if op.Path == "" {
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return domain.ErrInvalidSCIMPatch
+		}
+		for path, value := range attributes {
+			if err := req.setAttribute(path, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return req.setAttribute(op.Path, op.Value)
+}
+
+// setAttribute sets an attribute of the SCIM user
+func (req *scimUserRequest) setAttribute(path string, value json.RawMessage) error {
+	var target any
+	switch strings.ToLower(path) {
+	case "username":
+		target = &req.UserName
+	case "displayname":
+		target = &req.DisplayName
+	case "name":
+		target = &req.Name
+	case "name.formatted":
+		target = &req.Name.Formatted
+	case "name.givenname":
+		target = &req.Name.GivenName
+	case "name.familyname":
+		target = &req.Name.FamilyName
+	case "active":
+		active, err := parseSCIMBool(value)
+		if err != nil {
+			return err
+		}
+		req.Active = &active
+		return nil
+	default:
+		return nil
+	}
+
+	if err := json.Unmarshal(value, target); err != nil {
+		return domain.ErrInvalidSCIMPatch
+	}
+
+	return nil
+}
+
+// memberIDs returns the ids of the members of the SCIM group, validated as UUIDs by the binding
+func (req *scimGroupRequest) memberIDs() []uuid.UUID {
+	memberIDs := make([]uuid.UUID, 0, len(req.Members))
+	for _, member := range req.Members {
+		if memberID, err := uuid.Parse(member.Value); err == nil {
+			memberIDs = append(memberIDs, memberID)
+		}
+	}
+
+	return memberIDs
+}
+
+// applyPatch applies an operation to the display name or the members of the SCIM group, the attributes not stored are ignored
+func (req *scimGroupRequest) applyPatch(op scimPatchOperation) error {
+	opName := strings.ToLower(op.Op)
+	path := strings.ToLower(op.Path)
+
+	switch {
+	case opName != "add" && opName != "replace" && opName != "remove":
+		return domain.ErrInvalidSCIMPatch
+
+	// Without a path the value holds the attributes to set
+	case path == "" && opName != "remove":
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return domain.ErrInvalidSCIMPatch
+		}
+		for attribute, value := range attributes {
+			if err := req.applyPatch(scimPatchOperation{Op: op.Op, Path: attribute, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case path == "displayname" && opName != "remove":
+		if err := json.Unmarshal(op.Value, &req.DisplayName); err != nil {
+			return domain.ErrInvalidSCIMPatch
+		}
+		return nil
+
+	case path == "members":
+		var members []scimMemberRequest
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return domain.ErrInvalidSCIMPatch
+			}
+		}
+
+		switch opName {
+		case "add":
+			req.Members = append(req.Members, members...)
+		case "replace":
+			req.Members = members
+		case "remove":
+			// Without a value all members are removed
+			if len(op.Value) == 0 {
+				req.Members = nil
+			}
+			for _, member := range members {
+				req.removeMember(member.Value)
+			}
+		}
+		return nil
+
+	// The member of a filter, e.g. members[value eq "2819c223-7f76-453a-919d-413861904646"]
+	case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]") && opName == "remove":
+		filter, err := parseSCIMFilter(op.Path[len("members[") : len(op.Path)-1])
+		if err != nil || filter == nil || strings.ToLower(filter.Attribute) != "value" {
+			return domain.ErrInvalidSCIMPatch
+		}
+		req.removeMember(filter.Value)
+		return nil
+
+	case path == "displayname" || path == "" || strings.HasPrefix(path, "members"):
+		return domain.ErrInvalidSCIMPatch
+
+	default:
+		return nil
+	}
+}
+
+// removeMember removes a member from the SCIM group
+func (req *scimGroupRequest) removeMember(memberID string) {
+	req.Members = slices.DeleteFunc(req.Members, func(member scimMemberRequest) bool {
+		return strings.EqualFold(member.Value, memberID)
+	})
+}
+
+// bindSCIMList binds the query of a SCIM list request, the page has 100 resources by default and 200 at most
+func bindSCIMList(ctx *gin.Context) (*domain.SCIMFilter, uint64, uint64, bool) {
+	var req scimListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.SCIMValidationError(ctx, err)
+		return nil, 0, 0, false
+	}
+
+	filter, err := parseSCIMFilter(req.Filter)
+	if err != nil {
+		response.HandleSCIMError(ctx, err)
+		return nil, 0, 0, false
+	}
+
+	startIndex := max(req.StartIndex, 1)
+	count := uint64(100)
+	if req.Count != nil {
+		count = min(*req.Count, response.SCIMMaxResults)
+	}
+
+	return filter, startIndex, count, true
+}
+
+// bindSCIMID binds the id of the path of a SCIM resource, an invalid id is an unknown resource
+func bindSCIMID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.HandleSCIMError(ctx, domain.ErrDataNotFound)
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// parseSCIMFilter parses a SCIM filter comparing an attribute with a string, an empty filter results in nil
+func parseSCIMFilter(filter string) (*domain.SCIMFilter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+
+	matches := scimFilterRegexp.FindStringSubmatch(filter)
+	if matches == nil {
+		return nil, domain.ErrInvalidSCIMFilter
+	}
+
+	value, err := strconv.Unquote(matches[2])
+	if err != nil {
+		return nil, domain.ErrInvalidSCIMFilter
+	}
+
+	return &domain.SCIMFilter{Attribute: matches[1], Value: value}, nil
+}
+
+// parseSCIMBool parses a SCIM boolean, some identity providers send it as a string like "False"
+func parseSCIMBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, domain.ErrInvalidSCIMPatch
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, domain.ErrInvalidSCIMPatch
+	}
+
+	return b, nil
+}
+
+// scimBaseURL returns the base URL of the SCIM API the resources are located under
+func scimBaseURL(ctx *gin.Context) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + ctx.Request.Host + "/scim/v2"
+}
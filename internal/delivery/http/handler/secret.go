@@ -27,13 +27,16 @@ func NewSecretHandler(svc service.SecretService) *SecretHandler {
 
 // createSecretRequest represents the request body for creating a secret
 type createSecretRequest struct {
-	Name        string `json:"name" binding:"required" example:"My Secret"`
-	Description string `json:"description" binding:"required" example:"This is a secret"`
-	URL         string `json:"url,omitempty" example:"https://example.com"`       // Optional for PasswordSecret
-	Login       string `json:"login,omitempty" example:"user@example.com"`        // Optional for PasswordSecret
-	Password    string `json:"password,omitempty" example:"password123"`          // Optional for PasswordSecret
-	Text        string `json:"text,omitempty" example:"This is some secret text"` // Optional for TextSecret
-	SecretType  string `json:"secret_type" binding:"required" example:"password"` // "password" or "text"
+	Name        string   `json:"name" binding:"required" example:"My Secret"`
+	Description string   `json:"description" binding:"required" example:"This is a secret"`
+	URL         string   `json:"url,omitempty" example:"https://example.com"`                        // Optional for PasswordSecret
+	Login       string   `json:"login,omitempty" example:"user@example.com"`                         // Optional for PasswordSecret
+	Password    string   `json:"password,omitempty" example:"password123"`                           // Optional for PasswordSecret
+	Text        string   `json:"text,omitempty" example:"This is some secret text"`                  // Optional for TextSecret
+	SecretType  string   `json:"secret_type" binding:"required" example:"password"`                  // "password" or "text"
+	FolderID    string   `json:"folder_id,omitempty" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"` // Empty for the collection root
+	Tags        []string `json:"tags,omitempty" example:"prod,db"`
+	Reprompt    bool     `json:"reprompt,omitempty" example:"false"` // Require the master password again to read the payload
 }
 
 // CreateSecret godoc
@@ -64,6 +67,12 @@ func (sh *SecretHandler) CreateSecret(ctx *gin.Context) {
 		return
 	}
 
+	folderID, err := helper.ParseOptionalUUID(req.FolderID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
 	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
 
 	var newSecret *domain.Secret
@@ -75,6 +84,9 @@ func (sh *SecretHandler) CreateSecret(ctx *gin.Context) {
 		}
 		newSecret = &domain.Secret{
 			CollectionID: collectionID,
+			FolderID:     folderID,
+			Tags:         req.Tags,
+			Reprompt:     req.Reprompt,
 			SecretType:   domain.PasswordSecretType,
 			Name:         req.Name,
 			Description:  req.Description,
@@ -93,6 +105,9 @@ func (sh *SecretHandler) CreateSecret(ctx *gin.Context) {
 		}
 		newSecret = &domain.Secret{
 			CollectionID: collectionID,
+			FolderID:     folderID,
+			Tags:         req.Tags,
+			Reprompt:     req.Reprompt,
 			SecretType:   domain.TextSecretType,
 			Name:         req.Name,
 			Description:  req.Description,
@@ -126,20 +141,27 @@ func (sh *SecretHandler) CreateSecret(ctx *gin.Context) {
 
 // listMeSecretsRequest represents the request body for listing secrets by user ID
 type listMeSecretsRequest struct {
-	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
-	Limit uint64 `form:"limit" binding:"required,min=5" example:"5"`
+	Skip      uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit     uint64 `form:"limit" binding:"required,min=5" example:"5"`
+	FolderID  string `form:"folder_id" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"`
+	Recursive *bool  `form:"recursive" example:"true"`
+	Tag       string `form:"tag" example:"prod"`
 }
 
 // ListMeSecrets godoc
 //
 //	@Summary		List me secrets
-//	@Description	List me secrets associated with pagination
+//	@Description	List me secrets associated with pagination. Without folder_id the collection root is listed,
+//	@Description	recursive (enabled by default) also includes the secrets of all nested folders.
 //	@Tags			Secrets
 //	@Accept			json
 //	@Produce		json
 //	@Param			collection_id	path		string					true	"Collection ID"
 //	@Param			skip			query		uint64					false "Skip"
 //	@Param			limit			query		uint64					true	"Limit"
+//	@Param			folder_id		query		string					false	"Folder ID"
+//	@Param			recursive		query		bool					false	"Include nested folders"
+//	@Param			tag				query		string					false	"Tag name"
 //	@Success		200				{object}	response.Meta			"Secrets displayed"
 //	@Failure		400				{object}	response.ErrorResponse	"Validation error"
 //	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
@@ -160,9 +182,148 @@ func (sh *SecretHandler) ListMeSecrets(ctx *gin.Context) {
 		return
 	}
 
+	folderID, err := helper.ParseOptionalUUID(req.FolderID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	recursive := req.Recursive == nil || *req.Recursive
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	encryptionKey, err := base64_util.Base64ToBytes(helper.GetEncryptionKey(ctx, middleware.EncryptionKey))
+	if err != nil {
+		response.HandleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	secrets, err := sh.svc.ListSecretsByCollectionID(ctx, authPayload.UserID, collectionID, folderID, recursive, req.Tag, encryptionKey, req.Skip, req.Limit)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, secret := range secrets {
+		secretsList = append(secretsList, response.NewSecretResponse(&secret, false))
+	}
+
+	total := uint64(len(secretsList))
+	meta := response.NewMeta(total, req.Limit, req.Skip)
+	rsp := helper.ToMap(meta, secretsList, "secrets")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// searchSecretsRequest represents the request body for searching secrets
+type searchSecretsRequest struct {
+	Query string `form:"q" binding:"required,max=256" example:"github"`
+	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=5" example:"5"`
+}
+
+// SearchSecrets godoc
+//
+//	@Summary		Search secrets
+//	@Description	Full-text search of secret names, descriptions, URLs and logins across all collections of the user.
+//	@Description	Words are matched by prefix and the best matches are returned first.
+//	@Description	Secrets with encrypted metadata are not indexed, use the lookup endpoint to find them.
+//	@Tags			Secrets
+//	@Accept			json
+//	@Produce		json
+//	@Param			q		query		string					true	"Search text"
+//	@Param			skip	query		uint64					false	"Skip"
+//	@Param			limit	query		uint64					true	"Limit"
+//	@Success		200		{object}	response.Meta			"Secrets found"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/search [get]
+//	@Security		BearerAuth
+func (sh *SecretHandler) SearchSecrets(ctx *gin.Context) {
+	var req searchSecretsRequest
+	var secretsList []response.SecretResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	encryptionKey, err := base64_util.Base64ToBytes(helper.GetEncryptionKey(ctx, middleware.EncryptionKey))
+	if err != nil {
+		response.HandleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	secrets, err := sh.svc.SearchSecrets(ctx, authPayload.UserID, req.Query, encryptionKey, req.Skip, req.Limit)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, secret := range secrets {
+		secretsList = append(secretsList, response.NewSecretResponse(&secret, false))
+	}
+
+	total := uint64(len(secretsList))
+	meta := response.NewMeta(total, req.Limit, req.Skip)
+	rsp := helper.ToMap(meta, secretsList, "secrets")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// lookupSecretsRequest represents the request query for looking up secrets by an exact value
+type lookupSecretsRequest struct {
+	Field string `form:"field" binding:"required,oneof=login url_host" example:"url_host"`
+	Value string `form:"value" binding:"required,max=2048" example:"github.com"`
+	Skip  uint64 `form:"skip" binding:"min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=5" example:"5"`
+}
+
+// LookupSecrets godoc
+//
+//	@Summary		Look up secrets by an exact value
+//	@Description	Find the secrets across all collections of the user whose login or URL host exactly matches the value.
+//	@Description	Logins are compared case-insensitively, a full URL can be given for the url_host field.
+//	@Tags			Secrets
+//	@Accept			json
+//	@Produce		json
+//	@Param			field	query		string					true	"Indexed field"	Enums(login, url_host)
+//	@Param			value	query		string					true	"Value"
+//	@Param			skip	query		uint64					false	"Skip"
+//	@Param			limit	query		uint64					true	"Limit"
+//	@Success		200		{object}	response.Meta			"Secrets found"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/search/lookup [get]
+//	@Security		BearerAuth
+func (sh *SecretHandler) LookupSecrets(ctx *gin.Context) {
+	var req lookupSecretsRequest
+	var secretsList []response.SecretResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	field, err := domain.ParseBlindIndexField(req.Field)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
 	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
 
-	secrets, err := sh.svc.ListSecretsByCollectionID(ctx, authPayload.UserID, collectionID, req.Skip, req.Limit)
+	encryptionKey, err := base64_util.Base64ToBytes(helper.GetEncryptionKey(ctx, middleware.EncryptionKey))
+	if err != nil {
+		response.HandleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	secrets, err := sh.svc.LookupSecrets(ctx, authPayload.UserID, field, req.Value, encryptionKey, req.Skip, req.Limit)
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -189,15 +350,20 @@ type getSecretRequest struct {
 //
 //	@Summary		Get a secret
 //	@Description	Get a secret by id
+//	@Description	A secret flagged for a re-prompt requires the master password in the X-Master-Password header, even while the vault is unlocked.
 //	@Tags			Secrets
 //	@Accept			json
 //	@Produce		json
-//	@Param			collection_id	path		string					true	"Collection ID"
-//	@Param			secret_id		path		string					true	"Secret ID"
-//	@Success		200				{object}	response.SecretResponse	"Secret displayed"
-//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
-//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
-//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Param			collection_id		path		string					true	"Collection ID"
+//	@Param			secret_id			path		string					true	"Secret ID"
+//	@Param			X-Master-Password	header		string					false	"Master password, required by the secrets flagged for a re-prompt"
+//	@Success		200					{object}	response.SecretResponse	"Secret displayed"
+//	@Failure		400					{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401					{object}	response.ErrorResponse	"Wrong master password"
+//	@Failure		403					{object}	response.ErrorResponse	"Master password re-prompt required"
+//	@Failure		404					{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		429					{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500					{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/collections/{collection_id}/secrets/{secret_id} [get]
 //	@Security		BearerAuth
 func (sh *SecretHandler) GetSecret(ctx *gin.Context) {
@@ -227,7 +393,9 @@ func (sh *SecretHandler) GetSecret(ctx *gin.Context) {
 		return
 	}
 
-	secret, err := sh.svc.GetSecret(ctx, authPayload.UserID, collectionID, secretID, encryptionKey)
+	reprompted := ctx.GetBool(middleware.MasterPasswordRepromptedKey)
+
+	secret, err := sh.svc.GetSecret(ctx, authPayload.UserID, collectionID, secretID, encryptionKey, reprompted)
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -239,29 +407,36 @@ func (sh *SecretHandler) GetSecret(ctx *gin.Context) {
 }
 
 type updateSecretRequest struct {
-	Name        string `json:"name" binding:"required" example:"My Secret"`
-	Description string `json:"description" binding:"required" example:"This is a secret"`
-	URL         string `json:"url,omitempty" example:"https://example.com"`       // Optional for PasswordSecret
-	Login       string `json:"login,omitempty" example:"user@example.com"`        // Optional for PasswordSecret
-	Password    string `json:"password,omitempty" example:"password123"`          // Optional for PasswordSecret
-	Text        string `json:"text,omitempty" example:"This is some secret text"` // Optional for TextSecret
-	SecretType  string `json:"secret_type" binding:"required" example:"password"` // "password" or "text"
+	Name        string   `json:"name" binding:"required" example:"My Secret"`
+	Description string   `json:"description" binding:"required" example:"This is a secret"`
+	URL         string   `json:"url,omitempty" example:"https://example.com"`       // Optional for PasswordSecret
+	Login       string   `json:"login,omitempty" example:"user@example.com"`        // Optional for PasswordSecret
+	Password    string   `json:"password,omitempty" example:"password123"`          // Optional for PasswordSecret
+	Text        string   `json:"text,omitempty" example:"This is some secret text"` // Optional for TextSecret
+	SecretType  string   `json:"secret_type" binding:"required" example:"password"` // "password" or "text"
+	Tags        []string `json:"tags,omitempty" example:"prod,db"`                  // Omit to keep the current tags, empty list removes them
+	Reprompt    *bool    `json:"reprompt,omitempty" example:"false"`                // Omit to keep the current flag, clearing it requires the master password again
 }
 
 // UpdateSecret godoc
 //
 //	@Summary		Update a secret
 //	@Description	Update a secret (password or text) by id
+//	@Description	Clearing the re-prompt flag of a secret requires the master password in the X-Master-Password header.
 //	@Tags			Secrets
 //	@Accept			json
 //	@Produce		json
-//	@Param			collection_id	path		string					true	"Collection ID"
-//	@Param			secret_id		path		string					true	"Secret ID"
-//	@Param			request			body		updateSecretRequest		true	"Update Secret Request"
-//	@Success		200				{object}	response.SecretResponse	"Secret updated"
-//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
-//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
-//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Param			collection_id		path		string					true	"Collection ID"
+//	@Param			secret_id			path		string					true	"Secret ID"
+//	@Param			X-Master-Password	header		string					false	"Master password, required to clear the re-prompt flag"
+//	@Param			request				body		updateSecretRequest		true	"Update Secret Request"
+//	@Success		200					{object}	response.SecretResponse	"Secret updated"
+//	@Failure		400					{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401					{object}	response.ErrorResponse	"Wrong master password"
+//	@Failure		403					{object}	response.ErrorResponse	"Master password re-prompt required"
+//	@Failure		404					{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		429					{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500					{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/collections/{collection_id}/secrets/{secret_id} [put]
 //	@Security		BearerAuth
 func (sh *SecretHandler) UpdateSecret(ctx *gin.Context) {
@@ -298,6 +473,7 @@ func (sh *SecretHandler) UpdateSecret(ctx *gin.Context) {
 			Name:         req.Name,
 			Description:  req.Description,
 			UpdatedBy:    authPayload.UserID,
+			Tags:         req.Tags,
 			SecretType:   domain.PasswordSecretType,
 			PasswordSecret: &domain.PasswordSecret{
 				URL:      req.URL,
@@ -316,6 +492,7 @@ func (sh *SecretHandler) UpdateSecret(ctx *gin.Context) {
 			Name:         req.Name,
 			Description:  req.Description,
 			UpdatedBy:    authPayload.UserID,
+			Tags:         req.Tags,
 			SecretType:   domain.TextSecretType,
 			TextSecret: &domain.TextSecret{
 				Text: req.Text,
@@ -332,7 +509,9 @@ func (sh *SecretHandler) UpdateSecret(ctx *gin.Context) {
 		return
 	}
 
-	updatedSecret, err := sh.svc.UpdateSecret(ctx, authPayload.UserID, collectionID, secret, encryptionKey)
+	reprompted := ctx.GetBool(middleware.MasterPasswordRepromptedKey)
+
+	updatedSecret, err := sh.svc.UpdateSecret(ctx, authPayload.UserID, collectionID, secret, req.Reprompt, encryptionKey, reprompted)
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -342,6 +521,154 @@ func (sh *SecretHandler) UpdateSecret(ctx *gin.Context) {
 	response.HandleSuccess(ctx, rsp)
 }
 
+// moveSecretRequest represents the request body for moving or copying a secret
+type moveSecretRequest struct {
+	CollectionID string `json:"collection_id,omitempty" example:"c2a06b0c-3e1b-4a7b-9b8a-6d6f1b6f3c1a"` // Empty for the current collection
+	FolderID     string `json:"folder_id,omitempty" example:"5950a459-5126-40b7-bd8e-82f7b91c2cf1"`     // Empty for the collection root
+}
+
+// MoveSecret godoc
+//
+//	@Summary		Move a secret
+//	@Description	Move a secret to another collection and/or folder, the user must be a member of both collections
+//	@Tags			Secrets
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			secret_id		path		string					true	"Secret ID"
+//	@Param			request			body		moveSecretRequest		true	"Move Secret Request"
+//	@Success		200				{object}	response.SecretResponse	"Secret moved"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/secrets/{secret_id}/move [post]
+//	@Security		BearerAuth
+func (sh *SecretHandler) MoveSecret(ctx *gin.Context) {
+	var req moveSecretRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	secretID, err := uuid.Parse(ctx.Param("secret_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	targetCollectionID, err := helper.ParseOptionalUUID(req.CollectionID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	folderID, err := helper.ParseOptionalUUID(req.FolderID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+	if targetCollectionID != uuid.Nil && !authPayload.CanAccessCollection(targetCollectionID) {
+		response.HandleError(ctx, domain.ErrInsufficientScope)
+		return
+	}
+
+	encryptionKey, err := base64_util.Base64ToBytes(helper.GetEncryptionKey(ctx, middleware.EncryptionKey))
+	if err != nil {
+		response.HandleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	movedSecret, err := sh.svc.MoveSecret(ctx, authPayload.UserID, collectionID, secretID, targetCollectionID, folderID, encryptionKey)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewSecretResponse(movedSecret, false)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// CopySecret godoc
+//
+//	@Summary		Copy a secret
+//	@Description	Copy a secret to another collection and/or folder, the user must be a member of both collections
+//	@Tags			Secrets
+//	@Accept			json
+//	@Produce		json
+//	@Param			collection_id	path		string					true	"Collection ID"
+//	@Param			secret_id		path		string					true	"Secret ID"
+//	@Param			request			body		moveSecretRequest		true	"Copy Secret Request"
+//	@Success		200				{object}	response.SecretResponse	"Secret copied"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/collections/{collection_id}/secrets/{secret_id}/copy [post]
+//	@Security		BearerAuth
+func (sh *SecretHandler) CopySecret(ctx *gin.Context) {
+	var req moveSecretRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	secretID, err := uuid.Parse(ctx.Param("secret_id"))
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	targetCollectionID, err := helper.ParseOptionalUUID(req.CollectionID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	folderID, err := helper.ParseOptionalUUID(req.FolderID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+	if targetCollectionID != uuid.Nil && !authPayload.CanAccessCollection(targetCollectionID) {
+		response.HandleError(ctx, domain.ErrInsufficientScope)
+		return
+	}
+
+	encryptionKey, err := base64_util.Base64ToBytes(helper.GetEncryptionKey(ctx, middleware.EncryptionKey))
+	if err != nil {
+		response.HandleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	copiedSecret, err := sh.svc.CopySecret(ctx, authPayload.UserID, collectionID, secretID, targetCollectionID, folderID, encryptionKey)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewSecretResponse(copiedSecret, false)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
 // deleteSecretRequest represents the request body for deleting a secret
 type deleteSecretRequest struct {
 	CollectionID string `uri:"collection_id" binding:"required"`
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SessionHandler represents the HTTP handler for session-related requests
+type SessionHandler struct {
+	svc service.SessionService
+}
+
+// NewSessionHandler creates a new SessionHandler instance
+func NewSessionHandler(svc service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		svc,
+	}
+}
+
+// ListSessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	List the devices the current user is logged in on, most recently used first.
+//	@Description	The session of the token making the request is marked as current.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Meta			"Sessions displayed"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/sessions [get]
+//	@Security		BearerAuth
+func (sh *SessionHandler) ListSessions(ctx *gin.Context) {
+	var sessionsList []response.SessionResponse
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	sessions, err := sh.svc.ListSessions(ctx, authPayload.UserID, authPayload.FamilyID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, session := range sessions {
+		sessionsList = append(sessionsList, response.NewSessionResponse(&session))
+	}
+
+	total := uint64(len(sessionsList))
+	meta := response.NewMeta(total, total, 0)
+	rsp := helper.ToMap(meta, sessionsList, "sessions")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// sessionRequest represents the request path of a session
+type sessionRequest struct {
+	SessionID string `uri:"session_id" binding:"required,uuid" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Sign out a session
+//	@Description	Sign out a device of the current user, its access and refresh tokens stop working
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			session_id	path		string					true	"Session ID"
+//	@Success		200			{object}	response.Response		"Session signed out"
+//	@Failure		400			{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401			{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404			{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/sessions/{session_id} [delete]
+//	@Security		BearerAuth
+func (sh *SessionHandler) RevokeSession(ctx *gin.Context) {
+	var req sessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	sessionID, err := uuid.Parse(req.SessionID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err = sh.svc.RevokeSession(ctx, authPayload.UserID, sessionID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
+
+// RevokeAllSessions godoc
+//
+//	@Summary		Sign out everywhere
+//	@Description	Sign out all devices of the current user, including the one making the request
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Response		"All sessions signed out"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/sessions [delete]
+//	@Security		BearerAuth
+func (sh *SessionHandler) RevokeAllSessions(ctx *gin.Context) {
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err := sh.svc.RevokeAllSessions(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// StepUpHandler represents the HTTP handler for step-up authentication requests
+type StepUpHandler struct {
+	svc service.StepUpService
+}
+
+// NewStepUpHandler creates a new StepUpHandler instance
+func NewStepUpHandler(svc service.StepUpService) *StepUpHandler {
+	return &StepUpHandler{
+		svc,
+	}
+}
+
+// stepUpRequest represents the request body for confirming the identity of the user
+type stepUpRequest struct {
+	Password string `json:"password" binding:"required_without=Code,excluded_with=Code" example:"12345678"`
+	Code     string `json:"code" binding:"required_without=Password,excluded_with=Password" example:"123456"`
+}
+
+// StepUp godoc
+//
+//	@Summary		Confirm the identity of the user
+//	@Description	Confirm the password or a TOTP or backup code of the current user, either one but not both.
+//	@Description	Sensitive operations, like changing the master password, deleting a collection or managing personal access tokens, require a login or a confirmation in the last minutes.
+//	@Description	They fail with 403 otherwise, the confirmation applies to the current session only.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		stepUpRequest			true	"Step-up request"
+//	@Success		200		{object}	response.StepUpResponse	"Identity confirmed"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error or TOTP not enabled"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error, wrong password or code"
+//	@Failure		429		{object}	response.ErrorResponse	"Too many failed attempts, retry after the Retry-After header"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/step-up [post]
+//	@Security		BearerAuth
+func (sh *StepUpHandler) StepUp(ctx *gin.Context) {
+	var req stepUpRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	expiresAt, err := sh.svc.StepUp(ctx, authPayload.UserID, authPayload.FamilyID, req.Password, req.Code, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, response.NewStepUpResponse(expiresAt))
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TagHandler represents the HTTP handler for tag-related requests
+type TagHandler struct {
+	svc service.TagService
+}
+
+// NewTagHandler creates a new TagHandler instance
+func NewTagHandler(svc service.TagService) *TagHandler {
+	return &TagHandler{
+		svc,
+	}
+}
+
+// tagRequest represents the request body for creating or renaming a tag
+type tagRequest struct {
+	Name string `json:"name" binding:"required" example:"prod"`
+}
+
+// CreateTag godoc
+//
+//	@Summary		Create a new tag
+//	@Description	Create a new tag, tag names are trimmed and lowercased
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		tagRequest				true	"Create Tag Request"
+//	@Success		200		{object}	response.TagResponse	"Tag created"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		409		{object}	response.ErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags [post]
+//	@Security		BearerAuth
+func (th *TagHandler) CreateTag(ctx *gin.Context) {
+	var req tagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	tag, err := th.svc.CreateTag(ctx, authPayload.UserID, req.Name)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewTagResponse(tag)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// ListTags godoc
+//
+//	@Summary		List tags
+//	@Description	List the tags of the current user
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Meta			"Tags displayed"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags [get]
+//	@Security		BearerAuth
+func (th *TagHandler) ListTags(ctx *gin.Context) {
+	var tagsList []response.TagResponse
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	tags, err := th.svc.ListTags(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, tag := range tags {
+		tagsList = append(tagsList, response.NewTagResponse(&tag))
+	}
+
+	total := uint64(len(tagsList))
+	meta := response.NewMeta(total, total, 0)
+	rsp := helper.ToMap(meta, tagsList, "tags")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// GetTagCloud godoc
+//
+//	@Summary		Get the tag cloud
+//	@Description	List the tags of the current user used by secrets with the number of secrets for each tag
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Meta			"Tag cloud displayed"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags/cloud [get]
+//	@Security		BearerAuth
+func (th *TagHandler) GetTagCloud(ctx *gin.Context) {
+	var tagCountsList []response.TagCountResponse
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	tagCounts, err := th.svc.GetTagCloud(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, tagCount := range tagCounts {
+		tagCountsList = append(tagCountsList, response.NewTagCountResponse(&tagCount))
+	}
+
+	total := uint64(len(tagCountsList))
+	meta := response.NewMeta(total, total, 0)
+	rsp := helper.ToMap(meta, tagCountsList, "tags")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// tagIDRequest represents the uri of a tag
+type tagIDRequest struct {
+	TagID string `uri:"tag_id" binding:"required,uuid"`
+}
+
+// UpdateTag godoc
+//
+//	@Summary		Rename a tag
+//	@Description	Rename a tag of the current user, the new name applies to all tagged secrets
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			tag_id	path		string					true	"Tag ID"
+//	@Param			request	body		tagRequest				true	"Update Tag Request"
+//	@Success		200		{object}	response.TagResponse	"Tag updated"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		409		{object}	response.ErrorResponse	"Data conflict error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags/{tag_id} [put]
+//	@Security		BearerAuth
+func (th *TagHandler) UpdateTag(ctx *gin.Context) {
+	var uri tagIDRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	var req tagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	tagID, err := uuid.Parse(uri.TagID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	tag, err := th.svc.UpdateTag(ctx, authPayload.UserID, tagID, req.Name)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewTagResponse(tag)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// DeleteTag godoc
+//
+//	@Summary		Delete a tag
+//	@Description	Delete a tag of the current user and remove it from all secrets
+//	@Tags			Tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			tag_id	path		string					true	"Tag ID"
+//	@Success		200		{object}	response.Response		"Tag deleted"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404		{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags/{tag_id} [delete]
+//	@Security		BearerAuth
+func (th *TagHandler) DeleteTag(ctx *gin.Context) {
+	var uri tagIDRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	tagID, err := uuid.Parse(uri.TagID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err = th.svc.DeleteTag(ctx, authPayload.UserID, tagID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
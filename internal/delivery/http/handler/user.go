@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"context"
+
 	"github.com/8thgencore/passfort/internal/delivery/http/helper"
 	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
 	"github.com/8thgencore/passfort/internal/delivery/http/response"
@@ -149,6 +151,7 @@ type updateUserRequest struct {
 //
 //	@Summary		Update a user
 //	@Description	Update a user's name, email, password, or role by id
+//	@Description	Changing the built-in role grants or revokes permissions, it requires the roles:write permission
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -176,6 +179,12 @@ func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	// An admin of the users cannot grant more permissions than an admin of the roles
+	if req.Role != "" && !helper.HasPermission(ctx, middleware.PermissionsKey, domain.RolesWritePermission) {
+		response.HandleError(ctx, domain.ErrForbidden)
+		return
+	}
+
 	user := domain.User{
 		ID:    uuid,
 		Name:  req.Name,
@@ -202,7 +211,8 @@ type deleteUserRequest struct {
 // DeleteUser godoc
 //
 //	@Summary		Delete a user
-//	@Description	Delete a user by id
+//	@Description	Delete a user by id, the collections owned by the user are transferred, deleted or block the deletion depending on the configuration.
+//	@Description	With the delete policy the collections shared with other members block the deletion.
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -212,6 +222,7 @@ type deleteUserRequest struct {
 //	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
 //	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
 //	@Failure		404	{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		409	{object}	response.ErrorResponse	"User owns collections"
 //	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/users/{id} [delete]
 //	@Security		BearerAuth
@@ -241,7 +252,7 @@ func (uh *UserHandler) DeleteUser(ctx *gin.Context) {
 	}
 
 	// Call the service to delete the user
-	err = uh.svc.DeleteUser(ctx, uuid)
+	err = uh.svc.DeleteUser(ctx, authPayload.UserID, uuid)
 	if err != nil {
 		response.HandleError(ctx, err)
 		return
@@ -249,3 +260,211 @@ func (uh *UserHandler) DeleteUser(ctx *gin.Context) {
 
 	response.HandleSuccess(ctx, nil)
 }
+
+// UnlockUser godoc
+//
+//	@Summary		Unlock a user
+//	@Description	End the lockouts of a user after too many failed attempts to log in, activate the master password or enter an OTP
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string					true	"User ID"
+//	@Success		200	{object}	response.Response		"User unlocked"
+//	@Failure		400	{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/unlock [post]
+//	@Security		BearerAuth
+func (uh *UserHandler) UnlockUser(ctx *gin.Context) {
+	var req getUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	err = uh.svc.UnlockUser(ctx, userID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
+
+// GetUserStatus godoc
+//
+//	@Summary		Get the status of a user
+//	@Description	Get the verification, the suspension, the actions forced at the next login and the lockouts of a user
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string						true	"User ID"
+//	@Success		200	{object}	response.UserStatusResponse	"User status displayed"
+//	@Failure		400	{object}	response.ErrorResponse		"Validation error"
+//	@Failure		401	{object}	response.ErrorResponse		"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse		"Forbidden error"
+//	@Failure		404	{object}	response.ErrorResponse		"Data not found error"
+//	@Failure		500	{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/users/{id}/status [get]
+//	@Security		BearerAuth
+func (uh *UserHandler) GetUserStatus(ctx *gin.Context) {
+	var req getUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	status, err := uh.svc.GetUserStatus(ctx, userID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewUserStatusResponse(status)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// SuspendUser godoc
+//
+//	@Summary		Suspend a user
+//	@Description	Suspend a user, who cannot log in until unsuspended.
+//	@Description	All the sessions of the user are revoked and their personal access tokens are refused while suspended.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string					true	"User ID"
+//	@Success		200	{object}	response.UserResponse	"User suspended"
+//	@Failure		400	{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/suspend [post]
+//	@Security		BearerAuth
+func (uh *UserHandler) SuspendUser(ctx *gin.Context) {
+	var req getUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	user, err := uh.svc.SuspendUser(ctx, authPayload.UserID, userID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewUserResponse(user)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// UnsuspendUser godoc
+//
+//	@Summary		Unsuspend a user
+//	@Description	Let a suspended user log in again
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string					true	"User ID"
+//	@Success		200	{object}	response.UserResponse	"User unsuspended"
+//	@Failure		400	{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/unsuspend [post]
+//	@Security		BearerAuth
+func (uh *UserHandler) UnsuspendUser(ctx *gin.Context) {
+	uh.updateUserStatus(ctx, uh.svc.UnsuspendUser)
+}
+
+// ForcePasswordReset godoc
+//
+//	@Summary		Force a password reset
+//	@Description	Make a user change their password at their next login.
+//	@Description	Until then the user can only change their password, enroll a second factor, log out and get their own information.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string					true	"User ID"
+//	@Success		200	{object}	response.UserResponse	"Password reset forced"
+//	@Failure		400	{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/force-password-reset [post]
+//	@Security		BearerAuth
+func (uh *UserHandler) ForcePasswordReset(ctx *gin.Context) {
+	uh.updateUserStatus(ctx, uh.svc.ForcePasswordReset)
+}
+
+// ForceMFAEnrollment godoc
+//
+//	@Summary		Force a two-factor authentication enrollment
+//	@Description	Make a user enable TOTP or register a passkey at their next login.
+//	@Description	Until then the user can only enroll a second factor, change their password, log out and get their own information.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string					true	"User ID"
+//	@Success		200	{object}	response.UserResponse	"Enrollment forced"
+//	@Failure		400	{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		403	{object}	response.ErrorResponse	"Forbidden error"
+//	@Failure		404	{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/users/{id}/force-mfa-enrollment [post]
+//	@Security		BearerAuth
+func (uh *UserHandler) ForceMFAEnrollment(ctx *gin.Context) {
+	uh.updateUserStatus(ctx, uh.svc.ForceMFAEnrollment)
+}
+
+// updateUserStatus applies a change of the status of the user of the request path and responds with the user
+func (uh *UserHandler) updateUserStatus(ctx *gin.Context, update func(ctx context.Context, id uuid.UUID) (*domain.User, error)) {
+	var req getUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	user, err := update(ctx, userID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewUserResponse(user)
+
+	response.HandleSuccess(ctx, rsp)
+}
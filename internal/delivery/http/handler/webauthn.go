@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BeginWebAuthnRegistration godoc
+//
+//	@Summary		Start a passkey registration
+//	@Description	Get the options for navigator.credentials.create() to register a new passkey or security key.
+//	@Description	The registration must be finished within 5 minutes.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Response		"WebAuthn credential creation options"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/webauthn/register/begin [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) BeginWebAuthnRegistration(ctx *gin.Context) {
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	creation, err := ah.svc.BeginWebAuthnRegistration(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, creation)
+}
+
+// finishWebAuthnRegistrationRequest represents the query parameters for finishing a passkey registration
+type finishWebAuthnRegistrationRequest struct {
+	Name string `form:"name" binding:"required,max=64" example:"MacBook Touch ID"`
+}
+
+// FinishWebAuthnRegistration godoc
+//
+//	@Summary		Finish a passkey registration
+//	@Description	Verify the credential returned by navigator.credentials.create() and store the new passkey.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	query		string								true	"Passkey name"
+//	@Param			request	body		object								true	"PublicKeyCredential returned by the authenticator"
+//	@Success		200		{object}	response.WebAuthnCredentialResponse	"Passkey registered"
+//	@Failure		400		{object}	response.ErrorResponse				"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse				"Unauthorized error"
+//	@Failure		409		{object}	response.ErrorResponse				"Data conflict error"
+//	@Failure		500		{object}	response.ErrorResponse				"Internal server error"
+//	@Router			/auth/webauthn/register/finish [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) FinishWebAuthnRegistration(ctx *gin.Context) {
+	var req finishWebAuthnRegistrationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	credential, err := ah.svc.FinishWebAuthnRegistration(ctx, authPayload.UserID, req.Name, body)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewWebAuthnCredentialResponse(credential)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// ListWebAuthnCredentials godoc
+//
+//	@Summary		List passkeys
+//	@Description	List the passkeys and security keys of the current user
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Meta			"Passkeys displayed"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/webauthn/credentials [get]
+//	@Security		BearerAuth
+func (ah *AuthHandler) ListWebAuthnCredentials(ctx *gin.Context) {
+	var credentialsList []response.WebAuthnCredentialResponse
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	credentials, err := ah.svc.ListWebAuthnCredentials(ctx, authPayload.UserID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	for _, credential := range credentials {
+		credentialsList = append(credentialsList, response.NewWebAuthnCredentialResponse(&credential))
+	}
+
+	total := uint64(len(credentialsList))
+	meta := response.NewMeta(total, total, 0)
+	rsp := helper.ToMap(meta, credentialsList, "credentials")
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// webAuthnCredentialRequest represents the request path of a passkey
+type webAuthnCredentialRequest struct {
+	CredentialID string `uri:"credential_id" binding:"required,uuid" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// DeleteWebAuthnCredential godoc
+//
+//	@Summary		Delete a passkey
+//	@Description	Delete a passkey of the current user, it can no longer be used to log in
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			credential_id	path		string					true	"Passkey ID"
+//	@Success		200				{object}	response.Response		"Passkey deleted"
+//	@Failure		400				{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		404				{object}	response.ErrorResponse	"Data not found error"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/webauthn/credentials/{credential_id} [delete]
+//	@Security		BearerAuth
+func (ah *AuthHandler) DeleteWebAuthnCredential(ctx *gin.Context) {
+	var req webAuthnCredentialRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	credentialID, err := uuid.Parse(req.CredentialID)
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	authPayload := helper.GetAuthPayload(ctx, middleware.AuthorizationPayloadKey)
+
+	err = ah.svc.DeleteWebAuthnCredential(ctx, authPayload.UserID, credentialID)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, nil)
+}
+
+// BeginWebAuthnLogin godoc
+//
+//	@Summary		Start a passwordless login
+//	@Description	Get the options for navigator.credentials.get() to log in with a passkey, no email is needed.
+//	@Description	The login must be finished within 5 minutes.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.Response		"WebAuthn credential request options"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/webauthn/login/begin [post]
+func (ah *AuthHandler) BeginWebAuthnLogin(ctx *gin.Context) {
+	assertion, err := ah.svc.BeginWebAuthnLogin(ctx)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, assertion)
+}
+
+// FinishWebAuthnLogin godoc
+//
+//	@Summary		Finish a passwordless login
+//	@Description	Verify the assertion returned by navigator.credentials.get() and log in the owner of the passkey.
+//	@Description	A passkey with user verification counts as two factors, so no MFA challenge follows.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		object					true	"PublicKeyCredential returned by the authenticator"
+//	@Param			X-Device-Name	header		string					false	"Device name shown in the sessions, derived from the user agent if empty"
+//	@Success		200		{object}	response.AuthResponse	"Succesfully logged in"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/webauthn/login/finish [post]
+func (ah *AuthHandler) FinishWebAuthnLogin(ctx *gin.Context) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	accessToken, refreshToken, err := ah.svc.FinishWebAuthnLogin(ctx, body, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewAuthResponse(accessToken, refreshToken)
+
+	response.HandleSuccess(ctx, rsp)
+}
+
+// beginWebAuthnMFARequest represents the request body for starting a passkey second factor
+type beginWebAuthnMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"Zm9vYmFyYmF6cXV4Zm9vYmFyYmF6cXV4Zm9vYmFyYmE"`
+}
+
+// BeginWebAuthnMFA godoc
+//
+//	@Summary		Start a passkey second factor
+//	@Description	Get the options for navigator.credentials.get() to complete a login with a passkey of the user.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		beginWebAuthnMFARequest	true	"WebAuthn MFA request body"
+//	@Success		200		{object}	response.Response		"WebAuthn credential request options"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/webauthn/mfa/begin [post]
+func (ah *AuthHandler) BeginWebAuthnMFA(ctx *gin.Context) {
+	var req beginWebAuthnMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	assertion, err := ah.svc.BeginWebAuthnMFA(ctx, req.MFAToken)
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	response.HandleSuccess(ctx, assertion)
+}
+
+// FinishWebAuthnMFA godoc
+//
+//	@Summary		Finish a passkey second factor
+//	@Description	Verify the assertion returned by navigator.credentials.get() and complete the pending login.
+//	@Tags			Authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		object					true	"PublicKeyCredential returned by the authenticator"
+//	@Param			X-Device-Name	header		string					false	"Device name shown in the sessions, derived from the user agent if empty"
+//	@Success		200		{object}	response.AuthResponse	"Succesfully logged in"
+//	@Failure		400		{object}	response.ErrorResponse	"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse	"Unauthorized error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/auth/webauthn/mfa/finish [post]
+func (ah *AuthHandler) FinishWebAuthnMFA(ctx *gin.Context) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		response.ValidationError(ctx, err)
+		return
+	}
+
+	accessToken, refreshToken, err := ah.svc.FinishWebAuthnMFA(ctx, body, helper.GetClientInfo(ctx))
+	if err != nil {
+		response.HandleError(ctx, err)
+		return
+	}
+
+	rsp := response.NewAuthResponse(accessToken, refreshToken)
+
+	response.HandleSuccess(ctx, rsp)
+}
@@ -1,11 +1,14 @@
 package helper
 
 import (
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/8thgencore/passfort/internal/delivery/http/response"
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // GetAuthPayload is a helper function to get the auth payload from the context
@@ -13,6 +16,29 @@ func GetAuthPayload(ctx *gin.Context, key string) *domain.UserClaims {
 	return ctx.MustGet(key).(*domain.UserClaims)
 }
 
+// HasPermission is a helper function to check if the user has a permission, from the permissions in the context
+func HasPermission(ctx *gin.Context, key string, permission domain.Permission) bool {
+	permissions, ok := ctx.Value(key).([]domain.Permission)
+	return ok && slices.Contains(permissions, permission)
+}
+
+// DeviceNameHeaderKey is the header a client can name its device with when logging in
+const DeviceNameHeaderKey = "X-Device-Name"
+
+// GetClientInfo is a helper function to get the client of the request
+func GetClientInfo(ctx *gin.Context) *domain.ClientInfo {
+	deviceName := []rune(strings.TrimSpace(ctx.GetHeader(DeviceNameHeaderKey)))
+	if len(deviceName) > domain.MaxDeviceNameLength {
+		deviceName = deviceName[:domain.MaxDeviceNameLength]
+	}
+
+	return &domain.ClientInfo{
+		DeviceName: string(deviceName),
+		UserAgent:  ctx.Request.UserAgent(),
+		IPAddress:  ctx.ClientIP(),
+	}
+}
+
 // GetEncryptionKey is a helper function to get the encryption key from the context
 func GetEncryptionKey(ctx *gin.Context, key string) string {
 	return ctx.MustGet(key).(string)
@@ -25,6 +51,15 @@ func StringToUint64(str string) (uint64, error) {
 	return num, err
 }
 
+// ParseOptionalUUID is a helper function to parse an optional UUID, an empty string results in uuid.Nil
+func ParseOptionalUUID(str string) (uuid.UUID, error) {
+	if str == "" {
+		return uuid.Nil, nil
+	}
+
+	return uuid.Parse(str)
+}
+
 // ToMap is a helper function to add meta and data to a map
 func ToMap(m response.Meta, data any, key string) map[string]any {
 	return map[string]any{
@@ -28,3 +28,8 @@ var SecretTypeValidator validator.Func = func(fl validator.FieldLevel) bool {
 		return false
 	}
 }
+
+// PermissionValidator is a custom validator function for Permission
+var PermissionValidator validator.Func = func(fl validator.FieldLevel) bool {
+	return fl.Field().Interface().(domain.Permission).IsValid()
+}
@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/8thgencore/passfort/internal/delivery/http/response"
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/service"
+	"github.com/8thgencore/passfort/pkg/base64_util"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const (
@@ -18,8 +21,46 @@ const (
 	AuthorizationPayloadKey = "authorization_payload"
 )
 
-// AuthMiddleware is a middleware to check if the user is authenticated
-func AuthMiddleware(tokenService service.TokenService) gin.HandlerFunc {
+// AuthMiddleware is a middleware to check if the user is authenticated.
+// Personal access tokens are authenticated as well, but only routes of a resource accept them.
+// Suspended users and users with actions forced by an admin are refused.
+func AuthMiddleware(
+	tokenService service.TokenService,
+	accessTokenService service.PersonalAccessTokenService,
+	userService service.UserService,
+) gin.HandlerFunc {
+	return authMiddleware(tokenService, accessTokenService, userService, "", false)
+}
+
+// PendingActionsAuthMiddleware is a middleware to check if the user is authenticated
+// on the routes letting a user complete the actions forced by an admin, e.g. changing their password
+func PendingActionsAuthMiddleware(
+	tokenService service.TokenService,
+	accessTokenService service.PersonalAccessTokenService,
+	userService service.UserService,
+) gin.HandlerFunc {
+	return authMiddleware(tokenService, accessTokenService, userService, "", true)
+}
+
+// ResourceAuthMiddleware is a middleware to check if the user is authenticated for a resource.
+// Personal access tokens need the read scope of the resource for safe methods and the write scope otherwise,
+// and a token restricted to some collections can only access them.
+func ResourceAuthMiddleware(
+	tokenService service.TokenService,
+	accessTokenService service.PersonalAccessTokenService,
+	userService service.UserService,
+	resource domain.TokenResource,
+) gin.HandlerFunc {
+	return authMiddleware(tokenService, accessTokenService, userService, resource, false)
+}
+
+func authMiddleware(
+	tokenService service.TokenService,
+	accessTokenService service.PersonalAccessTokenService,
+	userService service.UserService,
+	resource domain.TokenResource,
+	allowPendingActions bool,
+) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(AuthorizationHeaderKey)
 
@@ -45,13 +86,24 @@ func AuthMiddleware(tokenService service.TokenService) gin.HandlerFunc {
 			return
 		}
 
+		if strings.HasPrefix(fields[1], domain.PersonalAccessTokenPrefix) {
+			authenticatePersonalAccessToken(ctx, accessTokenService, userService, fields[1], resource)
+			return
+		}
+
 		payload, err := tokenService.ParseUserClaims(fields[1])
 		if err != nil {
 			response.HandleAbort(ctx, err)
 			return
 		}
 
-		exists, err := tokenService.CheckJWTTokenRevoked(ctx, payload.ID)
+		// A refresh token cannot be used as an access token
+		if payload.Type != domain.AccessToken {
+			response.HandleAbort(ctx, domain.ErrInvalidToken)
+			return
+		}
+
+		exists, err := tokenService.CheckJWTTokenRevoked(ctx, payload)
 		if err != nil {
 			response.HandleAbort(ctx, domain.ErrInvalidToken)
 			return
@@ -61,7 +113,87 @@ func AuthMiddleware(tokenService service.TokenService) gin.HandlerFunc {
 			return
 		}
 
+		if err := checkUserStatus(ctx, userService, payload.UserID, allowPendingActions); err != nil {
+			response.HandleAbort(ctx, err)
+			return
+		}
+
 		ctx.Set(AuthorizationPayloadKey, payload)
 		ctx.Next()
 	}
 }
+
+// authenticatePersonalAccessToken authenticates a request made with a personal access token.
+// The token unlocks the vault by itself, so the encryption key is set without a master password activation.
+func authenticatePersonalAccessToken(
+	ctx *gin.Context,
+	accessTokenService service.PersonalAccessTokenService,
+	userService service.UserService,
+	rawToken string,
+	resource domain.TokenResource,
+) {
+	token, encryptionKey, err := accessTokenService.Authenticate(ctx, rawToken)
+	if err != nil {
+		response.HandleAbort(ctx, err)
+		return
+	}
+
+	// The actions forced by an admin are completed by the user, not with a token
+	if err := checkUserStatus(ctx, userService, token.UserID, false); err != nil {
+		response.HandleAbort(ctx, err)
+		return
+	}
+
+	payload := &domain.UserClaims{
+		ID:                  token.ID,
+		UserID:              token.UserID,
+		Role:                domain.UserRole,
+		Type:                domain.AccessToken,
+		PersonalAccessToken: token,
+	}
+
+	if resource == "" || !token.HasScope(resource.Scope(!isSafeMethod(ctx.Request.Method))) {
+		response.HandleAbort(ctx, domain.ErrInsufficientScope)
+		return
+	}
+
+	// A token restricted to some collections cannot use routes outside of a collection, e.g. listing all collections
+	if len(token.CollectionIDs) > 0 {
+		collectionID, err := uuid.Parse(ctx.Param("collection_id"))
+		if err != nil || !token.AllowsCollection(collectionID) {
+			response.HandleAbort(ctx, domain.ErrInsufficientScope)
+			return
+		}
+	}
+
+	ctx.Set(AuthorizationPayloadKey, payload)
+	ctx.Set(EncryptionKey, base64_util.BytesToBase64(encryptionKey))
+	ctx.Next()
+}
+
+// checkUserStatus refuses the requests of a suspended user, and of a user with actions forced by an admin
+// unless the route lets them complete these actions. The user is cached, so the check is cheap.
+func checkUserStatus(ctx *gin.Context, userService service.UserService, userID uuid.UUID, allowPendingActions bool) error {
+	user, err := userService.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrUnauthorized
+		}
+		return err
+	}
+
+	if user.IsSuspended() {
+		return domain.ErrUserSuspended
+	}
+
+	if allowPendingActions {
+		return nil
+	}
+
+	return user.PendingActionError()
+}
+
+// isSafeMethod checks if the HTTP method only reads data
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
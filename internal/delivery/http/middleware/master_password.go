@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
 	"github.com/8thgencore/passfort/internal/delivery/http/response"
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/service"
@@ -11,6 +12,10 @@ import (
 const (
 	// EncryptionKey is the key for encryption and decryption secrets
 	EncryptionKey = "encryption_key"
+	// MasterPasswordHeaderKey is the header carrying the master password again for the secrets flagged for a re-prompt
+	MasterPasswordHeaderKey = "X-Master-Password"
+	// MasterPasswordRepromptedKey is the key for the master password re-prompt state of the request in the context
+	MasterPasswordRepromptedKey = "master_password_reprompted"
 )
 
 // MasterPasswordMiddleware is a middleware to check if the master password is activated recently
@@ -28,6 +33,12 @@ func MasterPasswordMiddleware(masterPasswordService service.MasterPasswordServic
 			return
 		}
 
+		// A personal access token carries the encryption key itself
+		if payload.PersonalAccessToken != nil {
+			ctx.Next()
+			return
+		}
+
 		encryptionKey, err := masterPasswordService.GetEncryptionKey(ctx, payload.UserID)
 		if err != nil {
 			response.HandleAbort(ctx, err)
@@ -43,3 +54,27 @@ func MasterPasswordMiddleware(masterPasswordService service.MasterPasswordServic
 		ctx.Next()
 	}
 }
+
+// MasterPasswordRepromptMiddleware is a middleware to check the master password sent again with the request,
+// so the secrets flagged for a re-prompt can be read even though the vault is already unlocked.
+// A request without the master password is not reprompted, a wrong master password is refused.
+func MasterPasswordRepromptMiddleware(masterPasswordService service.MasterPasswordService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		password := ctx.GetHeader(MasterPasswordHeaderKey)
+		if password == "" {
+			ctx.Set(MasterPasswordRepromptedKey, false)
+			ctx.Next()
+			return
+		}
+
+		payload := helper.GetAuthPayload(ctx, AuthorizationPayloadKey)
+
+		if err := masterPasswordService.VerifyMasterPassword(ctx, payload.UserID, password, helper.GetClientInfo(ctx)); err != nil {
+			response.HandleAbort(ctx, err)
+			return
+		}
+
+		ctx.Set(MasterPasswordRepromptedKey, true)
+		ctx.Next()
+	}
+}
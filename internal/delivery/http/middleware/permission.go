@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/8thgencore/passfort/internal/delivery/http/helper"
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionsKey is the key for the permissions of the user in the context
+const PermissionsKey = "permissions"
+
+// RequirePermission is a middleware to check if the user has all the given permissions.
+// The permissions are resolved on each request, not read from the token,
+// so role changes apply without a new login.
+func RequirePermission(roleService service.RoleService, permissions ...domain.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := helper.GetAuthPayload(ctx, AuthorizationPayloadKey)
+
+		userPermissions, err := roleService.GetUserPermissions(ctx, payload.UserID)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				err = domain.ErrUnauthorized
+			}
+			response.HandleAbort(ctx, err)
+			return
+		}
+
+		for _, permission := range permissions {
+			if !slices.Contains(userPermissions, permission) {
+				response.HandleAbort(ctx, domain.ErrForbidden)
+				return
+			}
+		}
+
+		ctx.Set(PermissionsKey, userPermissions)
+		ctx.Next()
+	}
+}
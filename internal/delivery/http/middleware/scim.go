@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMAuthMiddleware is a middleware to check the provisioning token of the identity provider calling the SCIM API.
+// The hashes of the tokens are compared in constant time, so the comparison does not leak the token length nor its prefix.
+func SCIMAuthMiddleware(token string) gin.HandlerFunc {
+	tokenHash := sha256.Sum256([]byte(token))
+
+	return func(ctx *gin.Context) {
+		fields := strings.Fields(ctx.GetHeader(AuthorizationHeaderKey))
+		if len(fields) != 2 || strings.ToLower(fields[0]) != AuthorizationType {
+			response.HandleSCIMAbort(ctx, domain.ErrInvalidAuthorizationHeader)
+			return
+		}
+
+		requestHash := sha256.Sum256([]byte(fields[1]))
+		if token == "" || subtle.ConstantTimeCompare(tokenHash[:], requestHash[:]) != 1 {
+			response.HandleSCIMAbort(ctx, domain.ErrUnauthorized)
+			return
+		}
+
+		ctx.Next()
+	}
+}
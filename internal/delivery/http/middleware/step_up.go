@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/8thgencore/passfort/internal/delivery/http/response"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// StepUpMiddleware is a middleware to check if the user authenticated recently, for sensitive operations.
+// The user confirms the password or a second factor at /auth/step-up when the login is too old.
+func StepUpMiddleware(stepUpService service.StepUpService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload, exists := ctx.Get(AuthorizationPayloadKey)
+		if !exists {
+			response.HandleAbort(ctx, domain.ErrUnauthorized)
+			return
+		}
+
+		payload, ok := authPayload.(*domain.UserClaims)
+		if !ok {
+			response.HandleAbort(ctx, domain.ErrUnauthorized)
+			return
+		}
+
+		if err := stepUpService.CheckRecentAuthentication(ctx, payload); err != nil {
+			response.HandleAbort(ctx, err)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/gin-gonic/gin"
@@ -28,12 +29,37 @@ var errorStatusMap = map[error]int{
 	domain.ErrExpiredToken:        http.StatusUnauthorized,
 	domain.ErrInvalidToken:        http.StatusUnauthorized,
 	domain.ErrInvalidRefreshToken: http.StatusUnauthorized,
+	domain.ErrRefreshTokenReused:  http.StatusUnauthorized,
 
 	// Authentication Errors
-	domain.ErrInvalidCredentials:  http.StatusUnauthorized,
-	domain.ErrPasswordsDoNotMatch: http.StatusBadRequest,
-	domain.ErrInvalidOTP:          http.StatusUnauthorized,
-	domain.ErrOTPAlreadySent:      http.StatusTooManyRequests,
+	domain.ErrInvalidCredentials:         http.StatusUnauthorized,
+	domain.ErrPasswordsDoNotMatch:        http.StatusBadRequest,
+	domain.ErrInvalidOTP:                 http.StatusUnauthorized,
+	domain.ErrOTPAlreadySent:             http.StatusTooManyRequests,
+	domain.ErrOTPAttemptsExceeded:        http.StatusUnauthorized,
+	domain.ErrInvalidMFACode:             http.StatusUnauthorized,
+	domain.ErrInvalidMFAToken:            http.StatusUnauthorized,
+	domain.ErrMFAAlreadyEnabled:          http.StatusConflict,
+	domain.ErrMFANotEnabled:              http.StatusBadRequest,
+	domain.ErrTOTPNotEnrolled:            http.StatusBadRequest,
+	domain.ErrInvalidWebAuthnResponse:    http.StatusBadRequest,
+	domain.ErrInvalidPasskey:             http.StatusUnauthorized,
+	domain.ErrOIDCNotConfigured:          http.StatusNotFound,
+	domain.ErrInvalidOIDCState:           http.StatusUnauthorized,
+	domain.ErrOIDCLoginFailed:            http.StatusUnauthorized,
+	domain.ErrOIDCEmailNotVerified:       http.StatusForbidden,
+	domain.ErrOIDCSignupDisabled:         http.StatusForbidden,
+	domain.ErrInvalidUserCode:            http.StatusBadRequest,
+	domain.ErrDeviceAuthorizationPending: http.StatusBadRequest,
+	domain.ErrDeviceSlowDown:             http.StatusBadRequest,
+	domain.ErrDeviceAccessDenied:         http.StatusBadRequest,
+	domain.ErrExpiredDeviceCode:          http.StatusBadRequest,
+	domain.ErrUnsupportedGrantType:       http.StatusBadRequest,
+	domain.ErrTooManyAttempts:            http.StatusTooManyRequests,
+	domain.ErrAccountLocked:              http.StatusTooManyRequests,
+	domain.ErrRegistrationClosed:         http.StatusForbidden,
+	domain.ErrInvalidInvite:              http.StatusForbidden,
+	domain.ErrWeakPassword:               http.StatusUnprocessableEntity,
 
 	// Authorization Errors
 	domain.ErrEmptyAuthorizationHeader:   http.StatusUnauthorized,
@@ -41,19 +67,73 @@ var errorStatusMap = map[error]int{
 	domain.ErrInvalidAuthorizationType:   http.StatusUnauthorized,
 	domain.ErrUnauthorized:               http.StatusUnauthorized,
 	domain.ErrForbidden:                  http.StatusForbidden,
+	domain.ErrInsufficientScope:          http.StatusForbidden,
+	domain.ErrStepUpRequired:             http.StatusForbidden,
 
 	// User Errors
-	domain.ErrUserNotVerified:  http.StatusUnauthorized,
-	domain.ErrDeleteOwnAccount: http.StatusForbidden,
+	domain.ErrUserNotVerified:           http.StatusUnauthorized,
+	domain.ErrDeleteOwnAccount:          http.StatusForbidden,
+	domain.ErrUserOwnsCollections:       http.StatusConflict,
+	domain.ErrUserOwnsSharedCollections: http.StatusConflict,
+	domain.ErrUserSuspended:             http.StatusForbidden,
+	domain.ErrSuspendOwnAccount:         http.StatusForbidden,
+	domain.ErrPasswordResetRequired:     http.StatusForbidden,
+	domain.ErrMFAEnrollmentRequired:     http.StatusForbidden,
 
 	// Master Password Errors
 	domain.ErrMasterPasswordActivationExpired: http.StatusUnauthorized,
 	domain.ErrMasterPasswordNotSet:            http.StatusUnauthorized,
 	domain.ErrInvalidMasterPassword:           http.StatusUnauthorized,
 	domain.ErrMasterPasswordAlreadyExists:     http.StatusConflict,
+	domain.ErrMasterPasswordRepromptRequired:  http.StatusForbidden,
 
 	// Secrets
-	domain.ErrInvalidSecretType: http.StatusBadRequest,
+	domain.ErrInvalidSecretType:      http.StatusBadRequest,
+	domain.ErrInvalidBlindIndexField: http.StatusBadRequest,
+
+	// Folders
+	domain.ErrFolderCycle: http.StatusConflict,
+
+	// Tags
+	domain.ErrInvalidTagName: http.StatusBadRequest,
+
+	// SCIM
+	domain.ErrInvalidSCIMFilter: http.StatusBadRequest,
+	domain.ErrInvalidSCIMPatch:  http.StatusBadRequest,
+}
+
+// oauthErrorCodes is a map of the errors of the device authorization grant and their error codes (RFC 8628)
+var oauthErrorCodes = map[error]string{
+	domain.ErrDeviceAuthorizationPending: "authorization_pending",
+	domain.ErrDeviceSlowDown:             "slow_down",
+	domain.ErrDeviceAccessDenied:         "access_denied",
+	domain.ErrExpiredDeviceCode:          "expired_token",
+	domain.ErrUnsupportedGrantType:       "unsupported_grant_type",
+}
+
+// OAuthErrorResponse represents an error response body of the OAuth 2.0 endpoints (RFC 6749), used by standard clients
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"authorization_pending"`
+	ErrorDescription string `json:"error_description,omitempty" example:"device authorization is pending"`
+}
+
+// HandleOAuthError sends an error response of the OAuth 2.0 endpoints with the error code of the error,
+// "invalid_request" for the errors without their own code
+func HandleOAuthError(ctx *gin.Context, err error) {
+	code, ok := oauthErrorCodes[err]
+	if !ok {
+		code = "invalid_request"
+	}
+
+	statusCode := http.StatusBadRequest
+	if errorStatusMap[err] == http.StatusInternalServerError {
+		statusCode, code = http.StatusInternalServerError, "server_error"
+	}
+
+	ctx.JSON(statusCode, OAuthErrorResponse{
+		Error:            code,
+		ErrorDescription: err.Error(),
+	})
 }
 
 // ValidationError sends an error response for some specific request validation error
@@ -65,26 +145,42 @@ func ValidationError(ctx *gin.Context, err error) {
 
 // HandleError determines the status code of an error and returns a JSON response with the error message and status code
 func HandleError(ctx *gin.Context, err error) {
-	statusCode, ok := errorStatusMap[err]
-	if !ok {
-		statusCode = http.StatusInternalServerError
-	}
-
-	errMsg := ParseError(err)
-	errRsp := NewErrorResponse(errMsg)
+	statusCode, errRsp := newErrorResponse(ctx, err)
 	ctx.JSON(statusCode, errRsp)
 }
 
 // HandleAbort sends an error response and aborts the request with the specified status code and error message
 func HandleAbort(ctx *gin.Context, err error) {
+	statusCode, errRsp := newErrorResponse(ctx, err)
+	ctx.AbortWithStatusJSON(statusCode, errRsp)
+}
+
+// newErrorResponse determines the status code and the response body of an error.
+// The delay of an error refusing requests for some time is also sent in the Retry-After header,
+// and the broken rules of a password policy error are sent as the messages.
+func newErrorResponse(ctx *gin.Context, err error) (int, ErrorResponse) {
+	errRsp := NewErrorResponse(ParseError(err))
+
+	var retryErr *domain.RetryAfterError
+	if errors.As(err, &retryErr) {
+		errRsp.RetryAfter = retryErr.Seconds()
+		ctx.Header("Retry-After", strconv.Itoa(errRsp.RetryAfter))
+		err = retryErr.Err
+	}
+
+	// Each broken rule of the password policy is reported
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		errRsp.Messages = policyErr.Violations
+		err = domain.ErrWeakPassword
+	}
+
 	statusCode, ok := errorStatusMap[err]
 	if !ok {
 		statusCode = http.StatusInternalServerError
 	}
 
-	errMsg := ParseError(err)
-	errRsp := NewErrorResponse(errMsg)
-	ctx.AbortWithStatusJSON(statusCode, errRsp)
+	return statusCode, errRsp
 }
 
 // ParseError parses error messages from the error object and returns a slice of error messages
@@ -104,8 +200,9 @@ func ParseError(err error) []string {
 
 // ErrorResponse represents an error response body format
 type ErrorResponse struct {
-	Success  bool     `json:"success" example:"false"`
-	Messages []string `json:"messages" example:"Error message 1, Error message 2"`
+	Success    bool     `json:"success" example:"false"`
+	Messages   []string `json:"messages" example:"Error message 1, Error message 2"`
+	RetryAfter int      `json:"retry_after,omitempty" example:"30"` // seconds to wait before retrying, if the request was refused for some time
 }
 
 // NewErrorResponse is a helper function to create an error response body
@@ -0,0 +1,250 @@
+package response
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// SCIM schemas of the resources and the messages (RFC 7643, RFC 7644)
+const (
+	SCIMUserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMGroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMPatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// SCIMMaxResults is the largest page of a SCIM list response
+const SCIMMaxResults = 200
+
+// scimContentType is the media type of the SCIM messages
+const scimContentType = "application/scim+json"
+
+// SCIMMeta represents the metadata of a SCIM resource
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType" example:"User"`
+	Created      time.Time `json:"created" example:"1970-01-01T00:00:00Z"`
+	LastModified time.Time `json:"lastModified" example:"1970-01-01T00:00:00Z"`
+	Location     string    `json:"location" example:"https://api.example.com/scim/v2/Users/bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// SCIMName represents the name of a SCIM user
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty" example:"John Doe"`
+	GivenName  string `json:"givenName,omitempty" example:"John"`
+	FamilyName string `json:"familyName,omitempty" example:"Doe"`
+}
+
+// SCIMEmail represents an email of a SCIM user
+type SCIMEmail struct {
+	Value   string `json:"value" example:"john@example.com"`
+	Type    string `json:"type,omitempty" example:"work"`
+	Primary bool   `json:"primary,omitempty" example:"true"`
+}
+
+// SCIMUserResponse represents a SCIM user, its userName is the email of the user
+type SCIMUserResponse struct {
+	Schemas     []string    `json:"schemas" example:"urn:ietf:params:scim:schemas:core:2.0:User"`
+	ID          string      `json:"id" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+	UserName    string      `json:"userName" example:"john@example.com"`
+	Name        SCIMName    `json:"name"`
+	DisplayName string      `json:"displayName" example:"John Doe"`
+	Emails      []SCIMEmail `json:"emails"`
+	Active      bool        `json:"active" example:"true"`
+	Meta        SCIMMeta    `json:"meta"`
+}
+
+// NewSCIMUserResponse is a helper function to create a SCIM user from a user, located under the SCIM base URL
+func NewSCIMUserResponse(user *domain.User, baseURL string) SCIMUserResponse {
+	return SCIMUserResponse{
+		Schemas:     []string{SCIMUserSchema},
+		ID:          user.ID.String(),
+		UserName:    user.Email,
+		Name:        SCIMName{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails:      []SCIMEmail{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      !user.IsSuspended(),
+		Meta: SCIMMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     baseURL + "/Users/" + user.ID.String(),
+		},
+	}
+}
+
+// SCIMMemberResponse represents a member of a SCIM group
+type SCIMMemberResponse struct {
+	Value string `json:"value" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+	Ref   string `json:"$ref" example:"https://api.example.com/scim/v2/Users/bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+}
+
+// SCIMGroupResponse represents a SCIM group, a custom role with the users it is assigned to
+type SCIMGroupResponse struct {
+	Schemas     []string             `json:"schemas" example:"urn:ietf:params:scim:schemas:core:2.0:Group"`
+	ID          string               `json:"id" example:"bb073c91-f09b-4858-b2d1-d14116e73b8d"`
+	DisplayName string               `json:"displayName" example:"support"`
+	Members     []SCIMMemberResponse `json:"members"`
+	Meta        SCIMMeta             `json:"meta"`
+}
+
+// NewSCIMGroupResponse is a helper function to create a SCIM group from a group, located under the SCIM base URL
+func NewSCIMGroupResponse(group *domain.SCIMGroup, baseURL string) SCIMGroupResponse {
+	members := make([]SCIMMemberResponse, 0, len(group.MemberIDs))
+	for _, memberID := range group.MemberIDs {
+		members = append(members, SCIMMemberResponse{
+			Value: memberID.String(),
+			Ref:   baseURL + "/Users/" + memberID.String(),
+		})
+	}
+
+	return SCIMGroupResponse{
+		Schemas:     []string{SCIMGroupSchema},
+		ID:          group.Role.ID.String(),
+		DisplayName: group.Role.Name,
+		Members:     members,
+		Meta: SCIMMeta{
+			ResourceType: "Group",
+			Created:      group.Role.CreatedAt,
+			LastModified: group.Role.UpdatedAt,
+			Location:     baseURL + "/Groups/" + group.Role.ID.String(),
+		},
+	}
+}
+
+// SCIMListResponse represents a page of SCIM resources
+type SCIMListResponse struct {
+	Schemas      []string `json:"schemas" example:"urn:ietf:params:scim:api:messages:2.0:ListResponse"`
+	TotalResults uint64   `json:"totalResults" example:"1"`
+	StartIndex   uint64   `json:"startIndex" example:"1"`
+	ItemsPerPage int      `json:"itemsPerPage" example:"1"`
+	Resources    any      `json:"Resources"`
+}
+
+// NewSCIMListResponse is a helper function to create a page of SCIM resources
+func NewSCIMListResponse[T any](resources []T, totalResults, startIndex uint64) SCIMListResponse {
+	return SCIMListResponse{
+		Schemas:      []string{SCIMListResponseSchema},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// SCIMSupported represents a feature of the service provider configuration
+type SCIMSupported struct {
+	Supported bool `json:"supported" example:"true"`
+}
+
+// SCIMFilterSupported represents the filtering of the service provider configuration
+type SCIMFilterSupported struct {
+	Supported  bool `json:"supported" example:"true"`
+	MaxResults int  `json:"maxResults" example:"200"`
+}
+
+// SCIMBulkSupported represents the bulk operations of the service provider configuration
+type SCIMBulkSupported struct {
+	Supported      bool `json:"supported" example:"false"`
+	MaxOperations  int  `json:"maxOperations" example:"0"`
+	MaxPayloadSize int  `json:"maxPayloadSize" example:"0"`
+}
+
+// SCIMAuthenticationScheme represents an authentication scheme of the service provider configuration
+type SCIMAuthenticationScheme struct {
+	Type        string `json:"type" example:"oauthbearertoken"`
+	Name        string `json:"name" example:"OAuth Bearer Token"`
+	Description string `json:"description" example:"Authentication with the provisioning token"`
+}
+
+// SCIMServiceProviderConfigResponse represents the SCIM features supported by the API
+type SCIMServiceProviderConfigResponse struct {
+	Schemas               []string                   `json:"schemas" example:"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"`
+	Patch                 SCIMSupported              `json:"patch"`
+	Bulk                  SCIMBulkSupported          `json:"bulk"`
+	Filter                SCIMFilterSupported        `json:"filter"`
+	ChangePassword        SCIMSupported              `json:"changePassword"`
+	Sort                  SCIMSupported              `json:"sort"`
+	ETag                  SCIMSupported              `json:"etag"`
+	AuthenticationSchemes []SCIMAuthenticationScheme `json:"authenticationSchemes"`
+}
+
+// NewSCIMServiceProviderConfigResponse is a helper function to create the SCIM service provider configuration
+func NewSCIMServiceProviderConfigResponse() SCIMServiceProviderConfigResponse {
+	return SCIMServiceProviderConfigResponse{
+		Schemas: []string{SCIMServiceProviderConfigSchema},
+		Patch:   SCIMSupported{Supported: true},
+		Filter:  SCIMFilterSupported{Supported: true, MaxResults: SCIMMaxResults},
+		AuthenticationSchemes: []SCIMAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with the provisioning token of the identity provider",
+		}},
+	}
+}
+
+// scimErrorTypes is a map of the errors of the SCIM API and their SCIM error types (RFC 7644)
+var scimErrorTypes = map[error]string{
+	domain.ErrConflictingData:   "uniqueness",
+	domain.ErrInvalidSCIMFilter: "invalidFilter",
+	domain.ErrInvalidSCIMPatch:  "invalidValue",
+}
+
+// SCIMErrorResponse represents an error response body of the SCIM API
+type SCIMErrorResponse struct {
+	Schemas  []string `json:"schemas" example:"urn:ietf:params:scim:api:messages:2.0:Error"`
+	Status   string   `json:"status" example:"409"`
+	ScimType string   `json:"scimType,omitempty" example:"uniqueness"`
+	Detail   string   `json:"detail" example:"data conflicts with existing data in unique column"`
+}
+
+// HandleSCIMSuccess sends a SCIM response with the specified status code
+func HandleSCIMSuccess(ctx *gin.Context, statusCode int, data any) {
+	ctx.Header("Content-Type", scimContentType)
+	ctx.JSON(statusCode, data)
+}
+
+// HandleSCIMError sends a SCIM error response with the status code of the error
+func HandleSCIMError(ctx *gin.Context, err error) {
+	statusCode, errRsp := newSCIMErrorResponse(err)
+	ctx.Header("Content-Type", scimContentType)
+	ctx.JSON(statusCode, errRsp)
+}
+
+// HandleSCIMAbort sends a SCIM error response and aborts the request
+func HandleSCIMAbort(ctx *gin.Context, err error) {
+	statusCode, errRsp := newSCIMErrorResponse(err)
+	ctx.Header("Content-Type", scimContentType)
+	ctx.AbortWithStatusJSON(statusCode, errRsp)
+}
+
+// SCIMValidationError sends a SCIM error response for a request validation error
+func SCIMValidationError(ctx *gin.Context, err error) {
+	ctx.Header("Content-Type", scimContentType)
+	ctx.JSON(http.StatusBadRequest, SCIMErrorResponse{
+		Schemas:  []string{SCIMErrorSchema},
+		Status:   strconv.Itoa(http.StatusBadRequest),
+		ScimType: "invalidValue",
+		Detail:   ParseError(err)[0],
+	})
+}
+
+// newSCIMErrorResponse determines the status code and the SCIM response body of an error
+func newSCIMErrorResponse(err error) (int, SCIMErrorResponse) {
+	statusCode, ok := errorStatusMap[err]
+	if !ok {
+		statusCode = http.StatusInternalServerError
+	}
+
+	return statusCode, SCIMErrorResponse{
+		Schemas:  []string{SCIMErrorSchema},
+		Status:   strconv.Itoa(statusCode),
+		ScimType: scimErrorTypes[err],
+		Detail:   err.Error(),
+	}
+}
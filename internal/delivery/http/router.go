@@ -8,6 +8,7 @@ import (
 	"github.com/8thgencore/passfort/internal/delivery/http/handler"
 	"github.com/8thgencore/passfort/internal/delivery/http/helper"
 	"github.com/8thgencore/passfort/internal/delivery/http/middleware"
+	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/service"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -28,12 +29,27 @@ func NewRouter(
 	log *slog.Logger,
 	cfg *config.Config,
 	tokenService service.TokenService,
+	accessTokenService service.PersonalAccessTokenService,
 	masterPasswordService service.MasterPasswordService,
+	stepUpService service.StepUpService,
+	userService service.UserService,
+	roleService service.RoleService,
 	userHander handler.UserHandler,
 	authHandler handler.AuthHandler,
+	mfaHandler handler.MFAHandler,
+	sessionHandler handler.SessionHandler,
+	accessTokenHandler handler.PersonalAccessTokenHandler,
 	collectionHandler handler.CollectionHandler,
+	folderHandler handler.FolderHandler,
+	tagHandler handler.TagHandler,
 	secretHandler handler.SecretHandler,
 	masterPasswordHandler handler.MasterPasswordHandler,
+	jwksHandler handler.JWKSHandler,
+	inviteHandler handler.InviteHandler,
+	deviceHandler handler.DeviceHandler,
+	stepUpHandler handler.StepUpHandler,
+	roleHandler handler.RoleHandler,
+	scimHandler handler.SCIMHandler,
 ) (*Router, error) {
 	// Disable debug mode in production
 	if cfg.Env == config.Prod {
@@ -46,8 +62,9 @@ func NewRouter(
 		AllowOriginFunc: func(origin string) bool {
 			return true
 		},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", helper.DeviceNameHeaderKey, middleware.MasterPasswordHeaderKey},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		ExposeHeaders:    []string{"Retry-After"},
 		AllowCredentials: true,
 	}
 
@@ -63,15 +80,28 @@ func NewRouter(
 		if err := v.RegisterValidation("secret_type", helper.SecretTypeValidator); err != nil {
 			return nil, err
 		}
+		if err := v.RegisterValidation("permission", helper.PermissionValidator); err != nil {
+			return nil, err
+		}
 	}
 
 	// Swagger
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Token verification keys
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	// Middleware
-	authMiddleware := middleware.AuthMiddleware(tokenService)
-	adminMiddleware := middleware.AdminMiddleware()
+	authMiddleware := middleware.AuthMiddleware(tokenService, accessTokenService, userService)
+	pendingActionsAuthMiddleware := middleware.PendingActionsAuthMiddleware(tokenService, accessTokenService, userService)
+	collectionsAuthMiddleware := middleware.ResourceAuthMiddleware(tokenService, accessTokenService, userService, domain.CollectionsResource)
+	secretsAuthMiddleware := middleware.ResourceAuthMiddleware(tokenService, accessTokenService, userService, domain.SecretsResource)
+	requirePermission := func(permissions ...domain.Permission) gin.HandlerFunc {
+		return middleware.RequirePermission(roleService, permissions...)
+	}
 	masterPasswordMiddleware := middleware.MasterPasswordMiddleware(masterPasswordService)
+	stepUpMiddleware := middleware.StepUpMiddleware(stepUpService)
+	masterPasswordRepromptMiddleware := middleware.MasterPasswordRepromptMiddleware(masterPasswordService)
 
 	// Endpoints
 	api := router.Group("/api")
@@ -82,17 +112,58 @@ func NewRouter(
 			auth := v1.Group("/auth")
 			{
 				auth.POST("/login", authHandler.Login)
+				auth.POST("/login/mfa", authHandler.LoginMFA)
 				auth.POST("/register", authHandler.Register)
 				auth.POST("/register/confirm", authHandler.ConfirmRegistration)
 				auth.POST("/register/resend-otp", authHandler.ResendOTPCode)
 				auth.POST("/forgot-password", authHandler.ForgotPassword)
 				auth.POST("/reset-password", authHandler.ResetPassword)
 				auth.POST("/refresh-token", authHandler.RefreshToken)
+				auth.POST("/webauthn/login/begin", authHandler.BeginWebAuthnLogin)
+				auth.POST("/webauthn/login/finish", authHandler.FinishWebAuthnLogin)
+				auth.POST("/webauthn/mfa/begin", authHandler.BeginWebAuthnMFA)
+				auth.POST("/webauthn/mfa/finish", authHandler.FinishWebAuthnMFA)
+				auth.POST("/oidc/login/begin", authHandler.BeginOIDCLogin)
+				auth.POST("/oidc/login/finish", authHandler.FinishOIDCLogin)
+				auth.POST("/device/code", deviceHandler.RequestDeviceCode)
+				auth.POST("/device/token", deviceHandler.RequestDeviceToken)
+
+				// A user with actions forced by an admin can only complete them
+				authPending := auth.Group("", pendingActionsAuthMiddleware)
+				{
+					authPending.POST("/logout", authHandler.Logout)
+					authPending.PUT("/change-password", authHandler.ChangePassword)
+
+					authPending.GET("/mfa", mfaHandler.GetMFAStatus)
+					authPending.POST("/mfa/totp", mfaHandler.EnrollTOTP)
+					authPending.POST("/mfa/totp/confirm", mfaHandler.ConfirmTOTP)
+
+					authPending.POST("/webauthn/register/begin", authHandler.BeginWebAuthnRegistration)
+					authPending.POST("/webauthn/register/finish", authHandler.FinishWebAuthnRegistration)
+				}
 
 				authUser := auth.Use(authMiddleware)
 				{
-					authUser.POST("/logout", authHandler.Logout)
-					authUser.PUT("/change-password", authHandler.ChangePassword)
+					authUser.POST("/step-up", stepUpHandler.StepUp)
+					authUser.POST("/change-email", authHandler.ChangeEmail)
+					authUser.POST("/change-email/confirm", authHandler.ConfirmEmailChange)
+
+					authUser.GET("/device", deviceHandler.GetDevice)
+					authUser.POST("/device/approve", stepUpMiddleware, deviceHandler.ApproveDevice)
+					authUser.POST("/device/deny", deviceHandler.DenyDevice)
+
+					authUser.POST("/mfa/totp/disable", mfaHandler.DisableTOTP)
+
+					authUser.GET("/webauthn/credentials", authHandler.ListWebAuthnCredentials)
+					authUser.DELETE("/webauthn/credentials/:credential_id", authHandler.DeleteWebAuthnCredential)
+
+					authUser.GET("/sessions", sessionHandler.ListSessions)
+					authUser.DELETE("/sessions", sessionHandler.RevokeAllSessions)
+					authUser.DELETE("/sessions/:session_id", sessionHandler.RevokeSession)
+
+					authUser.POST("/tokens", stepUpMiddleware, masterPasswordMiddleware, accessTokenHandler.CreatePersonalAccessToken)
+					authUser.GET("/tokens", accessTokenHandler.ListPersonalAccessTokens)
+					authUser.DELETE("/tokens/:token_id", stepUpMiddleware, accessTokenHandler.DeletePersonalAccessToken)
 				}
 			}
 
@@ -100,52 +171,137 @@ func NewRouter(
 			masterPassword := v1.Group("/master-password").Use(authMiddleware)
 			{
 				masterPassword.POST("", masterPasswordHandler.CreateMasterPassword)
-				masterPassword.PUT("", masterPasswordHandler.ChangeMasterPassword)
+				masterPassword.PUT("", stepUpMiddleware, masterPasswordHandler.ChangeMasterPassword)
 				masterPassword.POST("/activate", masterPasswordHandler.ActivateMasterPassword)
 			}
 
 			// User Routes
 			usersGroup := v1.Group("/users")
 			{
-				users := usersGroup.Use(authMiddleware)
+				// The user shows the actions forced by an admin
+				usersGroup.GET("/me", pendingActionsAuthMiddleware, userHander.GetUserMe)
+
+				users := usersGroup.Group("", authMiddleware)
 				{
-					users.GET("/me", userHander.GetUserMe)
+					users.GET("/me/permissions", roleHandler.GetMePermissions)
 					users.GET("/:id", userHander.GetUser)
 				}
 
-				admin := users.Use(adminMiddleware)
+				usersRead := users.Group("", requirePermission(domain.UsersReadPermission))
 				{
-					admin.GET("", userHander.ListUsers)
-					admin.PUT("/:id", userHander.UpdateUser)
-					admin.DELETE("/:id", userHander.DeleteUser)
+					usersRead.GET("", userHander.ListUsers)
+					usersRead.GET("/:id/status", userHander.GetUserStatus)
 				}
+
+				usersWrite := users.Group("", requirePermission(domain.UsersWritePermission))
+				{
+					usersWrite.PUT("/:id", userHander.UpdateUser)
+					usersWrite.DELETE("/:id", userHander.DeleteUser)
+					usersWrite.POST("/:id/unlock", userHander.UnlockUser)
+					usersWrite.POST("/:id/suspend", userHander.SuspendUser)
+					usersWrite.POST("/:id/unsuspend", userHander.UnsuspendUser)
+					usersWrite.POST("/:id/force-password-reset", userHander.ForcePasswordReset)
+					usersWrite.POST("/:id/force-mfa-enrollment", userHander.ForceMFAEnrollment)
+				}
+
+				users.GET("/:id/roles", requirePermission(domain.RolesReadPermission), roleHandler.ListUserRoles)
+				users.PUT("/:id/roles", requirePermission(domain.RolesWritePermission), roleHandler.SetUserRoles)
+			}
+
+			// Role Routes
+			roles := v1.Group("/roles").Use(authMiddleware)
+			{
+				roles.GET("", requirePermission(domain.RolesReadPermission), roleHandler.ListRoles)
+				roles.GET("/permissions", requirePermission(domain.RolesReadPermission), roleHandler.ListPermissions)
+				roles.POST("", requirePermission(domain.RolesWritePermission), roleHandler.CreateRole)
+				roles.PUT("/:role_id", requirePermission(domain.RolesWritePermission), roleHandler.UpdateRole)
+				roles.DELETE("/:role_id", requirePermission(domain.RolesWritePermission), roleHandler.DeleteRole)
+			}
+
+			// Invite Routes
+			invites := v1.Group("/invites").Use(authMiddleware)
+			{
+				invites.POST("", requirePermission(domain.InvitesWritePermission), inviteHandler.CreateInvite)
+				invites.GET("", requirePermission(domain.InvitesReadPermission), inviteHandler.ListInvites)
+				invites.DELETE("/:invite_id", requirePermission(domain.InvitesWritePermission), inviteHandler.DeleteInvite)
+			}
+
+			// Search Routes
+			search := v1.Group("/search").Use(authMiddleware).Use(masterPasswordMiddleware)
+			{
+				search.GET("", secretHandler.SearchSecrets)
+				search.GET("/lookup", secretHandler.LookupSecrets)
+			}
+
+			// Tag Routes
+			tags := v1.Group("/tags").Use(authMiddleware)
+			{
+				tags.GET("", tagHandler.ListTags)
+				tags.POST("", tagHandler.CreateTag)
+				tags.GET("/cloud", tagHandler.GetTagCloud)
+				tags.PUT("/:tag_id", tagHandler.UpdateTag)
+				tags.DELETE("/:tag_id", tagHandler.DeleteTag)
 			}
 
 			// Collection Routes
 			collectionsGroup := v1.Group("/collections")
 			{
-				collections := collectionsGroup.Use(authMiddleware).Use(masterPasswordMiddleware)
+				collections := collectionsGroup.Group("", collectionsAuthMiddleware, masterPasswordMiddleware)
 				{
 					collections.GET("/me", collectionHandler.ListMeCollections)
 					collections.POST("", collectionHandler.CreateCollection)
 					collections.GET("/:collection_id", collectionHandler.GetCollection)
 					collections.PUT("/:collection_id", collectionHandler.UpdateCollection)
-					collections.DELETE("/:collection_id", collectionHandler.DeleteCollection)
+					collections.DELETE("/:collection_id", stepUpMiddleware, collectionHandler.DeleteCollection)
+					collections.POST("/:collection_id/transfer", collectionHandler.TransferCollection)
+				}
+
+				// Nest the /folders routes under /collections/:id
+				folders := collectionsGroup.Group("/:collection_id/folders", collectionsAuthMiddleware, masterPasswordMiddleware)
+				{
+					folders.GET("", folderHandler.ListFolders)
+					folders.POST("", folderHandler.CreateFolder)
+					folders.GET("/:folder_id", folderHandler.GetFolder)
+					folders.PUT("/:folder_id", folderHandler.UpdateFolder)
+					folders.POST("/:folder_id/move", folderHandler.MoveFolder)
+					folders.DELETE("/:folder_id", folderHandler.DeleteFolder)
 				}
 
 				// Nest the /secrets routes under /collections/:id
-				secrets := collectionsGroup.Group("/:collection_id/secrets").Use(authMiddleware).Use(masterPasswordMiddleware)
+				secrets := collectionsGroup.Group("/:collection_id/secrets", secretsAuthMiddleware, masterPasswordMiddleware)
 				{
 					secrets.GET("", secretHandler.ListMeSecrets)
 					secrets.POST("", secretHandler.CreateSecret)
-					secrets.GET("/:secret_id", secretHandler.GetSecret)
-					secrets.PUT("/:secret_id", secretHandler.UpdateSecret)
+					secrets.GET("/:secret_id", masterPasswordRepromptMiddleware, secretHandler.GetSecret)
+					secrets.PUT("/:secret_id", masterPasswordRepromptMiddleware, secretHandler.UpdateSecret)
+					secrets.POST("/:secret_id/move", secretHandler.MoveSecret)
+					secrets.POST("/:secret_id/copy", secretHandler.CopySecret)
 					secrets.DELETE("/:secret_id", secretHandler.DeleteSecret)
 				}
 			}
 		}
 	}
 
+	// The SCIM API of the identity provider is served at its standard location, outside of the versioned API
+	if cfg.SCIM.Enabled {
+		scim := router.Group("/scim/v2").Use(middleware.SCIMAuthMiddleware(cfg.SCIM.Token))
+		{
+			scim.GET("/ServiceProviderConfig", scimHandler.GetServiceProviderConfig)
+			scim.GET("/Users", scimHandler.ListUsers)
+			scim.POST("/Users", scimHandler.CreateUser)
+			scim.GET("/Users/:id", scimHandler.GetUser)
+			scim.PUT("/Users/:id", scimHandler.ReplaceUser)
+			scim.PATCH("/Users/:id", scimHandler.PatchUser)
+			scim.DELETE("/Users/:id", scimHandler.DeleteUser)
+			scim.GET("/Groups", scimHandler.ListGroups)
+			scim.POST("/Groups", scimHandler.CreateGroup)
+			scim.GET("/Groups/:id", scimHandler.GetGroup)
+			scim.PUT("/Groups/:id", scimHandler.ReplaceGroup)
+			scim.PATCH("/Groups/:id", scimHandler.PatchGroup)
+			scim.DELETE("/Groups/:id", scimHandler.DeleteGroup)
+		}
+	}
+
 	return &Router{
 		router,
 	}, nil
@@ -0,0 +1,174 @@
+package http_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/8thgencore/passfort/internal/config"
+	router "github.com/8thgencore/passfort/internal/delivery/http"
+	"github.com/8thgencore/passfort/internal/delivery/http/handler"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rawAccessToken  = domain.PersonalAccessTokenPrefix + "token"
+	rawSessionToken = "session-token"
+)
+
+// tokenService parses the access token of a single session
+type tokenService struct {
+	service.TokenService
+	claims *domain.UserClaims
+}
+
+func (s *tokenService) ParseUserClaims(accessToken string) (*domain.UserClaims, error) {
+	if accessToken != rawSessionToken {
+		return nil, domain.ErrInvalidToken
+	}
+	return s.claims, nil
+}
+
+func (s *tokenService) CheckJWTTokenRevoked(context.Context, *domain.UserClaims) (bool, error) {
+	return false, nil
+}
+
+// stepUpService refuses the sessions without a recent authentication
+type stepUpService struct {
+	service.StepUpService
+}
+
+func (s *stepUpService) CheckRecentAuthentication(context.Context, *domain.UserClaims) error {
+	return domain.ErrStepUpRequired
+}
+
+// accessTokenService authenticates a single personal access token
+type accessTokenService struct {
+	service.PersonalAccessTokenService
+	token *domain.PersonalAccessToken
+}
+
+func (s *accessTokenService) Authenticate(_ context.Context, rawToken string) (*domain.PersonalAccessToken, []byte, error) {
+	if rawToken != rawAccessToken {
+		return nil, nil, domain.ErrInvalidToken
+	}
+	return s.token, make([]byte, 32), nil
+}
+
+// userService returns an active user
+type userService struct {
+	service.UserService
+}
+
+func (s *userService) GetUserByID(_ context.Context, id uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: id}, nil
+}
+
+// secretService lists no secrets
+type secretService struct {
+	service.SecretService
+}
+
+func (s *secretService) ListSecretsByCollectionID(
+	context.Context, uuid.UUID, uuid.UUID, uuid.UUID, bool, string, []byte, uint64, uint64,
+) ([]domain.Secret, error) {
+	return nil, nil
+}
+
+// newRouter creates the router with the services authenticating the requests,
+// the handlers only serve the routes listing secrets
+func newRouter(t *testing.T, tokens service.TokenService, accessTokens service.PersonalAccessTokenService) *router.Router {
+	cfg := &config.Config{Env: config.Prod}
+	cfg.HTTP.AllowOrigins = "http://localhost"
+
+	r, err := router.NewRouter(
+		slog.Default(), cfg, tokens, accessTokens, nil, &stepUpService{}, &userService{}, nil,
+		handler.UserHandler{}, handler.AuthHandler{}, handler.MFAHandler{}, handler.SessionHandler{},
+		handler.PersonalAccessTokenHandler{}, handler.CollectionHandler{}, handler.FolderHandler{},
+		handler.TagHandler{}, *handler.NewSecretHandler(&secretService{}), handler.MasterPasswordHandler{},
+		handler.JWKSHandler{}, handler.InviteHandler{}, handler.DeviceHandler{}, handler.StepUpHandler{},
+		handler.RoleHandler{}, handler.SCIMHandler{},
+	)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestPersonalAccessTokenScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []domain.TokenScope
+		method string
+		path   string
+		status int
+	}{
+		{
+			name:   "Token with the secrets read scope lists secrets",
+			scopes: []domain.TokenScope{domain.ScopeSecretsRead},
+			method: http.MethodGet,
+			path:   "/api/v1/collections/" + uuid.NewString() + "/secrets?limit=5",
+			status: http.StatusOK,
+		},
+		{
+			name:   "Token with the secrets read scope cannot list collections",
+			scopes: []domain.TokenScope{domain.ScopeSecretsRead},
+			method: http.MethodGet,
+			path:   "/api/v1/collections/me",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "Token with the collections read scope cannot list secrets",
+			scopes: []domain.TokenScope{domain.ScopeCollectionsRead},
+			method: http.MethodGet,
+			path:   "/api/v1/collections/" + uuid.NewString() + "/secrets?limit=5",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			accessTokens := &accessTokenService{token: &domain.PersonalAccessToken{
+				ID:     uuid.New(),
+				UserID: uuid.New(),
+				Scopes: test.scopes,
+			}}
+
+			r := newRouter(t, &tokenService{}, accessTokens)
+
+			req := httptest.NewRequest(test.method, test.path, nil)
+			req.Header.Set("Authorization", "Bearer "+rawAccessToken)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestDeviceApprovalStepUp(t *testing.T) {
+	tokens := &tokenService{claims: &domain.UserClaims{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		FamilyID: uuid.New(),
+		Role:     domain.UserRole,
+		Type:     domain.AccessToken,
+	}}
+	r := newRouter(t, tokens, &accessTokenService{})
+
+	// A session without a recent authentication cannot log a device in
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/device/approve", strings.NewReader(`{"user_code":"BCDF-GHJK"}`))
+	req.Header.Set("Authorization", "Bearer "+rawSessionToken)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), domain.ErrStepUpRequired.Error())
+}
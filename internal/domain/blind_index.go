@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// BlindIndexField is an enum for the secret fields that have a blind index
+type BlindIndexField string
+
+// BlindIndexField enum values
+const (
+	LoginBlindIndex   BlindIndexField = "login"
+	URLHostBlindIndex BlindIndexField = "url_host"
+)
+
+// ParseBlindIndexField parses a string into BlindIndexField
+func ParseBlindIndexField(field string) (BlindIndexField, error) {
+	switch field {
+	case string(LoginBlindIndex):
+		return LoginBlindIndex, nil
+	case string(URLHostBlindIndex):
+		return URLHostBlindIndex, nil
+	default:
+		return "", ErrInvalidBlindIndexField
+	}
+}
+
+// NormalizeBlindIndexValue normalizes a value before it is indexed or looked up,
+// so that "User@Example.com" matches "user@example.com" and "https://www.example.com/login" matches "www.example.com".
+// It returns an empty string if there is nothing to index.
+func NormalizeBlindIndexValue(field BlindIndexField, value string) string {
+	value = strings.TrimSpace(value)
+
+	switch field {
+	case LoginBlindIndex:
+		return strings.ToLower(value)
+	case URLHostBlindIndex:
+		if value == "" {
+			return ""
+		}
+		if !strings.Contains(value, "://") {
+			value = "https://" + value
+		}
+
+		u, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+
+		return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	default:
+		return ""
+	}
+}
@@ -15,3 +15,16 @@ type Collection struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+// OwnedCollectionsPolicy defines what happens to the collections owned by a user when the user is deleted
+type OwnedCollectionsPolicy string
+
+// OwnedCollectionsPolicy values
+const (
+	// TransferOwnedCollections transfers the collections to the admin deleting the user
+	TransferOwnedCollections OwnedCollectionsPolicy = "transfer"
+	// DeleteOwnedCollections deletes the collections together with their secrets
+	DeleteOwnedCollections OwnedCollectionsPolicy = "delete"
+	// BlockOwnedCollections rejects the deletion while the user owns collections
+	BlockOwnedCollections OwnedCollectionsPolicy = "block"
+)
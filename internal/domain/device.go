@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceCodeGrantType is the grant type of the token requests of the device authorization grant (RFC 8628)
+const DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuthorizationStatus is the state of a device authorization
+type DeviceAuthorizationStatus string
+
+// DeviceAuthorizationStatus enum values
+const (
+	DeviceAuthorizationPending  DeviceAuthorizationStatus = "pending"
+	DeviceAuthorizationApproved DeviceAuthorizationStatus = "approved"
+	DeviceAuthorizationDenied   DeviceAuthorizationStatus = "denied"
+)
+
+// DeviceAuthorization represents a login of a CLI or headless client approved by the user on another device.
+// The device polls for the tokens with a secret device code while the user enters the user code on the verification page.
+type DeviceAuthorization struct {
+	UserCode        string // short code typed by the user, e.g. "BDWP-HQTK"
+	VerificationURI string // page where the user enters the user code
+	Interval        time.Duration
+	Status          DeviceAuthorizationStatus
+	UserID          uuid.UUID // user who approved or denied the device
+	DeviceName      string
+	UserAgent       string
+	IPAddress       string
+	CreatedAt       time.Time
+	ExpiresAt       time.Time
+}
+
+// VerificationURIComplete returns the verification page with the user code, to be opened without typing the code
+func (d *DeviceAuthorization) VerificationURIComplete() string {
+	return d.VerificationURI + "?user_code=" + d.UserCode
+}
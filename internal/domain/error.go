@@ -2,7 +2,10 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"strings"
+	"time"
 )
 
 var (
@@ -35,6 +38,8 @@ var (
 	ErrInvalidToken = errors.New("access token is invalid")
 	// ErrInvalidRefreshToken is an error for when the refresh token is invalid
 	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	// ErrRefreshTokenReused is an error for when an already exchanged refresh token is presented again
+	ErrRefreshTokenReused = errors.New("refresh token has already been used, please log in again")
 
 	// Authentication Errors
 	// ErrInvalidCredentials is an error for when the credentials are invalid
@@ -45,6 +50,54 @@ var (
 	ErrInvalidOTP = errors.New("invalid OTP")
 	// ErrOTPAlreadySent is an error for when an OTP (One-Time Password) has already been sent to the user
 	ErrOTPAlreadySent = errors.New("an OTP has already been sent to the user")
+	// ErrOTPAttemptsExceeded is an error for when an OTP is invalidated after too many wrong codes
+	ErrOTPAttemptsExceeded = errors.New("too many wrong codes, request a new OTP")
+	// ErrInvalidMFACode is an error for when a TOTP or backup code is invalid
+	ErrInvalidMFACode = errors.New("invalid authentication code")
+	// ErrInvalidMFAToken is an error for when the MFA challenge token is invalid or has expired
+	ErrInvalidMFAToken = errors.New("MFA token is invalid or has expired")
+	// ErrMFAAlreadyEnabled is an error for when TOTP is enabled again
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrMFANotEnabled is an error for when TOTP is not enabled for the user
+	ErrMFANotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTOTPNotEnrolled is an error for when TOTP is confirmed without a pending enrollment
+	ErrTOTPNotEnrolled = errors.New("TOTP enrollment has not been started")
+	// ErrInvalidWebAuthnResponse is an error for when a WebAuthn response is malformed or its ceremony has expired
+	ErrInvalidWebAuthnResponse = errors.New("WebAuthn response is invalid or has expired")
+	// ErrInvalidPasskey is an error for when a WebAuthn assertion cannot be verified
+	ErrInvalidPasskey = errors.New("passkey could not be verified")
+	// ErrOIDCNotConfigured is an error for when the single sign-on is used without an OpenID Connect provider
+	ErrOIDCNotConfigured = errors.New("single sign-on is not configured")
+	// ErrInvalidOIDCState is an error for when the state of a single sign-on is unknown or has expired
+	ErrInvalidOIDCState = errors.New("single sign-on state is invalid or has expired")
+	// ErrOIDCLoginFailed is an error for when the provider does not confirm the identity of the user
+	ErrOIDCLoginFailed = errors.New("single sign-on could not be verified")
+	// ErrOIDCEmailNotVerified is an error for when the provider has not verified the email of the user
+	ErrOIDCEmailNotVerified = errors.New("email is not verified by the identity provider")
+	// ErrOIDCSignupDisabled is an error for when an unknown user logs in and accounts are not created by the single sign-on
+	ErrOIDCSignupDisabled = errors.New("no account exists for this email")
+	// ErrInvalidUserCode is an error for when a device user code is unknown, has expired or has already been used
+	ErrInvalidUserCode = errors.New("user code is invalid or has expired")
+	// ErrDeviceAuthorizationPending is an error for when a device polls for tokens before the user approved it
+	ErrDeviceAuthorizationPending = errors.New("device authorization is pending")
+	// ErrDeviceSlowDown is an error for when a device polls for tokens more often than the interval
+	ErrDeviceSlowDown = errors.New("device polls too often, slow down")
+	// ErrDeviceAccessDenied is an error for when the user denied the device authorization
+	ErrDeviceAccessDenied = errors.New("device authorization was denied")
+	// ErrExpiredDeviceCode is an error for when a device code is unknown, has expired or has already been exchanged
+	ErrExpiredDeviceCode = errors.New("device code is invalid or has expired")
+	// ErrUnsupportedGrantType is an error for when a token request uses another grant than the device code
+	ErrUnsupportedGrantType = errors.New("grant type is not supported")
+	// ErrTooManyAttempts is an error for when a client has to wait before trying again after failed attempts
+	ErrTooManyAttempts = errors.New("too many failed attempts")
+	// ErrAccountLocked is an error for when an account is temporarily locked after too many failed attempts
+	ErrAccountLocked = errors.New("account is temporarily locked after too many failed attempts")
+	// ErrRegistrationClosed is an error for when a user registers without an invite code while sign-ups are restricted
+	ErrRegistrationClosed = errors.New("registration requires an invite code")
+	// ErrInvalidInvite is an error for when an invite code is unknown, has expired or is used up
+	ErrInvalidInvite = errors.New("invite code is invalid, expired or used up")
+	// ErrWeakPassword is an error for when a new password does not meet the password policy
+	ErrWeakPassword = errors.New("password does not meet the password policy")
 
 	// Authorization Errors
 	// ErrEmptyAuthorizationHeader is an error for when the authorization header is empty
@@ -57,12 +110,28 @@ var (
 	ErrUnauthorized = errors.New("user is unauthorized to access the resource")
 	// ErrForbidden is an error for when the user is forbidden to access the resource
 	ErrForbidden = errors.New("user is forbidden to access the resource")
+	// ErrInsufficientScope is an error for when a personal access token is not granted access to the resource
+	ErrInsufficientScope = errors.New("personal access token is not granted access to the resource")
+	// ErrStepUpRequired is an error for when a sensitive operation needs the user to authenticate again
+	ErrStepUpRequired = errors.New("recent authentication is required, confirm your password or second factor")
 
 	// User Errors
 	// ErrUserNotVerified is an error for when a user is not verified
 	ErrUserNotVerified = errors.New("user not verified")
 	// ErrDeleteOwnAccount is an error for when a user tries to delete their own account
 	ErrDeleteOwnAccount = errors.New("you cannot delete your own account")
+	// ErrUserOwnsCollections is an error for when a user to delete still owns collections
+	ErrUserOwnsCollections = errors.New("user still owns collections, transfer them before deleting the user")
+	// ErrUserOwnsSharedCollections is an error for when a user to delete owns collections shared with other members
+	ErrUserOwnsSharedCollections = errors.New("user owns collections shared with other members, transfer them before deleting the user")
+	// ErrUserSuspended is an error for when a suspended user logs in or uses their tokens
+	ErrUserSuspended = errors.New("user is suspended")
+	// ErrSuspendOwnAccount is an error for when an admin tries to suspend their own account
+	ErrSuspendOwnAccount = errors.New("you cannot suspend your own account")
+	// ErrPasswordResetRequired is an error for when a user has to change their password before using their account
+	ErrPasswordResetRequired = errors.New("password reset is required, change your password to continue")
+	// ErrMFAEnrollmentRequired is an error for when a user has to enable a second factor before using their account
+	ErrMFAEnrollmentRequired = errors.New("two-factor authentication is required, enable it to continue")
 
 	// Master Password Errors
 	// ErrMasterPasswordActivationExpired is an error for when master password validation has expired
@@ -73,11 +142,69 @@ var (
 	ErrInvalidMasterPassword = errors.New("invalid master password")
 	// ErrMasterPasswordAlreadyExists is an error for when a master password already exists for the user
 	ErrMasterPasswordAlreadyExists = errors.New("master password already exists")
+	// ErrMasterPasswordRepromptRequired is an error for when a flagged secret is read without a fresh master password proof
+	ErrMasterPasswordRepromptRequired = errors.New("secret requires the master password again, send it in the X-Master-Password header")
 
 	// Error for invalid secret type
 	ErrInvalidSecretType = errors.New("invalid secret type")
+	// ErrInvalidBlindIndexField is an error for when a lookup uses a field without a blind index
+	ErrInvalidBlindIndexField = errors.New("invalid lookup field, must be login or url_host")
+
+	// Folder Errors
+	// ErrFolderCycle is an error for when a folder is moved into itself or one of its descendants
+	ErrFolderCycle = errors.New("folder cannot be moved into itself or its descendants")
+
+	// Tag Errors
+	// ErrInvalidTagName is an error for when a tag name is empty or too long
+	ErrInvalidTagName = errors.New("tag name must be between 1 and 64 characters")
+
+	// SCIM Errors
+	// ErrInvalidSCIMFilter is an error for when a SCIM filter is malformed or compares an unsupported attribute
+	ErrInvalidSCIMFilter = errors.New("filter must compare a supported attribute with eq, e.g. userName eq \"john@example.com\"")
+	// ErrInvalidSCIMPatch is an error for when a SCIM patch operation is malformed or targets an unsupported attribute
+	ErrInvalidSCIMPatch = errors.New("patch operation is invalid or targets an unsupported attribute")
 )
 
+// RetryAfterError is an error for when a request is refused until some time has passed
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+// NewRetryAfterError creates an error refusing requests until the given time
+func NewRetryAfterError(err error, until time.Time) *RetryAfterError {
+	return &RetryAfterError{
+		Err:        err,
+		RetryAfter: time.Until(until),
+	}
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%s, try again in %d seconds", e.Err, e.Seconds())
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// Seconds returns the delay in whole seconds, rounded up so a client never retries too early
+func (e *RetryAfterError) Seconds() int {
+	return max(1, int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// PasswordPolicyError is an error for when a new password breaks rules of the password policy
+type PasswordPolicyError struct {
+	Violations []string // the rules broken, e.g. "password must be at least 8 characters long"
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrWeakPassword, strings.Join(e.Violations, ", "))
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
 // IsUniqueConstraintViolationError checks if the error is a unique constraint violation error
 func IsUniqueConstraintViolationError(err error) bool {
 	return strings.Contains(err.Error(), "23505")
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Folder represents a node of the folder tree inside a collection.
+// A folder with an empty ParentID is located at the collection root.
+type Folder struct {
+	ID           uuid.UUID
+	CollectionID uuid.UUID
+	ParentID     uuid.UUID
+	Name         string
+	CreatedBy    uuid.UUID
+	UpdatedBy    uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegistrationMode defines who can register an account at /auth/register
+type RegistrationMode string
+
+// RegistrationMode values
+const (
+	// OpenRegistration lets anyone register, an invite code still gives its role
+	OpenRegistration RegistrationMode = "open"
+	// InviteRegistration requires an invite code created by an admin
+	InviteRegistration RegistrationMode = "invite"
+	// DomainRegistration lets the emails of the allowed domains register, other emails require an invite code
+	DomainRegistration RegistrationMode = "domain"
+)
+
+// Invite represents an invite code created by an admin to register accounts with a preassigned role
+type Invite struct {
+	ID        uuid.UUID
+	CreatedBy uuid.UUID
+	Role      UserRoleEnum
+	MaxUses   int // number of accounts the code registers, 1 for a single-use code
+	Uses      int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// IsExpired checks if the invite has expired
+func (i *Invite) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
+
+// IsUsedUp checks if the invite has registered as many accounts as it can
+func (i *Invite) IsUsedUp() bool {
+	return i.Uses >= i.MaxUses
+}
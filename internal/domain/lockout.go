@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// LockoutAction is an action limited against brute force, its failed attempts are counted separately
+type LockoutAction string
+
+// LockoutAction enum values
+const (
+	LoginAction                  LockoutAction = "login"
+	ActivateMasterPasswordAction LockoutAction = "activate_master_password"
+	ConfirmRegistrationAction    LockoutAction = "confirm_registration"
+	ResetPasswordAction          LockoutAction = "reset_password"
+	ConfirmEmailChangeAction     LockoutAction = "confirm_email_change"
+	VerifyDeviceAction           LockoutAction = "verify_device"
+	StepUpAction                 LockoutAction = "step_up"
+	MFAAction                    LockoutAction = "mfa"
+)
+
+// LockoutActions are all the actions limited against brute force
+var LockoutActions = []LockoutAction{
+	LoginAction,
+	ActivateMasterPasswordAction,
+	ConfirmRegistrationAction,
+	ResetPasswordAction,
+	ConfirmEmailChangeAction,
+	VerifyDeviceAction,
+	StepUpAction,
+	MFAAction,
+}
+
+// AccountLock is a refusal of the attempts of an account for an action
+type AccountLock struct {
+	Action LockoutAction
+	Locked bool // a lockout, otherwise a backoff delay
+	Until  time.Time
+}
@@ -0,0 +1,17 @@
+package domain
+
+// BackupCodesCount is the number of one-time backup codes generated when TOTP is enabled
+const BackupCodesCount = 10
+
+// TOTPEnrollment represents a pending TOTP enrollment of a user
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+	QRCode []byte // PNG image of the otpauth URI
+}
+
+// MFAStatus represents the multi-factor authentication settings of a user
+type MFAStatus struct {
+	TOTPEnabled          bool
+	BackupCodesRemaining uint64
+}
@@ -0,0 +1,11 @@
+package domain
+
+// OTPPurpose is what a one-time password is sent for, a code is only accepted for its purpose
+type OTPPurpose string
+
+// OTPPurpose enum values
+const (
+	RegistrationOTP  OTPPurpose = "registration"
+	PasswordResetOTP OTPPurpose = "password_reset"
+	EmailChangeOTP   OTPPurpose = "email_change"
+)
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission is a permission to use the admin features, formatted as "<resource>:<action>"
+type Permission string
+
+// Permission enum values
+const (
+	UsersReadPermission    Permission = "users:read"
+	UsersWritePermission   Permission = "users:write"
+	InvitesReadPermission  Permission = "invites:read"
+	InvitesWritePermission Permission = "invites:write"
+	RolesReadPermission    Permission = "roles:read"
+	RolesWritePermission   Permission = "roles:write"
+	AuditReadPermission    Permission = "audit:read"
+)
+
+// Permissions are all the permissions roles can grant
+var Permissions = []Permission{
+	UsersReadPermission,
+	UsersWritePermission,
+	InvitesReadPermission,
+	InvitesWritePermission,
+	RolesReadPermission,
+	RolesWritePermission,
+	AuditReadPermission,
+}
+
+// IsValid checks if the permission is known
+func (p Permission) IsValid() bool {
+	return slices.Contains(Permissions, p)
+}
+
+// Permissions returns the permissions granted by the built-in role, the admins have them all
+func (r UserRoleEnum) Permissions() []Permission {
+	if r == AdminRole {
+		return Permissions
+	}
+	return nil
+}
+
+// Role is a custom role granting permissions to the users it is assigned to,
+// on top of the permissions of their built-in role
+type Role struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	Permissions []Permission
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	SCIMManaged bool // created as a group by the identity provider
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix marks personal access tokens, so they can be told apart from JWTs and found by secret scanners
+const PersonalAccessTokenPrefix = "pfat_"
+
+// TokenScope is a permission of a personal access token, formatted as "<resource>:<action>"
+type TokenScope string
+
+// TokenScope enum values
+const (
+	ScopeCollectionsRead  TokenScope = "collections:read"
+	ScopeCollectionsWrite TokenScope = "collections:write"
+	ScopeSecretsRead      TokenScope = "secrets:read"
+	ScopeSecretsWrite     TokenScope = "secrets:write"
+)
+
+// TokenResource is a group of routes personal access tokens can be granted access to
+type TokenResource string
+
+// TokenResource enum values
+const (
+	CollectionsResource TokenResource = "collections"
+	SecretsResource     TokenResource = "secrets"
+)
+
+// Scope returns the scope needed to read or to write the resource
+func (r TokenResource) Scope(write bool) TokenScope {
+	if write {
+		return TokenScope(r + ":write")
+	}
+	return TokenScope(r + ":read")
+}
+
+// PersonalAccessToken represents a long-lived token of a user for non-interactive access
+type PersonalAccessToken struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	Name          string
+	Scopes        []TokenScope
+	CollectionIDs []uuid.UUID // collections the token is restricted to, all collections of the user if empty
+	WrappedKey    []byte      // vault encryption key encrypted with a key derived from the token
+	ExpiresAt     time.Time   // zero if the token does not expire
+	LastUsedAt    time.Time   // zero if the token was never used
+	CreatedAt     time.Time
+}
+
+// HasScope checks if the token is granted the scope
+func (t *PersonalAccessToken) HasScope(scope TokenScope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// AllowsCollection checks if the token can access the collection
+func (t *PersonalAccessToken) AllowsCollection(collectionID uuid.UUID) bool {
+	return len(t.CollectionIDs) == 0 || slices.Contains(t.CollectionIDs, collectionID)
+}
+
+// IsExpired checks if the token has expired
+func (t *PersonalAccessToken) IsExpired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().After(t.ExpiresAt)
+}
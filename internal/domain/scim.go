@@ -0,0 +1,16 @@
+package domain
+
+import "github.com/google/uuid"
+
+// SCIMFilter is a filter of the SCIM list requests comparing an attribute with a value, e.g. userName eq "john@example.com".
+// Identity providers look the resources up by their unique attributes, other filters are not supported.
+type SCIMFilter struct {
+	Attribute string
+	Value     string
+}
+
+// SCIMGroup is a group provisioned through SCIM, a custom role with the users it is assigned to
+type SCIMGroup struct {
+	Role      Role
+	MemberIDs []uuid.UUID
+}
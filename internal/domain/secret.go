@@ -18,6 +18,7 @@ const (
 type Secret struct {
 	ID             uuid.UUID
 	CollectionID   uuid.UUID
+	FolderID       uuid.UUID
 	SecretType     SecretTypeEnum
 	Name           string
 	Description    string
@@ -26,6 +27,8 @@ type Secret struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	LinkedSecretId uuid.UUID
+	Reprompt       bool // the payload is only returned with a fresh master password proof
+	Tags           []string
 	PasswordSecret *PasswordSecret
 	TextSecret     *TextSecret
 }
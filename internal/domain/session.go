@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDeviceNameLength is the maximum length of a device name given by a client
+const MaxDeviceNameLength = 64
+
+// ClientInfo describes the client a user logs in or refreshes a token with
+type ClientInfo struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
+
+// Session represents a login of a user on a device, it lasts as long as its refresh token family
+type Session struct {
+	ID         uuid.UUID
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+	Current    bool // the session of the token making the request
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
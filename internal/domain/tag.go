@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxTagNameLength is the maximum length of a tag name
+const MaxTagNameLength = 64
+
+// Tag represents a free-form label of a user that can be attached to secrets
+type Tag struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TagCount represents a tag of the tag cloud with the number of secrets using it
+type TagCount struct {
+	Name  string
+	Count uint64
+}
+
+// NormalizeTagName trims and lowercases a tag name so that "Prod" and "prod " are the same tag
+func NormalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || len(name) > MaxTagNameLength {
+		return "", ErrInvalidTagName
+	}
+
+	return name, nil
+}
+
+// NormalizeTagNames normalizes a list of tag names and removes duplicates
+func NormalizeTagNames(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+
+	for _, name := range names {
+		name, err := NormalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		normalized = append(normalized, name)
+	}
+
+	return normalized, nil
+}
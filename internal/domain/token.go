@@ -1,16 +1,28 @@
 package domain
 
 import (
+	"crypto"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // UserClaims is an entity that represents the payload of the token
 type UserClaims struct {
-	ID     uuid.UUID
-	UserID uuid.UUID
-	Role   UserRoleEnum
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	Role     UserRoleEnum
+	Type     TokenEnum
+	FamilyID uuid.UUID // refresh token family the token was issued with
+
+	PersonalAccessToken *PersonalAccessToken // set when authenticated with a personal access token instead of a JWT
+}
+
+// CanAccessCollection checks if the credentials of the request can access the collection.
+// Personal access tokens may be restricted to some collections, JWTs are not.
+func (c *UserClaims) CanAccessCollection(collectionID uuid.UUID) bool {
+	return c.PersonalAccessToken == nil || c.PersonalAccessToken.AllowsCollection(collectionID)
 }
 
 // TokenEnum is an enum for user's role
@@ -33,3 +45,24 @@ func ParseTokenEnum(token string) (TokenEnum, error) {
 		return "", fmt.Errorf("invalid role: %s", token)
 	}
 }
+
+// SigningAlgorithm is an algorithm signing the JWT tokens
+type SigningAlgorithm string
+
+// SigningAlgorithm values
+const (
+	RS256 SigningAlgorithm = "RS256"
+	EdDSA SigningAlgorithm = "EdDSA"
+)
+
+// SigningKey is a key pair signing the JWT tokens, the tokens name it in their kid header.
+// A single key signs new tokens, the retired keys still verify the tokens they signed until they expire.
+type SigningKey struct {
+	ID         string
+	Algorithm  SigningAlgorithm
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+	CreatedAt  time.Time
+	RetiredAt  time.Time // zero while the key signs new tokens
+	ExpiresAt  time.Time // zero while the key signs new tokens
+}
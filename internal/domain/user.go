@@ -7,15 +7,42 @@ import (
 )
 
 type User struct {
-	ID                uuid.UUID    `json:"id"`
-	Name              string       `json:"name"`
-	Email             string       `json:"email"`
-	Password          string       `json:"-"` // Hide the password field
-	MasterPassword    string       `json:"-"` // Hide the master password field
-	MasterPasswordSet bool         `json:"master_password_set"`
-	Salt              []byte       `json:"-"` // Hide the salt field
-	IsVerified        bool         `json:"is_verified"`
-	Role              UserRoleEnum `json:"role"`
-	CreatedAt         time.Time    `json:"created_at"`
-	UpdatedAt         time.Time    `json:"updated_at"`
+	ID                 uuid.UUID    `json:"id"`
+	Name               string       `json:"name"`
+	Email              string       `json:"email"`
+	Password           string       `json:"-"` // Hide the password field
+	MasterPassword     string       `json:"-"` // Hide the master password field
+	MasterPasswordSet  bool         `json:"master_password_set"`
+	Salt               []byte       `json:"-"` // Hide the salt field
+	IsVerified         bool         `json:"is_verified"`
+	Role               UserRoleEnum `json:"role"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+	SuspendedAt        time.Time    `json:"suspended_at"`         // zero unless an admin suspended the user
+	ForcePasswordReset bool         `json:"force_password_reset"` // the password has to be changed before using the account
+	ForceMFAEnrollment bool         `json:"force_mfa_enrollment"` // a second factor has to be enabled before using the account
+}
+
+// UserStatus is the state of the account of a user shown to the admins
+type UserStatus struct {
+	User  *User
+	Locks []AccountLock // the current lockouts of the account
+}
+
+// IsSuspended checks if an admin suspended the user
+func (u *User) IsSuspended() bool {
+	return !u.SuspendedAt.IsZero()
+}
+
+// PendingActionError returns the error of the first action the user has to complete
+// before using their account, nil if there is none
+func (u *User) PendingActionError() error {
+	switch {
+	case u.ForcePasswordReset:
+		return ErrPasswordResetRequired
+	case u.ForceMFAEnrollment:
+		return ErrMFAEnrollmentRequired
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxWebAuthnCredentialNameLength is the maximum length of a passkey name
+const MaxWebAuthnCredentialNameLength = 64
+
+// WebAuthnCredential represents a passkey or a security key registered by a user
+type WebAuthnCredential struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	CredentialID    []byte
+	PublicKey       []byte
+	AttestationType string
+	AAGUID          []byte
+	SignCount       uint32
+	Transports      []string
+	BackupEligible  bool
+	BackupState     bool
+	Name            string
+	CreatedAt       time.Time
+	LastUsedAt      time.Time // zero if the credential was never used to log in
+}
@@ -38,6 +38,12 @@ func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Dura
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
+// SetIfAbsent stores the value in the redis database only if the key does not exist,
+// it reports if the value was stored
+func (r *Redis) SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 // Get retrieves the value from the redis database
 func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 	res, err := r.client.Get(ctx, key).Result()
@@ -77,6 +83,23 @@ func (r *Redis) DeleteByPrefix(ctx context.Context, prefix string) error {
 	return nil
 }
 
+// Increment increments the counter stored at the key in the redis database,
+// the ttl is set in the same transaction when the counter is created
+func (r *Redis) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
 // Exists checks if a key exists in the redis database
 func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
 	exists, err := r.client.Exists(ctx, key).Result()
@@ -247,6 +247,75 @@ func (r *CollectionRepository) DeleteCollection(ctx context.Context, id uuid.UUI
 	return nil
 }
 
+// TransferCollectionOwnership makes another user the owner of a collection.
+// The new owner becomes a member of the collection if they are not one yet.
+func (r *CollectionRepository) TransferCollectionOwnership(ctx context.Context, collectionID, newOwnerID, updatedBy uuid.UUID) (*dao.CollectionDAO, error) {
+	var collectionDAO dao.CollectionDAO
+
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Add the new owner to the collection members
+	usersCollectionsQuery := r.db.QueryBuilder.Insert("users_collections").
+		Columns("user_id", "collection_id").
+		Values(newOwnerID, collectionID).
+		Suffix("ON CONFLICT DO NOTHING")
+
+	usersCollectionsSQL, usersCollectionsArgs, err := usersCollectionsQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, usersCollectionsSQL, usersCollectionsArgs...)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	// Change the owner of the collection
+	collectionQuery := r.db.QueryBuilder.Update("collections").
+		Set("created_by", newOwnerID).
+		Set("updated_by", updatedBy).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": collectionID}).
+		Suffix("RETURNING *")
+
+	collectionSQL, collectionArgs, err := collectionQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, collectionSQL, collectionArgs...).Scan(
+		&collectionDAO.ID,
+		&collectionDAO.Name,
+		&collectionDAO.Description,
+		&collectionDAO.CreatedBy,
+		&collectionDAO.UpdatedBy,
+		&collectionDAO.CreatedAt,
+		&collectionDAO.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	// Commit the transaction
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &collectionDAO, nil
+}
+
 // IsUserPartOfCollection checks if the user is part of the specified collection
 func (r *CollectionRepository) IsUserPartOfCollection(ctx context.Context, userID, collectionID uuid.UUID) (bool, error) {
 	query := r.db.QueryBuilder.Select("1").
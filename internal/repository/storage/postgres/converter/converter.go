@@ -1,9 +1,12 @@
 package converter
 
 import (
+	"database/sql"
+
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/repository/storage/postgres"
 	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/google/uuid"
 )
 
 func ToUserDAO(user *domain.User) *dao.UserDAO {
@@ -18,22 +21,31 @@ func ToUserDAO(user *domain.User) *dao.UserDAO {
 		Role:           string(user.Role),
 		CreatedAt:      user.CreatedAt,
 		UpdatedAt:      user.UpdatedAt,
+		SuspendedAt: sql.NullTime{
+			Time:  user.SuspendedAt,
+			Valid: !user.SuspendedAt.IsZero(),
+		},
+		ForcePasswordReset: user.ForcePasswordReset,
+		ForceMFAEnrollment: user.ForceMFAEnrollment,
 	}
 }
 
 func ToUser(userDAO *dao.UserDAO) *domain.User {
 	return &domain.User{
-		ID:                userDAO.ID,
-		Name:              userDAO.Name,
-		Email:             userDAO.Email,
-		Password:          userDAO.Password,
-		MasterPassword:    userDAO.MasterPassword.String,
-		MasterPasswordSet: len(userDAO.MasterPassword.String) > 0,
-		Salt:              userDAO.Salt,
-		IsVerified:        userDAO.IsVerified,
-		Role:              domain.UserRoleEnum(userDAO.Role),
-		CreatedAt:         userDAO.CreatedAt,
-		UpdatedAt:         userDAO.UpdatedAt,
+		ID:                 userDAO.ID,
+		Name:               userDAO.Name,
+		Email:              userDAO.Email,
+		Password:           userDAO.Password,
+		MasterPassword:     userDAO.MasterPassword.String,
+		MasterPasswordSet:  len(userDAO.MasterPassword.String) > 0,
+		Salt:               userDAO.Salt,
+		IsVerified:         userDAO.IsVerified,
+		Role:               domain.UserRoleEnum(userDAO.Role),
+		CreatedAt:          userDAO.CreatedAt,
+		UpdatedAt:          userDAO.UpdatedAt,
+		SuspendedAt:        userDAO.SuspendedAt.Time,
+		ForcePasswordReset: userDAO.ForcePasswordReset,
+		ForceMFAEnrollment: userDAO.ForceMFAEnrollment,
 	}
 }
 
@@ -61,11 +73,200 @@ func ToCollection(collectionDAO *dao.CollectionDAO) *domain.Collection {
 	}
 }
 
+// ToFolderDAO converts a domain.Folder to a dao.FolderDAO
+func ToFolderDAO(folder *domain.Folder) *dao.FolderDAO {
+	return &dao.FolderDAO{
+		ID:           folder.ID,
+		CollectionID: folder.CollectionID,
+		ParentID:     folder.ParentID,
+		Name:         folder.Name,
+		CreatedBy:    folder.CreatedBy,
+		UpdatedBy:    folder.UpdatedBy,
+		CreatedAt:    folder.CreatedAt,
+		UpdatedAt:    folder.UpdatedAt,
+	}
+}
+
+// ToFolder converts a dao.FolderDAO to a domain.Folder
+func ToFolder(folderDAO *dao.FolderDAO) *domain.Folder {
+	return &domain.Folder{
+		ID:           folderDAO.ID,
+		CollectionID: folderDAO.CollectionID,
+		ParentID:     folderDAO.ParentID,
+		Name:         folderDAO.Name,
+		CreatedBy:    folderDAO.CreatedBy,
+		UpdatedBy:    folderDAO.UpdatedBy,
+		CreatedAt:    folderDAO.CreatedAt,
+		UpdatedAt:    folderDAO.UpdatedAt,
+	}
+}
+
+// ToTagDAO converts a domain.Tag to a dao.TagDAO
+func ToTagDAO(tag *domain.Tag) *dao.TagDAO {
+	return &dao.TagDAO{
+		ID:        tag.ID,
+		UserID:    tag.UserID,
+		Name:      tag.Name,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+// ToTag converts a dao.TagDAO to a domain.Tag
+func ToTag(tagDAO *dao.TagDAO) *domain.Tag {
+	return &domain.Tag{
+		ID:        tagDAO.ID,
+		UserID:    tagDAO.UserID,
+		Name:      tagDAO.Name,
+		CreatedAt: tagDAO.CreatedAt,
+		UpdatedAt: tagDAO.UpdatedAt,
+	}
+}
+
+// ToTagCount converts a dao.TagCountDAO to a domain.TagCount
+func ToTagCount(tagCountDAO *dao.TagCountDAO) *domain.TagCount {
+	return &domain.TagCount{
+		Name:  tagCountDAO.Name,
+		Count: tagCountDAO.Count,
+	}
+}
+
+// ToWebAuthnCredentialDAO converts a domain.WebAuthnCredential to a dao.WebAuthnCredentialDAO
+func ToWebAuthnCredentialDAO(credential *domain.WebAuthnCredential) *dao.WebAuthnCredentialDAO {
+	return &dao.WebAuthnCredentialDAO{
+		ID:              credential.ID,
+		UserID:          credential.UserID,
+		CredentialID:    credential.CredentialID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.AAGUID,
+		SignCount:       int64(credential.SignCount),
+		Transports:      credential.Transports,
+		BackupEligible:  credential.BackupEligible,
+		BackupState:     credential.BackupState,
+		Name:            credential.Name,
+		CreatedAt:       credential.CreatedAt,
+		LastUsedAt: sql.NullTime{
+			Time:  credential.LastUsedAt,
+			Valid: !credential.LastUsedAt.IsZero(),
+		},
+	}
+}
+
+// ToWebAuthnCredential converts a dao.WebAuthnCredentialDAO to a domain.WebAuthnCredential
+func ToWebAuthnCredential(credentialDAO *dao.WebAuthnCredentialDAO) *domain.WebAuthnCredential {
+	return &domain.WebAuthnCredential{
+		ID:              credentialDAO.ID,
+		UserID:          credentialDAO.UserID,
+		CredentialID:    credentialDAO.CredentialID,
+		PublicKey:       credentialDAO.PublicKey,
+		AttestationType: credentialDAO.AttestationType,
+		AAGUID:          credentialDAO.AAGUID,
+		SignCount:       uint32(credentialDAO.SignCount),
+		Transports:      credentialDAO.Transports,
+		BackupEligible:  credentialDAO.BackupEligible,
+		BackupState:     credentialDAO.BackupState,
+		Name:            credentialDAO.Name,
+		CreatedAt:       credentialDAO.CreatedAt,
+		LastUsedAt:      credentialDAO.LastUsedAt.Time,
+	}
+}
+
+// ToPersonalAccessTokenDAO converts a domain.PersonalAccessToken to a dao.PersonalAccessTokenDAO, the token hash is set by the caller
+func ToPersonalAccessTokenDAO(token *domain.PersonalAccessToken) *dao.PersonalAccessTokenDAO {
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	collectionIDs := token.CollectionIDs
+	if collectionIDs == nil {
+		collectionIDs = []uuid.UUID{}
+	}
+
+	return &dao.PersonalAccessTokenDAO{
+		ID:            token.ID,
+		UserID:        token.UserID,
+		Name:          token.Name,
+		Scopes:        scopes,
+		CollectionIDs: collectionIDs,
+		WrappedKey:    token.WrappedKey,
+		ExpiresAt: sql.NullTime{
+			Time:  token.ExpiresAt,
+			Valid: !token.ExpiresAt.IsZero(),
+		},
+		LastUsedAt: sql.NullTime{
+			Time:  token.LastUsedAt,
+			Valid: !token.LastUsedAt.IsZero(),
+		},
+		CreatedAt: token.CreatedAt,
+	}
+}
+
+// ToPersonalAccessToken converts a dao.PersonalAccessTokenDAO to a domain.PersonalAccessToken
+func ToPersonalAccessToken(tokenDAO *dao.PersonalAccessTokenDAO) *domain.PersonalAccessToken {
+	scopes := make([]domain.TokenScope, 0, len(tokenDAO.Scopes))
+	for _, scope := range tokenDAO.Scopes {
+		scopes = append(scopes, domain.TokenScope(scope))
+	}
+
+	return &domain.PersonalAccessToken{
+		ID:            tokenDAO.ID,
+		UserID:        tokenDAO.UserID,
+		Name:          tokenDAO.Name,
+		Scopes:        scopes,
+		CollectionIDs: tokenDAO.CollectionIDs,
+		WrappedKey:    tokenDAO.WrappedKey,
+		ExpiresAt:     tokenDAO.ExpiresAt.Time,
+		LastUsedAt:    tokenDAO.LastUsedAt.Time,
+		CreatedAt:     tokenDAO.CreatedAt,
+	}
+}
+
+// ToInviteDAO converts a domain.Invite to a dao.InviteDAO, the code hash is set by the caller
+func ToInviteDAO(invite *domain.Invite) *dao.InviteDAO {
+	return &dao.InviteDAO{
+		ID:        invite.ID,
+		CreatedBy: invite.CreatedBy,
+		Role:      string(invite.Role),
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		ExpiresAt: invite.ExpiresAt,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+// ToInvite converts a dao.InviteDAO to a domain.Invite
+func ToInvite(inviteDAO *dao.InviteDAO) *domain.Invite {
+	return &domain.Invite{
+		ID:        inviteDAO.ID,
+		CreatedBy: inviteDAO.CreatedBy,
+		Role:      domain.UserRoleEnum(inviteDAO.Role),
+		MaxUses:   inviteDAO.MaxUses,
+		Uses:      inviteDAO.Uses,
+		ExpiresAt: inviteDAO.ExpiresAt,
+		CreatedAt: inviteDAO.CreatedAt,
+	}
+}
+
+// ToSession converts a dao.TokenFamilyDAO to a domain.Session
+func ToSession(familyDAO *dao.TokenFamilyDAO) *domain.Session {
+	return &domain.Session{
+		ID:         familyDAO.ID,
+		DeviceName: familyDAO.DeviceName,
+		UserAgent:  familyDAO.UserAgent,
+		IPAddress:  familyDAO.IPAddress,
+		CreatedAt:  familyDAO.CreatedAt,
+		LastUsedAt: familyDAO.LastUsedAt,
+	}
+}
+
 // ToSecretDAO converts a domain.Secret to a dao.SecretDAO
 func ToSecretDAO(secret *domain.Secret) *dao.SecretDAO {
 	secretDAO := &dao.SecretDAO{
 		ID:             secret.ID,
 		CollectionID:   secret.CollectionID,
+		FolderID:       secret.FolderID,
 		SecretType:     dao.SecretType(secret.SecretType),
 		Name:           secret.Name,
 		Description:    secret.Description,
@@ -74,6 +275,7 @@ func ToSecretDAO(secret *domain.Secret) *dao.SecretDAO {
 		CreatedAt:      secret.CreatedAt,
 		UpdatedAt:      secret.UpdatedAt,
 		LinkedSecretId: secret.LinkedSecretId,
+		Reprompt:       secret.Reprompt,
 	}
 
 	switch secret.SecretType {
@@ -95,6 +297,7 @@ func ToSecret(secretDAO *dao.SecretDAO) *domain.Secret {
 	secret := &domain.Secret{
 		ID:             secretDAO.ID,
 		CollectionID:   secretDAO.CollectionID,
+		FolderID:       secretDAO.FolderID,
 		SecretType:     domain.SecretTypeEnum(secretDAO.SecretType),
 		Name:           secretDAO.Name,
 		Description:    secretDAO.Description,
@@ -103,6 +306,7 @@ func ToSecret(secretDAO *dao.SecretDAO) *domain.Secret {
 		CreatedAt:      secretDAO.CreatedAt,
 		UpdatedAt:      secretDAO.UpdatedAt,
 		LinkedSecretId: secretDAO.LinkedSecretId,
+		Reprompt:       secretDAO.Reprompt,
 	}
 
 	switch secretDAO.SecretType {
@@ -150,3 +354,39 @@ func ToTextSecret(dao *dao.TextSecretDAO) *domain.TextSecret {
 		Text: string(dao.Text),
 	}
 }
+
+// ToRoleDAO converts a domain.Role to a dao.RoleDAO
+func ToRoleDAO(role *domain.Role) *dao.RoleDAO {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, string(permission))
+	}
+
+	return &dao.RoleDAO{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+		SCIMManaged: role.SCIMManaged,
+	}
+}
+
+// ToRole converts a dao.RoleDAO to a domain.Role
+func ToRole(roleDAO *dao.RoleDAO) *domain.Role {
+	permissions := make([]domain.Permission, 0, len(roleDAO.Permissions))
+	for _, permission := range roleDAO.Permissions {
+		permissions = append(permissions, domain.Permission(permission))
+	}
+
+	return &domain.Role{
+		ID:          roleDAO.ID,
+		Name:        roleDAO.Name,
+		Description: roleDAO.Description,
+		Permissions: permissions,
+		CreatedAt:   roleDAO.CreatedAt,
+		UpdatedAt:   roleDAO.UpdatedAt,
+		SCIMManaged: roleDAO.SCIMManaged,
+	}
+}
@@ -0,0 +1,89 @@
+package converter
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/cipherkit"
+)
+
+// encryptedMetadataPrefix marks the metadata values stored encrypted.
+// Values without the prefix are plaintext, so both kinds can be read while secrets are being encrypted.
+const encryptedMetadataPrefix = "enc:v1:"
+
+// EncryptSecretMetadata encrypts the name, the description and the URL and login of a password secret in place
+func EncryptSecretMetadata(secretDAO *dao.SecretDAO, key []byte) error {
+	for _, value := range secretMetadata(secretDAO) {
+		encrypted, err := encryptMetadataValue(*value, key)
+		if err != nil {
+			return err
+		}
+		*value = encrypted
+	}
+
+	return nil
+}
+
+// DecryptSecretMetadata decrypts the encrypted metadata of a secret in place, plaintext values are kept as is
+func DecryptSecretMetadata(secretDAO *dao.SecretDAO, key []byte) error {
+	for _, value := range secretMetadata(secretDAO) {
+		decrypted, err := decryptMetadataValue(*value, key)
+		if err != nil {
+			return err
+		}
+		*value = decrypted
+	}
+
+	return nil
+}
+
+// RedactSecretMetadata replaces the metadata of a secret that cannot be decrypted, the name by a placeholder
+func RedactSecretMetadata(secretDAO *dao.SecretDAO, placeholder string) {
+	for _, value := range secretMetadata(secretDAO) {
+		*value = ""
+	}
+	secretDAO.Name = placeholder
+}
+
+// secretMetadata returns the metadata fields of a secret
+func secretMetadata(secretDAO *dao.SecretDAO) []*string {
+	values := []*string{&secretDAO.Name, &secretDAO.Description}
+	if secretDAO.SecretType == dao.PasswordSecretType {
+		values = append(values, &secretDAO.PasswordSecret.URL, &secretDAO.PasswordSecret.Login)
+	}
+
+	return values
+}
+
+func encryptMetadataValue(value string, key []byte) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+
+	ciphertext, err := cipherkit.Encrypt([]byte(value), key)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedMetadataPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func decryptMetadataValue(value string, key []byte) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedMetadataPrefix)
+	if !ok {
+		return value, nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := cipherkit.Decrypt(ciphertext, key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
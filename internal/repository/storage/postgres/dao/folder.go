@@ -0,0 +1,19 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FolderDAO is a model of a folder in a data store.
+type FolderDAO struct {
+	ID           uuid.UUID `db:"id"`
+	CollectionID uuid.UUID `db:"collection_id"`
+	ParentID     uuid.UUID `db:"parent_id"`
+	Name         string    `db:"name"`
+	CreatedBy    uuid.UUID `db:"created_by"`
+	UpdatedBy    uuid.UUID `db:"updated_by"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InviteDAO is a model of an invite code in a data store.
+type InviteDAO struct {
+	ID        uuid.UUID `db:"id"`
+	CodeHash  string    `db:"code_hash"`
+	CreatedBy uuid.UUID `db:"created_by"`
+	Role      string    `db:"role"`
+	MaxUses   int       `db:"max_uses"`
+	Uses      int       `db:"uses"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
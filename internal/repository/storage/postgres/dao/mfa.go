@@ -0,0 +1,26 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTPDAO is a model of the TOTP secret of a user in a data store.
+type TOTPDAO struct {
+	UserID    uuid.UUID `db:"user_id"`
+	Secret    string    `db:"secret"`
+	Enabled   bool      `db:"enabled"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// BackupCodeDAO is a model of a hashed one-time backup code in a data store.
+type BackupCodeDAO struct {
+	ID        uuid.UUID    `db:"id"`
+	UserID    uuid.UUID    `db:"user_id"`
+	CodeHash  string       `db:"code_hash"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
@@ -0,0 +1,18 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCIdentityDAO is a model of a user identity at an OpenID Connect provider in a data store.
+type OIDCIdentityDAO struct {
+	ID          uuid.UUID `db:"id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Issuer      string    `db:"issuer"`
+	Subject     string    `db:"subject"`
+	Email       string    `db:"email"`
+	CreatedAt   time.Time `db:"created_at"`
+	LastLoginAt time.Time `db:"last_login_at"`
+}
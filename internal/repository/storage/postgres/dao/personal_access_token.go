@@ -0,0 +1,22 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenDAO is a model of a personal access token in a data store.
+type PersonalAccessTokenDAO struct {
+	ID            uuid.UUID    `db:"id"`
+	UserID        uuid.UUID    `db:"user_id"`
+	Name          string       `db:"name"`
+	TokenHash     string       `db:"token_hash"`
+	Scopes        []string     `db:"scopes"`
+	CollectionIDs []uuid.UUID  `db:"collection_ids"`
+	WrappedKey    []byte       `db:"wrapped_key"`
+	ExpiresAt     sql.NullTime `db:"expires_at"`
+	LastUsedAt    sql.NullTime `db:"last_used_at"`
+	CreatedAt     time.Time    `db:"created_at"`
+}
@@ -0,0 +1,18 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleDAO is a model of a custom role in a data store.
+type RoleDAO struct {
+	ID          uuid.UUID `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Permissions []string  `db:"permissions"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	SCIMManaged bool      `db:"scim_managed"`
+}
@@ -26,6 +26,8 @@ type SecretDAO struct {
 	CreatedAt      time.Time         `db:"created_at"`
 	UpdatedAt      time.Time         `db:"updated_at"`
 	LinkedSecretId uuid.UUID         `db:"linked_secret_id"`
+	FolderID       uuid.UUID         `db:"folder_id"`
+	Reprompt       bool              `db:"reprompt"`
 	PasswordSecret PasswordSecretDAO `db:"-"`
 	TextSecret     TextSecretDAO     `db:"-"`
 }
@@ -43,3 +45,10 @@ type TextSecretDAO struct {
 	ID   uuid.UUID `db:"id"`
 	Text []byte    `db:"text"`
 }
+
+// BlindIndexDAO represents a blind index of a secret field.
+type BlindIndexDAO struct {
+	SecretID uuid.UUID `db:"secret_id"`
+	Field    string    `db:"field"`
+	Value    []byte    `db:"value"`
+}
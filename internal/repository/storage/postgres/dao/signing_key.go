@@ -0,0 +1,20 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SigningKeyDAO is a model of a JWT signing key pair in a data store.
+// The private key is stored in PKCS #8 and the public key in PKIX, both DER encoded.
+type SigningKeyDAO struct {
+	ID         uuid.UUID    `db:"id"`
+	Algorithm  string       `db:"algorithm"`
+	PrivateKey []byte       `db:"private_key"`
+	PublicKey  []byte       `db:"public_key"`
+	CreatedAt  time.Time    `db:"created_at"`
+	RetiredAt  sql.NullTime `db:"retired_at"`
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+}
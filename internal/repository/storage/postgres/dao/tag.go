@@ -0,0 +1,22 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TagDAO is a model of a tag in a data store.
+type TagDAO struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// TagCountDAO is a tag name with the number of secrets using it.
+type TagCountDAO struct {
+	Name  string `db:"name"`
+	Count uint64 `db:"count"`
+}
@@ -0,0 +1,23 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenFamilyDAO is a model of a refresh token family in a data store.
+type TokenFamilyDAO struct {
+	ID              uuid.UUID    `db:"id"`
+	UserID          uuid.UUID    `db:"user_id"`
+	CurrentTokenID  uuid.UUID    `db:"current_token_id"`
+	RevokedAt       sql.NullTime `db:"revoked_at"`
+	CreatedAt       time.Time    `db:"created_at"`
+	UpdatedAt       time.Time    `db:"updated_at"`
+	DeviceName      string       `db:"device_name"`
+	UserAgent       string       `db:"user_agent"`
+	IPAddress       string       `db:"ip_address"`
+	LastUsedAt      time.Time    `db:"last_used_at"`
+	AuthenticatedAt sql.NullTime `db:"authenticated_at"` // login of the user, null for the sessions of approved devices
+}
@@ -8,14 +8,17 @@ import (
 )
 
 type UserDAO struct {
-	ID             uuid.UUID      `db:"id"`
-	Name           string         `db:"name"`
-	Email          string         `db:"email"`
-	Password       string         `db:"password"`
-	MasterPassword sql.NullString `db:"master_password"`
-	Salt           []byte         `db:"salt"`
-	IsVerified     bool           `db:"is_verified"`
-	Role           string         `db:"role"`
-	CreatedAt      time.Time      `db:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at"`
+	ID                 uuid.UUID      `db:"id"`
+	Name               string         `db:"name"`
+	Email              string         `db:"email"`
+	Password           string         `db:"password"`
+	MasterPassword     sql.NullString `db:"master_password"`
+	Salt               []byte         `db:"salt"`
+	IsVerified         bool           `db:"is_verified"`
+	Role               string         `db:"role"`
+	CreatedAt          time.Time      `db:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at"`
+	SuspendedAt        sql.NullTime   `db:"suspended_at"`
+	ForcePasswordReset bool           `db:"force_password_reset"`
+	ForceMFAEnrollment bool           `db:"force_mfa_enrollment"`
 }
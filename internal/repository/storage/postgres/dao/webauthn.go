@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebAuthnCredentialDAO is a model of a WebAuthn credential in a data store.
+type WebAuthnCredentialDAO struct {
+	ID              uuid.UUID    `db:"id"`
+	UserID          uuid.UUID    `db:"user_id"`
+	CredentialID    []byte       `db:"credential_id"`
+	PublicKey       []byte       `db:"public_key"`
+	AttestationType string       `db:"attestation_type"`
+	AAGUID          []byte       `db:"aaguid"`
+	SignCount       int64        `db:"sign_count"`
+	Transports      []string     `db:"transports"`
+	BackupEligible  bool         `db:"backup_eligible"`
+	BackupState     bool         `db:"backup_state"`
+	Name            string       `db:"name"`
+	CreatedAt       time.Time    `db:"created_at"`
+	LastUsedAt      sql.NullTime `db:"last_used_at"`
+}
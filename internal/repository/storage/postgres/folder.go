@@ -0,0 +1,273 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// folderSubtreeQuery selects the ids of a folder and all of its descendants.
+// UNION (not UNION ALL) stops the recursion even if the tree contains a cycle.
+const folderSubtreeQuery = `WITH RECURSIVE subtree AS (
+	SELECT id FROM folders WHERE id = ?
+	UNION
+	SELECT f.id FROM folders f JOIN subtree s ON f.parent_id = s.id
+) SELECT id FROM subtree`
+
+/**
+ * FolderRepository implements postgres.FolderRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type FolderRepository struct {
+	db *database.DB
+}
+
+// NewFolderRepository creates a new folder repository instance
+func NewFolderRepository(db *database.DB) *FolderRepository {
+	return &FolderRepository{
+		db,
+	}
+}
+
+// CreateFolder creates a new folder in the database
+func (r *FolderRepository) CreateFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error) {
+	var folderDAO dao.FolderDAO
+
+	query := r.db.QueryBuilder.Insert("folders").
+		Columns("collection_id", "parent_id", "name", "created_by", "updated_by").
+		Values(folder.CollectionID, nullUUID(folder.ParentID), folder.Name, folder.CreatedBy, folder.UpdatedBy).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&folderDAO.ID,
+		&folderDAO.CollectionID,
+		&folderDAO.ParentID,
+		&folderDAO.Name,
+		&folderDAO.CreatedBy,
+		&folderDAO.UpdatedBy,
+		&folderDAO.CreatedAt,
+		&folderDAO.UpdatedAt,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &folderDAO, nil
+}
+
+// GetFolderByID gets a folder by ID from the database
+func (r *FolderRepository) GetFolderByID(ctx context.Context, id uuid.UUID) (*dao.FolderDAO, error) {
+	var folderDAO dao.FolderDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("folders").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&folderDAO.ID,
+		&folderDAO.CollectionID,
+		&folderDAO.ParentID,
+		&folderDAO.Name,
+		&folderDAO.CreatedBy,
+		&folderDAO.UpdatedBy,
+		&folderDAO.CreatedAt,
+		&folderDAO.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &folderDAO, nil
+}
+
+// ListFoldersByParentID lists the direct children of a folder.
+// An empty parentID lists the folders located at the collection root.
+func (r *FolderRepository) ListFoldersByParentID(ctx context.Context, collectionID, parentID uuid.UUID) ([]dao.FolderDAO, error) {
+	var foldersDAO []dao.FolderDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("folders").
+		Where(sq.Eq{"collection_id": collectionID}).
+		OrderBy("name")
+
+	if parentID == uuid.Nil {
+		query = query.Where(sq.Eq{"parent_id": nil})
+	} else {
+		query = query.Where(sq.Eq{"parent_id": parentID})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var folderDAO dao.FolderDAO
+		err := rows.Scan(
+			&folderDAO.ID,
+			&folderDAO.CollectionID,
+			&folderDAO.ParentID,
+			&folderDAO.Name,
+			&folderDAO.CreatedBy,
+			&folderDAO.UpdatedBy,
+			&folderDAO.CreatedAt,
+			&folderDAO.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		foldersDAO = append(foldersDAO, folderDAO)
+	}
+
+	return foldersDAO, nil
+}
+
+// UpdateFolder renames a folder
+func (r *FolderRepository) UpdateFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error) {
+	var folderDAO dao.FolderDAO
+
+	query := r.db.QueryBuilder.Update("folders").
+		Set("name", sq.Expr("COALESCE(?, name)", NullString(folder.Name))).
+		Set("updated_at", time.Now()).
+		Set("updated_by", folder.UpdatedBy).
+		Where(sq.Eq{"id": folder.ID}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&folderDAO.ID,
+		&folderDAO.CollectionID,
+		&folderDAO.ParentID,
+		&folderDAO.Name,
+		&folderDAO.CreatedBy,
+		&folderDAO.UpdatedBy,
+		&folderDAO.CreatedAt,
+		&folderDAO.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &folderDAO, nil
+}
+
+// MoveFolder changes the parent of a folder.
+// The database trigger rejects moves that would create a cycle in the tree.
+func (r *FolderRepository) MoveFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error) {
+	var folderDAO dao.FolderDAO
+
+	query := r.db.QueryBuilder.Update("folders").
+		Set("parent_id", nullUUID(folder.ParentID)).
+		Set("updated_at", time.Now()).
+		Set("updated_by", folder.UpdatedBy).
+		Where(sq.Eq{"id": folder.ID}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&folderDAO.ID,
+		&folderDAO.CollectionID,
+		&folderDAO.ParentID,
+		&folderDAO.Name,
+		&folderDAO.CreatedBy,
+		&folderDAO.UpdatedBy,
+		&folderDAO.CreatedAt,
+		&folderDAO.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		switch r.db.ErrorCode(err) {
+		case "23514":
+			return nil, domain.ErrFolderCycle
+		case "23503":
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &folderDAO, nil
+}
+
+// DeleteFolder deletes a folder together with all nested folders and their secrets
+func (r *FolderRepository) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Delete the secrets of the whole subtree, this also fires the linked secrets cleanup trigger
+	secretsQuery := r.db.QueryBuilder.Delete("secrets").
+		Where("folder_id IN ("+folderSubtreeQuery+")", id)
+
+	secretsSQL, secretsArgs, err := secretsQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, secretsSQL, secretsArgs...)
+	if err != nil {
+		return err
+	}
+
+	// Delete the folders of the subtree
+	foldersQuery := r.db.QueryBuilder.Delete("folders").
+		Where("id IN ("+folderSubtreeQuery+")", id)
+
+	foldersSQL, foldersArgs, err := foldersQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, foldersSQL, foldersArgs...)
+	if err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit(ctx)
+}
@@ -2,6 +2,10 @@ package postgres
 
 import (
 	"database/sql"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
 )
 
 // NullString converts a string to sql.NullString for empty string check
@@ -73,3 +77,25 @@ func nullBool(value bool) sql.NullBool {
 		Valid: value,
 	}
 }
+
+// nullUUID converts a uuid.UUID to uuid.NullUUID for empty uuid check
+func nullUUID(value uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{
+		UUID:  value,
+		Valid: value != uuid.Nil,
+	}
+}
+
+// prefixTSQuery converts a free-form search text into a tsquery matching all words by prefix,
+// for example "git hub" becomes "git:* & hub:*". It returns an empty string if there are no words.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * InviteRepository implements postgres.InviteRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type InviteRepository struct {
+	db *database.DB
+}
+
+// NewInviteRepository creates a new invite repository instance
+func NewInviteRepository(db *database.DB) *InviteRepository {
+	return &InviteRepository{
+		db,
+	}
+}
+
+// CreateInvite creates a new invite record in the database
+func (r *InviteRepository) CreateInvite(ctx context.Context, invite *dao.InviteDAO) (*dao.InviteDAO, error) {
+	var inviteDAO dao.InviteDAO
+
+	query := r.db.QueryBuilder.Insert("invites").
+		Columns("code_hash", "created_by", "role", "max_uses", "expires_at").
+		Values(invite.CodeHash, nullUUID(invite.CreatedBy), invite.Role, invite.MaxUses, invite.ExpiresAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanInvite(r.db.QueryRow(ctx, sql, args...), &inviteDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &inviteDAO, nil
+}
+
+// ListInvites selects all invites, the newest first
+func (r *InviteRepository) ListInvites(ctx context.Context) ([]dao.InviteDAO, error) {
+	var invitesDAO []dao.InviteDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("invites").
+		OrderBy("created_at DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var inviteDAO dao.InviteDAO
+		if err := scanInvite(rows, &inviteDAO); err != nil {
+			return nil, err
+		}
+
+		invitesDAO = append(invitesDAO, inviteDAO)
+	}
+
+	return invitesDAO, rows.Err()
+}
+
+// DeleteInvite deletes an invite, its code can no longer be used
+func (r *InviteRepository) DeleteInvite(ctx context.Context, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Delete("invites").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// CreateInvitedUser uses the invite of the code hash and creates the user with the role of the invite.
+// The invite is only used if the user is created, it is not found if it has expired or is used up.
+func (r *InviteRepository) CreateInvitedUser(ctx context.Context, codeHash string, user *dao.UserDAO) (*dao.UserDAO, error) {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The row is locked until the end of the transaction, concurrent registrations cannot exceed the uses
+	useQuery := r.db.QueryBuilder.Update("invites").
+		Set("uses", sq.Expr("uses + 1")).
+		Where(sq.Eq{"code_hash": codeHash}).
+		Where("uses < max_uses").
+		Where("expires_at > now()").
+		Suffix("RETURNING role")
+
+	useSQL, useArgs, err := useQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var role string
+	if err := tx.QueryRow(ctx, useSQL, useArgs...).Scan(&role); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	var userDao dao.UserDAO
+
+	userQuery := r.db.QueryBuilder.Insert("users").
+		Columns("name", "email", "password", "role").
+		Values(user.Name, user.Email, user.Password, role).
+		Suffix("RETURNING *")
+
+	userSQL, userArgs, err := userQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, userSQL, userArgs...).Scan(
+		&userDao.ID,
+		&userDao.Name,
+		&userDao.Email,
+		&userDao.Password,
+		&userDao.MasterPassword,
+		&userDao.Salt,
+		&userDao.IsVerified,
+		&userDao.Role,
+		&userDao.CreatedAt,
+		&userDao.UpdatedAt,
+		&userDao.SuspendedAt,
+		&userDao.ForcePasswordReset,
+		&userDao.ForceMFAEnrollment,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &userDao, nil
+}
+
+// scanInvite scans an invites row into the invite
+func scanInvite(row pgx.Row, inviteDAO *dao.InviteDAO) error {
+	return row.Scan(
+		&inviteDAO.ID,
+		&inviteDAO.CodeHash,
+		&inviteDAO.CreatedBy,
+		&inviteDAO.Role,
+		&inviteDAO.MaxUses,
+		&inviteDAO.Uses,
+		&inviteDAO.ExpiresAt,
+		&inviteDAO.CreatedAt,
+	)
+}
@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * MFARepository implements postgres.MFARepository interface
+ * and provides access to the PostgreSQL database
+ */
+type MFARepository struct {
+	db *database.DB
+}
+
+// NewMFARepository creates a new multi-factor authentication repository instance
+func NewMFARepository(db *database.DB) *MFARepository {
+	return &MFARepository{
+		db,
+	}
+}
+
+// UpsertTOTP stores a pending TOTP secret of a user, replacing a previous pending secret.
+// It fails with a conflict if TOTP is already enabled for the user.
+func (r *MFARepository) UpsertTOTP(ctx context.Context, totp *dao.TOTPDAO) (*dao.TOTPDAO, error) {
+	var totpDAO dao.TOTPDAO
+
+	query := r.db.QueryBuilder.Insert("users_totp").
+		Columns("user_id", "secret").
+		Values(totp.UserID, totp.Secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, updated_at = now() WHERE users_totp.enabled = false").
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&totpDAO.UserID,
+		&totpDAO.Secret,
+		&totpDAO.Enabled,
+		&totpDAO.CreatedAt,
+		&totpDAO.UpdatedAt,
+	)
+	if err != nil {
+		// The conflicting row was not updated because TOTP is enabled
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrConflictingData
+		}
+		if errCode := r.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &totpDAO, nil
+}
+
+// GetTOTPByUserID gets the TOTP secret of a user from the database
+func (r *MFARepository) GetTOTPByUserID(ctx context.Context, userID uuid.UUID) (*dao.TOTPDAO, error) {
+	var totpDAO dao.TOTPDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("users_totp").
+		Where(sq.Eq{"user_id": userID}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&totpDAO.UserID,
+		&totpDAO.Secret,
+		&totpDAO.Enabled,
+		&totpDAO.CreatedAt,
+		&totpDAO.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &totpDAO, nil
+}
+
+// UpdateTOTPSecret replaces the stored TOTP secret of a user, e.g. to store it encrypted
+func (r *MFARepository) UpdateTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	query := r.db.QueryBuilder.Update("users_totp").
+		Set("secret", secret).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EnableTOTP enables the pending TOTP secret of a user and replaces the backup codes with the given hashes
+func (r *MFARepository) EnableTOTP(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	enableQuery := r.db.QueryBuilder.Update("users_totp").
+		Set("enabled", true).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"user_id": userID, "enabled": false})
+
+	enableSQL, enableArgs, err := enableQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, enableSQL, enableArgs...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	if err := r.replaceBackupCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit(ctx)
+}
+
+// DeleteTOTP disables TOTP for a user and deletes the backup codes
+func (r *MFARepository) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.replaceBackupCodes(ctx, tx, userID, nil); err != nil {
+		return err
+	}
+
+	deleteQuery := r.db.QueryBuilder.Delete("users_totp").
+		Where(sq.Eq{"user_id": userID})
+
+	deleteSQL, deleteArgs, err := deleteQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, deleteSQL, deleteArgs...)
+	if err != nil {
+		return err
+	}
+
+	// Commit the transaction
+	return tx.Commit(ctx)
+}
+
+// CountUnusedBackupCodes counts the backup codes of a user that have not been used yet
+func (r *MFARepository) CountUnusedBackupCodes(ctx context.Context, userID uuid.UUID) (uint64, error) {
+	var count uint64
+
+	query := r.db.QueryBuilder.Select("COUNT(*)").
+		From("users_backup_codes").
+		Where(sq.Eq{"user_id": userID, "used_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// UseBackupCode marks an unused backup code of a user as used.
+// It returns domain.ErrDataNotFound if no unused backup code matches the hash.
+func (r *MFARepository) UseBackupCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	query := r.db.QueryBuilder.Update("users_backup_codes").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{"user_id": userID, "code_hash": codeHash, "used_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// replaceBackupCodes deletes the backup codes of a user and inserts the given hashes within a transaction
+func (r *MFARepository) replaceBackupCodes(ctx context.Context, tx pgx.Tx, userID uuid.UUID, codeHashes []string) error {
+	deleteQuery := r.db.QueryBuilder.Delete("users_backup_codes").
+		Where(sq.Eq{"user_id": userID})
+
+	deleteSQL, deleteArgs, err := deleteQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, deleteSQL, deleteArgs...)
+	if err != nil {
+		return err
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	insertQuery := r.db.QueryBuilder.Insert("users_backup_codes").
+		Columns("user_id", "code_hash")
+	for _, codeHash := range codeHashes {
+		insertQuery = insertQuery.Values(userID, codeHash)
+	}
+
+	insertSQL, insertArgs, err := insertQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, insertSQL, insertArgs...)
+	return err
+}
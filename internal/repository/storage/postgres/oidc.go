@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * OIDCRepository implements postgres.OIDCRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type OIDCRepository struct {
+	db *database.DB
+}
+
+// NewOIDCRepository creates a new OpenID Connect identity repository instance
+func NewOIDCRepository(db *database.DB) *OIDCRepository {
+	return &OIDCRepository{
+		db,
+	}
+}
+
+// CreateIdentity links an identity at an OpenID Connect provider to a user
+func (r *OIDCRepository) CreateIdentity(ctx context.Context, identity *dao.OIDCIdentityDAO) (*dao.OIDCIdentityDAO, error) {
+	var identityDAO dao.OIDCIdentityDAO
+
+	query := r.db.QueryBuilder.Insert("oidc_identities").
+		Columns("user_id", "issuer", "subject", "email").
+		Values(identity.UserID, identity.Issuer, identity.Subject, identity.Email).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanIdentity(r.db.QueryRow(ctx, sql, args...), &identityDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		} else if errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &identityDAO, nil
+}
+
+// GetIdentityBySubject selects an identity by its issuer and its subject at the issuer
+func (r *OIDCRepository) GetIdentityBySubject(ctx context.Context, issuer, subject string) (*dao.OIDCIdentityDAO, error) {
+	var identityDAO dao.OIDCIdentityDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("oidc_identities").
+		Where(sq.Eq{"issuer": issuer, "subject": subject}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanIdentity(r.db.QueryRow(ctx, sql, args...), &identityDAO)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &identityDAO, nil
+}
+
+// UpdateIdentityLogin records a login with an identity and the email given by the issuer
+func (r *OIDCRepository) UpdateIdentityLogin(ctx context.Context, id uuid.UUID, email string) error {
+	query := r.db.QueryBuilder.Update("oidc_identities").
+		Set("email", email).
+		Set("last_login_at", time.Now()).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// scanIdentity scans an oidc_identities row into the identity
+func scanIdentity(row pgx.Row, identityDAO *dao.OIDCIdentityDAO) error {
+	return row.Scan(
+		&identityDAO.ID,
+		&identityDAO.UserID,
+		&identityDAO.Issuer,
+		&identityDAO.Subject,
+		&identityDAO.Email,
+		&identityDAO.CreatedAt,
+		&identityDAO.LastLoginAt,
+	)
+}
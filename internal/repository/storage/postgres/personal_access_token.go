@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * PersonalAccessTokenRepository implements postgres.PersonalAccessTokenRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type PersonalAccessTokenRepository struct {
+	db *database.DB
+}
+
+// NewPersonalAccessTokenRepository creates a new personal access token repository instance
+func NewPersonalAccessTokenRepository(db *database.DB) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{
+		db,
+	}
+}
+
+// CreatePersonalAccessToken creates a new personal access token record in the database
+func (r *PersonalAccessTokenRepository) CreatePersonalAccessToken(ctx context.Context, token *dao.PersonalAccessTokenDAO) (*dao.PersonalAccessTokenDAO, error) {
+	var tokenDAO dao.PersonalAccessTokenDAO
+
+	query := r.db.QueryBuilder.Insert("personal_access_tokens").
+		Columns(
+			"user_id",
+			"name",
+			"token_hash",
+			"scopes",
+			"collection_ids",
+			"wrapped_key",
+			"expires_at",
+		).
+		Values(
+			token.UserID,
+			token.Name,
+			token.TokenHash,
+			token.Scopes,
+			token.CollectionIDs,
+			token.WrappedKey,
+			token.ExpiresAt,
+		).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanPersonalAccessToken(r.db.QueryRow(ctx, sql, args...), &tokenDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		} else if errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &tokenDAO, nil
+}
+
+// GetPersonalAccessTokenByHash selects a personal access token by the hash of the token
+func (r *PersonalAccessTokenRepository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*dao.PersonalAccessTokenDAO, error) {
+	var tokenDAO dao.PersonalAccessTokenDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("personal_access_tokens").
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanPersonalAccessToken(r.db.QueryRow(ctx, sql, args...), &tokenDAO)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &tokenDAO, nil
+}
+
+// ListPersonalAccessTokensByUserID lists the personal access tokens of a user
+func (r *PersonalAccessTokenRepository) ListPersonalAccessTokensByUserID(ctx context.Context, userID uuid.UUID) ([]dao.PersonalAccessTokenDAO, error) {
+	var tokensDAO []dao.PersonalAccessTokenDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("personal_access_tokens").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tokenDAO dao.PersonalAccessTokenDAO
+		if err := scanPersonalAccessToken(rows, &tokenDAO); err != nil {
+			return nil, err
+		}
+
+		tokensDAO = append(tokensDAO, tokenDAO)
+	}
+
+	return tokensDAO, rows.Err()
+}
+
+// UpdatePersonalAccessTokenUsage records the use of a personal access token
+func (r *PersonalAccessTokenRepository) UpdatePersonalAccessTokenUsage(ctx context.Context, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Update("personal_access_tokens").
+		Set("last_used_at", time.Now()).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// DeletePersonalAccessToken deletes a personal access token of a user
+func (r *PersonalAccessTokenRepository) DeletePersonalAccessToken(ctx context.Context, userID, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Delete("personal_access_tokens").
+		Where(sq.Eq{"id": id, "user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// DeletePersonalAccessTokensByUserID deletes all personal access tokens of a user
+func (r *PersonalAccessTokenRepository) DeletePersonalAccessTokensByUserID(ctx context.Context, userID uuid.UUID) error {
+	query := r.db.QueryBuilder.Delete("personal_access_tokens").
+		Where(sq.Eq{"user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, sql, args...)
+	return err
+}
+
+// scanPersonalAccessToken scans a personal_access_tokens row into the token
+func scanPersonalAccessToken(row pgx.Row, tokenDAO *dao.PersonalAccessTokenDAO) error {
+	return row.Scan(
+		&tokenDAO.ID,
+		&tokenDAO.UserID,
+		&tokenDAO.Name,
+		&tokenDAO.TokenHash,
+		&tokenDAO.Scopes,
+		&tokenDAO.CollectionIDs,
+		&tokenDAO.WrappedKey,
+		&tokenDAO.ExpiresAt,
+		&tokenDAO.LastUsedAt,
+		&tokenDAO.CreatedAt,
+	)
+}
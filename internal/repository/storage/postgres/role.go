@@ -0,0 +1,309 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * RoleRepository implements postgres.RoleRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type RoleRepository struct {
+	db *database.DB
+}
+
+// NewRoleRepository creates a new role repository instance
+func NewRoleRepository(db *database.DB) *RoleRepository {
+	return &RoleRepository{
+		db,
+	}
+}
+
+// CreateRole creates a new role in the database
+func (r *RoleRepository) CreateRole(ctx context.Context, role *dao.RoleDAO) (*dao.RoleDAO, error) {
+	var roleDAO dao.RoleDAO
+
+	query := r.db.QueryBuilder.Insert("roles").
+		Columns("name", "description", "permissions", "scim_managed").
+		Values(role.Name, role.Description, role.Permissions, role.SCIMManaged).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRole(r.db.QueryRow(ctx, sql, args...), &roleDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &roleDAO, nil
+}
+
+// GetRoleByID selects a role by ID from the database
+func (r *RoleRepository) GetRoleByID(ctx context.Context, id uuid.UUID) (*dao.RoleDAO, error) {
+	return r.getRole(ctx, sq.Eq{"id": id})
+}
+
+// GetRoleByName selects a role by name from the database
+func (r *RoleRepository) GetRoleByName(ctx context.Context, name string) (*dao.RoleDAO, error) {
+	return r.getRole(ctx, sq.Eq{"name": name})
+}
+
+// ListRoles lists all roles from the database
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]dao.RoleDAO, error) {
+	query := r.db.QueryBuilder.Select("*").
+		From("roles").
+		OrderBy("name")
+
+	return r.listRoles(ctx, query)
+}
+
+// ListSCIMManagedRoles lists the roles created as groups by the identity provider
+func (r *RoleRepository) ListSCIMManagedRoles(ctx context.Context) ([]dao.RoleDAO, error) {
+	query := r.db.QueryBuilder.Select("*").
+		From("roles").
+		Where(sq.Eq{"scim_managed": true}).
+		OrderBy("name")
+
+	return r.listRoles(ctx, query)
+}
+
+// ListRolesByUserID lists the roles assigned to a user
+func (r *RoleRepository) ListRolesByUserID(ctx context.Context, userID uuid.UUID) ([]dao.RoleDAO, error) {
+	query := r.db.QueryBuilder.Select("r.*").
+		From("roles r").
+		Join("user_roles ur ON ur.role_id = r.id").
+		Where(sq.Eq{"ur.user_id": userID}).
+		OrderBy("r.name")
+
+	return r.listRoles(ctx, query)
+}
+
+// ListRoleMemberIDs lists the IDs of the users a role is assigned to
+func (r *RoleRepository) ListRoleMemberIDs(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+
+	query := r.db.QueryBuilder.Select("user_id").
+		From("user_roles").
+		Where(sq.Eq{"role_id": roleID}).
+		OrderBy("user_id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// UpdateRole updates a role by ID in the database
+func (r *RoleRepository) UpdateRole(ctx context.Context, role *dao.RoleDAO) (*dao.RoleDAO, error) {
+	var roleDAO dao.RoleDAO
+
+	query := r.db.QueryBuilder.Update("roles").
+		Set("name", role.Name).
+		Set("description", role.Description).
+		Set("permissions", role.Permissions).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": role.ID}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRole(r.db.QueryRow(ctx, sql, args...), &roleDAO)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &roleDAO, nil
+}
+
+// DeleteRole deletes a role by ID from the database, it is unassigned from its users
+func (r *RoleRepository) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Delete("roles").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// SetUserRoles replaces the roles assigned to a user
+func (r *RoleRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) error {
+	assignments := make([][2]uuid.UUID, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		assignments = append(assignments, [2]uuid.UUID{userID, roleID})
+	}
+
+	return r.replaceAssignments(ctx, sq.Eq{"user_id": userID}, assignments)
+}
+
+// SetRoleMembers replaces the users a role is assigned to
+func (r *RoleRepository) SetRoleMembers(ctx context.Context, roleID uuid.UUID, userIDs []uuid.UUID) error {
+	assignments := make([][2]uuid.UUID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		assignments = append(assignments, [2]uuid.UUID{userID, roleID})
+	}
+
+	return r.replaceAssignments(ctx, sq.Eq{"role_id": roleID}, assignments)
+}
+
+// replaceAssignments replaces the role assignments matching a condition with the given user and role ID pairs
+func (r *RoleRepository) replaceAssignments(ctx context.Context, where sq.Eq, assignments [][2]uuid.UUID) error {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	deleteQuery := r.db.QueryBuilder.Delete("user_roles").
+		Where(where)
+
+	deleteSQL, deleteArgs, err := deleteQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, deleteSQL, deleteArgs...); err != nil {
+		return err
+	}
+
+	if len(assignments) > 0 {
+		insertQuery := r.db.QueryBuilder.Insert("user_roles").
+			Columns("user_id", "role_id")
+		for _, assignment := range assignments {
+			insertQuery = insertQuery.Values(assignment[0], assignment[1])
+		}
+
+		insertSQL, insertArgs, err := insertQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, insertSQL, insertArgs...); err != nil {
+			if errCode := r.db.ErrorCode(err); errCode == "23503" {
+				return domain.ErrDataNotFound
+			} else if errCode == "23505" {
+				return domain.ErrConflictingData
+			}
+			return err
+		}
+	}
+
+	// Commit the transaction
+	return tx.Commit(ctx)
+}
+
+// getRole selects the role matching a condition
+func (r *RoleRepository) getRole(ctx context.Context, where sq.Eq) (*dao.RoleDAO, error) {
+	var roleDAO dao.RoleDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("roles").
+		Where(where).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRole(r.db.QueryRow(ctx, sql, args...), &roleDAO)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &roleDAO, nil
+}
+
+// listRoles selects the roles of a query
+func (r *RoleRepository) listRoles(ctx context.Context, query sq.SelectBuilder) ([]dao.RoleDAO, error) {
+	var rolesDAO []dao.RoleDAO
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var roleDAO dao.RoleDAO
+		if err := scanRole(rows, &roleDAO); err != nil {
+			return nil, err
+		}
+
+		rolesDAO = append(rolesDAO, roleDAO)
+	}
+
+	return rolesDAO, rows.Err()
+}
+
+// scanRole scans a roles row into the role
+func scanRole(row pgx.Row, roleDAO *dao.RoleDAO) error {
+	return row.Scan(
+		&roleDAO.ID,
+		&roleDAO.Name,
+		&roleDAO.Description,
+		&roleDAO.Permissions,
+		&roleDAO.CreatedAt,
+		&roleDAO.UpdatedAt,
+		&roleDAO.SCIMManaged,
+	)
+}
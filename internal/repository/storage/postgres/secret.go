@@ -31,8 +31,8 @@ func (r *SecretRepository) CreateSecret(ctx context.Context, collectionID uuid.U
 	var createdSecret dao.SecretDAO
 
 	query := r.db.QueryBuilder.Insert("secrets").
-		Columns("collection_id", "secret_type", "name", "description", "created_by", "updated_by", "linked_secret_id").
-		Values(collectionID, secret.SecretType, secret.Name, secret.Description, secret.CreatedBy, secret.UpdatedBy, secret.LinkedSecretId).
+		Columns("collection_id", "folder_id", "secret_type", "name", "description", "created_by", "updated_by", "linked_secret_id", "reprompt").
+		Values(collectionID, nullUUID(secret.FolderID), secret.SecretType, secret.Name, secret.Description, secret.CreatedBy, secret.UpdatedBy, secret.LinkedSecretId, secret.Reprompt).
 		Suffix("RETURNING *")
 
 	sql, args, err := query.ToSql()
@@ -51,6 +51,8 @@ func (r *SecretRepository) CreateSecret(ctx context.Context, collectionID uuid.U
 		&createdSecret.CreatedAt,
 		&createdSecret.UpdatedAt,
 		&createdSecret.LinkedSecretId,
+		&createdSecret.FolderID,
+		&createdSecret.Reprompt,
 	)
 
 	if err != nil {
@@ -85,6 +87,8 @@ func (r *SecretRepository) GetSecretByID(ctx context.Context, id uuid.UUID) (*da
 		&secret.CreatedAt,
 		&secret.UpdatedAt,
 		&secret.LinkedSecretId,
+		&secret.FolderID,
+		&secret.Reprompt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -96,13 +100,188 @@ func (r *SecretRepository) GetSecretByID(ctx context.Context, id uuid.UUID) (*da
 	return &secret, nil
 }
 
-// ListSecretsByCollectionID selects a list of secrets for a specific collection ID
-func (r *SecretRepository) ListSecretsByCollectionID(ctx context.Context, collectionID uuid.UUID, skip, limit uint64) ([]dao.SecretDAO, error) {
+// ListSecretsByCollectionID selects a list of secrets for a specific collection ID.
+// An empty folderID means the collection root, recursive includes secrets of all nested folders.
+// A non-empty tag only selects the secrets having a tag of the user with this name.
+func (r *SecretRepository) ListSecretsByCollectionID(ctx context.Context, userID, collectionID, folderID uuid.UUID, recursive bool, tag string, skip, limit uint64) ([]dao.SecretDAO, error) {
 	var secrets []dao.SecretDAO
 
 	query := r.db.QueryBuilder.Select("*").From("secrets").
-		Where(sq.Eq{"collection_id": collectionID}).
-		Offset(skip).Limit(limit)
+		Where(sq.Eq{"collection_id": collectionID})
+
+	switch {
+	case folderID == uuid.Nil && !recursive:
+		query = query.Where(sq.Eq{"folder_id": nil})
+	case folderID != uuid.Nil && !recursive:
+		query = query.Where(sq.Eq{"folder_id": folderID})
+	case folderID != uuid.Nil && recursive:
+		query = query.Where("folder_id IN ("+folderSubtreeQuery+")", folderID)
+	}
+
+	if tag != "" {
+		query = query.Where("id IN (SELECT st.secret_id FROM secrets_tags st JOIN tags t ON t.id = st.tag_id WHERE t.name = ? AND t.user_id = ?)", tag, userID)
+	}
+
+	query = query.OrderBy("created_at DESC").Offset(skip).Limit(limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var secret dao.SecretDAO
+		err := rows.Scan(
+			&secret.ID,
+			&secret.CollectionID,
+			&secret.SecretType,
+			&secret.Name,
+			&secret.Description,
+			&secret.CreatedBy,
+			&secret.UpdatedBy,
+			&secret.CreatedAt,
+			&secret.UpdatedAt,
+			&secret.LinkedSecretId,
+			&secret.FolderID,
+			&secret.Reprompt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+// SearchSecrets searches the secrets of all collections the user is a member of.
+// Names, descriptions, URLs and logins are matched by word prefix, the best ranked secrets come first.
+func (r *SecretRepository) SearchSecrets(ctx context.Context, userID uuid.UUID, text string, skip, limit uint64) ([]dao.SecretDAO, error) {
+	var secrets []dao.SecretDAO
+
+	tsQuery := prefixTSQuery(text)
+	if tsQuery == "" {
+		return secrets, nil
+	}
+
+	query := r.db.QueryBuilder.Select(
+		"s.id", "s.collection_id", "s.secret_type", "s.name", "s.description", "s.created_by",
+		"s.updated_by", "s.created_at", "s.updated_at", "s.linked_secret_id", "s.folder_id", "s.reprompt",
+	).
+		From("secrets s").
+		Join("secrets_search ss ON ss.secret_id = s.id").
+		Join("users_collections uc ON uc.collection_id = s.collection_id").
+		Where(sq.Eq{"uc.user_id": userID}).
+		Where("ss.document @@ to_tsquery('simple', ?)", tsQuery).
+		OrderByClause("ts_rank(ss.document, to_tsquery('simple', ?)) DESC", tsQuery).
+		OrderBy("s.updated_at DESC").
+		Offset(skip).
+		Limit(limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var secret dao.SecretDAO
+		err := rows.Scan(
+			&secret.ID,
+			&secret.CollectionID,
+			&secret.SecretType,
+			&secret.Name,
+			&secret.Description,
+			&secret.CreatedBy,
+			&secret.UpdatedBy,
+			&secret.CreatedAt,
+			&secret.UpdatedAt,
+			&secret.LinkedSecretId,
+			&secret.FolderID,
+			&secret.Reprompt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+// SetBlindIndexes replaces the blind indexes of a secret
+func (r *SecretRepository) SetBlindIndexes(ctx context.Context, secretID uuid.UUID, indexes []dao.BlindIndexDAO) error {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	deleteQuery := r.db.QueryBuilder.Delete("secret_blind_indexes").
+		Where(sq.Eq{"secret_id": secretID})
+
+	deleteSQL, deleteArgs, err := deleteQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, deleteSQL, deleteArgs...)
+	if err != nil {
+		return err
+	}
+
+	if len(indexes) > 0 {
+		insertQuery := r.db.QueryBuilder.Insert("secret_blind_indexes").
+			Columns("secret_id", "field", "value")
+		for _, index := range indexes {
+			insertQuery = insertQuery.Values(secretID, index.Field, index.Value)
+		}
+
+		insertSQL, insertArgs, err := insertQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, insertSQL, insertArgs...)
+		if err != nil {
+			if errCode := r.db.ErrorCode(err); errCode == "23503" {
+				return domain.ErrDataNotFound
+			}
+			return err
+		}
+	}
+
+	// Commit the transaction
+	return tx.Commit(ctx)
+}
+
+// LookupSecretsByBlindIndex selects the secrets of the user's collections having the given blind index
+func (r *SecretRepository) LookupSecretsByBlindIndex(ctx context.Context, userID uuid.UUID, index *dao.BlindIndexDAO, skip, limit uint64) ([]dao.SecretDAO, error) {
+	var secrets []dao.SecretDAO
+
+	query := r.db.QueryBuilder.Select(
+		"s.id", "s.collection_id", "s.secret_type", "s.name", "s.description", "s.created_by",
+		"s.updated_by", "s.created_at", "s.updated_at", "s.linked_secret_id", "s.folder_id", "s.reprompt",
+	).
+		From("secrets s").
+		Join("secret_blind_indexes bi ON bi.secret_id = s.id").
+		Join("users_collections uc ON uc.collection_id = s.collection_id").
+		Where(sq.Eq{"uc.user_id": userID, "bi.field": index.Field, "bi.value": index.Value}).
+		OrderBy("s.updated_at DESC").
+		Offset(skip).
+		Limit(limit)
 
 	sql, args, err := query.ToSql()
 	if err != nil {
@@ -128,6 +307,8 @@ func (r *SecretRepository) ListSecretsByCollectionID(ctx context.Context, collec
 			&secret.CreatedAt,
 			&secret.UpdatedAt,
 			&secret.LinkedSecretId,
+			&secret.FolderID,
+			&secret.Reprompt,
 		)
 		if err != nil {
 			return nil, err
@@ -146,6 +327,7 @@ func (r *SecretRepository) UpdateSecret(ctx context.Context, secret *dao.SecretD
 		Set("collection_id", secret.CollectionID).
 		Set("name", secret.Name).
 		Set("description", secret.Description).
+		Set("reprompt", secret.Reprompt).
 		Set("updated_at", secret.UpdatedAt).
 		Set("updated_by", secret.UpdatedBy).
 		Where(sq.Eq{"id": secret.ID}).
@@ -167,6 +349,8 @@ func (r *SecretRepository) UpdateSecret(ctx context.Context, secret *dao.SecretD
 		&updatedSecret.CreatedAt,
 		&updatedSecret.UpdatedAt,
 		&updatedSecret.LinkedSecretId,
+		&updatedSecret.FolderID,
+		&updatedSecret.Reprompt,
 	)
 	if err != nil {
 		return nil, err
@@ -175,6 +359,161 @@ func (r *SecretRepository) UpdateSecret(ctx context.Context, secret *dao.SecretD
 	return &updatedSecret, nil
 }
 
+// MoveSecret moves a secret to another collection and/or folder.
+// The move is done in a single transaction that locks the secret row and checks
+// that it still belongs to the source collection.
+func (r *SecretRepository) MoveSecret(ctx context.Context, fromCollectionID uuid.UUID, secret *dao.SecretDAO) (*dao.SecretDAO, error) {
+	var movedSecret dao.SecretDAO
+
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the secret so concurrent moves of the same secret are serialized
+	lockQuery := r.db.QueryBuilder.Select("collection_id").
+		From("secrets").
+		Where(sq.Eq{"id": secret.ID}).
+		Suffix("FOR UPDATE")
+
+	lockSQL, lockArgs, err := lockQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var currentCollectionID uuid.UUID
+	err = tx.QueryRow(ctx, lockSQL, lockArgs...).Scan(&currentCollectionID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	if currentCollectionID != fromCollectionID {
+		return nil, domain.ErrDataNotFound
+	}
+
+	updateQuery := r.db.QueryBuilder.Update("secrets").
+		Set("collection_id", secret.CollectionID).
+		Set("folder_id", nullUUID(secret.FolderID)).
+		Set("updated_at", secret.UpdatedAt).
+		Set("updated_by", secret.UpdatedBy).
+		Where(sq.Eq{"id": secret.ID}).
+		Suffix("RETURNING *")
+
+	updateSQL, updateArgs, err := updateQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, updateSQL, updateArgs...).Scan(
+		&movedSecret.ID,
+		&movedSecret.CollectionID,
+		&movedSecret.SecretType,
+		&movedSecret.Name,
+		&movedSecret.Description,
+		&movedSecret.CreatedBy,
+		&movedSecret.UpdatedBy,
+		&movedSecret.CreatedAt,
+		&movedSecret.UpdatedAt,
+		&movedSecret.LinkedSecretId,
+		&movedSecret.FolderID,
+		&movedSecret.Reprompt,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &movedSecret, nil
+}
+
+// CopySecret creates a copy of a secret together with its already encrypted payload.
+// The payload and the secret are inserted in a single transaction.
+func (r *SecretRepository) CopySecret(ctx context.Context, secret *dao.SecretDAO) (*dao.SecretDAO, error) {
+	var copiedSecret dao.SecretDAO
+
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var payloadQuery sq.InsertBuilder
+	switch secret.SecretType {
+	case dao.PasswordSecretType:
+		payloadQuery = r.db.QueryBuilder.Insert("password_secrets").
+			Columns("url", "login", "password").
+			Values(secret.PasswordSecret.URL, secret.PasswordSecret.Login, secret.PasswordSecret.Password).
+			Suffix("RETURNING id")
+	case dao.TextSecretType:
+		payloadQuery = r.db.QueryBuilder.Insert("text_secrets").
+			Columns("text").
+			Values(secret.TextSecret.Text).
+			Suffix("RETURNING id")
+	default:
+		return nil, domain.ErrInvalidSecretType
+	}
+
+	payloadSQL, payloadArgs, err := payloadQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var linkedSecretID uuid.UUID
+	if err := tx.QueryRow(ctx, payloadSQL, payloadArgs...).Scan(&linkedSecretID); err != nil {
+		return nil, err
+	}
+
+	secretQuery := r.db.QueryBuilder.Insert("secrets").
+		Columns("collection_id", "folder_id", "secret_type", "name", "description", "created_by", "updated_by", "linked_secret_id", "reprompt").
+		Values(secret.CollectionID, nullUUID(secret.FolderID), secret.SecretType, secret.Name, secret.Description, secret.CreatedBy, secret.UpdatedBy, linkedSecretID, secret.Reprompt).
+		Suffix("RETURNING *")
+
+	secretSQL, secretArgs, err := secretQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, secretSQL, secretArgs...).Scan(
+		&copiedSecret.ID,
+		&copiedSecret.CollectionID,
+		&copiedSecret.SecretType,
+		&copiedSecret.Name,
+		&copiedSecret.Description,
+		&copiedSecret.CreatedBy,
+		&copiedSecret.UpdatedBy,
+		&copiedSecret.CreatedAt,
+		&copiedSecret.UpdatedAt,
+		&copiedSecret.LinkedSecretId,
+		&copiedSecret.FolderID,
+		&copiedSecret.Reprompt,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &copiedSecret, nil
+}
+
 // DeleteSecret deletes a secret
 func (r *SecretRepository) DeleteSecret(ctx context.Context, id uuid.UUID) error {
 	query := r.db.QueryBuilder.Delete("secrets").Where(sq.Eq{"id": id})
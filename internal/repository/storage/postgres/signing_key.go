@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * SigningKeyRepository implements postgres.SigningKeyRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type SigningKeyRepository struct {
+	db *database.DB
+}
+
+// NewSigningKeyRepository creates a new JWT signing key repository instance
+func NewSigningKeyRepository(db *database.DB) *SigningKeyRepository {
+	return &SigningKeyRepository{
+		db,
+	}
+}
+
+// CreateSigningKey inserts the first signing key.
+// It returns domain.ErrConflictingData if another key is already signing.
+func (r *SigningKeyRepository) CreateSigningKey(ctx context.Context, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error) {
+	var keyDAO dao.SigningKeyDAO
+
+	query := r.db.QueryBuilder.Insert("signing_keys").
+		Columns("algorithm", "private_key", "public_key").
+		Values(key.Algorithm, key.PrivateKey, key.PublicKey).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanSigningKey(r.db.QueryRow(ctx, sql, args...), &keyDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &keyDAO, nil
+}
+
+// RotateSigningKey retires the current signing key, keeping it for verification until the given time,
+// and inserts the key replacing it. The expired keys are deleted.
+// It returns domain.ErrConflictingData if the current key has already been rotated.
+func (r *SigningKeyRepository) RotateSigningKey(ctx context.Context, currentID uuid.UUID, verifyUntil time.Time, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error) {
+	var keyDAO dao.SigningKeyDAO
+
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+
+	deleteQuery := r.db.QueryBuilder.Delete("signing_keys").
+		Where(sq.Lt{"expires_at": now})
+
+	deleteSQL, deleteArgs, err := deleteQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, deleteSQL, deleteArgs...); err != nil {
+		return nil, err
+	}
+
+	retireQuery := r.db.QueryBuilder.Update("signing_keys").
+		Set("retired_at", now).
+		Set("expires_at", verifyUntil).
+		Where(sq.Eq{"id": currentID, "retired_at": nil})
+
+	retireSQL, retireArgs, err := retireQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, retireSQL, retireArgs...)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrConflictingData
+	}
+
+	insertQuery := r.db.QueryBuilder.Insert("signing_keys").
+		Columns("algorithm", "private_key", "public_key").
+		Values(key.Algorithm, key.PrivateKey, key.PublicKey).
+		Suffix("RETURNING *")
+
+	insertSQL, insertArgs, err := insertQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanSigningKey(tx.QueryRow(ctx, insertSQL, insertArgs...), &keyDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	// Commit the transaction
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyDAO, nil
+}
+
+// ListSigningKeys lists the signing key and the retired keys that have not expired, newest first
+func (r *SigningKeyRepository) ListSigningKeys(ctx context.Context) ([]dao.SigningKeyDAO, error) {
+	var keysDAO []dao.SigningKeyDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("signing_keys").
+		Where(sq.Or{
+			sq.Eq{"retired_at": nil},
+			sq.Gt{"expires_at": time.Now()},
+		}).
+		OrderBy("created_at DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var keyDAO dao.SigningKeyDAO
+		if err := scanSigningKey(rows, &keyDAO); err != nil {
+			return nil, err
+		}
+
+		keysDAO = append(keysDAO, keyDAO)
+	}
+
+	return keysDAO, rows.Err()
+}
+
+// scanSigningKey scans a signing_keys row into the signing key
+func scanSigningKey(row pgx.Row, keyDAO *dao.SigningKeyDAO) error {
+	return row.Scan(
+		&keyDAO.ID,
+		&keyDAO.Algorithm,
+		&keyDAO.PrivateKey,
+		&keyDAO.PublicKey,
+		&keyDAO.CreatedAt,
+		&keyDAO.RetiredAt,
+		&keyDAO.ExpiresAt,
+	)
+}
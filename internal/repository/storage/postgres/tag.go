@@ -0,0 +1,332 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * TagRepository implements postgres.TagRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type TagRepository struct {
+	db *database.DB
+}
+
+// NewTagRepository creates a new tag repository instance
+func NewTagRepository(db *database.DB) *TagRepository {
+	return &TagRepository{
+		db,
+	}
+}
+
+// CreateTag creates a new tag in the database
+func (r *TagRepository) CreateTag(ctx context.Context, tag *dao.TagDAO) (*dao.TagDAO, error) {
+	var tagDAO dao.TagDAO
+
+	query := r.db.QueryBuilder.Insert("tags").
+		Columns("user_id", "name").
+		Values(tag.UserID, tag.Name).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&tagDAO.ID,
+		&tagDAO.UserID,
+		&tagDAO.Name,
+		&tagDAO.CreatedAt,
+		&tagDAO.UpdatedAt,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &tagDAO, nil
+}
+
+// GetTagByID gets a tag by ID from the database
+func (r *TagRepository) GetTagByID(ctx context.Context, id uuid.UUID) (*dao.TagDAO, error) {
+	var tagDAO dao.TagDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("tags").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&tagDAO.ID,
+		&tagDAO.UserID,
+		&tagDAO.Name,
+		&tagDAO.CreatedAt,
+		&tagDAO.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &tagDAO, nil
+}
+
+// ListTagsByUserID lists the tags of a user ordered by name
+func (r *TagRepository) ListTagsByUserID(ctx context.Context, userID uuid.UUID) ([]dao.TagDAO, error) {
+	var tagsDAO []dao.TagDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("tags").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tagDAO dao.TagDAO
+		err := rows.Scan(
+			&tagDAO.ID,
+			&tagDAO.UserID,
+			&tagDAO.Name,
+			&tagDAO.CreatedAt,
+			&tagDAO.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		tagsDAO = append(tagsDAO, tagDAO)
+	}
+
+	return tagsDAO, nil
+}
+
+// UpdateTag renames a tag
+func (r *TagRepository) UpdateTag(ctx context.Context, tag *dao.TagDAO) (*dao.TagDAO, error) {
+	var tagDAO dao.TagDAO
+
+	query := r.db.QueryBuilder.Update("tags").
+		Set("name", tag.Name).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": tag.ID}).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&tagDAO.ID,
+		&tagDAO.UserID,
+		&tagDAO.Name,
+		&tagDAO.CreatedAt,
+		&tagDAO.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &tagDAO, nil
+}
+
+// DeleteTag deletes a tag, the tag is detached from all secrets by cascade
+func (r *TagRepository) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Delete("tags").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, sql, args...)
+	return err
+}
+
+// GetTagCloud counts the secrets using each tag of a user.
+// Only the secrets of the collections the user is a member of are counted.
+func (r *TagRepository) GetTagCloud(ctx context.Context, userID uuid.UUID) ([]dao.TagCountDAO, error) {
+	var tagCountsDAO []dao.TagCountDAO
+
+	query := r.db.QueryBuilder.Select("t.name", "COUNT(st.secret_id)").
+		From("tags t").
+		Join("secrets_tags st ON st.tag_id = t.id").
+		Join("secrets s ON s.id = st.secret_id").
+		Join("users_collections uc ON uc.collection_id = s.collection_id AND uc.user_id = t.user_id").
+		Where(sq.Eq{"t.user_id": userID}).
+		GroupBy("t.name").
+		OrderBy("COUNT(st.secret_id) DESC", "t.name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tagCountDAO dao.TagCountDAO
+		err := rows.Scan(
+			&tagCountDAO.Name,
+			&tagCountDAO.Count,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		tagCountsDAO = append(tagCountsDAO, tagCountDAO)
+	}
+
+	return tagCountsDAO, nil
+}
+
+// SetSecretTags replaces the tags of a secret.
+// Missing tags are created for the user, an empty list removes all tags from the secret.
+func (r *TagRepository) SetSecretTags(ctx context.Context, userID, secretID uuid.UUID, names []string) error {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Create the tags the user does not have yet
+	if len(names) > 0 {
+		tagsQuery := r.db.QueryBuilder.Insert("tags").
+			Columns("user_id", "name").
+			Suffix("ON CONFLICT (user_id, name) DO NOTHING")
+		for _, name := range names {
+			tagsQuery = tagsQuery.Values(userID, name)
+		}
+
+		tagsSQL, tagsArgs, err := tagsQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, tagsSQL, tagsArgs...)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Detach the current tags of the user, the other members of the collection keep theirs
+	deleteQuery := r.db.QueryBuilder.Delete("secrets_tags").
+		Where(sq.Eq{"secret_id": secretID}).
+		Where("tag_id IN (SELECT id FROM tags WHERE user_id = ?)", userID)
+
+	deleteSQL, deleteArgs, err := deleteQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, deleteSQL, deleteArgs...)
+	if err != nil {
+		return err
+	}
+
+	// Attach the new tags
+	if len(names) > 0 {
+		tagIDsQuery := r.db.QueryBuilder.Select().
+			Column(sq.Expr("?::uuid", secretID)).
+			Column("id").
+			From("tags").
+			Where(sq.Eq{"user_id": userID, "name": names})
+
+		secretsTagsQuery := r.db.QueryBuilder.Insert("secrets_tags").
+			Columns("secret_id", "tag_id").
+			Select(tagIDsQuery)
+
+		secretsTagsSQL, secretsTagsArgs, err := secretsTagsQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, secretsTagsSQL, secretsTagsArgs...)
+		if err != nil {
+			if errCode := r.db.ErrorCode(err); errCode == "23503" {
+				return domain.ErrDataNotFound
+			}
+			return err
+		}
+	}
+
+	// Commit the transaction
+	return tx.Commit(ctx)
+}
+
+// ListTagNamesBySecretIDs returns the names of the user's tags of each secret ordered by name
+func (r *TagRepository) ListTagNamesBySecretIDs(ctx context.Context, userID uuid.UUID, secretIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tags := make(map[uuid.UUID][]string, len(secretIDs))
+	if len(secretIDs) == 0 {
+		return tags, nil
+	}
+
+	query := r.db.QueryBuilder.Select("st.secret_id", "t.name").
+		Distinct().
+		From("secrets_tags st").
+		Join("tags t ON t.id = st.tag_id").
+		Where(sq.Eq{"st.secret_id": secretIDs, "t.user_id": userID}).
+		OrderBy("t.name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var secretID uuid.UUID
+		var name string
+		if err := rows.Scan(&secretID, &name); err != nil {
+			return nil, err
+		}
+
+		tags[secretID] = append(tags[secretID], name)
+	}
+
+	return tags, nil
+}
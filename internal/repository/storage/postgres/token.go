@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * TokenRepository implements postgres.TokenRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type TokenRepository struct {
+	db *database.DB
+}
+
+// NewTokenRepository creates a new refresh token family repository instance
+func NewTokenRepository(db *database.DB) *TokenRepository {
+	return &TokenRepository{
+		db,
+	}
+}
+
+// CreateTokenFamily creates a new refresh token family record in the database
+func (r *TokenRepository) CreateTokenFamily(ctx context.Context, family *dao.TokenFamilyDAO) (*dao.TokenFamilyDAO, error) {
+	var familyDAO dao.TokenFamilyDAO
+
+	query := r.db.QueryBuilder.Insert("refresh_token_families").
+		Columns("user_id", "current_token_id", "device_name", "user_agent", "ip_address", "authenticated_at").
+		Values(family.UserID, family.CurrentTokenID, family.DeviceName, family.UserAgent, family.IPAddress, family.AuthenticatedAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanTokenFamily(r.db.QueryRow(ctx, sql, args...), &familyDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &familyDAO, nil
+}
+
+// GetTokenFamilyByID gets a refresh token family by id from the database
+func (r *TokenRepository) GetTokenFamilyByID(ctx context.Context, id uuid.UUID) (*dao.TokenFamilyDAO, error) {
+	var familyDAO dao.TokenFamilyDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("refresh_token_families").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanTokenFamily(r.db.QueryRow(ctx, sql, args...), &familyDAO)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &familyDAO, nil
+}
+
+// ListTokenFamiliesByUserID lists the active refresh token families of a user used since the given time
+func (r *TokenRepository) ListTokenFamiliesByUserID(ctx context.Context, userID uuid.UUID, usedSince time.Time) ([]dao.TokenFamilyDAO, error) {
+	var familiesDAO []dao.TokenFamilyDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("refresh_token_families").
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil}).
+		Where(sq.Gt{"last_used_at": usedSince}).
+		OrderBy("last_used_at DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var familyDAO dao.TokenFamilyDAO
+		if err := scanTokenFamily(rows, &familyDAO); err != nil {
+			return nil, err
+		}
+
+		familiesDAO = append(familiesDAO, familyDAO)
+	}
+
+	return familiesDAO, rows.Err()
+}
+
+// RotateTokenFamily replaces the current refresh token of an active family and records its use.
+// It returns domain.ErrDataNotFound if the family is revoked or the given token is not the current one.
+func (r *TokenRepository) RotateTokenFamily(ctx context.Context, id, currentTokenID, newTokenID uuid.UUID, ipAddress string) error {
+	now := time.Now()
+
+	query := r.db.QueryBuilder.Update("refresh_token_families").
+		Set("current_token_id", newTokenID).
+		Set("ip_address", ipAddress).
+		Set("last_used_at", now).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": id, "current_token_id": currentTokenID, "revoked_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// RevokeTokenFamily revokes a refresh token family, none of its refresh tokens can be used anymore
+func (r *TokenRepository) RevokeTokenFamily(ctx context.Context, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Update("refresh_token_families").
+		Set("revoked_at", time.Now()).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "revoked_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, sql, args...)
+	return err
+}
+
+// scanTokenFamily scans a refresh_token_families row into the token family
+func scanTokenFamily(row pgx.Row, familyDAO *dao.TokenFamilyDAO) error {
+	return row.Scan(
+		&familyDAO.ID,
+		&familyDAO.UserID,
+		&familyDAO.CurrentTokenID,
+		&familyDAO.RevokedAt,
+		&familyDAO.CreatedAt,
+		&familyDAO.UpdatedAt,
+		&familyDAO.DeviceName,
+		&familyDAO.UserAgent,
+		&familyDAO.IPAddress,
+		&familyDAO.LastUsedAt,
+		&familyDAO.AuthenticatedAt,
+	)
+}
@@ -52,6 +52,48 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *dao.UserDAO) (*da
 		&userDao.Role,
 		&userDao.CreatedAt,
 		&userDao.UpdatedAt,
+		&userDao.SuspendedAt,
+		&userDao.ForcePasswordReset,
+		&userDao.ForceMFAEnrollment,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &userDao, nil
+}
+
+// ProvisionUser inserts a user with their verification and suspension in a single statement
+func (r *UserRepository) ProvisionUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error) {
+	var userDao dao.UserDAO
+
+	query := r.db.QueryBuilder.Insert("users").
+		Columns("name", "email", "password", "is_verified", "suspended_at").
+		Values(user.Name, user.Email, user.Password, user.IsVerified, user.SuspendedAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&userDao.ID,
+		&userDao.Name,
+		&userDao.Email,
+		&userDao.Password,
+		&userDao.MasterPassword,
+		&userDao.Salt,
+		&userDao.IsVerified,
+		&userDao.Role,
+		&userDao.CreatedAt,
+		&userDao.UpdatedAt,
+		&userDao.SuspendedAt,
+		&userDao.ForcePasswordReset,
+		&userDao.ForceMFAEnrollment,
 	)
 	if err != nil {
 		if errCode := r.db.ErrorCode(err); errCode == "23505" {
@@ -88,6 +130,9 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*dao.Us
 		&userDao.Role,
 		&userDao.CreatedAt,
 		&userDao.UpdatedAt,
+		&userDao.SuspendedAt,
+		&userDao.ForcePasswordReset,
+		&userDao.ForceMFAEnrollment,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -124,6 +169,9 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*dao
 		&userDao.Role,
 		&userDao.CreatedAt,
 		&userDao.UpdatedAt,
+		&userDao.SuspendedAt,
+		&userDao.ForcePasswordReset,
+		&userDao.ForceMFAEnrollment,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -169,6 +217,9 @@ func (r *UserRepository) ListUsers(ctx context.Context, skip, limit uint64) ([]d
 			&userDao.Role,
 			&userDao.CreatedAt,
 			&userDao.UpdatedAt,
+			&userDao.SuspendedAt,
+			&userDao.ForcePasswordReset,
+			&userDao.ForceMFAEnrollment,
 		)
 		if err != nil {
 			return nil, err
@@ -181,7 +232,28 @@ func (r *UserRepository) ListUsers(ctx context.Context, skip, limit uint64) ([]d
 	return usersDAO, nil
 }
 
-// UpdateUser updates a user by ID in the database
+// CountUsers counts all users in the database
+func (r *UserRepository) CountUsers(ctx context.Context) (uint64, error) {
+	var count uint64
+
+	query := r.db.QueryBuilder.Select("COUNT(*)").
+		From("users")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// UpdateUser updates a user by ID in the database.
+// Empty values are left unchanged, setting the password clears a forced password reset.
 func (r *UserRepository) UpdateUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error) {
 	var userDao dao.UserDAO
 
@@ -201,6 +273,8 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *dao.UserDAO) (*da
 		Set("salt", sq.Expr("COALESCE(?, salt)", salt)).
 		Set("is_verified", sq.Expr("COALESCE(?, is_verified)", isVerified)).
 		Set("role", sq.Expr("COALESCE(?, role)", role)).
+		// A new password completes a password reset forced by an admin
+		Set("force_password_reset", sq.Expr("force_password_reset AND ?::text IS NULL", password)).
 		Set("updated_at", time.Now()).
 		Where(sq.Eq{"id": user.ID}).
 		Suffix("RETURNING *")
@@ -221,6 +295,9 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *dao.UserDAO) (*da
 		&userDao.Role,
 		&userDao.CreatedAt,
 		&userDao.UpdatedAt,
+		&userDao.SuspendedAt,
+		&userDao.ForcePasswordReset,
+		&userDao.ForceMFAEnrollment,
 	)
 	if err != nil {
 		if errCode := r.db.ErrorCode(err); errCode == "23505" {
@@ -232,20 +309,189 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *dao.UserDAO) (*da
 	return &userDao, nil
 }
 
-// DeleteUser deletes a user by ID from the database
-func (r *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	query := r.db.QueryBuilder.Delete("users").
-		Where(sq.Eq{"id": id})
+// UpdateUserStatus sets the suspension and the forced actions of a user by ID in the database.
+// Unlike UpdateUser, the values are set as given, so they can be cleared.
+func (r *UserRepository) UpdateUserStatus(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error) {
+	var userDao dao.UserDAO
+
+	query := r.db.QueryBuilder.Update("users").
+		Set("suspended_at", user.SuspendedAt).
+		Set("force_password_reset", user.ForcePasswordReset).
+		Set("force_mfa_enrollment", user.ForceMFAEnrollment).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": user.ID}).
+		Suffix("RETURNING *")
 
 	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&userDao.ID,
+		&userDao.Name,
+		&userDao.Email,
+		&userDao.Password,
+		&userDao.MasterPassword,
+		&userDao.Salt,
+		&userDao.IsVerified,
+		&userDao.Role,
+		&userDao.CreatedAt,
+		&userDao.UpdatedAt,
+		&userDao.SuspendedAt,
+		&userDao.ForcePasswordReset,
+		&userDao.ForceMFAEnrollment,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &userDao, nil
+}
+
+// DeleteUser deletes a user together with the handling of the collections they own, in one transaction.
+// With the transfer policy the collections are given to the new owner, with the delete policy they are
+// deleted unless other members share them, and with the block policy the user must own no collections.
+func (r *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID, policy domain.OwnedCollectionsPolicy, newOwnerID uuid.UUID) error {
+	// Begin a transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ownedQuery := "SELECT id FROM collections WHERE created_by = ?"
+
+	switch policy {
+	case domain.TransferOwnedCollections:
+		// Add the new owner to the members of the collections
+		usersCollectionsQuery := r.db.QueryBuilder.Insert("users_collections").
+			Columns("user_id", "collection_id").
+			Select(r.db.QueryBuilder.Select().
+				Column(sq.Expr("?::uuid", newOwnerID)).
+				Column("id").
+				From("collections").
+				Where(sq.Eq{"created_by": id})).
+			Suffix("ON CONFLICT DO NOTHING")
+
+		usersCollectionsSQL, usersCollectionsArgs, err := usersCollectionsQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, usersCollectionsSQL, usersCollectionsArgs...)
+		if err != nil {
+			if errCode := r.db.ErrorCode(err); errCode == "23503" {
+				return domain.ErrDataNotFound
+			}
+			return err
+		}
+
+		// Change the owner of the collections
+		collectionsQuery := r.db.QueryBuilder.Update("collections").
+			Set("created_by", newOwnerID).
+			Set("updated_by", newOwnerID).
+			Set("updated_at", time.Now()).
+			Where(sq.Eq{"created_by": id})
+
+		collectionsSQL, collectionsArgs, err := collectionsQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, collectionsSQL, collectionsArgs...)
+		if err != nil {
+			return err
+		}
+
+	case domain.DeleteOwnedCollections:
+		// The collections shared with other members are not deleted with their owner
+		sharedQuery := r.db.QueryBuilder.Select("1").
+			From("users_collections").
+			Where("collection_id IN ("+ownedQuery+")", id).
+			Where(sq.NotEq{"user_id": id}).
+			Limit(1)
+
+		sharedSQL, sharedArgs, err := sharedQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		var shared int
+		err = tx.QueryRow(ctx, sharedSQL, sharedArgs...).Scan(&shared)
+		if err == nil {
+			return domain.ErrUserOwnsSharedCollections
+		}
+		if err != pgx.ErrNoRows {
+			return err
+		}
+
+		// Delete links in users_collections associated with the collections
+		usersCollectionsQuery := r.db.QueryBuilder.Delete("users_collections").
+			Where("collection_id IN ("+ownedQuery+")", id)
+
+		usersCollectionsSQL, usersCollectionsArgs, err := usersCollectionsQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, usersCollectionsSQL, usersCollectionsArgs...)
+		if err != nil {
+			return err
+		}
+
+		// Delete the collections, folders and secrets are removed by cascade
+		collectionsQuery := r.db.QueryBuilder.Delete("collections").
+			Where(sq.Eq{"created_by": id})
+
+		collectionsSQL, collectionsArgs, err := collectionsQuery.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, collectionsSQL, collectionsArgs...)
+		if err != nil {
+			return err
+		}
+
+	default:
+		// Unknown policies are treated as block so no data is lost
+		ownedSQL, ownedArgs, err := r.db.QueryBuilder.Select("1").
+			From("collections").
+			Where(sq.Eq{"created_by": id}).
+			Limit(1).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		var owned int
+		err = tx.QueryRow(ctx, ownedSQL, ownedArgs...).Scan(&owned)
+		if err == nil {
+			return domain.ErrUserOwnsCollections
+		}
+		if err != pgx.ErrNoRows {
+			return err
+		}
+	}
+
+	// Delete the user, the memberships are removed by cascade
+	userQuery := r.db.QueryBuilder.Delete("users").
+		Where(sq.Eq{"id": id})
+
+	userSQL, userArgs, err := userQuery.ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.Exec(ctx, sql, args...)
+	_, err = tx.Exec(ctx, userSQL, userArgs...)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Commit the transaction
+	return tx.Commit(ctx)
 }
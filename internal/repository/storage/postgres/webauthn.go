@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/database"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+/**
+ * WebAuthnRepository implements postgres.WebAuthnRepository interface
+ * and provides access to the PostgreSQL database
+ */
+type WebAuthnRepository struct {
+	db *database.DB
+}
+
+// NewWebAuthnRepository creates a new WebAuthn credential repository instance
+func NewWebAuthnRepository(db *database.DB) *WebAuthnRepository {
+	return &WebAuthnRepository{
+		db,
+	}
+}
+
+// CreateCredential creates a new WebAuthn credential record in the database
+func (r *WebAuthnRepository) CreateCredential(ctx context.Context, credential *dao.WebAuthnCredentialDAO) (*dao.WebAuthnCredentialDAO, error) {
+	var credentialDAO dao.WebAuthnCredentialDAO
+
+	query := r.db.QueryBuilder.Insert("webauthn_credentials").
+		Columns(
+			"user_id",
+			"credential_id",
+			"public_key",
+			"attestation_type",
+			"aaguid",
+			"sign_count",
+			"transports",
+			"backup_eligible",
+			"backup_state",
+			"name",
+		).
+		Values(
+			credential.UserID,
+			credential.CredentialID,
+			credential.PublicKey,
+			credential.AttestationType,
+			credential.AAGUID,
+			credential.SignCount,
+			credential.Transports,
+			credential.BackupEligible,
+			credential.BackupState,
+			credential.Name,
+		).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanCredential(r.db.QueryRow(ctx, sql, args...), &credentialDAO)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		} else if errCode == "23503" {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &credentialDAO, nil
+}
+
+// ListCredentialsByUserID lists the WebAuthn credentials of a user
+func (r *WebAuthnRepository) ListCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]dao.WebAuthnCredentialDAO, error) {
+	var credentialsDAO []dao.WebAuthnCredentialDAO
+
+	query := r.db.QueryBuilder.Select("*").
+		From("webauthn_credentials").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var credentialDAO dao.WebAuthnCredentialDAO
+		if err := scanCredential(rows, &credentialDAO); err != nil {
+			return nil, err
+		}
+
+		credentialsDAO = append(credentialsDAO, credentialDAO)
+	}
+
+	return credentialsDAO, rows.Err()
+}
+
+// UpdateCredentialUsage stores the sign counter and the backup state of a credential after a login
+func (r *WebAuthnRepository) UpdateCredentialUsage(ctx context.Context, id uuid.UUID, signCount int64, backupState bool) error {
+	query := r.db.QueryBuilder.Update("webauthn_credentials").
+		Set("sign_count", signCount).
+		Set("backup_state", backupState).
+		Set("last_used_at", time.Now()).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// DeleteCredential deletes a WebAuthn credential of a user
+func (r *WebAuthnRepository) DeleteCredential(ctx context.Context, userID, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Delete("webauthn_credentials").
+		Where(sq.Eq{"id": id, "user_id": userID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// scanCredential scans a webauthn_credentials row into the credential
+func scanCredential(row pgx.Row, credentialDAO *dao.WebAuthnCredentialDAO) error {
+	return row.Scan(
+		&credentialDAO.ID,
+		&credentialDAO.UserID,
+		&credentialDAO.CredentialID,
+		&credentialDAO.PublicKey,
+		&credentialDAO.AttestationType,
+		&credentialDAO.AAGUID,
+		&credentialDAO.SignCount,
+		&credentialDAO.Transports,
+		&credentialDAO.BackupEligible,
+		&credentialDAO.BackupState,
+		&credentialDAO.Name,
+		&credentialDAO.CreatedAt,
+		&credentialDAO.LastUsedAt,
+	)
+}
@@ -9,12 +9,16 @@ import (
 type CacheRepository interface {
 	// Set stores the value in the cache
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetIfAbsent stores the value in the cache only if the key does not exist, it reports if the value was stored
+	SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
 	// Get retrieves the value from the cache
 	Get(ctx context.Context, key string) ([]byte, error)
 	// Delete removes the value from the cache
 	Delete(ctx context.Context, key string) error
 	// DeleteByPrefix removes the value from the cache with the given prefix
 	DeleteByPrefix(ctx context.Context, prefix string) error
+	// Increment increments the counter stored at the key, the ttl is set when the counter is created
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
 	// Exists checks if a key exists in the redis database
 	Exists(ctx context.Context, key string) (bool, error)
 	// Close closes the connection to the cache server
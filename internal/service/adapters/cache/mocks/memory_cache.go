@@ -0,0 +1,94 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-memory cache.CacheRepository ignoring the expirations,
+// for the tests where the values are kept between calls, e.g. counters and ceremony states
+type MemoryCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{values: make(map[string][]byte)}
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *MemoryCache) SetIfAbsent(_ context.Context, key string, value []byte, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = value
+	return true, nil
+}
+
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+	return value, nil
+}
+
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func (c *MemoryCache) DeleteByPrefix(_ context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.values {
+		if strings.HasPrefix(key, strings.TrimSuffix(prefix, "*")) {
+			delete(c.values, key)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) Increment(_ context.Context, key string, _ time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, _ := strconv.ParseInt(string(c.values[key]), 10, 64)
+	value++
+	c.values[key] = []byte(strconv.FormatInt(value, 10))
+	return value, nil
+}
+
+func (c *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.Get(ctx, key)
+	return err == nil, nil
+}
+
+func (c *MemoryCache) Close() error { return nil }
+
+// Keys returns the keys stored in the cache
+func (c *MemoryCache) Keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	return keys
+}
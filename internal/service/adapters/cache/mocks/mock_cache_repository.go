@@ -100,6 +100,27 @@ func (_m *CacheRepository) Get(ctx context.Context, key string) ([]byte, error)
 	return r0, r1
 }
 
+// Increment provides a mock function with given fields: ctx, key, ttl
+func (_m *CacheRepository) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	ret := _m.Called(ctx, key, ttl)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) int64); ok {
+		r0 = rf(ctx, key, ttl)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Set provides a mock function with given fields: ctx, key, value, ttl
 func (_m *CacheRepository) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	ret := _m.Called(ctx, key, value, ttl)
@@ -114,6 +135,27 @@ func (_m *CacheRepository) Set(ctx context.Context, key string, value []byte, tt
 	return r0
 }
 
+// SetIfAbsent provides a mock function with given fields: ctx, key, value, ttl
+func (_m *CacheRepository) SetIfAbsent(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, value, ttl)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, time.Duration) bool); ok {
+		r0 = rf(ctx, key, value, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte, time.Duration) error); ok {
+		r1 = rf(ctx, key, value, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewCacheRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"time"
 
+	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
 	"github.com/google/uuid"
 )
@@ -11,16 +13,22 @@ import (
 type UserRepository interface {
 	// CreateUser inserts a new user into the database
 	CreateUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error)
+	// ProvisionUser inserts a verified or suspended user in a single write
+	ProvisionUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error)
 	// GetUserByID selects a user by id
 	GetUserByID(ctx context.Context, id uuid.UUID) (*dao.UserDAO, error)
 	// GetUserByEmail selects a user by email
 	GetUserByEmail(ctx context.Context, email string) (*dao.UserDAO, error)
 	// ListUsers selects a list of users with pagination
 	ListUsers(ctx context.Context, skip, limit uint64) ([]dao.UserDAO, error)
+	// CountUsers counts all users
+	CountUsers(ctx context.Context) (uint64, error)
 	// UpdateUser updates a user
 	UpdateUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error)
-	// DeleteUser deletes a user
-	DeleteUser(ctx context.Context, id uuid.UUID) error
+	// UpdateUserStatus sets the suspension and the forced actions of a user
+	UpdateUserStatus(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error)
+	// DeleteUser deletes a user and applies the owned collections policy in one transaction
+	DeleteUser(ctx context.Context, id uuid.UUID, policy domain.OwnedCollectionsPolicy, newOwnerID uuid.UUID) error
 }
 
 // CollectionRepository is an interface for interacting with collection-related data
@@ -37,6 +45,24 @@ type CollectionRepository interface {
 	DeleteCollection(ctx context.Context, id uuid.UUID) error
 	// IsUserPartOfCollection checks if the user is part of the specified collection
 	IsUserPartOfCollection(ctx context.Context, userID, collectionID uuid.UUID) (bool, error)
+	// TransferCollectionOwnership makes another user the owner of a collection
+	TransferCollectionOwnership(ctx context.Context, collectionID, newOwnerID, updatedBy uuid.UUID) (*dao.CollectionDAO, error)
+}
+
+// FolderRepository is an interface for interacting with folder-related data
+type FolderRepository interface {
+	// CreateFolder inserts a new folder into the database
+	CreateFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error)
+	// GetFolderByID selects a folder by id
+	GetFolderByID(ctx context.Context, id uuid.UUID) (*dao.FolderDAO, error)
+	// ListFoldersByParentID selects the direct children of a folder, an empty parent ID selects the collection root
+	ListFoldersByParentID(ctx context.Context, collectionID, parentID uuid.UUID) ([]dao.FolderDAO, error)
+	// UpdateFolder updates a folder
+	UpdateFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error)
+	// MoveFolder changes the parent of a folder
+	MoveFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error)
+	// DeleteFolder deletes a folder with all nested folders and secrets
+	DeleteFolder(ctx context.Context, id uuid.UUID) error
 }
 
 // SecretRepository is an interface for interacting with secret-related data
@@ -45,10 +71,20 @@ type SecretRepository interface {
 	CreateSecret(ctx context.Context, collectionID uuid.UUID, secret *dao.SecretDAO) (*dao.SecretDAO, error)
 	// GetSecretByID selects a secret by id
 	GetSecretByID(ctx context.Context, id uuid.UUID) (*dao.SecretDAO, error)
-	// ListSecretsByCollectionID selects a list of secrets for a specific collection ID
-	ListSecretsByCollectionID(ctx context.Context, collectionID uuid.UUID, skip, limit uint64) ([]dao.SecretDAO, error)
+	// ListSecretsByCollectionID selects a list of secrets for a specific collection ID, folder and tag
+	ListSecretsByCollectionID(ctx context.Context, userID, collectionID, folderID uuid.UUID, recursive bool, tag string, skip, limit uint64) ([]dao.SecretDAO, error)
+	// SearchSecrets selects the secrets of the user's collections matching a full-text search
+	SearchSecrets(ctx context.Context, userID uuid.UUID, text string, skip, limit uint64) ([]dao.SecretDAO, error)
+	// SetBlindIndexes replaces the blind indexes of a secret
+	SetBlindIndexes(ctx context.Context, secretID uuid.UUID, indexes []dao.BlindIndexDAO) error
+	// LookupSecretsByBlindIndex selects the secrets of the user's collections having the given blind index
+	LookupSecretsByBlindIndex(ctx context.Context, userID uuid.UUID, index *dao.BlindIndexDAO, skip, limit uint64) ([]dao.SecretDAO, error)
 	// UpdateSecret updates a secret
 	UpdateSecret(ctx context.Context, secret *dao.SecretDAO) (*dao.SecretDAO, error)
+	// MoveSecret moves a secret to another collection and/or folder in a single transaction
+	MoveSecret(ctx context.Context, fromCollectionID uuid.UUID, secret *dao.SecretDAO) (*dao.SecretDAO, error)
+	// CopySecret inserts a copy of a secret and its encrypted payload in a single transaction
+	CopySecret(ctx context.Context, secret *dao.SecretDAO) (*dao.SecretDAO, error)
 	// DeleteSecret deletes a secret
 	DeleteSecret(ctx context.Context, id uuid.UUID) error
 	// CreatePasswordSecret creates a new password secret in the data warehouse
@@ -64,3 +100,142 @@ type SecretRepository interface {
 	// UpdateTextSecret updates a text secret
 	UpdateTextSecret(ctx context.Context, secret *dao.TextSecretDAO) (*dao.TextSecretDAO, error)
 }
+
+// TagRepository is an interface for interacting with tag-related data
+type TagRepository interface {
+	// CreateTag inserts a new tag into the database
+	CreateTag(ctx context.Context, tag *dao.TagDAO) (*dao.TagDAO, error)
+	// GetTagByID selects a tag by id
+	GetTagByID(ctx context.Context, id uuid.UUID) (*dao.TagDAO, error)
+	// ListTagsByUserID selects the tags of a user
+	ListTagsByUserID(ctx context.Context, userID uuid.UUID) ([]dao.TagDAO, error)
+	// UpdateTag renames a tag
+	UpdateTag(ctx context.Context, tag *dao.TagDAO) (*dao.TagDAO, error)
+	// DeleteTag deletes a tag
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+	// GetTagCloud counts the secrets using each tag of a user
+	GetTagCloud(ctx context.Context, userID uuid.UUID) ([]dao.TagCountDAO, error)
+	// SetSecretTags replaces the tags of a secret
+	SetSecretTags(ctx context.Context, userID, secretID uuid.UUID, names []string) error
+	// ListTagNamesBySecretIDs selects the names of the user's tags of the given secrets
+	ListTagNamesBySecretIDs(ctx context.Context, userID uuid.UUID, secretIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+// MFARepository is an interface for interacting with multi-factor authentication data
+type MFARepository interface {
+	// UpsertTOTP inserts or replaces the pending TOTP secret of a user
+	UpsertTOTP(ctx context.Context, totp *dao.TOTPDAO) (*dao.TOTPDAO, error)
+	// GetTOTPByUserID selects the TOTP secret of a user
+	GetTOTPByUserID(ctx context.Context, userID uuid.UUID) (*dao.TOTPDAO, error)
+	// UpdateTOTPSecret replaces the stored TOTP secret of a user
+	UpdateTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	// EnableTOTP enables the pending TOTP secret of a user and replaces the backup codes
+	EnableTOTP(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	// DeleteTOTP deletes the TOTP secret and the backup codes of a user
+	DeleteTOTP(ctx context.Context, userID uuid.UUID) error
+	// CountUnusedBackupCodes counts the unused backup codes of a user
+	CountUnusedBackupCodes(ctx context.Context, userID uuid.UUID) (uint64, error)
+	// UseBackupCode marks an unused backup code of a user as used
+	UseBackupCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+}
+
+// WebAuthnRepository is an interface for interacting with WebAuthn credential data
+type WebAuthnRepository interface {
+	// CreateCredential inserts a new WebAuthn credential into the database
+	CreateCredential(ctx context.Context, credential *dao.WebAuthnCredentialDAO) (*dao.WebAuthnCredentialDAO, error)
+	// ListCredentialsByUserID selects the WebAuthn credentials of a user
+	ListCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]dao.WebAuthnCredentialDAO, error)
+	// UpdateCredentialUsage stores the sign counter and the backup state of a credential after a login
+	UpdateCredentialUsage(ctx context.Context, id uuid.UUID, signCount int64, backupState bool) error
+	// DeleteCredential deletes a WebAuthn credential of a user
+	DeleteCredential(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// OIDCRepository is an interface for interacting with the identities of users at OpenID Connect providers
+type OIDCRepository interface {
+	// CreateIdentity links an identity at an OpenID Connect provider to a user
+	CreateIdentity(ctx context.Context, identity *dao.OIDCIdentityDAO) (*dao.OIDCIdentityDAO, error)
+	// GetIdentityBySubject selects an identity by its issuer and its subject at the issuer
+	GetIdentityBySubject(ctx context.Context, issuer, subject string) (*dao.OIDCIdentityDAO, error)
+	// UpdateIdentityLogin records a login with an identity and the email given by the issuer
+	UpdateIdentityLogin(ctx context.Context, id uuid.UUID, email string) error
+}
+
+// TokenRepository is an interface for interacting with refresh token family data
+type TokenRepository interface {
+	// CreateTokenFamily inserts a new refresh token family into the database
+	CreateTokenFamily(ctx context.Context, family *dao.TokenFamilyDAO) (*dao.TokenFamilyDAO, error)
+	// GetTokenFamilyByID selects a refresh token family by id
+	GetTokenFamilyByID(ctx context.Context, id uuid.UUID) (*dao.TokenFamilyDAO, error)
+	// ListTokenFamiliesByUserID selects the active refresh token families of a user used since the given time
+	ListTokenFamiliesByUserID(ctx context.Context, userID uuid.UUID, usedSince time.Time) ([]dao.TokenFamilyDAO, error)
+	// RotateTokenFamily replaces the current refresh token of an active family and records its use
+	RotateTokenFamily(ctx context.Context, id, currentTokenID, newTokenID uuid.UUID, ipAddress string) error
+	// RevokeTokenFamily revokes a refresh token family
+	RevokeTokenFamily(ctx context.Context, id uuid.UUID) error
+}
+
+// SigningKeyRepository is an interface for interacting with the key pairs signing the JWT tokens
+type SigningKeyRepository interface {
+	// CreateSigningKey inserts the first signing key, it fails if another key is already signing
+	CreateSigningKey(ctx context.Context, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error)
+	// RotateSigningKey retires the current signing key, kept for verification until the given time, and inserts the key replacing it
+	RotateSigningKey(ctx context.Context, currentID uuid.UUID, verifyUntil time.Time, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error)
+	// ListSigningKeys selects the signing key and the retired keys that have not expired, newest first
+	ListSigningKeys(ctx context.Context) ([]dao.SigningKeyDAO, error)
+}
+
+// PersonalAccessTokenRepository is an interface for interacting with personal access token-related data
+type PersonalAccessTokenRepository interface {
+	// CreatePersonalAccessToken inserts a new personal access token into the database
+	CreatePersonalAccessToken(ctx context.Context, token *dao.PersonalAccessTokenDAO) (*dao.PersonalAccessTokenDAO, error)
+	// GetPersonalAccessTokenByHash selects a personal access token by the hash of the token
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*dao.PersonalAccessTokenDAO, error)
+	// ListPersonalAccessTokensByUserID selects the personal access tokens of a user
+	ListPersonalAccessTokensByUserID(ctx context.Context, userID uuid.UUID) ([]dao.PersonalAccessTokenDAO, error)
+	// UpdatePersonalAccessTokenUsage records the use of a personal access token
+	UpdatePersonalAccessTokenUsage(ctx context.Context, id uuid.UUID) error
+	// DeletePersonalAccessToken deletes a personal access token of a user
+	DeletePersonalAccessToken(ctx context.Context, userID, id uuid.UUID) error
+	// DeletePersonalAccessTokensByUserID deletes all personal access tokens of a user
+	DeletePersonalAccessTokensByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// InviteRepository is an interface for interacting with the invite codes of the restricted registration
+type InviteRepository interface {
+	// CreateInvite inserts a new invite into the database
+	CreateInvite(ctx context.Context, invite *dao.InviteDAO) (*dao.InviteDAO, error)
+	// ListInvites selects all invites, newest first
+	ListInvites(ctx context.Context) ([]dao.InviteDAO, error)
+	// DeleteInvite deletes an invite
+	DeleteInvite(ctx context.Context, id uuid.UUID) error
+	// CreateInvitedUser uses the invite of the code hash and inserts the user with the role of the invite,
+	// it fails with ErrDataNotFound if the invite has expired or is used up
+	CreateInvitedUser(ctx context.Context, codeHash string, user *dao.UserDAO) (*dao.UserDAO, error)
+}
+
+// RoleRepository is an interface for interacting with the custom roles and their assignments
+type RoleRepository interface {
+	// CreateRole inserts a new role into the database
+	CreateRole(ctx context.Context, role *dao.RoleDAO) (*dao.RoleDAO, error)
+	// GetRoleByID selects a role by id
+	GetRoleByID(ctx context.Context, id uuid.UUID) (*dao.RoleDAO, error)
+	// GetRoleByName selects a role by name
+	GetRoleByName(ctx context.Context, name string) (*dao.RoleDAO, error)
+	// ListRoles selects all roles
+	ListRoles(ctx context.Context) ([]dao.RoleDAO, error)
+	// ListSCIMManagedRoles selects the roles created as groups by the identity provider
+	ListSCIMManagedRoles(ctx context.Context) ([]dao.RoleDAO, error)
+	// ListRolesByUserID selects the roles assigned to a user
+	ListRolesByUserID(ctx context.Context, userID uuid.UUID) ([]dao.RoleDAO, error)
+	// ListRoleMemberIDs selects the ids of the users a role is assigned to
+	ListRoleMemberIDs(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error)
+	// UpdateRole updates a role
+	UpdateRole(ctx context.Context, role *dao.RoleDAO) (*dao.RoleDAO, error)
+	// DeleteRole deletes a role, it is unassigned from its users
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	// SetUserRoles replaces the roles assigned to a user
+	SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) error
+	// SetRoleMembers replaces the users a role is assigned to
+	SetRoleMembers(ctx context.Context, roleID uuid.UUID, userIDs []uuid.UUID) error
+}
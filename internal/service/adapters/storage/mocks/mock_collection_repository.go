@@ -120,6 +120,29 @@ func (_m *CollectionRepository) ListCollectionsByUserID(ctx context.Context, use
 	return r0, r1
 }
 
+// TransferCollectionOwnership provides a mock function with given fields: ctx, collectionID, newOwnerID, updatedBy
+func (_m *CollectionRepository) TransferCollectionOwnership(ctx context.Context, collectionID uuid.UUID, newOwnerID uuid.UUID, updatedBy uuid.UUID) (*dao.CollectionDAO, error) {
+	ret := _m.Called(ctx, collectionID, newOwnerID, updatedBy)
+
+	var r0 *dao.CollectionDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) *dao.CollectionDAO); ok {
+		r0 = rf(ctx, collectionID, newOwnerID, updatedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.CollectionDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, collectionID, newOwnerID, updatedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateCollection provides a mock function with given fields: ctx, collection
 func (_m *CollectionRepository) UpdateCollection(ctx context.Context, collection *dao.CollectionDAO) (*dao.CollectionDAO, error) {
 	ret := _m.Called(ctx, collection)
@@ -0,0 +1,161 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// FolderRepository is an autogenerated mock type for the FolderRepository type
+type FolderRepository struct {
+	mock.Mock
+}
+
+// CreateFolder provides a mock function with given fields: ctx, folder
+func (_m *FolderRepository) CreateFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error) {
+	ret := _m.Called(ctx, folder)
+
+	var r0 *dao.FolderDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.FolderDAO) *dao.FolderDAO); ok {
+		r0 = rf(ctx, folder)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.FolderDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.FolderDAO) error); ok {
+		r1 = rf(ctx, folder)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteFolder provides a mock function with given fields: ctx, id
+func (_m *FolderRepository) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFolderByID provides a mock function with given fields: ctx, id
+func (_m *FolderRepository) GetFolderByID(ctx context.Context, id uuid.UUID) (*dao.FolderDAO, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *dao.FolderDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *dao.FolderDAO); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.FolderDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFoldersByParentID provides a mock function with given fields: ctx, collectionID, parentID
+func (_m *FolderRepository) ListFoldersByParentID(ctx context.Context, collectionID uuid.UUID, parentID uuid.UUID) ([]dao.FolderDAO, error) {
+	ret := _m.Called(ctx, collectionID, parentID)
+
+	var r0 []dao.FolderDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []dao.FolderDAO); ok {
+		r0 = rf(ctx, collectionID, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.FolderDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, collectionID, parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveFolder provides a mock function with given fields: ctx, folder
+func (_m *FolderRepository) MoveFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error) {
+	ret := _m.Called(ctx, folder)
+
+	var r0 *dao.FolderDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.FolderDAO) *dao.FolderDAO); ok {
+		r0 = rf(ctx, folder)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.FolderDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.FolderDAO) error); ok {
+		r1 = rf(ctx, folder)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFolder provides a mock function with given fields: ctx, folder
+func (_m *FolderRepository) UpdateFolder(ctx context.Context, folder *dao.FolderDAO) (*dao.FolderDAO, error) {
+	ret := _m.Called(ctx, folder)
+
+	var r0 *dao.FolderDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.FolderDAO) *dao.FolderDAO); ok {
+		r0 = rf(ctx, folder)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.FolderDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.FolderDAO) error); ok {
+		r1 = rf(ctx, folder)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewFolderRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewFolderRepository creates a new instance of FolderRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewFolderRepository(t mockConstructorTestingTNewFolderRepository) *FolderRepository {
+	mock := &FolderRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
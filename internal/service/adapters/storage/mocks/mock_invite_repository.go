@@ -0,0 +1,115 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// InviteRepository is an autogenerated mock type for the InviteRepository type
+type InviteRepository struct {
+	mock.Mock
+}
+
+// CreateInvite provides a mock function with given fields: ctx, invite
+func (_m *InviteRepository) CreateInvite(ctx context.Context, invite *dao.InviteDAO) (*dao.InviteDAO, error) {
+	ret := _m.Called(ctx, invite)
+
+	var r0 *dao.InviteDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.InviteDAO) *dao.InviteDAO); ok {
+		r0 = rf(ctx, invite)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.InviteDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.InviteDAO) error); ok {
+		r1 = rf(ctx, invite)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateInvitedUser provides a mock function with given fields: ctx, codeHash, user
+func (_m *InviteRepository) CreateInvitedUser(ctx context.Context, codeHash string, user *dao.UserDAO) (*dao.UserDAO, error) {
+	ret := _m.Called(ctx, codeHash, user)
+
+	var r0 *dao.UserDAO
+	if rf, ok := ret.Get(0).(func(context.Context, string, *dao.UserDAO) *dao.UserDAO); ok {
+		r0 = rf(ctx, codeHash, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.UserDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *dao.UserDAO) error); ok {
+		r1 = rf(ctx, codeHash, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteInvite provides a mock function with given fields: ctx, id
+func (_m *InviteRepository) DeleteInvite(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListInvites provides a mock function with given fields: ctx
+func (_m *InviteRepository) ListInvites(ctx context.Context) ([]dao.InviteDAO, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []dao.InviteDAO
+	if rf, ok := ret.Get(0).(func(context.Context) []dao.InviteDAO); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.InviteDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewInviteRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewInviteRepository creates a new instance of InviteRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewInviteRepository(t mockConstructorTestingTNewInviteRepository) *InviteRepository {
+	mock := &InviteRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,155 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MFARepository is an autogenerated mock type for the MFARepository type
+type MFARepository struct {
+	mock.Mock
+}
+
+// CountUnusedBackupCodes provides a mock function with given fields: ctx, userID
+func (_m *MFARepository) CountUnusedBackupCodes(ctx context.Context, userID uuid.UUID) (uint64, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) uint64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTOTP provides a mock function with given fields: ctx, userID
+func (_m *MFARepository) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTOTP provides a mock function with given fields: ctx, userID, codeHashes
+func (_m *MFARepository) EnableTOTP(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	ret := _m.Called(ctx, userID, codeHashes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []string) error); ok {
+		r0 = rf(ctx, userID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTOTPByUserID provides a mock function with given fields: ctx, userID
+func (_m *MFARepository) GetTOTPByUserID(ctx context.Context, userID uuid.UUID) (*dao.TOTPDAO, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *dao.TOTPDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *dao.TOTPDAO); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TOTPDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTOTPSecret provides a mock function with given fields: ctx, userID, secret
+func (_m *MFARepository) UpdateTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	ret := _m.Called(ctx, userID, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertTOTP provides a mock function with given fields: ctx, totp
+func (_m *MFARepository) UpsertTOTP(ctx context.Context, totp *dao.TOTPDAO) (*dao.TOTPDAO, error) {
+	ret := _m.Called(ctx, totp)
+
+	var r0 *dao.TOTPDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.TOTPDAO) *dao.TOTPDAO); ok {
+		r0 = rf(ctx, totp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TOTPDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.TOTPDAO) error); ok {
+		r1 = rf(ctx, totp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseBackupCode provides a mock function with given fields: ctx, userID, codeHash
+func (_m *MFARepository) UseBackupCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMFARepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMFARepository creates a new instance of MFARepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMFARepository(t mockConstructorTestingTNewMFARepository) *MFARepository {
+	mock := &MFARepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// OIDCRepository is an autogenerated mock type for the OIDCRepository type
+type OIDCRepository struct {
+	mock.Mock
+}
+
+// CreateIdentity provides a mock function with given fields: ctx, identity
+func (_m *OIDCRepository) CreateIdentity(ctx context.Context, identity *dao.OIDCIdentityDAO) (*dao.OIDCIdentityDAO, error) {
+	ret := _m.Called(ctx, identity)
+
+	var r0 *dao.OIDCIdentityDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.OIDCIdentityDAO) *dao.OIDCIdentityDAO); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.OIDCIdentityDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.OIDCIdentityDAO) error); ok {
+		r1 = rf(ctx, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIdentityBySubject provides a mock function with given fields: ctx, issuer, subject
+func (_m *OIDCRepository) GetIdentityBySubject(ctx context.Context, issuer string, subject string) (*dao.OIDCIdentityDAO, error) {
+	ret := _m.Called(ctx, issuer, subject)
+
+	var r0 *dao.OIDCIdentityDAO
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.OIDCIdentityDAO); ok {
+		r0 = rf(ctx, issuer, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.OIDCIdentityDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, issuer, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateIdentityLogin provides a mock function with given fields: ctx, id, email
+func (_m *OIDCRepository) UpdateIdentityLogin(ctx context.Context, id uuid.UUID, email string) error {
+	ret := _m.Called(ctx, id, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, id, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewOIDCRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOIDCRepository creates a new instance of OIDCRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOIDCRepository(t mockConstructorTestingTNewOIDCRepository) *OIDCRepository {
+	mock := &OIDCRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
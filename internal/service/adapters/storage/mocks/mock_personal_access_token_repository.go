@@ -0,0 +1,143 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// PersonalAccessTokenRepository is an autogenerated mock type for the PersonalAccessTokenRepository type
+type PersonalAccessTokenRepository struct {
+	mock.Mock
+}
+
+// CreatePersonalAccessToken provides a mock function with given fields: ctx, token
+func (_m *PersonalAccessTokenRepository) CreatePersonalAccessToken(ctx context.Context, token *dao.PersonalAccessTokenDAO) (*dao.PersonalAccessTokenDAO, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *dao.PersonalAccessTokenDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.PersonalAccessTokenDAO) *dao.PersonalAccessTokenDAO); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PersonalAccessTokenDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.PersonalAccessTokenDAO) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePersonalAccessToken provides a mock function with given fields: ctx, userID, id
+func (_m *PersonalAccessTokenRepository) DeletePersonalAccessToken(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	ret := _m.Called(ctx, userID, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePersonalAccessTokensByUserID provides a mock function with given fields: ctx, userID
+func (_m *PersonalAccessTokenRepository) DeletePersonalAccessTokensByUserID(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPersonalAccessTokenByHash provides a mock function with given fields: ctx, tokenHash
+func (_m *PersonalAccessTokenRepository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*dao.PersonalAccessTokenDAO, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	var r0 *dao.PersonalAccessTokenDAO
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dao.PersonalAccessTokenDAO); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PersonalAccessTokenDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPersonalAccessTokensByUserID provides a mock function with given fields: ctx, userID
+func (_m *PersonalAccessTokenRepository) ListPersonalAccessTokensByUserID(ctx context.Context, userID uuid.UUID) ([]dao.PersonalAccessTokenDAO, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []dao.PersonalAccessTokenDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []dao.PersonalAccessTokenDAO); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PersonalAccessTokenDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePersonalAccessTokenUsage provides a mock function with given fields: ctx, id
+func (_m *PersonalAccessTokenRepository) UpdatePersonalAccessTokenUsage(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPersonalAccessTokenRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPersonalAccessTokenRepository creates a new instance of PersonalAccessTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPersonalAccessTokenRepository(t mockConstructorTestingTNewPersonalAccessTokenRepository) *PersonalAccessTokenRepository {
+	mock := &PersonalAccessTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
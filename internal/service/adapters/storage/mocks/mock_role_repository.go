@@ -0,0 +1,258 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// RoleRepository is an autogenerated mock type for the RoleRepository type
+type RoleRepository struct {
+	mock.Mock
+}
+
+// CreateRole provides a mock function with given fields: ctx, role
+func (_m *RoleRepository) CreateRole(ctx context.Context, role *dao.RoleDAO) (*dao.RoleDAO, error) {
+	ret := _m.Called(ctx, role)
+
+	var r0 *dao.RoleDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.RoleDAO) *dao.RoleDAO); ok {
+		r0 = rf(ctx, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.RoleDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.RoleDAO) error); ok {
+		r1 = rf(ctx, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteRole provides a mock function with given fields: ctx, id
+func (_m *RoleRepository) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRoleByID provides a mock function with given fields: ctx, id
+func (_m *RoleRepository) GetRoleByID(ctx context.Context, id uuid.UUID) (*dao.RoleDAO, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *dao.RoleDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *dao.RoleDAO); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.RoleDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoleByName provides a mock function with given fields: ctx, name
+func (_m *RoleRepository) GetRoleByName(ctx context.Context, name string) (*dao.RoleDAO, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *dao.RoleDAO
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dao.RoleDAO); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.RoleDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRoleMemberIDs provides a mock function with given fields: ctx, roleID
+func (_m *RoleRepository) ListRoleMemberIDs(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, roleID)
+
+	var r0 []uuid.UUID
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []uuid.UUID); ok {
+		r0 = rf(ctx, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, roleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRoles provides a mock function with given fields: ctx
+func (_m *RoleRepository) ListRoles(ctx context.Context) ([]dao.RoleDAO, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []dao.RoleDAO
+	if rf, ok := ret.Get(0).(func(context.Context) []dao.RoleDAO); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.RoleDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRolesByUserID provides a mock function with given fields: ctx, userID
+func (_m *RoleRepository) ListRolesByUserID(ctx context.Context, userID uuid.UUID) ([]dao.RoleDAO, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []dao.RoleDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []dao.RoleDAO); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.RoleDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSCIMManagedRoles provides a mock function with given fields: ctx
+func (_m *RoleRepository) ListSCIMManagedRoles(ctx context.Context) ([]dao.RoleDAO, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []dao.RoleDAO
+	if rf, ok := ret.Get(0).(func(context.Context) []dao.RoleDAO); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.RoleDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRoleMembers provides a mock function with given fields: ctx, roleID, userIDs
+func (_m *RoleRepository) SetRoleMembers(ctx context.Context, roleID uuid.UUID, userIDs []uuid.UUID) error {
+	ret := _m.Called(ctx, roleID, userIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(ctx, roleID, userIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserRoles provides a mock function with given fields: ctx, userID, roleIDs
+func (_m *RoleRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) error {
+	ret := _m.Called(ctx, userID, roleIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, roleIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: ctx, role
+func (_m *RoleRepository) UpdateRole(ctx context.Context, role *dao.RoleDAO) (*dao.RoleDAO, error) {
+	ret := _m.Called(ctx, role)
+
+	var r0 *dao.RoleDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.RoleDAO) *dao.RoleDAO); ok {
+		r0 = rf(ctx, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.RoleDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.RoleDAO) error); ok {
+		r1 = rf(ctx, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRoleRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRoleRepository creates a new instance of RoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRoleRepository(t mockConstructorTestingTNewRoleRepository) *RoleRepository {
+	mock := &RoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -16,6 +16,29 @@ type SecretRepository struct {
 	mock.Mock
 }
 
+// CopySecret provides a mock function with given fields: ctx, secret
+func (_m *SecretRepository) CopySecret(ctx context.Context, secret *dao.SecretDAO) (*dao.SecretDAO, error) {
+	ret := _m.Called(ctx, secret)
+
+	var r0 *dao.SecretDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.SecretDAO) *dao.SecretDAO); ok {
+		r0 = rf(ctx, secret)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.SecretDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.SecretDAO) error); ok {
+		r1 = rf(ctx, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreatePasswordSecret provides a mock function with given fields: ctx, secret
 func (_m *SecretRepository) CreatePasswordSecret(ctx context.Context, secret *dao.PasswordSecretDAO) (*dao.PasswordSecretDAO, error) {
 	ret := _m.Called(ctx, secret)
@@ -168,13 +191,82 @@ func (_m *SecretRepository) GetTextSecretByID(ctx context.Context, id uuid.UUID)
 	return r0, r1
 }
 
-// ListSecretsByCollectionID provides a mock function with given fields: ctx, collectionID, skip, limit
-func (_m *SecretRepository) ListSecretsByCollectionID(ctx context.Context, collectionID uuid.UUID, skip uint64, limit uint64) ([]dao.SecretDAO, error) {
-	ret := _m.Called(ctx, collectionID, skip, limit)
+// ListSecretsByCollectionID provides a mock function with given fields: ctx, userID, collectionID, folderID, recursive, tag, skip, limit
+func (_m *SecretRepository) ListSecretsByCollectionID(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID, folderID uuid.UUID, recursive bool, tag string, skip uint64, limit uint64) ([]dao.SecretDAO, error) {
+	ret := _m.Called(ctx, userID, collectionID, folderID, recursive, tag, skip, limit)
+
+	var r0 []dao.SecretDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, bool, string, uint64, uint64) []dao.SecretDAO); ok {
+		r0 = rf(ctx, userID, collectionID, folderID, recursive, tag, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.SecretDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, bool, string, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, collectionID, folderID, recursive, tag, skip, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LookupSecretsByBlindIndex provides a mock function with given fields: ctx, userID, index, skip, limit
+func (_m *SecretRepository) LookupSecretsByBlindIndex(ctx context.Context, userID uuid.UUID, index *dao.BlindIndexDAO, skip uint64, limit uint64) ([]dao.SecretDAO, error) {
+	ret := _m.Called(ctx, userID, index, skip, limit)
+
+	var r0 []dao.SecretDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *dao.BlindIndexDAO, uint64, uint64) []dao.SecretDAO); ok {
+		r0 = rf(ctx, userID, index, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.SecretDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *dao.BlindIndexDAO, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, index, skip, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveSecret provides a mock function with given fields: ctx, fromCollectionID, secret
+func (_m *SecretRepository) MoveSecret(ctx context.Context, fromCollectionID uuid.UUID, secret *dao.SecretDAO) (*dao.SecretDAO, error) {
+	ret := _m.Called(ctx, fromCollectionID, secret)
+
+	var r0 *dao.SecretDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *dao.SecretDAO) *dao.SecretDAO); ok {
+		r0 = rf(ctx, fromCollectionID, secret)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.SecretDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *dao.SecretDAO) error); ok {
+		r1 = rf(ctx, fromCollectionID, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchSecrets provides a mock function with given fields: ctx, userID, text, skip, limit
+func (_m *SecretRepository) SearchSecrets(ctx context.Context, userID uuid.UUID, text string, skip uint64, limit uint64) ([]dao.SecretDAO, error) {
+	ret := _m.Called(ctx, userID, text, skip, limit)
 
 	var r0 []dao.SecretDAO
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint64, uint64) []dao.SecretDAO); ok {
-		r0 = rf(ctx, collectionID, skip, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, uint64, uint64) []dao.SecretDAO); ok {
+		r0 = rf(ctx, userID, text, skip, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]dao.SecretDAO)
@@ -182,8 +274,8 @@ func (_m *SecretRepository) ListSecretsByCollectionID(ctx context.Context, colle
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint64, uint64) error); ok {
-		r1 = rf(ctx, collectionID, skip, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, uint64, uint64) error); ok {
+		r1 = rf(ctx, userID, text, skip, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -191,6 +283,20 @@ func (_m *SecretRepository) ListSecretsByCollectionID(ctx context.Context, colle
 	return r0, r1
 }
 
+// SetBlindIndexes provides a mock function with given fields: ctx, secretID, indexes
+func (_m *SecretRepository) SetBlindIndexes(ctx context.Context, secretID uuid.UUID, indexes []dao.BlindIndexDAO) error {
+	ret := _m.Called(ctx, secretID, indexes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []dao.BlindIndexDAO) error); ok {
+		r0 = rf(ctx, secretID, indexes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePasswordSecret provides a mock function with given fields: ctx, secret
 func (_m *SecretRepository) UpdatePasswordSecret(ctx context.Context, secret *dao.PasswordSecretDAO) (*dao.PasswordSecretDAO, error) {
 	ret := _m.Called(ctx, secret)
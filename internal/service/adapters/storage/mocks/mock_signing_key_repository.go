@@ -0,0 +1,103 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// SigningKeyRepository is an autogenerated mock type for the SigningKeyRepository type
+type SigningKeyRepository struct {
+	mock.Mock
+}
+
+// CreateSigningKey provides a mock function with given fields: ctx, key
+func (_m *SigningKeyRepository) CreateSigningKey(ctx context.Context, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 *dao.SigningKeyDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.SigningKeyDAO) *dao.SigningKeyDAO); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.SigningKeyDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.SigningKeyDAO) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSigningKeys provides a mock function with given fields: ctx
+func (_m *SigningKeyRepository) ListSigningKeys(ctx context.Context) ([]dao.SigningKeyDAO, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []dao.SigningKeyDAO
+	if rf, ok := ret.Get(0).(func(context.Context) []dao.SigningKeyDAO); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.SigningKeyDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateSigningKey provides a mock function with given fields: ctx, currentID, verifyUntil, key
+func (_m *SigningKeyRepository) RotateSigningKey(ctx context.Context, currentID uuid.UUID, verifyUntil time.Time, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error) {
+	ret := _m.Called(ctx, currentID, verifyUntil, key)
+
+	var r0 *dao.SigningKeyDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, *dao.SigningKeyDAO) *dao.SigningKeyDAO); ok {
+		r0 = rf(ctx, currentID, verifyUntil, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.SigningKeyDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, *dao.SigningKeyDAO) error); ok {
+		r1 = rf(ctx, currentID, verifyUntil, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSigningKeyRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSigningKeyRepository creates a new instance of SigningKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSigningKeyRepository(t mockConstructorTestingTNewSigningKeyRepository) *SigningKeyRepository {
+	mock := &SigningKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
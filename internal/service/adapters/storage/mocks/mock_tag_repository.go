@@ -0,0 +1,198 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// TagRepository is an autogenerated mock type for the TagRepository type
+type TagRepository struct {
+	mock.Mock
+}
+
+// CreateTag provides a mock function with given fields: ctx, tag
+func (_m *TagRepository) CreateTag(ctx context.Context, tag *dao.TagDAO) (*dao.TagDAO, error) {
+	ret := _m.Called(ctx, tag)
+
+	var r0 *dao.TagDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.TagDAO) *dao.TagDAO); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TagDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.TagDAO) error); ok {
+		r1 = rf(ctx, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTag provides a mock function with given fields: ctx, id
+func (_m *TagRepository) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTagByID provides a mock function with given fields: ctx, id
+func (_m *TagRepository) GetTagByID(ctx context.Context, id uuid.UUID) (*dao.TagDAO, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *dao.TagDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *dao.TagDAO); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TagDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTagCloud provides a mock function with given fields: ctx, userID
+func (_m *TagRepository) GetTagCloud(ctx context.Context, userID uuid.UUID) ([]dao.TagCountDAO, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []dao.TagCountDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []dao.TagCountDAO); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TagCountDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTagNamesBySecretIDs provides a mock function with given fields: ctx, userID, secretIDs
+func (_m *TagRepository) ListTagNamesBySecretIDs(ctx context.Context, userID uuid.UUID, secretIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	ret := _m.Called(ctx, userID, secretIDs)
+
+	var r0 map[uuid.UUID][]string
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) map[uuid.UUID][]string); ok {
+		r0 = rf(ctx, userID, secretIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID][]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, secretIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTagsByUserID provides a mock function with given fields: ctx, userID
+func (_m *TagRepository) ListTagsByUserID(ctx context.Context, userID uuid.UUID) ([]dao.TagDAO, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []dao.TagDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []dao.TagDAO); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TagDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSecretTags provides a mock function with given fields: ctx, userID, secretID, names
+func (_m *TagRepository) SetSecretTags(ctx context.Context, userID uuid.UUID, secretID uuid.UUID, names []string) error {
+	ret := _m.Called(ctx, userID, secretID, names)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, []string) error); ok {
+		r0 = rf(ctx, userID, secretID, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTag provides a mock function with given fields: ctx, tag
+func (_m *TagRepository) UpdateTag(ctx context.Context, tag *dao.TagDAO) (*dao.TagDAO, error) {
+	ret := _m.Called(ctx, tag)
+
+	var r0 *dao.TagDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.TagDAO) *dao.TagDAO); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TagDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.TagDAO) error); ok {
+		r1 = rf(ctx, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTagRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTagRepository creates a new instance of TagRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTagRepository(t mockConstructorTestingTNewTagRepository) *TagRepository {
+	mock := &TagRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,131 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// TokenRepository is an autogenerated mock type for the TokenRepository type
+type TokenRepository struct {
+	mock.Mock
+}
+
+// CreateTokenFamily provides a mock function with given fields: ctx, family
+func (_m *TokenRepository) CreateTokenFamily(ctx context.Context, family *dao.TokenFamilyDAO) (*dao.TokenFamilyDAO, error) {
+	ret := _m.Called(ctx, family)
+
+	var r0 *dao.TokenFamilyDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.TokenFamilyDAO) *dao.TokenFamilyDAO); ok {
+		r0 = rf(ctx, family)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TokenFamilyDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.TokenFamilyDAO) error); ok {
+		r1 = rf(ctx, family)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenFamilyByID provides a mock function with given fields: ctx, id
+func (_m *TokenRepository) GetTokenFamilyByID(ctx context.Context, id uuid.UUID) (*dao.TokenFamilyDAO, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *dao.TokenFamilyDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *dao.TokenFamilyDAO); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TokenFamilyDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTokenFamiliesByUserID provides a mock function with given fields: ctx, userID, usedSince
+func (_m *TokenRepository) ListTokenFamiliesByUserID(ctx context.Context, userID uuid.UUID, usedSince time.Time) ([]dao.TokenFamilyDAO, error) {
+	ret := _m.Called(ctx, userID, usedSince)
+
+	var r0 []dao.TokenFamilyDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) []dao.TokenFamilyDAO); ok {
+		r0 = rf(ctx, userID, usedSince)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TokenFamilyDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, userID, usedSince)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeTokenFamily provides a mock function with given fields: ctx, id
+func (_m *TokenRepository) RevokeTokenFamily(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateTokenFamily provides a mock function with given fields: ctx, id, currentTokenID, newTokenID, ipAddress
+func (_m *TokenRepository) RotateTokenFamily(ctx context.Context, id uuid.UUID, currentTokenID uuid.UUID, newTokenID uuid.UUID, ipAddress string) error {
+	ret := _m.Called(ctx, id, currentTokenID, newTokenID, ipAddress)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, id, currentTokenID, newTokenID, ipAddress)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTokenRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTokenRepository creates a new instance of TokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTokenRepository(t mockConstructorTestingTNewTokenRepository) *TokenRepository {
+	mock := &TokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
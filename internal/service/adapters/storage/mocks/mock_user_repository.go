@@ -5,7 +5,9 @@ package mocks
 import (
 	context "context"
 
+	domain "github.com/8thgencore/passfort/internal/domain"
 	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+
 	mock "github.com/stretchr/testify/mock"
 
 	uuid "github.com/google/uuid"
@@ -16,6 +18,27 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// CountUsers provides a mock function with given fields: ctx
+func (_m *UserRepository) CountUsers(ctx context.Context) (uint64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(context.Context) uint64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateUser provides a mock function with given fields: ctx, user
 func (_m *UserRepository) CreateUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error) {
 	ret := _m.Called(ctx, user)
@@ -39,13 +62,13 @@ func (_m *UserRepository) CreateUser(ctx context.Context, user *dao.UserDAO) (*d
 	return r0, r1
 }
 
-// DeleteUser provides a mock function with given fields: ctx, id
-func (_m *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
+// DeleteUser provides a mock function with given fields: ctx, id, policy, newOwnerID
+func (_m *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID, policy domain.OwnedCollectionsPolicy, newOwnerID uuid.UUID) error {
+	ret := _m.Called(ctx, id, policy, newOwnerID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.OwnedCollectionsPolicy, uuid.UUID) error); ok {
+		r0 = rf(ctx, id, policy, newOwnerID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -122,6 +145,29 @@ func (_m *UserRepository) ListUsers(ctx context.Context, skip uint64, limit uint
 	return r0, r1
 }
 
+// ProvisionUser provides a mock function with given fields: ctx, user
+func (_m *UserRepository) ProvisionUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error) {
+	ret := _m.Called(ctx, user)
+
+	var r0 *dao.UserDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.UserDAO) *dao.UserDAO); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.UserDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.UserDAO) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateUser provides a mock function with given fields: ctx, user
 func (_m *UserRepository) UpdateUser(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error) {
 	ret := _m.Called(ctx, user)
@@ -145,6 +191,29 @@ func (_m *UserRepository) UpdateUser(ctx context.Context, user *dao.UserDAO) (*d
 	return r0, r1
 }
 
+// UpdateUserStatus provides a mock function with given fields: ctx, user
+func (_m *UserRepository) UpdateUserStatus(ctx context.Context, user *dao.UserDAO) (*dao.UserDAO, error) {
+	ret := _m.Called(ctx, user)
+
+	var r0 *dao.UserDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.UserDAO) *dao.UserDAO); ok {
+		r0 = rf(ctx, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.UserDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.UserDAO) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewUserRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,106 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dao "github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// WebAuthnRepository is an autogenerated mock type for the WebAuthnRepository type
+type WebAuthnRepository struct {
+	mock.Mock
+}
+
+// CreateCredential provides a mock function with given fields: ctx, credential
+func (_m *WebAuthnRepository) CreateCredential(ctx context.Context, credential *dao.WebAuthnCredentialDAO) (*dao.WebAuthnCredentialDAO, error) {
+	ret := _m.Called(ctx, credential)
+
+	var r0 *dao.WebAuthnCredentialDAO
+	if rf, ok := ret.Get(0).(func(context.Context, *dao.WebAuthnCredentialDAO) *dao.WebAuthnCredentialDAO); ok {
+		r0 = rf(ctx, credential)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.WebAuthnCredentialDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *dao.WebAuthnCredentialDAO) error); ok {
+		r1 = rf(ctx, credential)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteCredential provides a mock function with given fields: ctx, userID, id
+func (_m *WebAuthnRepository) DeleteCredential(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	ret := _m.Called(ctx, userID, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListCredentialsByUserID provides a mock function with given fields: ctx, userID
+func (_m *WebAuthnRepository) ListCredentialsByUserID(ctx context.Context, userID uuid.UUID) ([]dao.WebAuthnCredentialDAO, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []dao.WebAuthnCredentialDAO
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []dao.WebAuthnCredentialDAO); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.WebAuthnCredentialDAO)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCredentialUsage provides a mock function with given fields: ctx, id, signCount, backupState
+func (_m *WebAuthnRepository) UpdateCredentialUsage(ctx context.Context, id uuid.UUID, signCount int64, backupState bool) error {
+	ret := _m.Called(ctx, id, signCount, backupState)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, bool) error); ok {
+		r0 = rf(ctx, id, signCount, backupState)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewWebAuthnRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebAuthnRepository creates a new instance of WebAuthnRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebAuthnRepository(t mockConstructorTestingTNewWebAuthnRepository) *WebAuthnRepository {
+	mock := &WebAuthnRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
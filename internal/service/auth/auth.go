@@ -2,37 +2,222 @@ package auth
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
 	"github.com/8thgencore/passfort/pkg/logger/sl"
 	"github.com/8thgencore/passfort/pkg/util"
 	"github.com/google/uuid"
 )
 
-// Login gives a registered user an access token if the credentials are valid
-func (svc *AuthService) Login(ctx context.Context, email, password string) (string, string, error) {
+// Login gives a registered user an access token if the credentials are valid.
+// If the user has enabled two-factor authentication or registered a passkey, no tokens are given
+// but a short-lived MFA challenge token, which must be exchanged with a code by LoginMFA
+// or with a passkey by FinishWebAuthnMFA.
+func (svc *AuthService) Login(ctx context.Context, email, password string, client *domain.ClientInfo) (string, string, string, error) {
+	if err := svc.lockout.Check(ctx, domain.LoginAction, email, client.IPAddress); err != nil {
+		return "", "", "", err
+	}
+
 	userDAO, err := svc.storage.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
-			return "", "", domain.ErrInvalidCredentials
+			return "", "", "", svc.failAttempt(ctx, domain.LoginAction, email, client, domain.ErrInvalidCredentials)
 		}
 		svc.log.Error("failed to get the user by email", sl.Err(err))
 
-		return "", "", domain.ErrInternal
+		return "", "", "", domain.ErrInternal
 	}
 	user := converter.ToUser(userDAO)
 
 	if !user.IsVerified {
-		return "", "", domain.ErrUserNotVerified
+		return "", "", "", domain.ErrUserNotVerified
 	}
 
 	err = util.CompareHash(password, user.Password)
 	if err != nil {
-		return "", "", domain.ErrInvalidCredentials
+		return "", "", "", svc.failAttempt(ctx, domain.LoginAction, email, client, domain.ErrInvalidCredentials)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.LoginAction, email); err != nil {
+		return "", "", "", err
+	}
+
+	return svc.completeLogin(ctx, user, client)
+}
+
+// failAttempt counts a failed attempt of the action and returns its error,
+// or the error refusing the next attempts once the account or the client has to wait
+func (svc *AuthService) failAttempt(ctx context.Context, action domain.LockoutAction, email string, client *domain.ClientInfo, err error) error {
+	if lockErr := svc.lockout.Fail(ctx, action, email, client.IPAddress); lockErr != nil {
+		return lockErr
+	}
+
+	return err
+}
+
+// completeLogin gives an authenticated user a token pair, or an MFA challenge token if a second factor is required.
+// A suspended user is refused only once authenticated, so the suspension is not shown to anyone knowing the email.
+// A user with actions forced by an admin is logged in, their tokens only let them complete these actions.
+func (svc *AuthService) completeLogin(ctx context.Context, user *domain.User, client *domain.ClientInfo) (string, string, string, error) {
+	if user.IsSuspended() {
+		return "", "", "", domain.ErrUserSuspended
+	}
+
+	mfaEnabled, err := svc.mfaRequired(ctx, user.ID)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if mfaEnabled {
+		// A user already having a second factor has nothing to enroll
+		if user.ForceMFAEnrollment {
+			if err := svc.mfa.CompleteEnrollment(ctx, user.ID); err != nil {
+				return "", "", "", err
+			}
+		}
+
+		mfaToken, err := svc.createMFAChallenge(ctx, user.ID)
+		if err != nil {
+			return "", "", "", err
+		}
+
+		return "", "", mfaToken, nil
+	}
+
+	accessToken, refreshToken, err := svc.tokenService.GenerateToken(ctx, user.ID, user.Role, client)
+	if err != nil {
+		return "", "", "", domain.ErrTokenCreation
+	}
+
+	return accessToken, refreshToken, "", nil
+}
+
+// mfaChallenge represents a pending login waiting for a second factor
+type mfaChallenge struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// maxMFAAttempts is the number of codes after which an MFA challenge is dropped
+const maxMFAAttempts = 5
+
+// LoginMFA completes a login started by Login with a TOTP code or a backup code.
+// The wrong codes are counted per challenge and, against brute force over new challenges, per account.
+func (svc *AuthService) LoginMFA(ctx context.Context, mfaToken, code string, client *domain.ClientInfo) (string, string, error) {
+	mfaTokenHash := util.HashToken(mfaToken)
+	cacheKey, challenge, err := svc.getMFAChallenge(ctx, mfaTokenHash)
+	if err != nil {
+		return "", "", err
+	}
+
+	userDAO, err := svc.storage.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return "", "", domain.ErrInvalidMFAToken
+	}
+
+	if err := svc.lockout.Check(ctx, domain.MFAAction, userDAO.Email, client.IPAddress); err != nil {
+		return "", "", err
+	}
+
+	if err := svc.countMFAAttempt(ctx, cacheKey, mfaTokenHash); err != nil {
+		return "", "", err
+	}
+
+	if err := svc.mfa.VerifyCode(ctx, challenge.UserID, code); err != nil {
+		if err != domain.ErrInvalidMFACode {
+			return "", "", err
+		}
+		return "", "", svc.failAttempt(ctx, domain.MFAAction, userDAO.Email, client, domain.ErrInvalidMFACode)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.MFAAction, userDAO.Email); err != nil {
+		return "", "", err
+	}
+
+	return svc.completeMFAChallenge(ctx, cacheKey, challenge, client)
+}
+
+// mfaRequired checks if the user must pass a second factor to log in,
+// which is the case with TOTP enabled or with a registered passkey
+func (svc *AuthService) mfaRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	totpEnabled, err := svc.mfa.IsEnabled(ctx, userID)
+	if err != nil {
+		return false, domain.ErrInternal
+	}
+	if totpEnabled {
+		return true, nil
+	}
+
+	credentialsDAO, err := svc.credentialStorage.ListCredentialsByUserID(ctx, userID)
+	if err != nil {
+		svc.log.Error("failed to list WebAuthn credentials", sl.Err(err))
+		return false, domain.ErrInternal
+	}
+
+	return len(credentialsDAO) > 0, nil
+}
+
+// getMFAChallenge gets the pending login and its cache key by the hash of the MFA challenge token.
+// Only the hash of the challenge token is used as the cache key.
+func (svc *AuthService) getMFAChallenge(ctx context.Context, mfaTokenHash string) (string, *mfaChallenge, error) {
+	cacheKey := util.GenerateCacheKey("mfa_challenge", mfaTokenHash)
+
+	challengeSerialized, err := svc.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return "", nil, domain.ErrInvalidMFAToken
+	}
+
+	var challenge mfaChallenge
+	if err := util.Deserialize(challengeSerialized, &challenge); err != nil {
+		return "", nil, domain.ErrInvalidMFAToken
+	}
+
+	return cacheKey, &challenge, nil
+}
+
+// countMFAAttempt counts a code given for a pending login before it is verified,
+// so parallel attempts cannot exceed the limit. The challenge is dropped after too many codes,
+// the user must log in again.
+func (svc *AuthService) countMFAAttempt(ctx context.Context, cacheKey, mfaTokenHash string) error {
+	attempts, err := svc.cache.Increment(ctx, util.GenerateCacheKey("mfa_challenge_attempts", mfaTokenHash), svc.mfaTokenTTL)
+	if err != nil {
+		svc.log.Error("failed to count MFA attempts", sl.Err(err))
+		return domain.ErrInternal
+	}
+	if attempts <= maxMFAAttempts {
+		return nil
+	}
+
+	if err := svc.cache.Delete(ctx, cacheKey); err != nil {
+		svc.log.Error("failed to delete MFA challenge", sl.Err(err))
+	}
+
+	return domain.ErrInvalidMFAToken
+}
+
+// completeMFAChallenge consumes a pending login after a successful second factor and gives the user a token
+func (svc *AuthService) completeMFAChallenge(ctx context.Context, cacheKey string, challenge *mfaChallenge, client *domain.ClientInfo) (string, string, error) {
+	// The challenge token can only be used once
+	if err := svc.cache.Delete(ctx, cacheKey); err != nil {
+		svc.log.Error("failed to delete MFA challenge", sl.Err(err))
+		return "", "", domain.ErrInternal
+	}
+
+	userDAO, err := svc.storage.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return "", "", domain.ErrInvalidMFAToken
+	}
+	user := converter.ToUser(userDAO)
+
+	// The user may have been suspended since the first factor
+	if user.IsSuspended() {
+		return "", "", domain.ErrUserSuspended
 	}
 
-	accessToken, refreshToken, err := svc.tokenService.GenerateToken(user.ID, user.Role)
+	accessToken, refreshToken, err := svc.tokenService.GenerateToken(ctx, user.ID, user.Role, client)
 	if err != nil {
 		return "", "", domain.ErrTokenCreation
 	}
@@ -40,8 +225,40 @@ func (svc *AuthService) Login(ctx context.Context, email, password string) (stri
 	return accessToken, refreshToken, nil
 }
 
-// Register creates a new user
-func (svc *AuthService) Register(ctx context.Context, user *domain.User) (*domain.User, error) {
+// createMFAChallenge stores a pending login of the user and returns its challenge token
+func (svc *AuthService) createMFAChallenge(ctx context.Context, userID uuid.UUID) (string, error) {
+	mfaToken, err := util.GenerateRandomToken(32)
+	if err != nil {
+		svc.log.Error("failed to generate MFA token", sl.Err(err))
+		return "", domain.ErrTokenCreation
+	}
+
+	challengeSerialized, err := util.Serialize(mfaChallenge{UserID: userID})
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("mfa_challenge", util.HashToken(mfaToken))
+	if err := svc.cache.Set(ctx, cacheKey, challengeSerialized, svc.mfaTokenTTL); err != nil {
+		svc.log.Error("failed to store MFA challenge", sl.Err(err))
+		return "", domain.ErrInternal
+	}
+
+	return mfaToken, nil
+}
+
+// Register creates a new user.
+// Unless the registration mode lets the email register freely an invite code is required,
+// a user registering with an invite code gets the role of the invite.
+func (svc *AuthService) Register(ctx context.Context, user *domain.User, inviteCode string) (*domain.User, error) {
+	if inviteCode == "" && !svc.canRegisterWithoutInvite(user.Email) {
+		return nil, domain.ErrRegistrationClosed
+	}
+
+	if err := svc.passwordPolicy.ValidatePassword(user.Password); err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := util.HashPassword(user.Password)
 	if err != nil {
 		return nil, domain.ErrInternal
@@ -49,7 +266,15 @@ func (svc *AuthService) Register(ctx context.Context, user *domain.User) (*domai
 
 	user.Password = hashedPassword
 
-	userDAO, err := svc.storage.CreateUser(ctx, converter.ToUserDAO(user))
+	var userDAO *dao.UserDAO
+	if inviteCode != "" {
+		userDAO, err = svc.inviteStorage.CreateInvitedUser(ctx, util.HashToken(inviteCode), converter.ToUserDAO(user))
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidInvite
+		}
+	} else {
+		userDAO, err = svc.storage.CreateUser(ctx, converter.ToUserDAO(user))
+	}
 	if err != nil {
 		if err == domain.ErrConflictingData {
 			return nil, err
@@ -61,7 +286,7 @@ func (svc *AuthService) Register(ctx context.Context, user *domain.User) (*domai
 	user = converter.ToUser(userDAO)
 
 	// Send confirm otp code
-	otp, err := svc.otp.GenerateOTP(ctx, user.ID)
+	otp, err := svc.otp.GenerateOTP(ctx, user.ID, domain.RegistrationOTP)
 	if err != nil {
 		return nil, domain.ErrInternal
 	}
@@ -91,22 +316,52 @@ func (svc *AuthService) Register(ctx context.Context, user *domain.User) (*domai
 	return user, nil
 }
 
+// canRegisterWithoutInvite checks if the registration mode lets the email register without an invite code.
+// Unknown modes require an invite code, so a typo in the settings does not open the sign-ups.
+func (svc *AuthService) canRegisterWithoutInvite(email string) bool {
+	switch svc.registrationMode {
+	case domain.OpenRegistration:
+		return true
+	case domain.DomainRegistration:
+		at := strings.LastIndex(email, "@")
+		if at < 0 {
+			return false
+		}
+		emailDomain := email[at+1:]
+		return slices.ContainsFunc(svc.registrationDomains, func(allowed string) bool {
+			return strings.EqualFold(strings.TrimPrefix(allowed, "@"), emailDomain)
+		})
+	default:
+		return false
+	}
+}
+
 // ConfirmRegistration confirms user registration with OTP code
-func (svc *AuthService) ConfirmRegistration(ctx context.Context, email, otp string) error {
-	// Implement the logic to confirm user registration with OTP code
+func (svc *AuthService) ConfirmRegistration(ctx context.Context, email, otp string, client *domain.ClientInfo) error {
+	if err := svc.lockout.Check(ctx, domain.ConfirmRegistrationAction, email, client.IPAddress); err != nil {
+		return err
+	}
+
 	// Retrieve user by email
 	userDAO, err := svc.storage.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
-			return domain.ErrInvalidOTP
+			return svc.failAttempt(ctx, domain.ConfirmRegistrationAction, email, client, domain.ErrInvalidOTP)
 		}
 		svc.log.Error("failed to get the user by email", sl.Err(err))
 		return domain.ErrInternal
 	}
 
 	// Validate OTP
-	if err := svc.otp.VerifyOTP(ctx, userDAO.ID, otp); err != nil {
-		return domain.ErrInvalidOTP
+	if err := svc.otp.VerifyOTP(ctx, userDAO.ID, domain.RegistrationOTP, otp); err != nil {
+		if err == domain.ErrInternal {
+			return err
+		}
+		return svc.failAttempt(ctx, domain.ConfirmRegistrationAction, email, client, err)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.ConfirmRegistrationAction, email); err != nil {
+		return err
 	}
 
 	userDAO.IsVerified = true
@@ -130,7 +385,7 @@ func (svc *AuthService) RequestNewRegistrationCode(ctx context.Context, email st
 	user := converter.ToUser(userDAO)
 
 	// Check if OTP already exists for the user
-	exists, err := svc.otp.CheckCacheForKey(ctx, user.ID)
+	exists, err := svc.otp.HasPendingOTP(ctx, user.ID, domain.RegistrationOTP)
 	if err != nil {
 		svc.log.Error("failed to check cache for OTP", sl.Err(err))
 		return domain.ErrInternal
@@ -140,7 +395,7 @@ func (svc *AuthService) RequestNewRegistrationCode(ctx context.Context, email st
 	}
 
 	// Generate and send new registration confirmation OTP
-	otp, err := svc.otp.GenerateOTP(ctx, user.ID)
+	otp, err := svc.otp.GenerateOTP(ctx, user.ID, domain.RegistrationOTP)
 	if err != nil {
 		svc.log.Error("failed to generate new registration confirmation OTP", sl.Err(err))
 		return domain.ErrInternal
@@ -155,19 +410,14 @@ func (svc *AuthService) RequestNewRegistrationCode(ctx context.Context, email st
 	return nil
 }
 
-// RefreshToken refreshes the access token for the user
-func (svc *AuthService) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+// RefreshToken refreshes the access token for the user.
+// The refresh token is rotated, each refresh token can be used only once.
+func (svc *AuthService) RefreshToken(ctx context.Context, refreshToken string, client *domain.ClientInfo) (string, string, error) {
 	token, err := svc.tokenService.ParseUserClaims(refreshToken)
-	if err != nil {
+	if err != nil || token.Type != domain.RefreshToken {
 		return "", "", domain.ErrInvalidRefreshToken
 	}
 
-	// Caching a revoked token
-	err = svc.tokenService.RevokeToken(ctx, token.ID)
-	if err != nil {
-		return "", "", err
-	}
-
 	userDAO, err := svc.storage.GetUserByID(ctx, token.UserID)
 	if err != nil {
 		return "", "", domain.ErrDataNotFound
@@ -175,15 +425,14 @@ func (svc *AuthService) RefreshToken(ctx context.Context, refreshToken string) (
 
 	user := converter.ToUser(userDAO)
 
-	accessToken, refreshToken, err := svc.tokenService.GenerateToken(user.ID, user.Role)
-	if err != nil {
-		return "", "", domain.ErrTokenCreation
+	if user.IsSuspended() {
+		return "", "", domain.ErrUserSuspended
 	}
 
-	return accessToken, refreshToken, nil
+	return svc.tokenService.RotateToken(ctx, token, user.Role, client)
 }
 
-// Logout invalidates the access token, logging the user out
+// Logout invalidates the access token and its refresh token family, logging the user out
 func (svc *AuthService) Logout(ctx context.Context, token *domain.UserClaims) error {
 	_, err := svc.storage.GetUserByID(ctx, token.UserID)
 	if err != nil {
@@ -199,21 +448,35 @@ func (svc *AuthService) Logout(ctx context.Context, token *domain.UserClaims) er
 		return err
 	}
 
-	return nil
+	// The refresh token of the session must not outlive the logout
+	return svc.tokenService.RevokeTokenFamily(ctx, token.FamilyID)
 }
 
 // ChangePassword implements the ChangePassword method of the AuthService interface
-func (svc *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
+// The old password is limited against brute force like the login.
+func (svc *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string, client *domain.ClientInfo) error {
+	if err := svc.passwordPolicy.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
 	// Retrieve the user based on the userID
 	user, err := svc.storage.GetUserByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 
+	if err := svc.lockout.Check(ctx, domain.LoginAction, user.Email, client.IPAddress); err != nil {
+		return err
+	}
+
 	// Verify the old password
 	err = util.CompareHash(oldPassword, user.Password)
 	if err != nil {
-		return domain.ErrPasswordsDoNotMatch
+		return svc.failAttempt(ctx, domain.LoginAction, user.Email, client, domain.ErrPasswordsDoNotMatch)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.LoginAction, user.Email); err != nil {
+		return err
 	}
 
 	// Update the password
@@ -223,8 +486,27 @@ func (svc *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, ol
 	}
 
 	user.Password = hashedPassword
-	_, err = svc.storage.UpdateUser(ctx, user)
-	return err
+	if _, err = svc.storage.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	// The new password completes a password reset forced by an admin
+	if user.ForcePasswordReset {
+		return svc.uncacheUser(ctx, userID)
+	}
+
+	return nil
+}
+
+// uncacheUser deletes the cached user once a forced action is completed,
+// the next requests of the user are checked against the updated user
+func (svc *AuthService) uncacheUser(ctx context.Context, userID uuid.UUID) error {
+	if err := svc.cache.Delete(ctx, util.GenerateCacheKey("user", userID)); err != nil {
+		svc.log.Error("failed to delete cached user", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
 }
 
 // ForgotPassword initiates the process of resetting a forgotten password
@@ -238,7 +520,7 @@ func (svc *AuthService) ForgotPassword(ctx context.Context, email string) error
 	user := converter.ToUser(userDAO)
 
 	// Generate and send reset OTP
-	resetOTP, err := svc.otp.GenerateOTP(ctx, user.ID)
+	resetOTP, err := svc.otp.GenerateOTP(ctx, user.ID, domain.PasswordResetOTP)
 	if err != nil {
 		svc.log.Error("failed to update user", sl.Err(err))
 		return domain.ErrInternal
@@ -254,12 +536,21 @@ func (svc *AuthService) ForgotPassword(ctx context.Context, email string) error
 }
 
 // ResetPassword confirms password reset with OTP code
-func (svc *AuthService) ResetPassword(ctx context.Context, email, newPassword, otp string) error {
+// The new password is checked first, a weak one does not use up the code.
+func (svc *AuthService) ResetPassword(ctx context.Context, email, newPassword, otp string, client *domain.ClientInfo) error {
+	if err := svc.passwordPolicy.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	if err := svc.lockout.Check(ctx, domain.ResetPasswordAction, email, client.IPAddress); err != nil {
+		return err
+	}
+
 	// Retrieve user by email
 	userDAO, err := svc.storage.GetUserByEmail(ctx, email)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
-			return domain.ErrInvalidOTP
+			return svc.failAttempt(ctx, domain.ResetPasswordAction, email, client, domain.ErrInvalidOTP)
 		}
 		svc.log.Error("failed to get the user by email", sl.Err(err))
 		return domain.ErrInternal
@@ -267,8 +558,16 @@ func (svc *AuthService) ResetPassword(ctx context.Context, email, newPassword, o
 	user := converter.ToUser(userDAO)
 
 	// Validate OTP
-	if err := svc.otp.VerifyOTP(ctx, user.ID, otp); err != nil {
-		return domain.ErrInvalidOTP
+	if err := svc.otp.VerifyOTP(ctx, user.ID, domain.PasswordResetOTP, otp); err != nil {
+		if err == domain.ErrInternal {
+			return err
+		}
+		return svc.failAttempt(ctx, domain.ResetPasswordAction, email, client, err)
+	}
+
+	// A reset password also ends a lockout of the login, which was probably the reason for it
+	if err := svc.lockout.Unlock(ctx, email); err != nil {
+		return err
 	}
 
 	// Update the password
@@ -284,5 +583,15 @@ func (svc *AuthService) ResetPassword(ctx context.Context, email, newPassword, o
 		return domain.ErrInternal
 	}
 
+	// The owner takes the account back, a pending email change may not be theirs
+	if err := svc.cache.Delete(ctx, util.GenerateCacheKey("email_change", user.ID)); err != nil {
+		svc.log.Error("failed to cancel email change", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if user.ForcePasswordReset {
+		return svc.uncacheUser(ctx, user.ID)
+	}
+
 	return nil
 }
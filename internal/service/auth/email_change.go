@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	mailSmtp "github.com/8thgencore/passfort/internal/clients/mail/smtp"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+)
+
+// emailChangeTTL is the time a user has to confirm an email change, the code sent may expire earlier
+const emailChangeTTL = time.Hour
+
+// emailChange is an email change waiting for the confirmation of the new email
+type emailChange struct {
+	NewEmail string `json:"new_email"`
+}
+
+// RequestEmailChange starts a change of the email of the user, who proves to be the owner with the password.
+// A code is sent to the new email, the change applies only once it is confirmed with ConfirmEmailChange.
+// The current email is notified of the request, resetting the password cancels the change.
+func (svc *AuthService) RequestEmailChange(ctx context.Context, userID uuid.UUID, password, newEmail string, client *domain.ClientInfo) error {
+	userDAO, err := svc.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if err := svc.lockout.Check(ctx, domain.LoginAction, userDAO.Email, client.IPAddress); err != nil {
+		return err
+	}
+
+	// The password is limited against brute force like the login
+	if err := util.CompareHash(password, userDAO.Password); err != nil {
+		return svc.failAttempt(ctx, domain.LoginAction, userDAO.Email, client, domain.ErrInvalidCredentials)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.LoginAction, userDAO.Email); err != nil {
+		return err
+	}
+
+	if newEmail == userDAO.Email {
+		return domain.ErrNoUpdatedData
+	}
+
+	_, err = svc.storage.GetUserByEmail(ctx, newEmail)
+	if err == nil {
+		return domain.ErrConflictingData
+	}
+	if err != domain.ErrDataNotFound {
+		svc.log.Error("failed to get the user by email", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	changeSerialized, err := util.Serialize(emailChange{NewEmail: newEmail})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("email_change", userID)
+	if err := svc.cache.Set(ctx, cacheKey, changeSerialized, emailChangeTTL); err != nil {
+		svc.log.Error("failed to store email change", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	otp, err := svc.otp.GenerateOTP(ctx, userID, domain.EmailChangeOTP)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := svc.notifier.SendEmailChangeCode(ctx, newEmail, otp); err != nil {
+		svc.log.Error("failed send email change confirmation", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if err := svc.notifier.SendNotice(ctx, userDAO.Email, mailSmtp.EmailChangeNotice); err != nil {
+		svc.log.Error("failed send email change notice", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange applies the pending email change of the user with the code sent to the new email.
+// The other sessions of the user are signed out if asked, the session making the request stays.
+func (svc *AuthService) ConfirmEmailChange(
+	ctx context.Context,
+	userID, currentSessionID uuid.UUID,
+	otp string,
+	revokeOtherSessions bool,
+	client *domain.ClientInfo,
+) (*domain.User, error) {
+	userDAO, err := svc.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.lockout.Check(ctx, domain.ConfirmEmailChangeAction, userDAO.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	cacheKey := util.GenerateCacheKey("email_change", userID)
+	changeSerialized, err := svc.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return nil, domain.ErrInvalidOTP
+	}
+
+	var change emailChange
+	if err := util.Deserialize(changeSerialized, &change); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.otp.VerifyOTP(ctx, userID, domain.EmailChangeOTP, otp); err != nil {
+		if err == domain.ErrInternal {
+			return nil, err
+		}
+		return nil, svc.failAttempt(ctx, domain.ConfirmEmailChangeAction, userDAO.Email, client, err)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.ConfirmEmailChangeAction, userDAO.Email); err != nil {
+		return nil, err
+	}
+
+	if err := svc.cache.Delete(ctx, cacheKey); err != nil {
+		svc.log.Error("failed to delete email change", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	userDAO.Email = change.NewEmail
+	userDAO, err = svc.storage.UpdateUser(ctx, userDAO)
+	if err != nil {
+		// The email has been taken since the request
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		svc.log.Error("failed to update user", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+	user := converter.ToUser(userDAO)
+
+	// Update cache
+	userSerialized, err := util.Serialize(user)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.cache.Set(ctx, util.GenerateCacheKey("user", user.ID), userSerialized, 0); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.cache.DeleteByPrefix(ctx, "users:*"); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if revokeOtherSessions {
+		if err := svc.tokenService.RevokeUserTokenFamilies(ctx, userID, currentSessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
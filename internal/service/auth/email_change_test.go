@@ -0,0 +1,232 @@
+package auth_test
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	mailv1 "github.com/8thgencore/mailfort/protos/gen/go/mail/v1"
+	mailGrpc "github.com/8thgencore/passfort/internal/clients/mail/grpc"
+	mailSmtp "github.com/8thgencore/passfort/internal/clients/mail/smtp"
+	"github.com/8thgencore/passfort/internal/clients/mail/smtp/smtptest"
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/auth"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/otp"
+	passwordpolicy "github.com/8thgencore/passfort/internal/service/password_policy"
+	"github.com/8thgencore/passfort/internal/service/token"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mailServer is a mail service keeping the last code sent to each email
+type mailServer struct {
+	mailv1.UnimplementedMailServiceServer
+
+	mu            sync.Mutex
+	confirmations map[string]string
+	resets        map[string]string
+}
+
+func (s *mailServer) SendConfirmationEmailOTPCode(_ context.Context, req *mailv1.SendEmailWithOTPCodeRequest) (*mailv1.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.confirmations[req.Email] = req.OtpCode
+	return &mailv1.Response{Success: true}, nil
+}
+
+func (s *mailServer) SendPasswordResetOTPCode(_ context.Context, req *mailv1.SendEmailWithOTPCodeRequest) (*mailv1.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resets[req.Email] = req.OtpCode
+	return &mailv1.Response{Success: true}, nil
+}
+
+// newMailServer starts a mail service and returns a client connected to it
+func newMailServer(t *testing.T) (*mailServer, *mailGrpc.Client) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &mailServer{confirmations: make(map[string]string), resets: make(map[string]string)}
+	grpcServer := grpc.NewServer()
+	mailv1.RegisterMailServiceServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	client, err := mailGrpc.New(context.Background(), slog.Default(), listener.Addr().String(), time.Second, 1)
+	require.NoError(t, err)
+
+	return server, client
+}
+
+const (
+	currentEmail = "user@example.com"
+	newEmail     = "new@example.com"
+	takenEmail   = "taken@example.com"
+	password     = "password123"
+)
+
+type emailChangeTest struct {
+	svc    *auth.AuthService
+	mail   *mailServer
+	smtp   *smtptest.Server
+	cache  *cacheMocks.MemoryCache
+	users  *mocks.UserRepository
+	tokens *mocks.TokenRepository
+	user   *dao.UserDAO
+}
+
+func setupEmailChangeTest(t *testing.T) *emailChangeTest {
+	log := slog.Default()
+	cache := cacheMocks.NewMemoryCache()
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	test := &emailChangeTest{
+		cache:  cache,
+		users:  &mocks.UserRepository{},
+		tokens: &mocks.TokenRepository{},
+		user:   &dao.UserDAO{ID: uuid.New(), Name: "Test", Email: currentEmail, Password: hashedPassword, IsVerified: true},
+	}
+	test.users.On("GetUserByID", mock.Anything, test.user.ID).Return(
+		func(context.Context, uuid.UUID) *dao.UserDAO { copied := *test.user; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	test.users.On("GetUserByEmail", mock.Anything, takenEmail).Return(&dao.UserDAO{ID: uuid.New(), Email: takenEmail}, nil)
+	test.users.On("GetUserByEmail", mock.Anything, newEmail).Return(nil, domain.ErrDataNotFound)
+	test.users.On("GetUserByEmail", mock.Anything, currentEmail).Return(
+		func(context.Context, string) *dao.UserDAO { copied := *test.user; return &copied },
+		func(context.Context, string) error { return nil },
+	)
+	test.users.On("UpdateUser", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, user *dao.UserDAO) *dao.UserDAO { *test.user = *user; return user },
+		func(context.Context, *dao.UserDAO) error { return nil },
+	)
+
+	var mailClient *mailGrpc.Client
+	test.mail, mailClient = newMailServer(t)
+	test.smtp = smtptest.NewServer(t)
+	notifier, err := mailSmtp.New(test.smtp.Host, test.smtp.Port, "", "", "PassFort <no-reply@example.com>", "PassFort", time.Second)
+	require.NoError(t, err)
+
+	otpService := otp.NewOtpService(log, cache, 10*time.Minute, 5)
+	tokenService := token.NewTokenService(log, newKeyring(t), "", 15*time.Minute, time.Hour, test.tokens, cache)
+	lockoutService := lockout.NewLockoutService(log, test.users, cache, nil, lockoutConfig)
+	passwordPolicy, err := passwordpolicy.NewPasswordPolicyService(log, &config.PasswordPolicy{
+		Account: config.PasswordRules{MinLength: 8, MinCharacterClasses: 2, MinEntropy: 40, Denylist: true},
+	})
+	require.NoError(t, err)
+	test.svc = auth.NewAuthService(log, test.users, &mocks.WebAuthnRepository{}, &mocks.OIDCRepository{}, &mocks.InviteRepository{}, cache, tokenService, otpService, nil, lockoutService,
+		passwordPolicy, mailClient, notifier, nil, nil, false, domain.OpenRegistration, nil, 5*time.Minute)
+
+	return test
+}
+
+var otpCode = regexp.MustCompile(`\b\d{6}\b`)
+
+// emailChangeCode returns the last code sent to confirm the new email
+func (test *emailChangeTest) emailChangeCode(t *testing.T) string {
+	messages := test.smtp.MessagesTo(newEmail)
+	require.NotEmpty(t, messages)
+	return otpCode.FindString(messages[len(messages)-1].Body)
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Sends a code to the new email and notifies the current one", func(t *testing.T) {
+		test := setupEmailChangeTest(t)
+
+		require.NoError(t, test.svc.RequestEmailChange(ctx, test.user.ID, password, newEmail, client))
+
+		assert.True(t, util.ValidateOTP(test.emailChangeCode(t)))
+		assert.Equal(t, "Confirm the new email of your PassFort account", test.smtp.MessagesTo(newEmail)[0].Subject)
+
+		notices := test.smtp.MessagesTo(currentEmail)
+		require.Len(t, notices, 1)
+		assert.Equal(t, "A new email was requested for your PassFort account", notices[0].Subject)
+		assert.Empty(t, test.mail.confirmations)
+		assert.Empty(t, test.mail.resets)
+		assert.Equal(t, currentEmail, test.user.Email)
+	})
+
+	t.Run("Returns error when password is wrong", func(t *testing.T) {
+		test := setupEmailChangeTest(t)
+
+		err := test.svc.RequestEmailChange(ctx, test.user.ID, "wrong-password", newEmail, client)
+		assert.Equal(t, domain.ErrInvalidCredentials, err)
+		assert.Empty(t, test.smtp.Messages())
+	})
+
+	t.Run("Returns error when email is taken or unchanged", func(t *testing.T) {
+		test := setupEmailChangeTest(t)
+
+		err := test.svc.RequestEmailChange(ctx, test.user.ID, password, takenEmail, client)
+		assert.Equal(t, domain.ErrConflictingData, err)
+
+		err = test.svc.RequestEmailChange(ctx, test.user.ID, password, currentEmail, client)
+		assert.Equal(t, domain.ErrNoUpdatedData, err)
+	})
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	ctx := context.Background()
+	currentSessionID, otherSessionID := uuid.New(), uuid.New()
+
+	t.Run("Changes the email and signs out the other sessions", func(t *testing.T) {
+		test := setupEmailChangeTest(t)
+		require.NoError(t, test.svc.RequestEmailChange(ctx, test.user.ID, password, newEmail, client))
+
+		_, err := test.svc.ConfirmEmailChange(ctx, test.user.ID, currentSessionID, "000000", true, client)
+		assert.Equal(t, domain.ErrInvalidOTP, err)
+		assert.Equal(t, currentEmail, test.user.Email)
+
+		test.tokens.On("ListTokenFamiliesByUserID", mock.Anything, test.user.ID, mock.Anything).Return([]dao.TokenFamilyDAO{
+			{ID: currentSessionID, UserID: test.user.ID},
+			{ID: otherSessionID, UserID: test.user.ID},
+		}, nil).Once()
+		test.tokens.On("RevokeTokenFamily", mock.Anything, otherSessionID).Return(nil).Once()
+
+		user, err := test.svc.ConfirmEmailChange(ctx, test.user.ID, currentSessionID, test.emailChangeCode(t), true, client)
+		require.NoError(t, err)
+		assert.Equal(t, newEmail, user.Email)
+		assert.Equal(t, newEmail, test.user.Email)
+		test.tokens.AssertExpectations(t)
+		test.tokens.AssertNotCalled(t, "RevokeTokenFamily", mock.Anything, currentSessionID)
+
+		var cached domain.User
+		cachedUser, err := test.cache.Get(ctx, "user:"+test.user.ID.String())
+		require.NoError(t, err)
+		require.NoError(t, util.Deserialize(cachedUser, &cached))
+		assert.Equal(t, newEmail, cached.Email)
+
+		// The change applies once
+		_, err = test.svc.ConfirmEmailChange(ctx, test.user.ID, currentSessionID, test.emailChangeCode(t), false, client)
+		assert.Equal(t, domain.ErrInvalidOTP, err)
+	})
+
+	t.Run("Resetting the password cancels the change", func(t *testing.T) {
+		test := setupEmailChangeTest(t)
+		require.NoError(t, test.svc.RequestEmailChange(ctx, test.user.ID, password, newEmail, client))
+
+		require.NoError(t, test.svc.ForgotPassword(ctx, currentEmail))
+		require.NoError(t, test.svc.ResetPassword(ctx, currentEmail, "Correct-Horse-42", test.mail.resets[currentEmail], client))
+
+		_, err := test.svc.ConfirmEmailChange(ctx, test.user.ID, currentSessionID, test.emailChangeCode(t), false, client)
+		assert.Equal(t, domain.ErrInvalidOTP, err)
+		assert.Equal(t, currentEmail, test.user.Email)
+	})
+}
@@ -0,0 +1,135 @@
+package auth_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginSuspendedUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Refuses a password login", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		wt.user.SuspendedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+		_, _, _, err := wt.svc.Login(ctx, wt.user.Email, "12345678", client)
+		assert.Equal(t, domain.ErrUserSuspended, err)
+
+		// A wrong password does not tell the account is suspended
+		_, _, _, err = wt.svc.Login(ctx, wt.user.Email, "wrong-password", client)
+		assert.Equal(t, domain.ErrInvalidCredentials, err)
+	})
+
+	t.Run("Refuses a passkey login", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		authenticator := newSoftAuthenticator(t, wt.user.ID)
+		wt.register(t, authenticator)
+		wt.credentials.On("UpdateCredentialUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		wt.user.SuspendedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+		assertion, err := wt.svc.BeginWebAuthnLogin(ctx)
+		require.NoError(t, err)
+
+		_, _, err = wt.svc.FinishWebAuthnLogin(ctx, authenticator.get(t, assertion), client)
+		assert.Equal(t, domain.ErrUserSuspended, err)
+	})
+}
+
+func TestForcedMFAEnrollment(t *testing.T) {
+	wt := setupWebAuthnTest(t)
+	wt.user.ForceMFAEnrollment = true
+	wt.users.On("UpdateUserStatus", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, user *dao.UserDAO) *dao.UserDAO { *wt.user = *user; return user },
+		func(context.Context, *dao.UserDAO) error { return nil },
+	).Once()
+
+	// The user logs in to enroll
+	accessToken, _, _, err := wt.svc.Login(context.Background(), wt.user.Email, "12345678", client)
+	require.NoError(t, err)
+	assert.NotEmpty(t, accessToken)
+	assert.True(t, wt.user.ForceMFAEnrollment)
+
+	// A registered passkey completes the enrollment
+	wt.register(t, newSoftAuthenticator(t, wt.user.ID))
+	assert.False(t, wt.user.ForceMFAEnrollment)
+	wt.users.AssertExpectations(t)
+}
+
+func TestLoginMFA(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*webAuthnTest, string) {
+		wt := setupWebAuthnTest(t)
+		key, err := totp.Generate(totp.GenerateOpts{Issuer: "passfort", AccountName: wt.user.Email})
+		require.NoError(t, err)
+		wt.totp = &dao.TOTPDAO{UserID: wt.user.ID, Secret: key.Secret(), Enabled: true}
+		return wt, key.Secret()
+	}
+
+	login := func(t *testing.T, wt *webAuthnTest) string {
+		_, _, mfaToken, err := wt.svc.Login(ctx, wt.user.Email, "12345678", client)
+		require.NoError(t, err)
+		require.NotEmpty(t, mfaToken)
+		return mfaToken
+	}
+
+	t.Run("Drops a challenge after too many codes", func(t *testing.T) {
+		// The account is not delayed before the challenge is dropped
+		freeAttempts := lockoutConfig.FreeAttempts
+		lockoutConfig.FreeAttempts = lockoutConfig.MaxAccountAttempts
+		t.Cleanup(func() { lockoutConfig.FreeAttempts = freeAttempts })
+
+		wt, secret := setup(t)
+		mfaToken := login(t, wt)
+
+		for range 5 {
+			_, _, err := wt.svc.LoginMFA(ctx, mfaToken, "wrong-code", client)
+			assert.Equal(t, domain.ErrInvalidMFACode, err)
+		}
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+		_, _, err = wt.svc.LoginMFA(ctx, mfaToken, code, client)
+		assert.Equal(t, domain.ErrInvalidMFAToken, err)
+	})
+
+	t.Run("Counts the wrong codes of the account over new challenges", func(t *testing.T) {
+		wt, secret := setup(t)
+
+		// A new challenge after each wrong code does not reset the count, the password login does not either
+		for range lockoutConfig.FreeAttempts {
+			_, _, err := wt.svc.LoginMFA(ctx, login(t, wt), "wrong-code", client)
+			assert.Equal(t, domain.ErrInvalidMFACode, err)
+		}
+		_, _, err := wt.svc.LoginMFA(ctx, login(t, wt), "wrong-code", client)
+		assert.ErrorIs(t, err, domain.ErrTooManyAttempts)
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+		_, _, err = wt.svc.LoginMFA(ctx, login(t, wt), code, client)
+		assert.ErrorIs(t, err, domain.ErrTooManyAttempts)
+	})
+
+	t.Run("Accepts a TOTP code only once", func(t *testing.T) {
+		wt, secret := setup(t)
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+
+		accessToken, _, err := wt.svc.LoginMFA(ctx, login(t, wt), code, client)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+
+		_, _, err = wt.svc.LoginMFA(ctx, login(t, wt), code, client)
+		assert.Equal(t, domain.ErrInvalidMFACode, err)
+	})
+}
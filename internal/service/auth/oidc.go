@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	oidcClient "github.com/8thgencore/passfort/internal/clients/oidc"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"golang.org/x/oauth2"
+)
+
+// oidcLoginTTL is the time a user has to log in at the provider
+const oidcLoginTTL = 10 * time.Minute
+
+// oidcLogin is a single sign-on waiting for the response of the provider
+type oidcLogin struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// BeginOIDCLogin starts a single sign-on and returns the URL of the provider to send the user to,
+// with the state the provider passes back to the redirect URL
+func (svc *AuthService) BeginOIDCLogin(ctx context.Context) (string, string, error) {
+	if svc.oidc == nil {
+		return "", "", domain.ErrOIDCNotConfigured
+	}
+
+	state, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", domain.ErrInternal
+	}
+	nonce, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", domain.ErrInternal
+	}
+
+	login := oidcLogin{
+		Nonce:    nonce,
+		Verifier: oauth2.GenerateVerifier(),
+	}
+
+	authURL, err := svc.oidc.AuthCodeURL(ctx, state, login.Nonce, login.Verifier)
+	if err != nil {
+		svc.log.Error("failed to discover the OpenID Connect provider", sl.Err(err))
+		return "", "", domain.ErrInternal
+	}
+
+	loginSerialized, err := util.Serialize(login)
+	if err != nil {
+		return "", "", domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("oidc_login", util.HashToken(state))
+	if err := svc.cache.Set(ctx, cacheKey, loginSerialized, oidcLoginTTL); err != nil {
+		svc.log.Error("failed to store OpenID Connect login", sl.Err(err))
+		return "", "", domain.ErrInternal
+	}
+
+	return authURL, state, nil
+}
+
+// FinishOIDCLogin completes a single sign-on with the code and the state passed to the redirect URL.
+// The user is found by the identity linked on a previous login, then by the email verified by the provider,
+// and is created if unknown and the sign-up is allowed.
+// As with Login, an MFA challenge token is returned instead of tokens if the user has a second factor.
+func (svc *AuthService) FinishOIDCLogin(ctx context.Context, code, state string, client *domain.ClientInfo) (string, string, string, error) {
+	if svc.oidc == nil {
+		return "", "", "", domain.ErrOIDCNotConfigured
+	}
+
+	// The state can only be used once
+	cacheKey := util.GenerateCacheKey("oidc_login", util.HashToken(state))
+	loginSerialized, err := svc.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return "", "", "", domain.ErrInvalidOIDCState
+	}
+	if err := svc.cache.Delete(ctx, cacheKey); err != nil {
+		svc.log.Error("failed to delete OpenID Connect login", sl.Err(err))
+		return "", "", "", domain.ErrInternal
+	}
+
+	var login oidcLogin
+	if err := util.Deserialize(loginSerialized, &login); err != nil {
+		return "", "", "", domain.ErrInvalidOIDCState
+	}
+
+	identity, err := svc.oidc.Exchange(ctx, code, login.Nonce, login.Verifier)
+	if err != nil {
+		svc.log.Warn("failed to verify OpenID Connect login", sl.Err(err))
+		return "", "", "", domain.ErrOIDCLoginFailed
+	}
+
+	user, err := svc.getOIDCUser(ctx, identity)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return svc.completeLogin(ctx, user, client)
+}
+
+// getOIDCUser gets the user of an identity at the provider, linking the identity on its first login
+func (svc *AuthService) getOIDCUser(ctx context.Context, identity *oidcClient.Identity) (*domain.User, error) {
+	identityDAO, err := svc.identityStorage.GetIdentityBySubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		if err := svc.identityStorage.UpdateIdentityLogin(ctx, identityDAO.ID, identity.Email); err != nil {
+			svc.log.Error("failed to update OpenID Connect identity", sl.Err(err))
+		}
+
+		userDAO, err := svc.storage.GetUserByID(ctx, identityDAO.UserID)
+		if err != nil {
+			svc.log.Error("failed to get the user of an OpenID Connect identity", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+
+		return converter.ToUser(userDAO), nil
+	}
+	if err != domain.ErrDataNotFound {
+		svc.log.Error("failed to get OpenID Connect identity", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	// Accounts are only linked by an email the provider has verified
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, domain.ErrOIDCEmailNotVerified
+	}
+
+	user, err := svc.getOrCreateOIDCUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.identityStorage.CreateIdentity(ctx, &dao.OIDCIdentityDAO{
+		UserID:  user.ID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		Email:   identity.Email,
+	})
+	if err != nil {
+		svc.log.Error("failed to link OpenID Connect identity", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return user, nil
+}
+
+// getOrCreateOIDCUser gets the user with the verified email of the identity or provisions a new one
+func (svc *AuthService) getOrCreateOIDCUser(ctx context.Context, identity *oidcClient.Identity) (*domain.User, error) {
+	userDAO, err := svc.storage.GetUserByEmail(ctx, identity.Email)
+	if err == nil && userDAO.IsVerified {
+		return converter.ToUser(userDAO), nil
+	}
+	if err != nil && err != domain.ErrDataNotFound {
+		svc.log.Error("failed to get the user by email", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+	// An unknown user signs up only if the registration mode lets them register without an invite code
+	if err == domain.ErrDataNotFound && (!svc.oidcAllowSignup || !svc.canRegisterWithoutInvite(identity.Email)) {
+		return nil, domain.ErrOIDCSignupDisabled
+	}
+
+	// A new account gets a random password, it logs in with the provider or resets its password.
+	// Nobody has proven to own the email of an unverified account, so its password is replaced as well.
+	password, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if userDAO == nil {
+		name := identity.Name
+		if name == "" {
+			name, _, _ = strings.Cut(identity.Email, "@")
+		}
+
+		userDAO, err = svc.storage.CreateUser(ctx, &dao.UserDAO{
+			Name:     name,
+			Email:    identity.Email,
+			Password: hashedPassword,
+		})
+		if err != nil {
+			svc.log.Error("failed to create a user", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+	}
+	userDAO.Password = hashedPassword
+	userDAO.IsVerified = true
+
+	userDAO, err = svc.storage.UpdateUser(ctx, userDAO)
+	if err != nil {
+		svc.log.Error("failed to update user", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.cache.Delete(ctx, util.GenerateCacheKey("user", userDAO.ID)); err != nil {
+		return nil, domain.ErrInternal
+	}
+	if err := svc.cache.DeleteByPrefix(ctx, "users:*"); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return converter.ToUser(userDAO), nil
+}
@@ -0,0 +1,323 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	oidcClient "github.com/8thgencore/passfort/internal/clients/oidc"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/auth"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/mfa"
+	"github.com/8thgencore/passfort/internal/service/token"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oidcClientID     = "passfort"
+	oidcClientSecret = "secret"
+	oidcKeyID        = "test-key"
+)
+
+// authorization is a code issued by the mock provider
+type authorization struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+// mockProvider is an OpenID Connect provider issuing ID tokens for the claims given to authorize
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &mockProvider{key: key, codes: make(map[string]authorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                p.server.URL,
+			"authorization_endpoint":                p.server.URL + "/authorize",
+			"token_endpoint":                        p.server.URL + "/token",
+			"jwks_uri":                              p.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": oidcKeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", p.token)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// authorize plays the login of the user at the provider and returns the code passed to the redirect URL
+func (p *mockProvider) authorize(t *testing.T, authURL string, claims jwt.MapClaims) string {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	query := u.Query()
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.Equal(t, oidcClientID, query.Get("client_id"))
+
+	code := uuid.NewString()
+	p.mu.Lock()
+	p.codes[code] = authorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		claims:    claims,
+	}
+	p.mu.Unlock()
+
+	return code
+}
+
+func (p *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	if clientID != oidcClientID || clientSecret != oidcClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	p.mu.Lock()
+	authz, ok := p.codes[r.PostFormValue("code")]
+	delete(p.codes, r.PostFormValue("code"))
+	p.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(verifier[:]) != authz.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   p.server.URL,
+		"aud":   oidcClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": authz.nonce,
+	}
+	for name, value := range authz.claims {
+		claims[name] = value
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = oidcKeyID
+	rawIDToken, err := idToken.SignedString(p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     rawIDToken,
+	})
+}
+
+type oidcTest struct {
+	svc        *auth.AuthService
+	provider   *mockProvider
+	users      *mocks.UserRepository
+	identities *mocks.OIDCRepository
+}
+
+func setupOIDCTest(t *testing.T, allowSignup bool, registrationMode domain.RegistrationMode) *oidcTest {
+	log := slog.Default()
+	cache := cacheMocks.NewMemoryCache()
+	provider := newMockProvider(t)
+
+	mfaStorage := &mocks.MFARepository{}
+	mfaStorage.On("GetTOTPByUserID", mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound)
+
+	credentials := &mocks.WebAuthnRepository{}
+	credentials.On("ListCredentialsByUserID", mock.Anything, mock.Anything).Return([]dao.WebAuthnCredentialDAO{}, nil)
+
+	tokens := &mocks.TokenRepository{}
+	tokens.On("CreateTokenFamily", mock.Anything, mock.Anything).Return(&dao.TokenFamilyDAO{ID: uuid.New()}, nil)
+
+	test := &oidcTest{
+		provider:   provider,
+		users:      &mocks.UserRepository{},
+		identities: &mocks.OIDCRepository{},
+	}
+
+	tokenService := token.NewTokenService(log, newKeyring(t), "", 15*time.Minute, time.Hour, tokens, cache)
+	lockoutService := lockout.NewLockoutService(log, test.users, cache, nil, lockoutConfig)
+	mfaService := mfa.NewMFAService(log, mfaStorage, test.users, cache, lockoutService, "passfort", make([]byte, 32))
+	oidc := oidcClient.New(provider.server.URL, oidcClientID, oidcClientSecret, "http://localhost:3000/callback", []string{"openid", "email", "profile"})
+	test.svc = auth.NewAuthService(log, test.users, credentials, test.identities, &mocks.InviteRepository{}, cache, tokenService, nil, mfaService, lockoutService,
+		nil, nil, nil, nil, oidc, allowSignup, registrationMode, nil, 5*time.Minute)
+
+	return test
+}
+
+// login runs a single sign-on of the user with the claims at the provider
+func (ot *oidcTest) login(t *testing.T, claims jwt.MapClaims) (string, string, string, error) {
+	ctx := context.Background()
+
+	authURL, state, err := ot.svc.BeginOIDCLogin(ctx)
+	require.NoError(t, err)
+
+	code := ot.provider.authorize(t, authURL, claims)
+
+	return ot.svc.FinishOIDCLogin(ctx, code, state, client)
+}
+
+func TestOIDCLogin(t *testing.T) {
+	claims := jwt.MapClaims{
+		"sub":            "provider-user-1",
+		"email":          "test@example.com",
+		"email_verified": true,
+		"name":           "John Doe",
+	}
+
+	t.Run("logs in a linked identity", func(t *testing.T) {
+		ot := setupOIDCTest(t, false, domain.OpenRegistration)
+		user := &dao.UserDAO{ID: uuid.New(), Email: "test@example.com", IsVerified: true, Role: string(domain.UserRole)}
+		identity := &dao.OIDCIdentityDAO{ID: uuid.New(), UserID: user.ID}
+
+		ot.identities.On("GetIdentityBySubject", mock.Anything, ot.provider.server.URL, "provider-user-1").Return(identity, nil).Once()
+		ot.identities.On("UpdateIdentityLogin", mock.Anything, identity.ID, "test@example.com").Return(nil).Once()
+		ot.users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil).Once()
+
+		accessToken, refreshToken, mfaToken, err := ot.login(t, claims)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+		assert.NotEmpty(t, refreshToken)
+		assert.Empty(t, mfaToken)
+		ot.identities.AssertExpectations(t)
+	})
+
+	t.Run("links a verified account by email", func(t *testing.T) {
+		ot := setupOIDCTest(t, false, domain.OpenRegistration)
+		user := &dao.UserDAO{ID: uuid.New(), Email: "test@example.com", IsVerified: true, Role: string(domain.UserRole)}
+
+		ot.identities.On("GetIdentityBySubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound).Once()
+		ot.users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(user, nil).Once()
+		ot.identities.On("CreateIdentity", mock.Anything, mock.MatchedBy(func(identity *dao.OIDCIdentityDAO) bool {
+			return identity.UserID == user.ID && identity.Subject == "provider-user-1"
+		})).Return(&dao.OIDCIdentityDAO{}, nil).Once()
+
+		accessToken, _, _, err := ot.login(t, claims)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+		ot.identities.AssertExpectations(t)
+	})
+
+	t.Run("provisions an unknown user", func(t *testing.T) {
+		ot := setupOIDCTest(t, true, domain.OpenRegistration)
+		userID := uuid.New()
+
+		ot.identities.On("GetIdentityBySubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound).Once()
+		ot.users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, domain.ErrDataNotFound).Once()
+		ot.users.On("CreateUser", mock.Anything, mock.MatchedBy(func(user *dao.UserDAO) bool {
+			return user.Name == "John Doe" && user.Password != ""
+		})).Return(func(_ context.Context, user *dao.UserDAO) *dao.UserDAO {
+			user.ID = userID
+			user.Role = string(domain.UserRole)
+			return user
+		}, nil).Once()
+		ot.users.On("UpdateUser", mock.Anything, mock.MatchedBy(func(user *dao.UserDAO) bool {
+			return user.ID == userID && user.IsVerified
+		})).Return(func(_ context.Context, user *dao.UserDAO) *dao.UserDAO { return user }, nil).Once()
+		ot.identities.On("CreateIdentity", mock.Anything, mock.Anything).Return(&dao.OIDCIdentityDAO{}, nil).Once()
+
+		accessToken, _, _, err := ot.login(t, claims)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+		ot.users.AssertExpectations(t)
+	})
+
+	t.Run("sign-up disabled", func(t *testing.T) {
+		ot := setupOIDCTest(t, false, domain.OpenRegistration)
+
+		ot.identities.On("GetIdentityBySubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound).Once()
+		ot.users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, domain.ErrDataNotFound).Once()
+
+		_, _, _, err := ot.login(t, claims)
+		assert.Equal(t, domain.ErrOIDCSignupDisabled, err)
+	})
+
+	t.Run("sign-up closed by the registration mode", func(t *testing.T) {
+		ot := setupOIDCTest(t, true, domain.InviteRegistration)
+
+		ot.identities.On("GetIdentityBySubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound).Once()
+		ot.users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, domain.ErrDataNotFound).Once()
+
+		_, _, _, err := ot.login(t, claims)
+		assert.Equal(t, domain.ErrOIDCSignupDisabled, err)
+		ot.users.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		ot := setupOIDCTest(t, true, domain.OpenRegistration)
+
+		ot.identities.On("GetIdentityBySubject", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound).Once()
+
+		_, _, _, err := ot.login(t, jwt.MapClaims{"sub": "provider-user-2", "email": "test@example.com", "email_verified": false})
+		assert.Equal(t, domain.ErrOIDCEmailNotVerified, err)
+	})
+
+	t.Run("state used twice", func(t *testing.T) {
+		ot := setupOIDCTest(t, false, domain.OpenRegistration)
+		ctx := context.Background()
+
+		_, state, err := ot.svc.BeginOIDCLogin(ctx)
+		require.NoError(t, err)
+
+		_, _, _, err = ot.svc.FinishOIDCLogin(ctx, "unknown-code", state, client)
+		assert.Equal(t, domain.ErrOIDCLoginFailed, err)
+
+		_, _, _, err = ot.svc.FinishOIDCLogin(ctx, "unknown-code", state, client)
+		assert.Equal(t, domain.ErrInvalidOIDCState, err)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+
+		_, _, err := wt.svc.BeginOIDCLogin(context.Background())
+		assert.Equal(t, domain.ErrOIDCNotConfigured, err)
+	})
+}
@@ -0,0 +1,129 @@
+package auth_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/auth"
+	"github.com/8thgencore/passfort/internal/service/otp"
+	passwordpolicy "github.com/8thgencore/passfort/internal/service/password_policy"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const inviteCode = "Zm9vYmFyYmF6cXV4Zm9vYmFy"
+
+type registerTest struct {
+	svc     *auth.AuthService
+	users   *mocks.UserRepository
+	invites *mocks.InviteRepository
+}
+
+func setupRegisterTest(t *testing.T, mode domain.RegistrationMode, domains ...string) *registerTest {
+	log := slog.Default()
+	cache := cacheMocks.NewMemoryCache()
+
+	test := &registerTest{
+		users:   &mocks.UserRepository{},
+		invites: &mocks.InviteRepository{},
+	}
+
+	// The stored user gets the role of the invite
+	createUser := func(role domain.UserRoleEnum) func(context.Context, *dao.UserDAO) *dao.UserDAO {
+		return func(_ context.Context, user *dao.UserDAO) *dao.UserDAO {
+			created := *user
+			created.ID = uuid.New()
+			created.Role = string(role)
+			return &created
+		}
+	}
+	test.users.On("CreateUser", mock.Anything, mock.Anything).Return(createUser(domain.UserRole), nil)
+	test.invites.On("CreateInvitedUser", mock.Anything, util.HashToken(inviteCode), mock.Anything).Return(
+		func(ctx context.Context, _ string, user *dao.UserDAO) *dao.UserDAO {
+			return createUser(domain.AdminRole)(ctx, user)
+		},
+		func(context.Context, string, *dao.UserDAO) error { return nil },
+	)
+	test.invites.On("CreateInvitedUser", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound)
+
+	_, mailClient := newMailServer(t)
+
+	passwordPolicy, err := passwordpolicy.NewPasswordPolicyService(log, &config.PasswordPolicy{
+		Account: config.PasswordRules{MinLength: 8, MinCharacterClasses: 2, MinEntropy: 40, Denylist: true},
+	})
+	require.NoError(t, err)
+
+	otpService := otp.NewOtpService(log, cache, 10*time.Minute, 5)
+	test.svc = auth.NewAuthService(log, test.users, &mocks.WebAuthnRepository{}, &mocks.OIDCRepository{}, test.invites, cache, nil, otpService, nil, nil,
+		passwordPolicy, mailClient, nil, nil, nil, false, mode, domains, 5*time.Minute)
+
+	return test
+}
+
+// newUser returns a user to register with the email
+func newUser(email string) *domain.User {
+	return &domain.User{Name: "Test", Email: email, Password: "Correct-Horse-42"}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Open registration needs no invite code", func(t *testing.T) {
+		test := setupRegisterTest(t, domain.OpenRegistration)
+
+		user, err := test.svc.Register(ctx, newUser("user@example.com"), "")
+		require.NoError(t, err)
+		assert.Equal(t, domain.UserRole, user.Role)
+		test.invites.AssertNotCalled(t, "CreateInvitedUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invite registration requires a valid invite code", func(t *testing.T) {
+		test := setupRegisterTest(t, domain.InviteRegistration)
+
+		_, err := test.svc.Register(ctx, newUser("user@example.com"), "")
+		assert.Equal(t, domain.ErrRegistrationClosed, err)
+
+		_, err = test.svc.Register(ctx, newUser("user@example.com"), "unknown")
+		assert.Equal(t, domain.ErrInvalidInvite, err)
+
+		test.users.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invite code gives its role", func(t *testing.T) {
+		test := setupRegisterTest(t, domain.InviteRegistration)
+
+		user, err := test.svc.Register(ctx, newUser("user@example.com"), inviteCode)
+		require.NoError(t, err)
+		assert.Equal(t, domain.AdminRole, user.Role)
+	})
+
+	t.Run("Domain registration lets the allowed domains register", func(t *testing.T) {
+		test := setupRegisterTest(t, domain.DomainRegistration, "example.com")
+
+		_, err := test.svc.Register(ctx, newUser("user@Example.com"), "")
+		require.NoError(t, err)
+
+		_, err = test.svc.Register(ctx, newUser("user@example.com.evil.org"), "")
+		assert.Equal(t, domain.ErrRegistrationClosed, err)
+
+		_, err = test.svc.Register(ctx, newUser("user@other.org"), inviteCode)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unknown mode requires an invite code", func(t *testing.T) {
+		test := setupRegisterTest(t, "opne")
+
+		_, err := test.svc.Register(ctx, newUser("user@example.com"), "")
+		assert.Equal(t, domain.ErrRegistrationClosed, err)
+	})
+}
@@ -2,11 +2,16 @@ package auth
 
 import (
 	"log/slog"
+	"time"
 
 	mailGrpc "github.com/8thgencore/passfort/internal/clients/mail/grpc"
+	mailSmtp "github.com/8thgencore/passfort/internal/clients/mail/smtp"
+	oidcClient "github.com/8thgencore/passfort/internal/clients/oidc"
+	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/service"
 	"github.com/8thgencore/passfort/internal/service/adapters/cache"
 	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+	"github.com/go-webauthn/webauthn/webauthn"
 )
 
 /**
@@ -15,30 +20,71 @@ import (
  * and token service
  */
 type AuthService struct {
-	log          *slog.Logger
-	storage      storage.UserRepository
-	cache        cache.CacheRepository
-	tokenService service.TokenService
-	otp          service.OtpService
-	mailClient   *mailGrpc.Client
+	log                 *slog.Logger
+	storage             storage.UserRepository
+	credentialStorage   storage.WebAuthnRepository
+	identityStorage     storage.OIDCRepository
+	inviteStorage       storage.InviteRepository
+	cache               cache.CacheRepository
+	tokenService        service.TokenService
+	otp                 service.OtpService
+	mfa                 service.MFAService
+	lockout             service.LockoutService
+	passwordPolicy      service.PasswordPolicyService
+	mailClient          *mailGrpc.Client
+	notifier            *mailSmtp.Client
+	webAuthn            *webauthn.WebAuthn
+	oidc                *oidcClient.Client
+	oidcAllowSignup     bool
+	registrationMode    domain.RegistrationMode
+	registrationDomains []string
+	mfaTokenTTL         time.Duration
 }
 
-// NewAuthService creates a new auth service instance
+// NewAuthService creates a new auth service instance.
+// The mail client sends the registration and password reset codes, the notifier the email change codes and notices.
+// The OpenID Connect client is nil when the single sign-on is disabled.
+// The registration domains are the email domains registering without an invite code in the domain mode.
 func NewAuthService(
 	log *slog.Logger,
 	storage storage.UserRepository,
+	credentialStorage storage.WebAuthnRepository,
+	identityStorage storage.OIDCRepository,
+	inviteStorage storage.InviteRepository,
 	cache cache.CacheRepository,
 	tokenService service.TokenService,
 	otpService service.OtpService,
+	mfaService service.MFAService,
+	lockoutService service.LockoutService,
+	passwordPolicy service.PasswordPolicyService,
 	mailClient *mailGrpc.Client,
-
+	notifier *mailSmtp.Client,
+	webAuthn *webauthn.WebAuthn,
+	oidc *oidcClient.Client,
+	oidcAllowSignup bool,
+	registrationMode domain.RegistrationMode,
+	registrationDomains []string,
+	mfaTokenTTL time.Duration,
 ) *AuthService {
 	return &AuthService{
 		log,
 		storage,
+		credentialStorage,
+		identityStorage,
+		inviteStorage,
 		cache,
 		tokenService,
 		otpService,
+		mfaService,
+		lockoutService,
+		passwordPolicy,
 		mailClient,
+		notifier,
+		webAuthn,
+		oidc,
+		oidcAllowSignup,
+		registrationMode,
+		registrationDomains,
+		mfaTokenTTL,
 	}
 }
@@ -0,0 +1,434 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// webAuthnSessionTTL is the time a user has to complete a WebAuthn ceremony
+const webAuthnSessionTTL = 5 * time.Minute
+
+// webAuthnUser adapts a user and their credentials to the webauthn.User interface
+type webAuthnUser struct {
+	user        *domain.User
+	credentials []domain.WebAuthnCredential
+}
+
+// WebAuthnID returns the user handle, which is the user ID
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+// WebAuthnName returns the email of the user
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+// WebAuthnDisplayName returns the name of the user
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Name
+}
+
+// WebAuthnCredentials returns the registered credentials of the user
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, credential := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+		for _, transport := range credential.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: credential.BackupEligible,
+				BackupState:    credential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+
+	return credentials
+}
+
+// findCredential finds the stored credential matching a validated webauthn credential
+func (u *webAuthnUser) findCredential(credential *webauthn.Credential) *domain.WebAuthnCredential {
+	for i := range u.credentials {
+		if string(u.credentials[i].CredentialID) == string(credential.ID) {
+			return &u.credentials[i]
+		}
+	}
+
+	return nil
+}
+
+// webAuthnMFASession represents a passkey assertion started for a pending login
+type webAuthnMFASession struct {
+	Session      webauthn.SessionData `json:"session"`
+	MFATokenHash string               `json:"mfa_token_hash"`
+}
+
+// BeginWebAuthnRegistration starts the registration of a new passkey for the user
+func (svc *AuthService) BeginWebAuthnRegistration(ctx context.Context, userID uuid.UUID) (*protocol.CredentialCreation, error) {
+	user, err := svc.getWebAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prevent registering the same authenticator twice
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.credentials))
+	for _, credential := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, session, err := svc.webAuthn.BeginRegistration(
+		user,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		svc.log.Error("failed to begin WebAuthn registration", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("webauthn_registration", userID)
+	if err := svc.setWebAuthnSession(ctx, cacheKey, session); err != nil {
+		return nil, err
+	}
+
+	return creation, nil
+}
+
+// FinishWebAuthnRegistration verifies the attestation of the authenticator and stores the new passkey
+func (svc *AuthService) FinishWebAuthnRegistration(ctx context.Context, userID uuid.UUID, name string, response []byte) (*domain.WebAuthnCredential, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, domain.ErrInvalidWebAuthnResponse
+	}
+
+	cacheKey := util.GenerateCacheKey("webauthn_registration", userID)
+
+	var session webauthn.SessionData
+	if err := svc.takeWebAuthnSession(ctx, cacheKey, &session); err != nil {
+		return nil, err
+	}
+
+	user, err := svc.getWebAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := svc.webAuthn.CreateCredential(user, session, parsed)
+	if err != nil {
+		return nil, domain.ErrInvalidWebAuthnResponse
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	credentialDAO, err := svc.credentialStorage.CreateCredential(ctx, &dao.WebAuthnCredentialDAO{
+		UserID:          userID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		Transports:      transports,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		Name:            name,
+	})
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		svc.log.Error("failed to store WebAuthn credential", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	// A passkey is a second factor, it completes an enrollment forced by an admin
+	if err := svc.mfa.CompleteEnrollment(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return converter.ToWebAuthnCredential(credentialDAO), nil
+}
+
+// ListWebAuthnCredentials returns the passkeys of the user
+func (svc *AuthService) ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]domain.WebAuthnCredential, error) {
+	credentialsDAO, err := svc.credentialStorage.ListCredentialsByUserID(ctx, userID)
+	if err != nil {
+		svc.log.Error("failed to list WebAuthn credentials", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	credentials := make([]domain.WebAuthnCredential, 0, len(credentialsDAO))
+	for _, credentialDAO := range credentialsDAO {
+		credentials = append(credentials, *converter.ToWebAuthnCredential(&credentialDAO))
+	}
+
+	return credentials, nil
+}
+
+// DeleteWebAuthnCredential deletes a passkey of the user
+func (svc *AuthService) DeleteWebAuthnCredential(ctx context.Context, userID, credentialID uuid.UUID) error {
+	err := svc.credentialStorage.DeleteCredential(ctx, userID, credentialID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("failed to delete WebAuthn credential", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// BeginWebAuthnLogin starts a passwordless login with a discoverable passkey.
+// The user is identified by the passkey itself, so no email is needed.
+func (svc *AuthService) BeginWebAuthnLogin(ctx context.Context) (*protocol.CredentialAssertion, error) {
+	assertion, session, err := svc.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		svc.log.Error("failed to begin WebAuthn login", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("webauthn_login", session.Challenge)
+	if err := svc.setWebAuthnSession(ctx, cacheKey, session); err != nil {
+		return nil, err
+	}
+
+	return assertion, nil
+}
+
+// FinishWebAuthnLogin verifies the passkey assertion and gives the user an access token.
+// A passkey with user verification is already multi-factor, so no second factor is requested.
+func (svc *AuthService) FinishWebAuthnLogin(ctx context.Context, response []byte, client *domain.ClientInfo) (string, string, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return "", "", domain.ErrInvalidWebAuthnResponse
+	}
+
+	cacheKey := util.GenerateCacheKey("webauthn_login", parsed.Response.CollectedClientData.Challenge)
+
+	var session webauthn.SessionData
+	if err := svc.takeWebAuthnSession(ctx, cacheKey, &session); err != nil {
+		return "", "", err
+	}
+
+	var user *webAuthnUser
+	credential, err := svc.webAuthn.ValidateDiscoverableLogin(
+		func(_, userHandle []byte) (webauthn.User, error) {
+			userID, err := uuid.FromBytes(userHandle)
+			if err != nil {
+				return nil, err
+			}
+
+			user, err = svc.getWebAuthnUser(ctx, userID)
+			return user, err
+		},
+		session,
+		parsed,
+	)
+	if err != nil {
+		return "", "", domain.ErrInvalidPasskey
+	}
+
+	if !user.user.IsVerified {
+		return "", "", domain.ErrUserNotVerified
+	}
+
+	if user.user.IsSuspended() {
+		return "", "", domain.ErrUserSuspended
+	}
+
+	if err := svc.updateWebAuthnCredential(ctx, user, credential); err != nil {
+		return "", "", err
+	}
+
+	accessToken, refreshToken, err := svc.tokenService.GenerateToken(ctx, user.user.ID, user.user.Role, client)
+	if err != nil {
+		return "", "", domain.ErrTokenCreation
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// BeginWebAuthnMFA starts a passkey assertion as the second factor of a login started by Login
+func (svc *AuthService) BeginWebAuthnMFA(ctx context.Context, mfaToken string) (*protocol.CredentialAssertion, error) {
+	_, challenge, err := svc.getMFAChallenge(ctx, util.HashToken(mfaToken))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := svc.getWebAuthnUser(ctx, challenge.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+
+	if len(user.credentials) == 0 {
+		return nil, domain.ErrMFANotEnabled
+	}
+
+	assertion, session, err := svc.webAuthn.BeginLogin(user)
+	if err != nil {
+		svc.log.Error("failed to begin WebAuthn login", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	mfaSession := webAuthnMFASession{
+		Session:      *session,
+		MFATokenHash: util.HashToken(mfaToken),
+	}
+
+	cacheKey := util.GenerateCacheKey("webauthn_mfa", session.Challenge)
+	if err := svc.setWebAuthnSession(ctx, cacheKey, mfaSession); err != nil {
+		return nil, err
+	}
+
+	return assertion, nil
+}
+
+// FinishWebAuthnMFA completes a login started by Login with a passkey assertion
+func (svc *AuthService) FinishWebAuthnMFA(ctx context.Context, response []byte, client *domain.ClientInfo) (string, string, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return "", "", domain.ErrInvalidWebAuthnResponse
+	}
+
+	cacheKey := util.GenerateCacheKey("webauthn_mfa", parsed.Response.CollectedClientData.Challenge)
+
+	var mfaSession webAuthnMFASession
+	if err := svc.takeWebAuthnSession(ctx, cacheKey, &mfaSession); err != nil {
+		return "", "", domain.ErrInvalidMFAToken
+	}
+
+	challengeKey, challenge, err := svc.getMFAChallenge(ctx, mfaSession.MFATokenHash)
+	if err != nil {
+		return "", "", err
+	}
+
+	user, err := svc.getWebAuthnUser(ctx, challenge.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return "", "", domain.ErrInvalidMFAToken
+		}
+		return "", "", err
+	}
+
+	if err := svc.countMFAAttempt(ctx, challengeKey, mfaSession.MFATokenHash); err != nil {
+		return "", "", err
+	}
+
+	credential, err := svc.webAuthn.ValidateLogin(user, mfaSession.Session, parsed)
+	if err != nil {
+		return "", "", domain.ErrInvalidPasskey
+	}
+
+	if err := svc.updateWebAuthnCredential(ctx, user, credential); err != nil {
+		return "", "", err
+	}
+
+	return svc.completeMFAChallenge(ctx, challengeKey, challenge, client)
+}
+
+// getWebAuthnUser gets a user with their registered passkeys
+func (svc *AuthService) getWebAuthnUser(ctx context.Context, userID uuid.UUID) (*webAuthnUser, error) {
+	userDAO, err := svc.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("failed to get the user by id", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	credentials, err := svc.ListWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webAuthnUser{
+		user:        converter.ToUser(userDAO),
+		credentials: credentials,
+	}, nil
+}
+
+// updateWebAuthnCredential stores the sign counter of a passkey after a successful assertion.
+// A sign counter that did not increase means the authenticator may have been cloned, so the login is refused.
+func (svc *AuthService) updateWebAuthnCredential(ctx context.Context, user *webAuthnUser, credential *webauthn.Credential) error {
+	stored := user.findCredential(credential)
+	if stored == nil {
+		return domain.ErrInvalidPasskey
+	}
+
+	if credential.Authenticator.CloneWarning {
+		svc.log.Warn("WebAuthn sign counter did not increase, the authenticator may be cloned",
+			"credentialID", stored.ID)
+		return domain.ErrInvalidPasskey
+	}
+
+	err := svc.credentialStorage.UpdateCredentialUsage(ctx, stored.ID, int64(credential.Authenticator.SignCount), credential.Flags.BackupState)
+	if err != nil {
+		svc.log.Error("failed to update WebAuthn credential", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// setWebAuthnSession stores the state of a WebAuthn ceremony until it is finished
+func (svc *AuthService) setWebAuthnSession(ctx context.Context, cacheKey string, session any) error {
+	sessionSerialized, err := util.Serialize(session)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := svc.cache.Set(ctx, cacheKey, sessionSerialized, webAuthnSessionTTL); err != nil {
+		svc.log.Error("failed to store WebAuthn session", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// takeWebAuthnSession gets and deletes the state of a WebAuthn ceremony, so each challenge is answered only once
+func (svc *AuthService) takeWebAuthnSession(ctx context.Context, cacheKey string, session any) error {
+	sessionSerialized, err := svc.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return domain.ErrInvalidWebAuthnResponse
+	}
+
+	if err := svc.cache.Delete(ctx, cacheKey); err != nil {
+		svc.log.Error("failed to delete WebAuthn session", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if err := util.Deserialize(sessionSerialized, session); err != nil {
+		return domain.ErrInvalidWebAuthnResponse
+	}
+
+	return nil
+}
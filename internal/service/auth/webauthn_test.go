@@ -0,0 +1,434 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/auth"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/mfa"
+	"github.com/8thgencore/passfort/internal/service/token"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rpID     = "localhost"
+	rpOrigin = "http://localhost:8080"
+)
+
+var client = &domain.ClientInfo{UserAgent: "Go-http-client/1.1", IPAddress: "127.0.0.1"}
+
+var lockoutConfig = &config.Lockout{
+	AttemptWindow:      15 * time.Minute,
+	FreeAttempts:       3,
+	BaseDelay:          time.Second,
+	MaxAccountAttempts: 10,
+	LockoutDuration:    15 * time.Minute,
+	MaxLockoutDuration: 24 * time.Hour,
+	MaxIPAttempts:      100,
+}
+
+// softAuthenticator is a software authenticator holding a single P-256 passkey
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T, userID uuid.UUID) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+
+	return &softAuthenticator{key: key, credentialID: credentialID, userHandle: userID[:]}
+}
+
+// authData builds the authenticator data, with the attested credential data for a registration
+func (a *softAuthenticator) authData(t *testing.T, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	// User present and user verified
+	flags := byte(0x01 | 0x04)
+	if attested {
+		flags |= 0x40
+	}
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	data = append(data, make([]byte, 16)...) // AAGUID
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+	data = append(data, a.credentialID...)
+	return append(data, publicKey...)
+}
+
+func clientData(t *testing.T, ceremony, challenge string) []byte {
+	data, err := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    rpOrigin,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// create answers navigator.credentials.create() with a "none" attestation
+func (a *softAuthenticator) create(t *testing.T, creation *protocol.CredentialCreation) []byte {
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(t, true),
+	})
+	require.NoError(t, err)
+
+	encode := base64.RawURLEncoding.EncodeToString
+	response, err := json.Marshal(map[string]any{
+		"id":    encode(a.credentialID),
+		"rawId": encode(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    encode(clientData(t, "webauthn.create", creation.Response.Challenge.String())),
+			"attestationObject": encode(attestationObject),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+// get answers navigator.credentials.get() with a signed assertion
+func (a *softAuthenticator) get(t *testing.T, assertion *protocol.CredentialAssertion) []byte {
+	a.signCount++
+
+	authData := a.authData(t, false)
+	clientDataJSON := clientData(t, "webauthn.get", assertion.Response.Challenge.String())
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	encode := base64.RawURLEncoding.EncodeToString
+	response, err := json.Marshal(map[string]any{
+		"id":    encode(a.credentialID),
+		"rawId": encode(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    encode(clientDataJSON),
+			"authenticatorData": encode(authData),
+			"signature":         encode(signature),
+			"userHandle":        encode(a.userHandle),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+type webAuthnTest struct {
+	svc         *auth.AuthService
+	users       *mocks.UserRepository
+	credentials *mocks.WebAuthnRepository
+	user        *dao.UserDAO
+	stored      []dao.WebAuthnCredentialDAO
+	totp        *dao.TOTPDAO // nil unless TOTP is enabled
+}
+
+func setupWebAuthnTest(t *testing.T) *webAuthnTest {
+	log := slog.Default()
+	cache := cacheMocks.NewMemoryCache()
+
+	password, err := util.HashPassword("12345678")
+	require.NoError(t, err)
+
+	user := &dao.UserDAO{
+		ID:         uuid.New(),
+		Name:       "John Doe",
+		Email:      "test@example.com",
+		Password:   password,
+		IsVerified: true,
+		Role:       string(domain.UserRole),
+	}
+
+	users := &mocks.UserRepository{}
+	users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+	users.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: "PassFort",
+		RPOrigins:     []string{rpOrigin},
+	})
+	require.NoError(t, err)
+
+	test := &webAuthnTest{
+		users:       users,
+		credentials: &mocks.WebAuthnRepository{},
+		user:        user,
+	}
+
+	mfaStorage := &mocks.MFARepository{}
+	mfaStorage.On("GetTOTPByUserID", mock.Anything, user.ID).Return(
+		func(context.Context, uuid.UUID) *dao.TOTPDAO {
+			if test.totp == nil {
+				return nil
+			}
+			copied := *test.totp
+			return &copied
+		},
+		func(context.Context, uuid.UUID) error {
+			if test.totp == nil {
+				return domain.ErrDataNotFound
+			}
+			return nil
+		},
+	)
+	mfaStorage.On("UpdateTOTPSecret", mock.Anything, user.ID, mock.Anything).Return(nil)
+	mfaStorage.On("UseBackupCode", mock.Anything, user.ID, mock.Anything).Return(domain.ErrDataNotFound)
+	test.credentials.On("ListCredentialsByUserID", mock.Anything, user.ID).Return(
+		func(context.Context, uuid.UUID) []dao.WebAuthnCredentialDAO { return test.stored },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+
+	tokens := &mocks.TokenRepository{}
+	tokens.On("CreateTokenFamily", mock.Anything, mock.Anything).Return(&dao.TokenFamilyDAO{ID: uuid.New()}, nil)
+
+	tokenService := token.NewTokenService(log, newKeyring(t), "", 15*time.Minute, time.Hour, tokens, cache)
+	lockoutService := lockout.NewLockoutService(log, users, cache, nil, lockoutConfig)
+	mfaService := mfa.NewMFAService(log, mfaStorage, users, cache, lockoutService, "passfort", make([]byte, 32))
+	test.svc = auth.NewAuthService(log, users, test.credentials, &mocks.OIDCRepository{}, &mocks.InviteRepository{}, cache, tokenService, nil, mfaService, lockoutService,
+		nil, nil, nil, webAuthn, nil, false, domain.OpenRegistration, nil, 5*time.Minute)
+
+	return test
+}
+
+// newKeyring creates a keyring with a signing key generated on load
+func newKeyring(t *testing.T) *token.Keyring {
+	var stored []dao.SigningKeyDAO
+
+	signingKeys := &mocks.SigningKeyRepository{}
+	signingKeys.On("ListSigningKeys", mock.Anything).Return(
+		func(context.Context) []dao.SigningKeyDAO { return stored },
+		func(context.Context) error { return nil },
+	)
+	signingKeys.On("CreateSigningKey", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, key *dao.SigningKeyDAO) *dao.SigningKeyDAO {
+			key.ID = uuid.New()
+			key.CreatedAt = time.Now()
+			stored = append(stored, *key)
+			return key
+		},
+		func(context.Context, *dao.SigningKeyDAO) error { return nil },
+	)
+
+	keyring := token.NewKeyring(slog.Default(), signingKeys, domain.EdDSA, 24*time.Hour, time.Hour)
+	require.NoError(t, keyring.Load(context.Background()))
+
+	return keyring
+}
+
+// register registers the passkey of the authenticator for the user
+func (wt *webAuthnTest) register(t *testing.T, authenticator *softAuthenticator) {
+	ctx := context.Background()
+
+	wt.credentials.On("CreateCredential", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, credential *dao.WebAuthnCredentialDAO) *dao.WebAuthnCredentialDAO {
+			credential.ID = uuid.New()
+			credential.CreatedAt = time.Now()
+			wt.stored = append(wt.stored, *credential)
+			return credential
+		},
+		func(context.Context, *dao.WebAuthnCredentialDAO) error { return nil },
+	).Once()
+
+	creation, err := wt.svc.BeginWebAuthnRegistration(ctx, wt.user.ID)
+	require.NoError(t, err)
+
+	credential, err := wt.svc.FinishWebAuthnRegistration(ctx, wt.user.ID, "Test key", authenticator.create(t, creation))
+	require.NoError(t, err)
+	assert.Equal(t, authenticator.credentialID, credential.CredentialID)
+	assert.Equal(t, "Test key", credential.Name)
+}
+
+func TestWebAuthnRegistration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("registers a passkey", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		wt.register(t, newSoftAuthenticator(t, wt.user.ID))
+
+		require.Len(t, wt.stored, 1)
+		assert.Equal(t, "none", wt.stored[0].AttestationType)
+		assert.NotEmpty(t, wt.stored[0].PublicKey)
+	})
+
+	t.Run("excludes registered passkeys", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		authenticator := newSoftAuthenticator(t, wt.user.ID)
+		wt.register(t, authenticator)
+
+		creation, err := wt.svc.BeginWebAuthnRegistration(ctx, wt.user.ID)
+		require.NoError(t, err)
+		require.Len(t, creation.Response.CredentialExcludeList, 1)
+		assert.Equal(t, protocol.URLEncodedBase64(authenticator.credentialID), creation.Response.CredentialExcludeList[0].CredentialID)
+	})
+
+	t.Run("rejects a response without a ceremony", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		authenticator := newSoftAuthenticator(t, wt.user.ID)
+
+		creation := &protocol.CredentialCreation{}
+		creation.Response.Challenge = protocol.URLEncodedBase64("unknown-challenge")
+
+		_, err := wt.svc.FinishWebAuthnRegistration(ctx, wt.user.ID, "Test key", authenticator.create(t, creation))
+		assert.Equal(t, domain.ErrInvalidWebAuthnResponse, err)
+	})
+}
+
+func TestWebAuthnLogin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("logs in with a passkey", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		authenticator := newSoftAuthenticator(t, wt.user.ID)
+		wt.register(t, authenticator)
+
+		wt.credentials.On("UpdateCredentialUsage", mock.Anything, wt.stored[0].ID, int64(1), false).Return(nil).Once()
+
+		assertion, err := wt.svc.BeginWebAuthnLogin(ctx)
+		require.NoError(t, err)
+
+		accessToken, refreshToken, err := wt.svc.FinishWebAuthnLogin(ctx, authenticator.get(t, assertion), client)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+		assert.NotEmpty(t, refreshToken)
+		wt.credentials.AssertExpectations(t)
+	})
+
+	t.Run("rejects a replayed assertion", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		authenticator := newSoftAuthenticator(t, wt.user.ID)
+		wt.register(t, authenticator)
+
+		wt.credentials.On("UpdateCredentialUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		assertion, err := wt.svc.BeginWebAuthnLogin(ctx)
+		require.NoError(t, err)
+
+		response := authenticator.get(t, assertion)
+		_, _, err = wt.svc.FinishWebAuthnLogin(ctx, response, client)
+		require.NoError(t, err)
+
+		_, _, err = wt.svc.FinishWebAuthnLogin(ctx, response, client)
+		assert.Equal(t, domain.ErrInvalidWebAuthnResponse, err)
+	})
+
+	t.Run("rejects a sign counter that did not increase", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		authenticator := newSoftAuthenticator(t, wt.user.ID)
+		wt.register(t, authenticator)
+
+		// A clone of the authenticator has already been used more often
+		wt.stored[0].SignCount = 5
+
+		assertion, err := wt.svc.BeginWebAuthnLogin(ctx)
+		require.NoError(t, err)
+
+		_, _, err = wt.svc.FinishWebAuthnLogin(ctx, authenticator.get(t, assertion), client)
+		assert.Equal(t, domain.ErrInvalidPasskey, err)
+		wt.credentials.AssertNotCalled(t, "UpdateCredentialUsage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an unknown passkey", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		wt.register(t, newSoftAuthenticator(t, wt.user.ID))
+
+		assertion, err := wt.svc.BeginWebAuthnLogin(ctx)
+		require.NoError(t, err)
+
+		_, _, err = wt.svc.FinishWebAuthnLogin(ctx, newSoftAuthenticator(t, wt.user.ID).get(t, assertion), client)
+		assert.Equal(t, domain.ErrInvalidPasskey, err)
+	})
+}
+
+func TestWebAuthnMFA(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("completes a password login with a passkey", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+		authenticator := newSoftAuthenticator(t, wt.user.ID)
+		wt.register(t, authenticator)
+
+		wt.credentials.On("UpdateCredentialUsage", mock.Anything, wt.stored[0].ID, int64(1), false).Return(nil).Once()
+
+		accessToken, _, mfaToken, err := wt.svc.Login(ctx, wt.user.Email, "12345678", client)
+		require.NoError(t, err)
+		assert.Empty(t, accessToken)
+		require.NotEmpty(t, mfaToken)
+
+		assertion, err := wt.svc.BeginWebAuthnMFA(ctx, mfaToken)
+		require.NoError(t, err)
+		require.Len(t, assertion.Response.AllowedCredentials, 1)
+
+		accessToken, refreshToken, err := wt.svc.FinishWebAuthnMFA(ctx, authenticator.get(t, assertion), client)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+		assert.NotEmpty(t, refreshToken)
+
+		// The MFA token is used up by the login
+		_, err = wt.svc.BeginWebAuthnMFA(ctx, mfaToken)
+		assert.Equal(t, domain.ErrInvalidMFAToken, err)
+	})
+
+	t.Run("logs in without MFA when no passkey is registered", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+
+		accessToken, _, mfaToken, err := wt.svc.Login(ctx, wt.user.Email, "12345678", client)
+		require.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+		assert.Empty(t, mfaToken)
+	})
+
+	t.Run("rejects an invalid MFA token", func(t *testing.T) {
+		wt := setupWebAuthnTest(t)
+
+		_, err := wt.svc.BeginWebAuthnMFA(ctx, "invalid")
+		assert.Equal(t, domain.ErrInvalidMFAToken, err)
+	})
+}
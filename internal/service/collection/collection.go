@@ -82,6 +82,33 @@ func (svc *CollectionService) DeleteCollection(ctx context.Context, userID, coll
 	return nil
 }
 
+// TransferOwnership makes another user the owner of a collection, only the current owner can transfer it
+func (svc *CollectionService) TransferOwnership(ctx context.Context, userID, collectionID, newOwnerID uuid.UUID) (*domain.Collection, error) {
+	collectionDAO, err := svc.storage.GetCollectionByID(ctx, collectionID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error getting collection", "collection", collectionID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if collectionDAO.CreatedBy != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	updatedCollectionDAO, err := svc.storage.TransferCollectionOwnership(ctx, collectionID, newOwnerID, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error transferring collection ownership", "collection", collectionID, "owner", newOwnerID, sl.Err(err))
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	return converter.ToCollection(updatedCollectionDAO), nil
+}
+
 // isUserPartOfCollection checks if the user is part of the given collection
 func (svc *CollectionService) isUserPartOfCollection(ctx context.Context, userID, collectionID uuid.UUID) bool {
 	isPartOfCollection, err := svc.storage.IsUserPartOfCollection(ctx, userID, collectionID)
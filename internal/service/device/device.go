@@ -0,0 +1,310 @@
+package device
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// deviceCodeSize is the number of random bytes of a device code
+	deviceCodeSize = 32
+	// userCodeAlphabet has no vowels so user codes do not spell words, as RFC 8628 suggests
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	// userCodeLength is the number of characters of a user code, shown as two groups of four
+	userCodeLength = 8
+)
+
+// RequestDeviceAuthorization starts the login of the device making the request.
+// The device code is returned only once, the device polls ExchangeDeviceCode with it
+// while the user approves the user code on the verification page.
+func (svc *DeviceService) RequestDeviceAuthorization(ctx context.Context, client *domain.ClientInfo) (string, *domain.DeviceAuthorization, error) {
+	deviceCode, err := util.GenerateRandomToken(deviceCodeSize)
+	if err != nil {
+		svc.log.Error("failed to generate device code", sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+
+	userCode, err := svc.generateUserCode(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	authorization := &domain.DeviceAuthorization{
+		UserCode:        userCode,
+		VerificationURI: svc.cfg.VerificationURI,
+		Interval:        svc.cfg.PollInterval,
+		Status:          domain.DeviceAuthorizationPending,
+		DeviceName:      client.DeviceName,
+		UserAgent:       client.UserAgent,
+		IPAddress:       client.IPAddress,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(svc.cfg.CodeTTL),
+	}
+
+	deviceCodeHash := util.HashToken(deviceCode)
+	if err := svc.storeAuthorization(ctx, deviceCodeHash, authorization); err != nil {
+		return "", nil, err
+	}
+
+	if err := svc.cache.Set(ctx, util.GenerateCacheKey("device_user_code", userCode), []byte(deviceCodeHash), svc.cfg.CodeTTL); err != nil {
+		svc.log.Error("failed to store device user code", sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+
+	return deviceCode, authorization, nil
+}
+
+// GetDeviceAuthorization returns the pending device authorization of the user code,
+// so the user can check the device before approving it
+func (svc *DeviceService) GetDeviceAuthorization(ctx context.Context, userID uuid.UUID, userCode string, client *domain.ClientInfo) (*domain.DeviceAuthorization, error) {
+	_, authorization, err := svc.verifyUserCode(ctx, userID, userCode, client)
+	return authorization, err
+}
+
+// ApproveDeviceAuthorization logs the device of the user code in as the user, it gets the tokens on its next poll
+func (svc *DeviceService) ApproveDeviceAuthorization(ctx context.Context, userID uuid.UUID, userCode string, client *domain.ClientInfo) error {
+	return svc.completeAuthorization(ctx, userID, userCode, domain.DeviceAuthorizationApproved, client)
+}
+
+// DenyDeviceAuthorization refuses the login of the device of the user code, it stops polling on its next poll
+func (svc *DeviceService) DenyDeviceAuthorization(ctx context.Context, userID uuid.UUID, userCode string, client *domain.ClientInfo) error {
+	return svc.completeAuthorization(ctx, userID, userCode, domain.DeviceAuthorizationDenied, client)
+}
+
+// ExchangeDeviceCode gives the device a token pair of a new session once the user approved it.
+// Until then it returns ErrDeviceAuthorizationPending, or ErrDeviceSlowDown if the device polls more often than the interval.
+// A device code is exchanged only once.
+func (svc *DeviceService) ExchangeDeviceCode(ctx context.Context, deviceCode string) (string, string, error) {
+	deviceCodeHash := util.HashToken(deviceCode)
+
+	authorization, err := svc.loadAuthorization(ctx, deviceCodeHash)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return "", "", domain.ErrExpiredDeviceCode
+		}
+		return "", "", err
+	}
+
+	// A single poll is counted per interval, so two polls cannot both exchange the approved code.
+	// The window is a little shorter than the interval, a device polling on time is not slowed down by network delays.
+	polls, err := svc.cache.Increment(ctx, util.GenerateCacheKey("device_poll", deviceCodeHash), svc.cfg.PollInterval*4/5)
+	if err != nil {
+		svc.log.Error("failed to count device polls", sl.Err(err))
+		return "", "", domain.ErrInternal
+	}
+	if polls > 1 {
+		return "", "", domain.ErrDeviceSlowDown
+	}
+
+	switch authorization.Status {
+	case domain.DeviceAuthorizationApproved:
+	case domain.DeviceAuthorizationDenied:
+		svc.deleteAuthorization(ctx, deviceCodeHash)
+		return "", "", domain.ErrDeviceAccessDenied
+	default:
+		return "", "", domain.ErrDeviceAuthorizationPending
+	}
+
+	svc.deleteAuthorization(ctx, deviceCodeHash)
+
+	userDAO, err := svc.userStorage.GetUserByID(ctx, authorization.UserID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			// The user has been deleted since the approval
+			return "", "", domain.ErrDeviceAccessDenied
+		}
+		svc.log.Error("failed to get the user approving the device", sl.Err(err))
+		return "", "", domain.ErrInternal
+	}
+
+	// The user has been suspended since the approval
+	if userDAO.SuspendedAt.Valid {
+		return "", "", domain.ErrDeviceAccessDenied
+	}
+
+	// The session is the one of the device, not of the browser approving it
+	client := &domain.ClientInfo{
+		DeviceName: authorization.DeviceName,
+		UserAgent:  authorization.UserAgent,
+		IPAddress:  authorization.IPAddress,
+	}
+
+	return svc.tokenService.GenerateDeviceToken(ctx, userDAO.ID, domain.UserRoleEnum(userDAO.Role), client)
+}
+
+// completeAuthorization records the decision of the user on the device authorization of the user code
+func (svc *DeviceService) completeAuthorization(
+	ctx context.Context,
+	userID uuid.UUID,
+	userCode string,
+	status domain.DeviceAuthorizationStatus,
+	client *domain.ClientInfo,
+) error {
+	deviceCodeHash, authorization, err := svc.verifyUserCode(ctx, userID, userCode, client)
+	if err != nil {
+		return err
+	}
+
+	// The user code is used once, the device code stays for the device to poll the result
+	if err := svc.cache.Delete(ctx, util.GenerateCacheKey("device_user_code", authorization.UserCode)); err != nil {
+		svc.log.Error("failed to delete device user code", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	authorization.Status = status
+	authorization.UserID = userID
+
+	return svc.storeAuthorization(ctx, deviceCodeHash, authorization)
+}
+
+// verifyUserCode returns the pending device authorization of the user code and the hash of its device code.
+// Wrong user codes are limited against brute force for the user entering them.
+func (svc *DeviceService) verifyUserCode(
+	ctx context.Context,
+	userID uuid.UUID,
+	userCode string,
+	client *domain.ClientInfo,
+) (string, *domain.DeviceAuthorization, error) {
+	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return "", nil, err
+		}
+		svc.log.Error("failed to get the user", sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+
+	if err := svc.lockout.Check(ctx, domain.VerifyDeviceAction, userDAO.Email, client.IPAddress); err != nil {
+		return "", nil, err
+	}
+
+	deviceCodeHash, authorization, err := svc.findAuthorization(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		if err == domain.ErrInvalidUserCode {
+			if lockErr := svc.lockout.Fail(ctx, domain.VerifyDeviceAction, userDAO.Email, client.IPAddress); lockErr != nil {
+				return "", nil, lockErr
+			}
+		}
+		return "", nil, err
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.VerifyDeviceAction, userDAO.Email); err != nil {
+		return "", nil, err
+	}
+
+	return deviceCodeHash, authorization, nil
+}
+
+// findAuthorization returns the pending device authorization of a normalized user code and the hash of its device code
+func (svc *DeviceService) findAuthorization(ctx context.Context, userCode string) (string, *domain.DeviceAuthorization, error) {
+	deviceCodeHash, err := svc.cache.Get(ctx, util.GenerateCacheKey("device_user_code", userCode))
+	if err != nil {
+		return "", nil, domain.ErrInvalidUserCode
+	}
+
+	authorization, err := svc.loadAuthorization(ctx, string(deviceCodeHash))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return "", nil, domain.ErrInvalidUserCode
+		}
+		return "", nil, err
+	}
+
+	if authorization.Status != domain.DeviceAuthorizationPending {
+		return "", nil, domain.ErrInvalidUserCode
+	}
+
+	return string(deviceCodeHash), authorization, nil
+}
+
+// loadAuthorization returns the device authorization of the device code hash
+func (svc *DeviceService) loadAuthorization(ctx context.Context, deviceCodeHash string) (*domain.DeviceAuthorization, error) {
+	authorizationSerialized, err := svc.cache.Get(ctx, util.GenerateCacheKey("device_code", deviceCodeHash))
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	var authorization domain.DeviceAuthorization
+	if err := util.Deserialize(authorizationSerialized, &authorization); err != nil {
+		svc.log.Error("failed to deserialize device authorization", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return &authorization, nil
+}
+
+// storeAuthorization stores the device authorization of the device code hash until it expires
+func (svc *DeviceService) storeAuthorization(ctx context.Context, deviceCodeHash string, authorization *domain.DeviceAuthorization) error {
+	ttl := time.Until(authorization.ExpiresAt)
+	if ttl <= 0 {
+		return domain.ErrInvalidUserCode
+	}
+
+	authorizationSerialized, err := util.Serialize(authorization)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if err := svc.cache.Set(ctx, util.GenerateCacheKey("device_code", deviceCodeHash), authorizationSerialized, ttl); err != nil {
+		svc.log.Error("failed to store device authorization", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// deleteAuthorization deletes the device authorization of the device code hash once its result is polled
+func (svc *DeviceService) deleteAuthorization(ctx context.Context, deviceCodeHash string) {
+	if err := svc.cache.Delete(ctx, util.GenerateCacheKey("device_code", deviceCodeHash)); err != nil {
+		svc.log.Error("failed to delete device authorization", sl.Err(err))
+	}
+}
+
+// generateUserCode generates a user code not used by another pending device authorization, e.g. "BDWP-HQTK"
+func (svc *DeviceService) generateUserCode(ctx context.Context) (string, error) {
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+
+	for range 3 {
+		code := make([]byte, userCodeLength)
+		for i := range code {
+			n, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				svc.log.Error("failed to generate device user code", sl.Err(err))
+				return "", domain.ErrInternal
+			}
+			code[i] = userCodeAlphabet[n.Int64()]
+		}
+		userCode := normalizeUserCode(string(code))
+
+		exists, err := svc.cache.Exists(ctx, util.GenerateCacheKey("device_user_code", userCode))
+		if err != nil {
+			svc.log.Error("failed to check device user code", sl.Err(err))
+			return "", domain.ErrInternal
+		}
+		if !exists {
+			return userCode, nil
+		}
+	}
+
+	svc.log.Error("failed to generate an unused device user code")
+	return "", domain.ErrInternal
+}
+
+// normalizeUserCode normalizes a user code typed by a user, so that "bdwphqtk" matches "BDWP-HQTK"
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != userCodeLength {
+		return code
+	}
+
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
@@ -0,0 +1,217 @@
+package device_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/device"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/token"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	deviceClient  = &domain.ClientInfo{DeviceName: "passfort-cli", UserAgent: "passfort-cli/1.0", IPAddress: "10.0.0.2"}
+	browserClient = &domain.ClientInfo{DeviceName: "Firefox", UserAgent: "Mozilla/5.0", IPAddress: "10.0.0.1"}
+)
+
+type deviceTest struct {
+	svc      *device.DeviceService
+	cache    *cacheMocks.MemoryCache
+	tokens   *mocks.TokenRepository
+	token    *token.TokenService
+	user     *dao.UserDAO
+	families []dao.TokenFamilyDAO
+}
+
+func setupDeviceTest(t *testing.T) *deviceTest {
+	log := slog.Default()
+
+	test := &deviceTest{
+		cache:  cacheMocks.NewMemoryCache(),
+		tokens: &mocks.TokenRepository{},
+		user:   &dao.UserDAO{ID: uuid.New(), Email: "user@example.com", Role: string(domain.UserRole), IsVerified: true},
+	}
+
+	users := &mocks.UserRepository{}
+	users.On("GetUserByID", mock.Anything, test.user.ID).Return(test.user, nil)
+	users.On("GetUserByEmail", mock.Anything, test.user.Email).Return(test.user, nil)
+
+	test.tokens.On("CreateTokenFamily", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, family *dao.TokenFamilyDAO) *dao.TokenFamilyDAO {
+			family.ID = uuid.New()
+			test.families = append(test.families, *family)
+			return family
+		},
+		func(context.Context, *dao.TokenFamilyDAO) error { return nil },
+	)
+
+	test.token = token.NewTokenService(log, newKeyring(t), "", 15*time.Minute, time.Hour, test.tokens, test.cache)
+	lockoutService := lockout.NewLockoutService(log, users, test.cache, nil, &config.Lockout{
+		AttemptWindow:      15 * time.Minute,
+		FreeAttempts:       3,
+		BaseDelay:          time.Second,
+		MaxAccountAttempts: 10,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: 24 * time.Hour,
+		MaxIPAttempts:      100,
+	})
+	test.svc = device.NewDeviceService(log, users, test.cache, test.token, lockoutService, &config.Device{
+		VerificationURI: "https://passfort.example.com/device",
+		CodeTTL:         10 * time.Minute,
+		PollInterval:    5 * time.Second,
+	})
+
+	return test
+}
+
+// newKeyring creates a keyring with a signing key generated on load
+func newKeyring(t *testing.T) *token.Keyring {
+	var stored []dao.SigningKeyDAO
+
+	signingKeys := &mocks.SigningKeyRepository{}
+	signingKeys.On("ListSigningKeys", mock.Anything).Return(
+		func(context.Context) []dao.SigningKeyDAO { return stored },
+		func(context.Context) error { return nil },
+	)
+	signingKeys.On("CreateSigningKey", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, key *dao.SigningKeyDAO) *dao.SigningKeyDAO {
+			key.ID = uuid.New()
+			key.CreatedAt = time.Now()
+			stored = append(stored, *key)
+			return key
+		},
+		func(context.Context, *dao.SigningKeyDAO) error { return nil },
+	)
+
+	keyring := token.NewKeyring(slog.Default(), signingKeys, domain.EdDSA, 24*time.Hour, time.Hour)
+	require.NoError(t, keyring.Load(context.Background()))
+
+	return keyring
+}
+
+// waitInterval lets the poll interval of the device code elapse
+func (dt *deviceTest) waitInterval(t *testing.T, deviceCode string) {
+	require.NoError(t, dt.cache.Delete(context.Background(), "device_poll:"+util.HashToken(deviceCode)))
+}
+
+func TestRequestDeviceAuthorization(t *testing.T) {
+	test := setupDeviceTest(t)
+
+	deviceCode, authorization, err := test.svc.RequestDeviceAuthorization(context.Background(), deviceClient)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, deviceCode)
+	assert.Regexp(t, `^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`, authorization.UserCode)
+	assert.Equal(t, "https://passfort.example.com/device", authorization.VerificationURI)
+	assert.Equal(t, "https://passfort.example.com/device?user_code="+authorization.UserCode, authorization.VerificationURIComplete())
+	assert.Equal(t, 5*time.Second, authorization.Interval)
+	assert.Equal(t, domain.DeviceAuthorizationPending, authorization.Status)
+
+	// Only the hash of the device code is stored
+	for _, key := range test.cache.Keys() {
+		assert.NotContains(t, key, deviceCode)
+	}
+}
+
+func TestExchangeDeviceCode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Polls until the user approves the device", func(t *testing.T) {
+		test := setupDeviceTest(t)
+		deviceCode, authorization, err := test.svc.RequestDeviceAuthorization(ctx, deviceClient)
+		require.NoError(t, err)
+
+		_, _, err = test.svc.ExchangeDeviceCode(ctx, deviceCode)
+		assert.Equal(t, domain.ErrDeviceAuthorizationPending, err)
+
+		// Polling again within the interval
+		_, _, err = test.svc.ExchangeDeviceCode(ctx, deviceCode)
+		assert.Equal(t, domain.ErrDeviceSlowDown, err)
+
+		// The user checks the device, typing the code loosely
+		pending, err := test.svc.GetDeviceAuthorization(ctx, test.user.ID, strings.ToLower(strings.ReplaceAll(authorization.UserCode, "-", "")), browserClient)
+		require.NoError(t, err)
+		assert.Equal(t, deviceClient.DeviceName, pending.DeviceName)
+
+		require.NoError(t, test.svc.ApproveDeviceAuthorization(ctx, test.user.ID, authorization.UserCode, browserClient))
+
+		test.waitInterval(t, deviceCode)
+		accessToken, refreshToken, err := test.svc.ExchangeDeviceCode(ctx, deviceCode)
+		require.NoError(t, err)
+		assert.NotEmpty(t, refreshToken)
+
+		claims, err := test.token.ParseUserClaims(accessToken)
+		require.NoError(t, err)
+		assert.Equal(t, test.user.ID, claims.UserID)
+
+		// The session is the one of the device
+		require.Len(t, test.families, 1)
+		assert.Equal(t, deviceClient.DeviceName, test.families[0].DeviceName)
+		assert.Equal(t, deviceClient.IPAddress, test.families[0].IPAddress)
+
+		// The user did not log in on the device, its session steps up for sensitive operations
+		assert.False(t, test.families[0].AuthenticatedAt.Valid)
+
+		// The device code is exchanged once
+		test.waitInterval(t, deviceCode)
+		_, _, err = test.svc.ExchangeDeviceCode(ctx, deviceCode)
+		assert.Equal(t, domain.ErrExpiredDeviceCode, err)
+		assert.Len(t, test.families, 1)
+	})
+
+	t.Run("Returns access denied when the user denies the device", func(t *testing.T) {
+		test := setupDeviceTest(t)
+		deviceCode, authorization, err := test.svc.RequestDeviceAuthorization(ctx, deviceClient)
+		require.NoError(t, err)
+
+		require.NoError(t, test.svc.DenyDeviceAuthorization(ctx, test.user.ID, authorization.UserCode, browserClient))
+
+		// The user code is used once
+		err = test.svc.ApproveDeviceAuthorization(ctx, test.user.ID, authorization.UserCode, browserClient)
+		assert.Equal(t, domain.ErrInvalidUserCode, err)
+
+		_, _, err = test.svc.ExchangeDeviceCode(ctx, deviceCode)
+		assert.Equal(t, domain.ErrDeviceAccessDenied, err)
+
+		test.waitInterval(t, deviceCode)
+		_, _, err = test.svc.ExchangeDeviceCode(ctx, deviceCode)
+		assert.Equal(t, domain.ErrExpiredDeviceCode, err)
+		assert.Empty(t, test.families)
+	})
+
+	t.Run("Returns expired token for an unknown device code", func(t *testing.T) {
+		test := setupDeviceTest(t)
+
+		_, _, err := test.svc.ExchangeDeviceCode(ctx, "unknown")
+		assert.Equal(t, domain.ErrExpiredDeviceCode, err)
+	})
+}
+
+func TestVerifyUserCode(t *testing.T) {
+	ctx := context.Background()
+	test := setupDeviceTest(t)
+
+	for range 3 {
+		err := test.svc.ApproveDeviceAuthorization(ctx, test.user.ID, "BCDF-GHJK", browserClient)
+		assert.Equal(t, domain.ErrInvalidUserCode, err)
+	}
+
+	// Guessing user codes is limited like the login
+	err := test.svc.ApproveDeviceAuthorization(ctx, test.user.ID, "BCDF-GHJK", browserClient)
+	var retryErr *domain.RetryAfterError
+	assert.True(t, errors.As(err, &retryErr), "expected a retry error, got %v", err)
+}
@@ -0,0 +1,42 @@
+package device
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/8thgencore/passfort/internal/service/adapters/cache"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * DeviceService implements service.DeviceService interface
+ * and logs in CLI and headless clients approved by a user with the token service
+ */
+type DeviceService struct {
+	log          *slog.Logger
+	userStorage  storage.UserRepository
+	cache        cache.CacheRepository
+	tokenService service.TokenService
+	lockout      service.LockoutService
+	cfg          *config.Device
+}
+
+// NewDeviceService creates a new device service instance
+func NewDeviceService(
+	log *slog.Logger,
+	userStorage storage.UserRepository,
+	cache cache.CacheRepository,
+	tokenService service.TokenService,
+	lockoutService service.LockoutService,
+	cfg *config.Device,
+) *DeviceService {
+	return &DeviceService{
+		log,
+		userStorage,
+		cache,
+		tokenService,
+		lockoutService,
+		cfg,
+	}
+}
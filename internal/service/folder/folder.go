@@ -0,0 +1,182 @@
+package folder
+
+import (
+	"context"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/google/uuid"
+)
+
+// CreateFolder creates a new folder inside a collection or inside another folder
+func (svc *FolderService) CreateFolder(ctx context.Context, userID uuid.UUID, folder *domain.Folder) (*domain.Folder, error) {
+	if !svc.isUserPartOfCollection(ctx, userID, folder.CollectionID) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	if folder.ParentID != uuid.Nil {
+		if _, err := svc.getCollectionFolder(ctx, folder.CollectionID, folder.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	folder.CreatedBy = userID
+	folder.UpdatedBy = userID
+
+	folderDAO, err := svc.folderStorage.CreateFolder(ctx, converter.ToFolderDAO(folder))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error creating folder", "collection", folder.CollectionID, sl.Err(err))
+		return nil, domain.ErrDataNotAdded
+	}
+
+	return converter.ToFolder(folderDAO), nil
+}
+
+// ListFolders lists the direct children of a folder, an empty parent ID lists the collection root
+func (svc *FolderService) ListFolders(ctx context.Context, userID, collectionID, parentID uuid.UUID) ([]domain.Folder, error) {
+	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	if parentID != uuid.Nil {
+		if _, err := svc.getCollectionFolder(ctx, collectionID, parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	foldersDAO, err := svc.folderStorage.ListFoldersByParentID(ctx, collectionID, parentID)
+	if err != nil {
+		svc.log.Error("Error listing folders", "collection", collectionID, "parent", parentID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	folders := make([]domain.Folder, 0, len(foldersDAO))
+	for _, folderDAO := range foldersDAO {
+		folders = append(folders, *converter.ToFolder(&folderDAO))
+	}
+
+	return folders, nil
+}
+
+// GetFolder retrieves a folder by ID
+func (svc *FolderService) GetFolder(ctx context.Context, userID, collectionID, folderID uuid.UUID) (*domain.Folder, error) {
+	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	folderDAO, err := svc.getCollectionFolder(ctx, collectionID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ToFolder(folderDAO), nil
+}
+
+// UpdateFolder renames a folder
+func (svc *FolderService) UpdateFolder(ctx context.Context, userID uuid.UUID, folder *domain.Folder) (*domain.Folder, error) {
+	if !svc.isUserPartOfCollection(ctx, userID, folder.CollectionID) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	if _, err := svc.getCollectionFolder(ctx, folder.CollectionID, folder.ID); err != nil {
+		return nil, err
+	}
+
+	folder.UpdatedBy = userID
+
+	updatedFolderDAO, err := svc.folderStorage.UpdateFolder(ctx, converter.ToFolderDAO(folder))
+	if err != nil {
+		svc.log.Error("Error updating folder", "folder", folder.ID, sl.Err(err))
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	return converter.ToFolder(updatedFolderDAO), nil
+}
+
+// MoveFolder moves a folder under a new parent, an empty parent ID moves it to the collection root
+func (svc *FolderService) MoveFolder(ctx context.Context, userID, collectionID, folderID, parentID uuid.UUID) (*domain.Folder, error) {
+	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	folderDAO, err := svc.getCollectionFolder(ctx, collectionID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID == folderID {
+		return nil, domain.ErrFolderCycle
+	}
+
+	if parentID != uuid.Nil {
+		if _, err := svc.getCollectionFolder(ctx, collectionID, parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	folderDAO.ParentID = parentID
+	folderDAO.UpdatedBy = userID
+
+	movedFolderDAO, err := svc.folderStorage.MoveFolder(ctx, folderDAO)
+	if err != nil {
+		if err == domain.ErrFolderCycle || err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error moving folder", "folder", folderID, "parent", parentID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return converter.ToFolder(movedFolderDAO), nil
+}
+
+// DeleteFolder deletes a folder with all nested folders and secrets
+func (svc *FolderService) DeleteFolder(ctx context.Context, userID, collectionID, folderID uuid.UUID) error {
+	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
+		return domain.ErrUnauthorized
+	}
+
+	if _, err := svc.getCollectionFolder(ctx, collectionID, folderID); err != nil {
+		return err
+	}
+
+	if err := svc.folderStorage.DeleteFolder(ctx, folderID); err != nil {
+		svc.log.Error("Error deleting folder", "folder", folderID, sl.Err(err))
+		return domain.ErrDataNotDeleted
+	}
+
+	return nil
+}
+
+// getCollectionFolder returns the folder if it exists and belongs to the given collection
+func (svc *FolderService) getCollectionFolder(ctx context.Context, collectionID, folderID uuid.UUID) (*dao.FolderDAO, error) {
+	folderDAO, err := svc.folderStorage.GetFolderByID(ctx, folderID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error getting folder", "folder", folderID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if folderDAO.CollectionID != collectionID {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return folderDAO, nil
+}
+
+// isUserPartOfCollection checks if the user is part of the given collection
+func (svc *FolderService) isUserPartOfCollection(ctx context.Context, userID, collectionID uuid.UUID) bool {
+	isPartOfCollection, err := svc.collectionStorage.IsUserPartOfCollection(ctx, userID, collectionID)
+	if err != nil {
+		svc.log.Error("Error checking if user is part of collection", "user", userID, "collection", collectionID, sl.Err(err))
+		return false
+	}
+
+	return isPartOfCollection
+}
@@ -0,0 +1,30 @@
+package folder
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * FolderService implements service.FolderService interface
+ * and provides an access to the folder repository
+ */
+type FolderService struct {
+	log               *slog.Logger
+	folderStorage     storage.FolderRepository
+	collectionStorage storage.CollectionRepository
+}
+
+// NewFolderService creates a new folder service instance
+func NewFolderService(
+	log *slog.Logger,
+	folderStorage storage.FolderRepository,
+	collectionStorage storage.CollectionRepository,
+) *FolderService {
+	return &FolderService{
+		log,
+		folderStorage,
+		collectionStorage,
+	}
+}
@@ -2,42 +2,218 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/google/uuid"
 )
 
 // TokenService represents a service for handling tokens.
 type TokenService interface {
-	// GenerateToken generates a new JWT token pair based on the provided user claims.
-	GenerateToken(userID uuid.UUID, role domain.UserRoleEnum) (string, string, error)
+	// GenerateToken generates a new JWT token pair of a new session the user logged in to.
+	GenerateToken(ctx context.Context, userID uuid.UUID, role domain.UserRoleEnum, client *domain.ClientInfo) (string, string, error)
+	// GenerateDeviceToken generates a new JWT token pair of a new session of a device approved from another session.
+	GenerateDeviceToken(ctx context.Context, userID uuid.UUID, role domain.UserRoleEnum, client *domain.ClientInfo) (string, string, error)
+	// RotateToken exchanges a refresh token for a new token pair of the same session.
+	RotateToken(ctx context.Context, claims *domain.UserClaims, role domain.UserRoleEnum, client *domain.ClientInfo) (string, string, error)
 	// ParseUserClaims parses the access token and returns the user claims.
 	ParseUserClaims(accessToken string) (*domain.UserClaims, error)
 	// RevokeToken revokes the specified JWT token.
 	RevokeToken(ctx context.Context, token uuid.UUID) error
-	// CheckJWTTokenRevoked checks if the JWT token is revoked.
-	CheckJWTTokenRevoked(ctx context.Context, token uuid.UUID) (bool, error)
+	// RevokeTokenFamily revokes a refresh token family with the access tokens issued with it.
+	RevokeTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	// RevokeUserTokenFamilies revokes the refresh token families of a user except the given one.
+	RevokeUserTokenFamilies(ctx context.Context, userID, exceptFamilyID uuid.UUID) error
+	// CheckJWTTokenRevoked checks if the JWT token or its token family is revoked.
+	CheckJWTTokenRevoked(ctx context.Context, claims *domain.UserClaims) (bool, error)
+	// ListVerificationKeys returns the public keys verifying the tokens, newest first.
+	ListVerificationKeys() []domain.SigningKey
+}
+
+// SessionService is an interface for interacting with session-related business logic
+type SessionService interface {
+	// ListSessions returns the active sessions of the user, the current one is marked
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]domain.Session, error)
+	// RevokeSession signs out a session of the user
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	// RevokeAllSessions signs out all sessions of the user
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+}
+
+// PersonalAccessTokenService is an interface for interacting with personal access token-related business logic
+type PersonalAccessTokenService interface {
+	// CreatePersonalAccessToken creates a new token with the vault key of the user and returns it once in plain text
+	CreatePersonalAccessToken(ctx context.Context, userID uuid.UUID, token *domain.PersonalAccessToken, encryptionKey []byte) (string, *domain.PersonalAccessToken, error)
+	// ListPersonalAccessTokens returns the personal access tokens of the user
+	ListPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error)
+	// DeletePersonalAccessToken revokes a personal access token of the user
+	DeletePersonalAccessToken(ctx context.Context, userID, tokenID uuid.UUID) error
+	// Authenticate verifies a personal access token and returns it with the vault key of its owner
+	Authenticate(ctx context.Context, rawToken string) (*domain.PersonalAccessToken, []byte, error)
+}
+
+// InviteService is an interface for interacting with the invite codes of the restricted registration
+type InviteService interface {
+	// CreateInvite creates a new invite code and returns it once in plain text
+	CreateInvite(ctx context.Context, adminID uuid.UUID, invite *domain.Invite) (string, *domain.Invite, error)
+	// ListInvites returns all invites
+	ListInvites(ctx context.Context) ([]domain.Invite, error)
+	// DeleteInvite revokes an invite
+	DeleteInvite(ctx context.Context, id uuid.UUID) error
+}
+
+// RoleService is an interface for managing the custom roles and resolving the permissions of users
+type RoleService interface {
+	// CreateRole creates a new custom role
+	CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	// ListRoles returns all custom roles
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+	// UpdateRole updates a custom role
+	UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	// DeleteRole deletes a custom role
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	// ListUserRoles returns the custom roles assigned to a user
+	ListUserRoles(ctx context.Context, userID uuid.UUID) ([]domain.Role, error)
+	// SetUserRoles replaces the custom roles assigned to a user
+	SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) ([]domain.Role, error)
+	// SetRoleMembers replaces the users a custom role is assigned to
+	SetRoleMembers(ctx context.Context, roleID uuid.UUID, userIDs []uuid.UUID) error
+	// GetUserPermissions returns the permissions granted to a user by their built-in and custom roles
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]domain.Permission, error)
+}
+
+// SCIMService is an interface for the SCIM 2.0 provisioning of the users and the groups by an identity provider (RFC 7644).
+// The groups are custom roles, their members are the users the roles are assigned to.
+type SCIMService interface {
+	// ListUsers returns a page of the users matching the filter with their total number
+	ListUsers(ctx context.Context, filter *domain.SCIMFilter, startIndex, count uint64) ([]domain.User, uint64, error)
+	// GetUser returns a user by id
+	GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	// CreateUser provisions a verified account, suspended if it is inactive
+	CreateUser(ctx context.Context, user *domain.User, active bool) (*domain.User, error)
+	// ReplaceUser updates a user and suspends or unsuspends them to match the active state
+	ReplaceUser(ctx context.Context, user *domain.User, active bool) (*domain.User, error)
+	// DeactivateUser suspends a deprovisioned user, the account is kept
+	DeactivateUser(ctx context.Context, id uuid.UUID) error
+	// ListGroups returns a page of the groups matching the filter with their total number
+	ListGroups(ctx context.Context, filter *domain.SCIMFilter, startIndex, count uint64) ([]domain.SCIMGroup, uint64, error)
+	// GetGroup returns a group by id
+	GetGroup(ctx context.Context, id uuid.UUID) (*domain.SCIMGroup, error)
+	// CreateGroup creates a group with its members
+	CreateGroup(ctx context.Context, name string, memberIDs []uuid.UUID) (*domain.SCIMGroup, error)
+	// ReplaceGroup renames a group and replaces its members
+	ReplaceGroup(ctx context.Context, id uuid.UUID, name string, memberIDs []uuid.UUID) (*domain.SCIMGroup, error)
+	// DeleteGroup deletes a group
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+}
+
+// DeviceService is an interface for the device authorization grant logging in CLI and headless clients (RFC 8628)
+type DeviceService interface {
+	// RequestDeviceAuthorization starts the login of a device and returns the device code, kept by the device, with the user code to approve
+	RequestDeviceAuthorization(ctx context.Context, client *domain.ClientInfo) (string, *domain.DeviceAuthorization, error)
+	// GetDeviceAuthorization returns the pending device authorization of the user code for the user to check it
+	GetDeviceAuthorization(ctx context.Context, userID uuid.UUID, userCode string, client *domain.ClientInfo) (*domain.DeviceAuthorization, error)
+	// ApproveDeviceAuthorization logs the device of the user code in as the user
+	ApproveDeviceAuthorization(ctx context.Context, userID uuid.UUID, userCode string, client *domain.ClientInfo) error
+	// DenyDeviceAuthorization refuses the login of the device of the user code
+	DenyDeviceAuthorization(ctx context.Context, userID uuid.UUID, userCode string, client *domain.ClientInfo) error
+	// ExchangeDeviceCode returns a token pair for the device once the user approved it
+	ExchangeDeviceCode(ctx context.Context, deviceCode string) (string, string, error)
 }
 
 // OtpService
 type OtpService interface {
-	// GenerateOTP generates a new OTP for the given user ID
-	GenerateOTP(ctx context.Context, userID uuid.UUID) (string, error)
-	// VerifyOTP verifies if the provided OTP is valid for the given user ID
-	VerifyOTP(ctx context.Context, userID uuid.UUID, otp2 string) error
-	// CheckCacheForKey checks if a value exists in the cache for the given user ID
-	CheckCacheForKey(ctx context.Context, userID uuid.UUID) (bool, error)
+	// GenerateOTP generates a new OTP of the purpose for the given user ID
+	GenerateOTP(ctx context.Context, userID uuid.UUID, purpose domain.OTPPurpose) (string, error)
+	// VerifyOTP verifies if the provided OTP is valid for the given user ID and purpose
+	VerifyOTP(ctx context.Context, userID uuid.UUID, purpose domain.OTPPurpose, otp string) error
+	// HasPendingOTP checks if an OTP of the purpose has been sent to the user and has not expired yet
+	HasPendingOTP(ctx context.Context, userID uuid.UUID, purpose domain.OTPPurpose) (bool, error)
+}
+
+// LockoutService is an interface for limiting failed attempts against brute force.
+// Accounts are identified by the email entered, so unknown emails are limited like existing accounts.
+type LockoutService interface {
+	// Check refuses an attempt while the account or the IP address is locked out or has to wait
+	Check(ctx context.Context, action domain.LockoutAction, account, ip string) error
+	// Fail counts a failed attempt and returns the error refusing the next attempts, if any
+	Fail(ctx context.Context, action domain.LockoutAction, account, ip string) error
+	// Succeed clears the failed attempts of the account
+	Succeed(ctx context.Context, action domain.LockoutAction, account string) error
+	// Unlock clears the lockouts and the failed attempts of the account for all actions
+	Unlock(ctx context.Context, account string) error
+	// ListLocks returns the current refusals of the attempts of the account, for all actions
+	ListLocks(ctx context.Context, account string) ([]domain.AccountLock, error)
+}
+
+// PasswordPolicyService is an interface for checking new passwords against the password policy
+type PasswordPolicyService interface {
+	// ValidatePassword checks a new account password, the error lists the broken rules
+	ValidatePassword(password string) error
+	// ValidateMasterPassword checks a new master password, which may have to differ from the account password of the hash
+	ValidateMasterPassword(password, accountPasswordHash string) error
+}
+
+// MFAService is an interface for interacting with multi-factor authentication-related business logic
+type MFAService interface {
+	// EnrollTOTP generates a new pending TOTP secret for the user
+	EnrollTOTP(ctx context.Context, userID uuid.UUID) (*domain.TOTPEnrollment, error)
+	// ConfirmTOTP enables the pending TOTP secret with a first code and returns the backup codes
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	// DisableTOTP disables TOTP for the user with a current code, the wrong codes are counted against brute force
+	DisableTOTP(ctx context.Context, userID uuid.UUID, code string, client *domain.ClientInfo) error
+	// GetStatus returns the multi-factor authentication settings of the user
+	GetStatus(ctx context.Context, userID uuid.UUID) (*domain.MFAStatus, error)
+	// CompleteEnrollment clears the enrollment an admin forced on the user once they enabled a second factor
+	CompleteEnrollment(ctx context.Context, userID uuid.UUID) error
+	// IsEnabled checks if TOTP is enabled for the user
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	// VerifyCode verifies a TOTP code or a backup code of the user
+	VerifyCode(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+// StepUpService is an interface for the recent authentication required by sensitive operations
+type StepUpService interface {
+	// StepUp confirms the password or a TOTP or backup code of the user of the session and returns until when it counts as recent
+	StepUp(ctx context.Context, userID, sessionID uuid.UUID, password, code string, client *domain.ClientInfo) (time.Time, error)
+	// CheckRecentAuthentication checks if the user of the request logged in or stepped up recently
+	CheckRecentAuthentication(ctx context.Context, claims *domain.UserClaims) error
 }
 
 // AuthService is an interface for interacting with user authentication-related business logic
 type AuthService interface {
-	// Login authenticates a user by email and password and returns a token
-	Login(ctx context.Context, email, password string) (string, string, error)
+	// Login authenticates a user by email and password and returns a token.
+	// For users with two-factor authentication it returns an MFA challenge token instead.
+	Login(ctx context.Context, email, password string, client *domain.ClientInfo) (string, string, string, error)
+	// LoginMFA completes a login with the MFA challenge token and a TOTP or backup code
+	LoginMFA(ctx context.Context, mfaToken, code string, client *domain.ClientInfo) (string, string, error)
+
+	// BeginWebAuthnRegistration starts the registration of a new passkey for the user
+	BeginWebAuthnRegistration(ctx context.Context, userID uuid.UUID) (*protocol.CredentialCreation, error)
+	// FinishWebAuthnRegistration verifies the authenticator response and stores the new passkey
+	FinishWebAuthnRegistration(ctx context.Context, userID uuid.UUID, name string, response []byte) (*domain.WebAuthnCredential, error)
+	// ListWebAuthnCredentials returns the passkeys of the user
+	ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]domain.WebAuthnCredential, error)
+	// DeleteWebAuthnCredential deletes a passkey of the user
+	DeleteWebAuthnCredential(ctx context.Context, userID, credentialID uuid.UUID) error
+	// BeginWebAuthnLogin starts a passwordless login with a passkey
+	BeginWebAuthnLogin(ctx context.Context) (*protocol.CredentialAssertion, error)
+	// FinishWebAuthnLogin verifies the passkey assertion and returns a token
+	FinishWebAuthnLogin(ctx context.Context, response []byte, client *domain.ClientInfo) (string, string, error)
+	// BeginWebAuthnMFA starts a passkey assertion as the second factor of a login
+	BeginWebAuthnMFA(ctx context.Context, mfaToken string) (*protocol.CredentialAssertion, error)
+	// FinishWebAuthnMFA completes a login with a passkey assertion as the second factor
+	FinishWebAuthnMFA(ctx context.Context, response []byte, client *domain.ClientInfo) (string, string, error)
+	// BeginOIDCLogin starts a single sign-on and returns the authorization URL of the provider with the state
+	BeginOIDCLogin(ctx context.Context) (string, string, error)
+	// FinishOIDCLogin completes a single sign-on and returns a token or an MFA challenge token
+	FinishOIDCLogin(ctx context.Context, code, state string, client *domain.ClientInfo) (string, string, string, error)
 
-	// Register registers a new user
-	Register(ctx context.Context, user *domain.User) (*domain.User, error)
+	// Register registers a new user, the invite code is required unless the registration mode lets the email register
+	Register(ctx context.Context, user *domain.User, inviteCode string) (*domain.User, error)
 	// ConfirmRegistration confirms user registration with OTP code
-	ConfirmRegistration(ctx context.Context, email, otp string) error
+	ConfirmRegistration(ctx context.Context, email, otp string, client *domain.ClientInfo) error
 	// RequestNewRegistrationCode requests a new registration confirmation code for a user
 	RequestNewRegistrationCode(ctx context.Context, email string) error
 
@@ -45,15 +221,19 @@ type AuthService interface {
 	Logout(ctx context.Context, token *domain.UserClaims) error
 
 	// RefreshToken refreshes the access token for the user
-	RefreshToken(ctx context.Context, refreshToken string) (string, string, error)
+	RefreshToken(ctx context.Context, refreshToken string, client *domain.ClientInfo) (string, string, error)
 
 	// ChangePassword changes the password for the authenticated user
-	ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string, client *domain.ClientInfo) error
+	// RequestEmailChange sends a code to the new email of the authenticated user and notifies the current email
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, password, newEmail string, client *domain.ClientInfo) error
+	// ConfirmEmailChange applies the pending email change with the code sent to the new email, optionally signing out the other sessions
+	ConfirmEmailChange(ctx context.Context, userID, currentSessionID uuid.UUID, otp string, revokeOtherSessions bool, client *domain.ClientInfo) (*domain.User, error)
 
 	// ForgotPassword initiates the process of resetting a forgotten password
 	ForgotPassword(ctx context.Context, email string) error
 	// ResetPassword confirms password reset with OTP code
-	ResetPassword(ctx context.Context, email, newPassword, otp string) error
+	ResetPassword(ctx context.Context, email, newPassword, otp string, client *domain.ClientInfo) error
 }
 
 // MasterPasswordService is an interface for interacting with master password-related business logic
@@ -63,9 +243,11 @@ type MasterPasswordService interface {
 	// SaveMasterPassword saves or updates the master password for the given user
 	SaveMasterPassword(ctx context.Context, userID uuid.UUID, password string) error
 	// ChangeMasterPassword changes the master password for the given user.
-	ChangeMasterPassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error
+	ChangeMasterPassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string, client *domain.ClientInfo) error
 	// ActivateMasterPassword validates the master password for the given user
-	ActivateMasterPassword(ctx context.Context, userID uuid.UUID, password string) error
+	ActivateMasterPassword(ctx context.Context, userID uuid.UUID, password string, client *domain.ClientInfo) error
+	// VerifyMasterPassword checks the master password for the given user without activating it
+	VerifyMasterPassword(ctx context.Context, userID uuid.UUID, password string, client *domain.ClientInfo) error
 	// GetEncryptionKey is required to encrypt or decrypt the password
 	GetEncryptionKey(ctx context.Context, userID uuid.UUID) ([]byte, error)
 }
@@ -78,8 +260,20 @@ type UserService interface {
 	ListUsers(ctx context.Context, skip, limit uint64) ([]domain.User, error)
 	// UpdateUser updates a user
 	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
-	// DeleteUser deletes a user
-	DeleteUser(ctx context.Context, id uuid.UUID) error
+	// DeleteUser deletes a user, handling the collections they own
+	DeleteUser(ctx context.Context, adminID, id uuid.UUID) error
+	// UnlockUser ends the lockouts of a user after too many failed attempts
+	UnlockUser(ctx context.Context, id uuid.UUID) error
+	// GetUserStatus returns the verification, the suspension, the forced actions and the lockouts of a user
+	GetUserStatus(ctx context.Context, id uuid.UUID) (*domain.UserStatus, error)
+	// SuspendUser suspends a user and revokes their sessions, a suspended user cannot log in
+	SuspendUser(ctx context.Context, adminID, id uuid.UUID) (*domain.User, error)
+	// UnsuspendUser lets a suspended user log in again
+	UnsuspendUser(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	// ForcePasswordReset makes a user change their password before using their account again
+	ForcePasswordReset(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	// ForceMFAEnrollment makes a user enable a second factor before using their account again
+	ForceMFAEnrollment(ctx context.Context, id uuid.UUID) (*domain.User, error)
 }
 
 // CollectionService is an interface for interacting with collection-related business logic
@@ -94,18 +288,58 @@ type CollectionService interface {
 	UpdateCollection(ctx context.Context, userID uuid.UUID, collection *domain.Collection) (*domain.Collection, error)
 	// DeleteCollection deletes a collection
 	DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error
+	// TransferOwnership makes another user the owner of a collection
+	TransferOwnership(ctx context.Context, userID, collectionID, newOwnerID uuid.UUID) (*domain.Collection, error)
+}
+
+// TagService is an interface for interacting with tag-related business logic
+type TagService interface {
+	// CreateTag creates a new tag for the user
+	CreateTag(ctx context.Context, userID uuid.UUID, name string) (*domain.Tag, error)
+	// ListTags returns the tags of the user
+	ListTags(ctx context.Context, userID uuid.UUID) ([]domain.Tag, error)
+	// GetTagCloud returns the tags of the user with the number of secrets using them
+	GetTagCloud(ctx context.Context, userID uuid.UUID) ([]domain.TagCount, error)
+	// UpdateTag renames a tag
+	UpdateTag(ctx context.Context, userID, tagID uuid.UUID, name string) (*domain.Tag, error)
+	// DeleteTag deletes a tag
+	DeleteTag(ctx context.Context, userID, tagID uuid.UUID) error
+}
+
+// FolderService is an interface for interacting with folder-related business logic
+type FolderService interface {
+	// CreateFolder inserts a new folder into the collection
+	CreateFolder(ctx context.Context, userID uuid.UUID, folder *domain.Folder) (*domain.Folder, error)
+	// ListFolders returns the direct children of a folder, an empty parent ID returns the collection root
+	ListFolders(ctx context.Context, userID, collectionID, parentID uuid.UUID) ([]domain.Folder, error)
+	// GetFolder returns a folder by id
+	GetFolder(ctx context.Context, userID, collectionID, folderID uuid.UUID) (*domain.Folder, error)
+	// UpdateFolder renames a folder
+	UpdateFolder(ctx context.Context, userID uuid.UUID, folder *domain.Folder) (*domain.Folder, error)
+	// MoveFolder moves a folder under a new parent, an empty parent ID moves it to the collection root
+	MoveFolder(ctx context.Context, userID, collectionID, folderID, parentID uuid.UUID) (*domain.Folder, error)
+	// DeleteFolder deletes a folder with all nested folders and secrets
+	DeleteFolder(ctx context.Context, userID, collectionID, folderID uuid.UUID) error
 }
 
 // SecretService is an interface for interacting with secret-related business logic
 type SecretService interface {
 	// CreateSecret inserts a new secret into the database
 	CreateSecret(ctx context.Context, userID uuid.UUID, secret *domain.Secret, encryptionKey []byte) (*domain.Secret, error)
-	// ListSecretsByCollectionID returns a list of secrets by collection ID with pagination
-	ListSecretsByCollectionID(ctx context.Context, userID uuid.UUID, collectionID uuid.UUID, skip, limit uint64) ([]domain.Secret, error)
-	// GetSecret returns a secret by id
-	GetSecret(ctx context.Context, userID, collectionID, secretID uuid.UUID, encryptionKey []byte) (*domain.Secret, error)
-	// UpdateSecret updates a secret
-	UpdateSecret(ctx context.Context, userID, collectionID uuid.UUID, secret *domain.Secret, encryptionKey []byte) (*domain.Secret, error)
+	// ListSecretsByCollectionID returns a list of secrets of a collection folder, optionally filtered by tag
+	ListSecretsByCollectionID(ctx context.Context, userID, collectionID, folderID uuid.UUID, recursive bool, tag string, encryptionKey []byte, skip, limit uint64) ([]domain.Secret, error)
+	// SearchSecrets returns the secrets of the user's collections matching a full-text search
+	SearchSecrets(ctx context.Context, userID uuid.UUID, text string, encryptionKey []byte, skip, limit uint64) ([]domain.Secret, error)
+	// LookupSecrets returns the secrets of the user's collections whose indexed field exactly matches a value
+	LookupSecrets(ctx context.Context, userID uuid.UUID, field domain.BlindIndexField, value string, encryptionKey []byte, skip, limit uint64) ([]domain.Secret, error)
+	// GetSecret returns a secret by id, a secret flagged for a re-prompt requires the master password to be reprompted
+	GetSecret(ctx context.Context, userID, collectionID, secretID uuid.UUID, encryptionKey []byte, reprompted bool) (*domain.Secret, error)
+	// UpdateSecret updates a secret, a nil reprompt keeps its re-prompt flag and clearing the flag requires the master password to be reprompted
+	UpdateSecret(ctx context.Context, userID, collectionID uuid.UUID, secret *domain.Secret, reprompt *bool, encryptionKey []byte, reprompted bool) (*domain.Secret, error)
+	// MoveSecret moves a secret to another collection and/or folder
+	MoveSecret(ctx context.Context, userID, collectionID, secretID, targetCollectionID, folderID uuid.UUID, encryptionKey []byte) (*domain.Secret, error)
+	// CopySecret copies a secret to another collection and/or folder
+	CopySecret(ctx context.Context, userID, collectionID, secretID, targetCollectionID, folderID uuid.UUID, encryptionKey []byte) (*domain.Secret, error)
 	// DeleteSecret deletes a secret
 	DeleteSecret(ctx context.Context, userID, collectionID, secretID uuid.UUID) error
 	// ReencryptAllSecrets reencrypt all secrets
@@ -0,0 +1,65 @@
+package invite
+
+import (
+	"context"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+)
+
+// codeSize is the number of random bytes of an invite code
+const codeSize = 16
+
+// CreateInvite creates a new invite code of the admin, it is returned only once and stored hashed
+func (svc *InviteService) CreateInvite(ctx context.Context, adminID uuid.UUID, invite *domain.Invite) (string, *domain.Invite, error) {
+	code, err := util.GenerateRandomToken(codeSize)
+	if err != nil {
+		svc.log.Error("Error generating invite code:", sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+
+	invite.CreatedBy = adminID
+	inviteDAO := converter.ToInviteDAO(invite)
+	inviteDAO.CodeHash = util.HashToken(code)
+
+	createdInvite, err := svc.storage.CreateInvite(ctx, inviteDAO)
+	if err != nil {
+		svc.log.Error("Error creating invite:", "adminID", adminID, sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+
+	return code, converter.ToInvite(createdInvite), nil
+}
+
+// ListInvites returns all invites, including the expired and used up ones
+func (svc *InviteService) ListInvites(ctx context.Context) ([]domain.Invite, error) {
+	invitesDAO, err := svc.storage.ListInvites(ctx)
+	if err != nil {
+		svc.log.Error("Error listing invites:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	invites := make([]domain.Invite, 0, len(invitesDAO))
+	for _, inviteDAO := range invitesDAO {
+		invites = append(invites, *converter.ToInvite(&inviteDAO))
+	}
+
+	return invites, nil
+}
+
+// DeleteInvite revokes an invite, the accounts it registered are kept
+func (svc *InviteService) DeleteInvite(ctx context.Context, id uuid.UUID) error {
+	err := svc.storage.DeleteInvite(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("Error deleting invite:", "inviteID", id, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
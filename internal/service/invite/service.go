@@ -0,0 +1,24 @@
+package invite
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * InviteService implements service.InviteService interface
+ * and provides an access to the invite repository
+ */
+type InviteService struct {
+	log     *slog.Logger
+	storage storage.InviteRepository
+}
+
+// NewInviteService creates a new invite service instance
+func NewInviteService(log *slog.Logger, storage storage.InviteRepository) *InviteService {
+	return &InviteService{
+		log,
+		storage,
+	}
+}
@@ -0,0 +1,211 @@
+package lockout
+
+import (
+	"context"
+	"time"
+
+	mailSmtp "github.com/8thgencore/passfort/internal/clients/mail/smtp"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+)
+
+// lockState is a refusal of the attempts of an account or an IP address until a time
+type lockState struct {
+	Until  time.Time `json:"until"`
+	Locked bool      `json:"locked"` // a lockout, otherwise a backoff delay
+}
+
+// Check refuses an attempt while the account or the IP address is locked out or has to wait
+func (svc *LockoutService) Check(ctx context.Context, action domain.LockoutAction, account, ip string) error {
+	if err := svc.checkState(ctx, stateKey("lockout_state", action, account)); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+
+	return svc.checkState(ctx, stateKey("lockout_ip_state", action, ip))
+}
+
+// Fail counts a failed attempt of the account from the IP address.
+// It returns the error refusing the next attempts once the account has to wait or is locked,
+// or the IP address is blocked.
+func (svc *LockoutService) Fail(ctx context.Context, action domain.LockoutAction, account, ip string) error {
+	var ipErr error
+	if ip != "" {
+		ipAttempts, err := svc.cache.Increment(ctx, stateKey("lockout_ip_attempts", action, ip), svc.cfg.AttemptWindow)
+		if err != nil {
+			svc.log.Error("failed to count a failed attempt", sl.Err(err))
+			return domain.ErrInternal
+		}
+
+		if ipAttempts >= int64(svc.cfg.MaxIPAttempts) {
+			svc.log.Warn("IP address blocked after too many failed attempts", "action", action, "ip", ip)
+
+			ipErr = svc.refuse(ctx, stateKey("lockout_ip_state", action, ip), svc.cfg.AttemptWindow, false)
+		}
+	}
+
+	attempts, err := svc.cache.Increment(ctx, stateKey("lockout_attempts", action, account), svc.cfg.AttemptWindow)
+	if err != nil {
+		svc.log.Error("failed to count a failed attempt", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if attempts >= int64(svc.cfg.MaxAccountAttempts) {
+		return svc.lock(ctx, action, account)
+	}
+
+	if attempts > int64(svc.cfg.FreeAttempts) {
+		delay := backoff(svc.cfg.BaseDelay, attempts-int64(svc.cfg.FreeAttempts)-1, svc.cfg.LockoutDuration)
+
+		return svc.refuse(ctx, stateKey("lockout_state", action, account), delay, false)
+	}
+
+	return ipErr
+}
+
+// Succeed clears the failed attempts of the account, its past lockouts still lengthen the next one
+func (svc *LockoutService) Succeed(ctx context.Context, action domain.LockoutAction, account string) error {
+	if err := svc.cache.Delete(ctx, stateKey("lockout_attempts", action, account)); err != nil {
+		svc.log.Error("failed to clear failed attempts", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// Unlock clears the lockouts and the failed attempts of the account for all actions
+func (svc *LockoutService) Unlock(ctx context.Context, account string) error {
+	for _, action := range domain.LockoutActions {
+		for _, prefix := range []string{"lockout_state", "lockout_attempts", "lockout_count"} {
+			if err := svc.cache.Delete(ctx, stateKey(prefix, action, account)); err != nil {
+				svc.log.Error("failed to unlock account", sl.Err(err))
+				return domain.ErrInternal
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListLocks returns the current refusals of the attempts of the account, for all actions
+func (svc *LockoutService) ListLocks(ctx context.Context, account string) ([]domain.AccountLock, error) {
+	locks := []domain.AccountLock{}
+	for _, action := range domain.LockoutActions {
+		stateSerialized, err := svc.cache.Get(ctx, stateKey("lockout_state", action, account))
+		if err != nil {
+			continue
+		}
+
+		var state lockState
+		if err := util.Deserialize(stateSerialized, &state); err != nil {
+			svc.log.Error("failed to deserialize lockout", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+
+		if time.Now().Before(state.Until) {
+			locks = append(locks, domain.AccountLock{Action: action, Locked: state.Locked, Until: state.Until})
+		}
+	}
+
+	return locks, nil
+}
+
+// lock locks the account for the action and notifies its owner.
+// Each lockout within MaxLockoutDuration of the previous one lasts twice as long.
+func (svc *LockoutService) lock(ctx context.Context, action domain.LockoutAction, account string) error {
+	lockouts, err := svc.cache.Increment(ctx, stateKey("lockout_count", action, account), svc.cfg.MaxLockoutDuration)
+	if err != nil {
+		svc.log.Error("failed to count a lockout", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	duration := backoff(svc.cfg.LockoutDuration, lockouts-1, svc.cfg.MaxLockoutDuration)
+
+	lockErr := svc.refuse(ctx, stateKey("lockout_state", action, account), duration, true)
+
+	// The attempts are counted again once the lockout is over
+	if err := svc.cache.Delete(ctx, stateKey("lockout_attempts", action, account)); err != nil {
+		svc.log.Error("failed to clear failed attempts", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	svc.log.Warn("Account locked after too many failed attempts", "action", action, "email", account, "duration", duration)
+	svc.notify(ctx, account)
+
+	return lockErr
+}
+
+// notify sends a lockout notice to the email of the owner of a locked account
+func (svc *LockoutService) notify(ctx context.Context, account string) {
+	userDAO, err := svc.userStorage.GetUserByEmail(ctx, account)
+	if err != nil || !userDAO.IsVerified {
+		return
+	}
+
+	if err := svc.notifier.SendNotice(ctx, userDAO.Email, mailSmtp.LockoutNotice); err != nil {
+		svc.log.Error("failed to send lockout notice", sl.Err(err))
+	}
+}
+
+// refuse stores a refusal of the attempts for the duration and returns its error.
+// The attempt that caused it is refused even if it cannot be stored.
+func (svc *LockoutService) refuse(ctx context.Context, key string, duration time.Duration, locked bool) error {
+	state := lockState{
+		Until:  time.Now().Add(duration),
+		Locked: locked,
+	}
+
+	stateSerialized, err := util.Serialize(state)
+	if err == nil {
+		err = svc.cache.Set(ctx, key, stateSerialized, duration)
+	}
+	if err != nil {
+		svc.log.Error("failed to store lockout", sl.Err(err))
+	}
+
+	return state.err()
+}
+
+// checkState returns the error of a refusal stored at the key if it is not over
+func (svc *LockoutService) checkState(ctx context.Context, key string) error {
+	stateSerialized, err := svc.cache.Get(ctx, key)
+	if err != nil {
+		return nil
+	}
+
+	var state lockState
+	if err := util.Deserialize(stateSerialized, &state); err != nil {
+		return nil
+	}
+
+	if !time.Now().Before(state.Until) {
+		return nil
+	}
+
+	return state.err()
+}
+
+func (s lockState) err() error {
+	if s.Locked {
+		return domain.NewRetryAfterError(domain.ErrAccountLocked, s.Until)
+	}
+	return domain.NewRetryAfterError(domain.ErrTooManyAttempts, s.Until)
+}
+
+// stateKey returns the cache key of the attempts or the refusal of an account or an IP address for an action
+func stateKey(prefix string, action domain.LockoutAction, subject string) string {
+	return util.GenerateCacheKey(prefix, util.GenerateCacheKeyParams(action, subject))
+}
+
+// backoff returns the base duration doubled n times, up to the max duration
+func backoff(base time.Duration, n int64, maxDuration time.Duration) time.Duration {
+	delay := base
+	for i := int64(0); i < n && delay < maxDuration; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDuration)
+}
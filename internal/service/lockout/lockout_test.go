@@ -0,0 +1,174 @@
+package lockout_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	mailSmtp "github.com/8thgencore/passfort/internal/clients/mail/smtp"
+	"github.com/8thgencore/passfort/internal/clients/mail/smtp/smtptest"
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	email = "test@example.com"
+	ip    = "203.0.113.7"
+)
+
+func setupLockoutService() *lockout.LockoutService {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// The owners of locked accounts are unknown, so no notice is sent
+	users := &mocks.UserRepository{}
+	users.On("GetUserByEmail", mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound)
+
+	return lockout.NewLockoutService(logger, users, cacheMocks.NewMemoryCache(), nil, &config.Lockout{
+		AttemptWindow:      15 * time.Minute,
+		FreeAttempts:       2,
+		BaseDelay:          10 * time.Second,
+		MaxAccountAttempts: 5,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: time.Hour,
+		MaxIPAttempts:      8,
+	})
+}
+
+// retryAfter asserts that the error refuses attempts with the reason and returns its delay
+func retryAfter(t *testing.T, err error, reason error) time.Duration {
+	var retryErr *domain.RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.ErrorIs(t, err, reason)
+	return retryErr.RetryAfter
+}
+
+func TestFail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delays the attempts after the free ones", func(t *testing.T) {
+		svc := setupLockoutService()
+
+		for range 2 {
+			require.NoError(t, svc.Fail(ctx, domain.LoginAction, email, ip))
+		}
+		require.NoError(t, svc.Check(ctx, domain.LoginAction, email, ip))
+
+		delay := retryAfter(t, svc.Fail(ctx, domain.LoginAction, email, ip), domain.ErrTooManyAttempts)
+		assert.InDelta(t, 10*time.Second, delay, float64(time.Second))
+
+		delay = retryAfter(t, svc.Fail(ctx, domain.LoginAction, email, ip), domain.ErrTooManyAttempts)
+		assert.InDelta(t, 20*time.Second, delay, float64(time.Second))
+
+		retryAfter(t, svc.Check(ctx, domain.LoginAction, email, ip), domain.ErrTooManyAttempts)
+
+		// Other actions are counted separately
+		assert.NoError(t, svc.Check(ctx, domain.ResetPasswordAction, email, ip))
+	})
+
+	t.Run("locks the account for longer each time", func(t *testing.T) {
+		svc := setupLockoutService()
+
+		for range 4 {
+			_ = svc.Fail(ctx, domain.LoginAction, email, "")
+		}
+		delay := retryAfter(t, svc.Fail(ctx, domain.LoginAction, email, ""), domain.ErrAccountLocked)
+		assert.InDelta(t, 15*time.Minute, delay, float64(time.Second))
+		retryAfter(t, svc.Check(ctx, domain.LoginAction, email, ""), domain.ErrAccountLocked)
+
+		for range 4 {
+			_ = svc.Fail(ctx, domain.LoginAction, email, "")
+		}
+		delay = retryAfter(t, svc.Fail(ctx, domain.LoginAction, email, ""), domain.ErrAccountLocked)
+		assert.InDelta(t, 30*time.Minute, delay, float64(time.Second))
+	})
+
+	t.Run("blocks an IP address trying many accounts", func(t *testing.T) {
+		svc := setupLockoutService()
+
+		for i := range 7 {
+			require.NoError(t, svc.Fail(ctx, domain.LoginAction, strconv.Itoa(i)+email, ip))
+		}
+		retryAfter(t, svc.Fail(ctx, domain.LoginAction, "other"+email, ip), domain.ErrTooManyAttempts)
+
+		retryAfter(t, svc.Check(ctx, domain.LoginAction, "another"+email, ip), domain.ErrTooManyAttempts)
+		assert.NoError(t, svc.Check(ctx, domain.LoginAction, "another"+email, "198.51.100.1"))
+	})
+}
+
+func TestLockoutNotice(t *testing.T) {
+	ctx := context.Background()
+	server := smtptest.NewServer(t)
+
+	users := &mocks.UserRepository{}
+	users.On("GetUserByEmail", mock.Anything, email).Return(&dao.UserDAO{Email: email, IsVerified: true}, nil)
+
+	notifier, err := mailSmtp.New(server.Host, server.Port, "", "", "PassFort <no-reply@localhost>", "PassFort", time.Second)
+	require.NoError(t, err)
+	svc := lockout.NewLockoutService(slog.Default(), users, cacheMocks.NewMemoryCache(), notifier, &config.Lockout{
+		AttemptWindow:      15 * time.Minute,
+		FreeAttempts:       2,
+		BaseDelay:          10 * time.Second,
+		MaxAccountAttempts: 5,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: time.Hour,
+	})
+
+	for range 4 {
+		_ = svc.Fail(ctx, domain.LoginAction, email, "")
+	}
+	assert.Empty(t, server.Messages())
+
+	retryAfter(t, svc.Fail(ctx, domain.LoginAction, email, ""), domain.ErrAccountLocked)
+
+	messages := server.MessagesTo(email)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Your PassFort account was locked", messages[0].Subject)
+	assert.Contains(t, messages[0].Body, "locked after too many failed attempts")
+}
+
+func TestSucceed(t *testing.T) {
+	ctx := context.Background()
+	svc := setupLockoutService()
+
+	for range 2 {
+		require.NoError(t, svc.Fail(ctx, domain.LoginAction, email, ip))
+	}
+	require.NoError(t, svc.Succeed(ctx, domain.LoginAction, email))
+
+	assert.NoError(t, svc.Fail(ctx, domain.LoginAction, email, ip))
+}
+
+func TestUnlock(t *testing.T) {
+	ctx := context.Background()
+	svc := setupLockoutService()
+
+	for range 5 {
+		_ = svc.Fail(ctx, domain.ActivateMasterPasswordAction, email, "")
+	}
+	retryAfter(t, svc.Check(ctx, domain.ActivateMasterPasswordAction, email, ""), domain.ErrAccountLocked)
+
+	locks, err := svc.ListLocks(ctx, email)
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	assert.Equal(t, domain.ActivateMasterPasswordAction, locks[0].Action)
+	assert.True(t, locks[0].Locked)
+
+	require.NoError(t, svc.Unlock(ctx, email))
+
+	locks, err = svc.ListLocks(ctx, email)
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+
+	assert.NoError(t, svc.Check(ctx, domain.ActivateMasterPasswordAction, email, ""))
+	assert.NoError(t, svc.Fail(ctx, domain.ActivateMasterPasswordAction, email, ""))
+}
@@ -0,0 +1,39 @@
+package lockout
+
+import (
+	"log/slog"
+
+	mailSmtp "github.com/8thgencore/passfort/internal/clients/mail/smtp"
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/service/adapters/cache"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * LockoutService implements service.LockoutService interface
+ * and counts the failed attempts in the cache
+ */
+type LockoutService struct {
+	log         *slog.Logger
+	userStorage storage.UserRepository
+	cache       cache.CacheRepository
+	notifier    *mailSmtp.Client
+	cfg         *config.Lockout
+}
+
+// NewLockoutService creates a new lockout service instance
+func NewLockoutService(
+	log *slog.Logger,
+	userStorage storage.UserRepository,
+	cache cache.CacheRepository,
+	notifier *mailSmtp.Client,
+	cfg *config.Lockout,
+) *LockoutService {
+	return &LockoutService{
+		log,
+		userStorage,
+		cache,
+		notifier,
+		cfg,
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
 	"github.com/8thgencore/passfort/pkg/cipherkit"
 	"github.com/8thgencore/passfort/pkg/logger/sl"
 	"github.com/8thgencore/passfort/pkg/util"
@@ -30,6 +31,10 @@ func (svc *MasterPasswordService) SaveMasterPassword(ctx context.Context, userID
 		return domain.ErrInternal
 	}
 
+	if err := svc.passwordPolicy.ValidateMasterPassword(password, userDAO.Password); err != nil {
+		return err
+	}
+
 	user := converter.ToUser(userDAO)
 
 	hashedPassword, err := util.HashPassword(password)
@@ -85,16 +90,29 @@ func (svc *MasterPasswordService) SaveMasterPassword(ctx context.Context, userID
 }
 
 // ChangeMasterPassword changes the master password for the given user.
-func (svc *MasterPasswordService) ChangeMasterPassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
+// The current master password is limited against brute force like its activation.
+func (svc *MasterPasswordService) ChangeMasterPassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string, client *domain.ClientInfo) error {
 	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
 	if err != nil {
 		return domain.ErrDataNotFound
 	}
 
+	if err := svc.passwordPolicy.ValidateMasterPassword(newPassword, userDAO.Password); err != nil {
+		return err
+	}
+
 	user := converter.ToUser(userDAO)
 
+	if err := svc.lockout.Check(ctx, domain.ActivateMasterPasswordAction, user.Email, client.IPAddress); err != nil {
+		return err
+	}
+
 	if err = util.CompareHash(oldPassword, user.MasterPassword); err != nil {
-		return domain.ErrInvalidMasterPassword
+		return svc.failActivation(ctx, user.Email, client)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.ActivateMasterPasswordAction, user.Email); err != nil {
+		return err
 	}
 
 	oldEncryptionKey := cipherkit.DeriveKey(oldPassword, user.Salt)
@@ -113,6 +131,12 @@ func (svc *MasterPasswordService) ChangeMasterPassword(ctx context.Context, user
 		return domain.ErrInternal
 	}
 
+	// Personal access tokens hold the old encryption key, they cannot be rewrapped without the tokens
+	if err = svc.tokenStorage.DeletePersonalAccessTokensByUserID(ctx, userID); err != nil {
+		svc.log.Error("Failed to delete personal access tokens", sl.Err(err))
+		return domain.ErrInternal
+	}
+
 	if err = svc.secretSvc.ReencryptAllSecrets(ctx, userID, oldEncryptionKey, newEncryptionKey); err != nil {
 		svc.log.Error("Failed to start re-encrypt all secrets", sl.Err(err))
 		return domain.ErrInternal
@@ -122,19 +146,10 @@ func (svc *MasterPasswordService) ChangeMasterPassword(ctx context.Context, user
 }
 
 // ActivateMasterPassword activates the master password for the given user.
-func (svc *MasterPasswordService) ActivateMasterPassword(ctx context.Context, userID uuid.UUID, password string) error {
-	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+func (svc *MasterPasswordService) ActivateMasterPassword(ctx context.Context, userID uuid.UUID, password string, client *domain.ClientInfo) error {
+	userDAO, err := svc.verifyMasterPassword(ctx, userID, password, client)
 	if err != nil {
-		svc.log.Error("Failed to activate master password", sl.Err(err))
-		return domain.ErrInternal
-	}
-
-	if !userDAO.MasterPassword.Valid || userDAO.Salt == nil {
-		return domain.ErrMasterPasswordNotSet
-	}
-
-	if err = util.CompareHash(password, userDAO.MasterPassword.String); err != nil {
-		return domain.ErrInvalidMasterPassword
+		return err
 	}
 
 	newKey := cipherkit.DeriveKey(password, userDAO.Salt)
@@ -154,6 +169,51 @@ func (svc *MasterPasswordService) ActivateMasterPassword(ctx context.Context, us
 	return nil
 }
 
+// VerifyMasterPassword checks the master password of the user without activating it,
+// as the proof required to read the secrets flagged for a re-prompt
+func (svc *MasterPasswordService) VerifyMasterPassword(ctx context.Context, userID uuid.UUID, password string, client *domain.ClientInfo) error {
+	_, err := svc.verifyMasterPassword(ctx, userID, password, client)
+	return err
+}
+
+// verifyMasterPassword checks the master password of the user and returns the user.
+// Wrong master passwords are limited against brute force like the activations.
+func (svc *MasterPasswordService) verifyMasterPassword(ctx context.Context, userID uuid.UUID, password string, client *domain.ClientInfo) (*dao.UserDAO, error) {
+	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		svc.log.Error("Failed to verify master password", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if !userDAO.MasterPassword.Valid || userDAO.Salt == nil {
+		return nil, domain.ErrMasterPasswordNotSet
+	}
+
+	if err := svc.lockout.Check(ctx, domain.ActivateMasterPasswordAction, userDAO.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	if err = util.CompareHash(password, userDAO.MasterPassword.String); err != nil {
+		return nil, svc.failActivation(ctx, userDAO.Email, client)
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.ActivateMasterPasswordAction, userDAO.Email); err != nil {
+		return nil, err
+	}
+
+	return userDAO, nil
+}
+
+// failActivation counts a wrong master password and returns its error,
+// or the error refusing the next attempts once the user has to wait
+func (svc *MasterPasswordService) failActivation(ctx context.Context, email string, client *domain.ClientInfo) error {
+	if lockErr := svc.lockout.Fail(ctx, domain.ActivateMasterPasswordAction, email, client.IPAddress); lockErr != nil {
+		return lockErr
+	}
+
+	return domain.ErrInvalidMasterPassword
+}
+
 // GetEncryptionKey retrieves the encryption key from the cache.
 func (svc *MasterPasswordService) GetEncryptionKey(ctx context.Context, userID uuid.UUID) ([]byte, error) {
 	cacheKey := util.GenerateCacheKey("encryption_key", userID.String())
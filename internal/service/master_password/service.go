@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/8thgencore/passfort/internal/service"
 	"github.com/8thgencore/passfort/internal/service/adapters/cache"
 	"github.com/8thgencore/passfort/internal/service/adapters/storage"
 	"github.com/8thgencore/passfort/internal/service/secret"
@@ -17,8 +18,11 @@ import (
 type MasterPasswordService struct {
 	log               *slog.Logger
 	userStorage       storage.UserRepository
+	tokenStorage      storage.PersonalAccessTokenRepository
 	cache             cache.CacheRepository
 	secretSvc         secret.SecretService
+	lockout           service.LockoutService
+	passwordPolicy    service.PasswordPolicyService
 	masterPasswordTTL time.Duration
 }
 
@@ -26,15 +30,21 @@ type MasterPasswordService struct {
 func NewMasterPasswordService(
 	log *slog.Logger,
 	userStorage storage.UserRepository,
+	tokenStorage storage.PersonalAccessTokenRepository,
 	cache cache.CacheRepository,
 	secretSvc secret.SecretService,
+	lockoutService service.LockoutService,
+	passwordPolicy service.PasswordPolicyService,
 	masterPasswordTTL time.Duration,
 ) *MasterPasswordService {
 	return &MasterPasswordService{
 		log:               log,
 		userStorage:       userStorage,
+		tokenStorage:      tokenStorage,
 		cache:             cache,
 		secretSvc:         secretSvc,
+		lockout:           lockoutService,
+		passwordPolicy:    passwordPolicy,
 		masterPasswordTTL: masterPasswordTTL,
 	}
 }
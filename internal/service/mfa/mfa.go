@@ -0,0 +1,335 @@
+package mfa
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/cipherkit"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// qrCodeSize is the width and height of the QR code image in pixels
+	qrCodeSize = 256
+	// usedCodeTTL covers the validity of a TOTP code including the allowed clock skew
+	usedCodeTTL = 90 * time.Second
+	// encryptedSecretPrefix marks the TOTP secrets stored encrypted, the secrets enrolled before are plaintext
+	encryptedSecretPrefix = "enc:v1:"
+)
+
+// EnrollTOTP starts a TOTP enrollment by generating a new secret for the user.
+// TOTP is enabled only once the secret is confirmed with a first code.
+func (svc *MFAService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (*domain.TOTPEnrollment, error) {
+	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error getting user:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      svc.issuer,
+		AccountName: userDAO.Email,
+	})
+	if err != nil {
+		svc.log.Error("Error generating TOTP secret:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	image, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		svc.log.Error("Error generating TOTP QR code:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	var qrCode bytes.Buffer
+	if err := png.Encode(&qrCode, image); err != nil {
+		svc.log.Error("Error encoding TOTP QR code:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	encryptedSecret, err := svc.encryptSecret(key.Secret())
+	if err != nil {
+		svc.log.Error("Error encrypting TOTP secret:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	_, err = svc.storage.UpsertTOTP(ctx, &dao.TOTPDAO{
+		UserID: userID,
+		Secret: encryptedSecret,
+	})
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, domain.ErrMFAAlreadyEnabled
+		}
+		svc.log.Error("Error storing TOTP secret:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: qrCode.Bytes(),
+	}, nil
+}
+
+// ConfirmTOTP enables the pending TOTP secret of the user with a first code.
+// It returns the one-time backup codes, which are only stored hashed and cannot be shown again.
+func (svc *MFAService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	totpDAO, err := svc.getTOTP(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrTOTPNotEnrolled
+		}
+		return nil, err
+	}
+
+	if totpDAO.Enabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	if err := svc.validateTOTPCode(ctx, totpDAO, code); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, domain.BackupCodesCount)
+	codeHashes := make([]string, 0, domain.BackupCodesCount)
+	for range domain.BackupCodesCount {
+		code, err := util.GenerateBackupCode()
+		if err != nil {
+			svc.log.Error("Error generating backup code:", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+
+		codes = append(codes, code)
+		codeHashes = append(codeHashes, util.HashToken(code))
+	}
+
+	if err := svc.storage.EnableTOTP(ctx, userID, codeHashes); err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrMFAAlreadyEnabled
+		}
+		svc.log.Error("Error enabling TOTP:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.CompleteEnrollment(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// CompleteEnrollment clears the enrollment an admin forced on the user once they enabled a second factor.
+// The cached user is deleted, so the next requests of the user are accepted again.
+func (svc *MFAService) CompleteEnrollment(ctx context.Context, userID uuid.UUID) error {
+	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		svc.log.Error("Error getting user:", "userID", userID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if !userDAO.ForceMFAEnrollment {
+		return nil
+	}
+
+	userDAO.ForceMFAEnrollment = false
+	if _, err := svc.userStorage.UpdateUserStatus(ctx, userDAO); err != nil {
+		svc.log.Error("Error completing forced MFA enrollment:", "userID", userID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if err := svc.cache.Delete(ctx, util.GenerateCacheKey("user", userID)); err != nil {
+		svc.log.Error("Error deleting cached user:", "userID", userID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// DisableTOTP disables TOTP for the user, a current code is required.
+// The wrong codes are counted with the ones of the logins, against brute force.
+func (svc *MFAService) DisableTOTP(ctx context.Context, userID uuid.UUID, code string, client *domain.ClientInfo) error {
+	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("Error getting user:", "userID", userID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if err := svc.lockout.Check(ctx, domain.MFAAction, userDAO.Email, client.IPAddress); err != nil {
+		return err
+	}
+
+	if err := svc.VerifyCode(ctx, userID, code); err != nil {
+		if err != domain.ErrInvalidMFACode {
+			return err
+		}
+		if lockErr := svc.lockout.Fail(ctx, domain.MFAAction, userDAO.Email, client.IPAddress); lockErr != nil {
+			return lockErr
+		}
+		return err
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.MFAAction, userDAO.Email); err != nil {
+		return err
+	}
+
+	if err := svc.storage.DeleteTOTP(ctx, userID); err != nil {
+		svc.log.Error("Error disabling TOTP:", "userID", userID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// GetStatus returns the multi-factor authentication settings of the user
+func (svc *MFAService) GetStatus(ctx context.Context, userID uuid.UUID) (*domain.MFAStatus, error) {
+	enabled, err := svc.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.MFAStatus{TOTPEnabled: enabled}
+	if !enabled {
+		return status, nil
+	}
+
+	status.BackupCodesRemaining, err = svc.storage.CountUnusedBackupCodes(ctx, userID)
+	if err != nil {
+		svc.log.Error("Error counting backup codes:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return status, nil
+}
+
+// IsEnabled checks if TOTP is enabled for the user
+func (svc *MFAService) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	totpDAO, err := svc.getTOTP(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return totpDAO.Enabled, nil
+}
+
+// VerifyCode verifies a current TOTP code or an unused backup code of the user.
+// A backup code is used up by a successful verification.
+func (svc *MFAService) VerifyCode(ctx context.Context, userID uuid.UUID, code string) error {
+	totpDAO, err := svc.getTOTP(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrMFANotEnabled
+		}
+		return err
+	}
+
+	if !totpDAO.Enabled {
+		return domain.ErrMFANotEnabled
+	}
+
+	if util.ValidateOTP(code) {
+		return svc.validateTOTPCode(ctx, totpDAO, code)
+	}
+
+	err = svc.storage.UseBackupCode(ctx, userID, util.HashToken(util.NormalizeBackupCode(code)))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidMFACode
+		}
+		svc.log.Error("Error using backup code:", "userID", userID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// getTOTP gets the TOTP secret of the user decrypted.
+// A secret stored in plaintext before the encryption is encrypted on its first use.
+func (svc *MFAService) getTOTP(ctx context.Context, userID uuid.UUID) (*dao.TOTPDAO, error) {
+	totpDAO, err := svc.storage.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error getting TOTP secret:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	encoded, encrypted := strings.CutPrefix(totpDAO.Secret, encryptedSecretPrefix)
+	if !encrypted {
+		encryptedSecret, err := svc.encryptSecret(totpDAO.Secret)
+		if err != nil {
+			svc.log.Error("Error encrypting TOTP secret:", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+		if err := svc.storage.UpdateTOTPSecret(ctx, userID, encryptedSecret); err != nil {
+			svc.log.Error("Error storing encrypted TOTP secret:", "userID", userID, sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+
+		return totpDAO, nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		svc.log.Error("Error decoding TOTP secret:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+	secret, err := cipherkit.Decrypt(ciphertext, svc.secretKey)
+	if err != nil {
+		svc.log.Error("Error decrypting TOTP secret:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+	totpDAO.Secret = string(secret)
+
+	return totpDAO, nil
+}
+
+// encryptSecret encrypts a TOTP secret with the secret key of the service
+func (svc *MFAService) encryptSecret(secret string) (string, error) {
+	ciphertext, err := cipherkit.Encrypt([]byte(secret), svc.secretKey)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// validateTOTPCode validates a TOTP code against the secret.
+// A code is accepted only once, so an intercepted code cannot be replayed.
+func (svc *MFAService) validateTOTPCode(ctx context.Context, totpDAO *dao.TOTPDAO, code string) error {
+	if !totp.Validate(code, totpDAO.Secret) {
+		return domain.ErrInvalidMFACode
+	}
+
+	// Only the first of parallel requests with the same code stores it
+	cacheKey := util.GenerateCacheKey("totp_used", util.GenerateCacheKeyParams(totpDAO.UserID, code))
+	stored, err := svc.cache.SetIfAbsent(ctx, cacheKey, []byte(code), usedCodeTTL)
+	if err != nil {
+		svc.log.Error("Error storing used TOTP code:", sl.Err(err))
+		return domain.ErrInternal
+	}
+	if !stored {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
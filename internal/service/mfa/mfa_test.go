@@ -0,0 +1,149 @@
+package mfa_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/mfa"
+	"github.com/8thgencore/passfort/pkg/cipherkit"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPSecretEncryption(t *testing.T) {
+	ctx := context.Background()
+	user := &dao.UserDAO{ID: uuid.New(), Email: "user@example.com"}
+	secretKey := cipherkit.TOTPKey("server secret")
+
+	users := &mocks.UserRepository{}
+	users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+	cache := &cacheMocks.CacheRepository{}
+	cache.On("Get", mock.Anything, mock.Anything).Return(nil, errors.New("key not found"))
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	cache.On("SetIfAbsent", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	t.Run("Stores the enrolled secret encrypted", func(t *testing.T) {
+		var stored dao.TOTPDAO
+		storage := &mocks.MFARepository{}
+		storage.On("UpsertTOTP", mock.Anything, mock.Anything).Return(
+			func(_ context.Context, totpDAO *dao.TOTPDAO) *dao.TOTPDAO { stored = *totpDAO; return totpDAO },
+			func(context.Context, *dao.TOTPDAO) error { return nil },
+		).Once()
+		storage.On("GetTOTPByUserID", mock.Anything, user.ID).Return(
+			func(context.Context, uuid.UUID) *dao.TOTPDAO { copied := stored; return &copied },
+			func(context.Context, uuid.UUID) error { return nil },
+		)
+		storage.On("EnableTOTP", mock.Anything, user.ID, mock.Anything).Return(nil).Once()
+
+		svc := mfa.NewMFAService(slog.Default(), storage, users, cache, nil, "passfort", secretKey)
+
+		enrollment, err := svc.EnrollTOTP(ctx, user.ID)
+		require.NoError(t, err)
+		assert.NotContains(t, stored.Secret, enrollment.Secret)
+
+		code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+
+		codes, err := svc.ConfirmTOTP(ctx, user.ID, code)
+		require.NoError(t, err)
+		assert.Len(t, codes, domain.BackupCodesCount)
+		storage.AssertNotCalled(t, "UpdateTOTPSecret", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Encrypts a plaintext secret on its first use", func(t *testing.T) {
+		key, err := totp.Generate(totp.GenerateOpts{Issuer: "passfort", AccountName: user.Email})
+		require.NoError(t, err)
+
+		storage := &mocks.MFARepository{}
+		storage.On("GetTOTPByUserID", mock.Anything, user.ID).Return(&dao.TOTPDAO{UserID: user.ID, Secret: key.Secret()}, nil)
+		storage.On("UpdateTOTPSecret", mock.Anything, user.ID, mock.MatchedBy(func(secret string) bool {
+			return strings.HasPrefix(secret, "enc:v1:") && !strings.Contains(secret, key.Secret())
+		})).Return(nil).Once()
+		storage.On("EnableTOTP", mock.Anything, user.ID, mock.Anything).Return(nil).Once()
+
+		svc := mfa.NewMFAService(slog.Default(), storage, users, cache, nil, "passfort", secretKey)
+
+		code, err := totp.GenerateCode(key.Secret(), time.Now())
+		require.NoError(t, err)
+
+		_, err = svc.ConfirmTOTP(ctx, user.ID, code)
+		require.NoError(t, err)
+		storage.AssertExpectations(t)
+	})
+}
+
+func TestDisableTOTP(t *testing.T) {
+	ctx := context.Background()
+	client := &domain.ClientInfo{IPAddress: "127.0.0.1"}
+	user := &dao.UserDAO{ID: uuid.New(), Email: "user@example.com"}
+
+	setup := func(t *testing.T) (*mfa.MFAService, *mocks.MFARepository, string) {
+		key, err := totp.Generate(totp.GenerateOpts{Issuer: "passfort", AccountName: user.Email})
+		require.NoError(t, err)
+
+		users := &mocks.UserRepository{}
+		users.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+		storage := &mocks.MFARepository{}
+		storage.On("GetTOTPByUserID", mock.Anything, user.ID).Return(
+			func(context.Context, uuid.UUID) *dao.TOTPDAO {
+				return &dao.TOTPDAO{UserID: user.ID, Secret: key.Secret(), Enabled: true}
+			},
+			func(context.Context, uuid.UUID) error { return nil },
+		)
+		storage.On("UpdateTOTPSecret", mock.Anything, user.ID, mock.Anything).Return(nil)
+		storage.On("UseBackupCode", mock.Anything, user.ID, mock.Anything).Return(domain.ErrDataNotFound)
+		storage.On("DeleteTOTP", mock.Anything, user.ID).Return(nil)
+
+		cache := cacheMocks.NewMemoryCache()
+		lockoutService := lockout.NewLockoutService(slog.Default(), users, cache, nil, &config.Lockout{
+			AttemptWindow:      15 * time.Minute,
+			FreeAttempts:       3,
+			BaseDelay:          time.Second,
+			MaxAccountAttempts: 10,
+			LockoutDuration:    15 * time.Minute,
+			MaxLockoutDuration: 24 * time.Hour,
+			MaxIPAttempts:      100,
+		})
+
+		return mfa.NewMFAService(slog.Default(), storage, users, cache, lockoutService, "passfort", make([]byte, 32)), storage, key.Secret()
+	}
+
+	t.Run("Delays the account after wrong codes", func(t *testing.T) {
+		svc, storage, secret := setup(t)
+
+		for range 3 {
+			assert.Equal(t, domain.ErrInvalidMFACode, svc.DisableTOTP(ctx, user.ID, "wrong-code", client))
+		}
+		assert.ErrorIs(t, svc.DisableTOTP(ctx, user.ID, "wrong-code", client), domain.ErrTooManyAttempts)
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+		assert.ErrorIs(t, svc.DisableTOTP(ctx, user.ID, code, client), domain.ErrTooManyAttempts)
+		storage.AssertNotCalled(t, "DeleteTOTP", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Accepts a TOTP code only once", func(t *testing.T) {
+		svc, storage, secret := setup(t)
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+
+		require.NoError(t, svc.VerifyCode(ctx, user.ID, code))
+		assert.Equal(t, domain.ErrInvalidMFACode, svc.DisableTOTP(ctx, user.ID, code, client))
+		storage.AssertNotCalled(t, "DeleteTOTP", mock.Anything, mock.Anything)
+	})
+}
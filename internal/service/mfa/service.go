@@ -0,0 +1,46 @@
+package mfa
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/8thgencore/passfort/internal/service/adapters/cache"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * MFAService implements service.MFAService interface
+ * and provides an access to the multi-factor authentication repository
+ */
+type MFAService struct {
+	log         *slog.Logger
+	storage     storage.MFARepository
+	userStorage storage.UserRepository
+	cache       cache.CacheRepository
+	lockout     service.LockoutService
+	issuer      string
+	secretKey   []byte
+}
+
+// NewMFAService creates a new multi-factor authentication service instance.
+// The issuer is the name shown for the account in authenticator apps,
+// the TOTP secrets are stored encrypted with the secret key.
+func NewMFAService(
+	log *slog.Logger,
+	storage storage.MFARepository,
+	userStorage storage.UserRepository,
+	cache cache.CacheRepository,
+	lockoutService service.LockoutService,
+	issuer string,
+	secretKey []byte,
+) *MFAService {
+	return &MFAService{
+		log,
+		storage,
+		userStorage,
+		cache,
+		lockoutService,
+		issuer,
+		secretKey,
+	}
+}
@@ -2,7 +2,6 @@ package otp
 
 import (
 	"context"
-	"time"
 
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/pkg/logger/sl"
@@ -10,62 +9,70 @@ import (
 	"github.com/google/uuid"
 )
 
-// GenerateOTP generates a new OTP for the given user ID
-func (svc *OtpService) GenerateOTP(ctx context.Context, userID uuid.UUID) (string, error) {
-	otpCode := util.GenerateOTP()
+// GenerateOTP generates a new OTP of the purpose for the given user ID, replacing a pending one of the same purpose.
+// Only a bcrypt hash of the code is stored, so a leaked cache cannot be searched for the codes within their TTL.
+func (svc *OtpService) GenerateOTP(ctx context.Context, userID uuid.UUID, purpose domain.OTPPurpose) (string, error) {
+	otpCode, err := util.GenerateOTP()
+	if err != nil {
+		svc.log.Error("Error generating OTP:", sl.Err(err))
+		return "", domain.ErrInternal
+	}
+
+	hashedOTP, err := util.HashPassword(otpCode)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
 
-	// Store the generated OTP in your repository
-	cacheKey := util.GenerateCacheKey("user_otp", userID)
-	serializedOtp := []byte(otpCode)
+	// The wrong codes entered for the previous OTP do not count against the new one
+	if err := svc.cache.Delete(ctx, attemptsKey(userID, purpose)); err != nil {
+		svc.log.Error("Error resetting OTP attempts:", sl.Err(err))
+		return "", domain.ErrInternal
+	}
 
-	if err := svc.cache.Set(ctx, cacheKey, serializedOtp, 10*time.Minute); err != nil {
+	if err := svc.cache.Set(ctx, otpKey(userID, purpose), []byte(hashedOTP), svc.ttl); err != nil {
 		svc.log.Error("Error storing OTP:", sl.Err(err))
 		return "", domain.ErrInternal
 	}
 
-	// Return the generated OTP
 	return otpCode, nil
 }
 
-// VerifyOTP verifies if the provided OTP is valid for the given user ID
-func (svc *OtpService) VerifyOTP(ctx context.Context, userID uuid.UUID, otpCode string) error {
-	var oldOtpCode string
-	// Retrieve the stored OTP for the user
-	cacheKey := util.GenerateCacheKey("user_otp", userID)
-
-	storedOTP, err := svc.cache.Get(ctx, cacheKey)
-	if err == nil {
-		oldOtpCode = string(storedOTP)
-	}
-
-	// Verify the provided OTP against the stored OTP
-	valid := util.ValidateOTP(otpCode)
-	if !valid {
-		svc.log.Error("Invalid OTP provided")
+// VerifyOTP verifies if the provided OTP is valid for the given user ID and purpose.
+// The OTP is used up by a successful verification, and invalidated after too many wrong codes.
+func (svc *OtpService) VerifyOTP(ctx context.Context, userID uuid.UUID, purpose domain.OTPPurpose, otpCode string) error {
+	if !util.ValidateOTP(otpCode) {
 		return domain.ErrInvalidOTP
 	}
 
-	if otpCode != oldOtpCode {
-		svc.log.Error("Provided OTP does not match stored OTP")
+	storedOTP, err := svc.cache.Get(ctx, otpKey(userID, purpose))
+	if err != nil {
 		return domain.ErrInvalidOTP
 	}
 
-	// OTP is valid, remove it from storage to ensure one-time use
-	if err := svc.cache.Delete(ctx, cacheKey); err != nil {
-		svc.log.Error("Error removing OTP:", sl.Err(err))
+	// Attempts are counted before comparing, so parallel guesses cannot exceed the limit
+	attempts, err := svc.cache.Increment(ctx, attemptsKey(userID, purpose), svc.ttl)
+	if err != nil {
+		svc.log.Error("Error counting OTP attempts:", sl.Err(err))
 		return domain.ErrInternal
 	}
+	if attempts > int64(svc.maxAttempts) {
+		if err := svc.deleteOTP(ctx, userID, purpose); err != nil {
+			return err
+		}
+		return domain.ErrOTPAttemptsExceeded
+	}
 
-	return nil
-}
+	if err := util.CompareHash(otpCode, string(storedOTP)); err != nil {
+		return domain.ErrInvalidOTP
+	}
 
-// CheckCacheForKey checks if a value exists in the cache for the given key
-func (svc *OtpService) CheckCacheForKey(ctx context.Context, userID uuid.UUID) (bool, error) {
-	// Retrieve the stored OTP for the user
-	key := util.GenerateCacheKey("user_otp", userID)
+	// OTP is valid, remove it from storage to ensure one-time use
+	return svc.deleteOTP(ctx, userID, purpose)
+}
 
-	// Check if the value exists in the cache for the given key
-	exists, err := svc.cache.Exists(ctx, key)
+// HasPendingOTP checks if an OTP of the purpose has been sent to the user and has not expired yet
+func (svc *OtpService) HasPendingOTP(ctx context.Context, userID uuid.UUID, purpose domain.OTPPurpose) (bool, error) {
+	exists, err := svc.cache.Exists(ctx, otpKey(userID, purpose))
 	if err != nil {
 		svc.log.Error("Error checking cache for key:", sl.Err(err))
 		return false, domain.ErrInternal
@@ -73,3 +80,23 @@ func (svc *OtpService) CheckCacheForKey(ctx context.Context, userID uuid.UUID) (
 
 	return exists, nil
 }
+
+// deleteOTP removes the OTP and its attempts from storage
+func (svc *OtpService) deleteOTP(ctx context.Context, userID uuid.UUID, purpose domain.OTPPurpose) error {
+	for _, key := range []string{otpKey(userID, purpose), attemptsKey(userID, purpose)} {
+		if err := svc.cache.Delete(ctx, key); err != nil {
+			svc.log.Error("Error removing OTP:", sl.Err(err))
+			return domain.ErrInternal
+		}
+	}
+
+	return nil
+}
+
+func otpKey(userID uuid.UUID, purpose domain.OTPPurpose) string {
+	return util.GenerateCacheKey("otp", util.GenerateCacheKeyParams(purpose, userID))
+}
+
+func attemptsKey(userID uuid.UUID, purpose domain.OTPPurpose) string {
+	return util.GenerateCacheKey("otp_attempts", util.GenerateCacheKeyParams(purpose, userID))
+}
@@ -5,43 +5,66 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"log/slog"
 
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
 	"github.com/8thgencore/passfort/internal/service/otp"
+	"github.com/8thgencore/passfort/pkg/util"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	otpTTL         = 10 * time.Minute
+	maxOTPAttempts = 5
 )
 
 func setupOtpService() (*otp.OtpService, *mocks.CacheRepository) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cacheMock := &mocks.CacheRepository{}
-	otpService := otp.NewOtpService(logger, cacheMock)
+	otpService := otp.NewOtpService(logger, cacheMock, otpTTL, maxOTPAttempts)
 	return otpService, cacheMock
 }
 
+func otpKey(userID uuid.UUID, purpose domain.OTPPurpose) string {
+	return "otp:" + string(purpose) + "-" + userID.String()
+}
+
+func attemptsKey(userID uuid.UUID, purpose domain.OTPPurpose) string {
+	return "otp_attempts:" + string(purpose) + "-" + userID.String()
+}
+
 func TestGenerateOTP(t *testing.T) {
 	otpService, cache := setupOtpService()
 
 	userID, _ := uuid.NewRandom()
 
 	t.Run("success", func(t *testing.T) {
-		cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		var stored []byte
+		cache.On("Delete", mock.Anything, attemptsKey(userID, domain.RegistrationOTP)).Return(nil)
+		cache.On("Set", mock.Anything, otpKey(userID, domain.RegistrationOTP), mock.Anything, otpTTL).
+			Run(func(args mock.Arguments) { stored = args.Get(2).([]byte) }).
+			Return(nil)
 
-		generatedOTP, err := otpService.GenerateOTP(context.Background(), userID)
+		generatedOTP, err := otpService.GenerateOTP(context.Background(), userID, domain.RegistrationOTP)
 		assert.NoError(t, err)
-		assert.Equal(t, generatedOTP, generatedOTP)
+		assert.True(t, util.ValidateOTP(generatedOTP))
+		assert.NotEqual(t, generatedOTP, string(stored))
+		assert.NoError(t, util.CompareHash(generatedOTP, string(stored)))
 		cache.AssertExpectations(t)
 		cache.ExpectedCalls = nil
 	})
 
 	t.Run("failure on cache set", func(t *testing.T) {
+		cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
 		cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("cache error"))
 
-		_, err := otpService.GenerateOTP(context.Background(), userID)
+		_, err := otpService.GenerateOTP(context.Background(), userID, domain.RegistrationOTP)
 		assert.Error(t, err)
 		assert.Equal(t, domain.ErrInternal, err)
 		cache.AssertExpectations(t)
@@ -54,53 +77,66 @@ func TestVerifyOTP(t *testing.T) {
 
 	userID, _ := uuid.NewRandom()
 	otpCode := "123456"
+	hashedOTP, err := util.HashPassword(otpCode)
+	require.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
-		cache.On("Get", mock.Anything, mock.Anything).Return([]byte(otpCode), nil)
-		cache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		cache.On("Get", mock.Anything, otpKey(userID, domain.PasswordResetOTP)).Return([]byte(hashedOTP), nil)
+		cache.On("Increment", mock.Anything, attemptsKey(userID, domain.PasswordResetOTP), otpTTL).Return(int64(1), nil)
+		cache.On("Delete", mock.Anything, otpKey(userID, domain.PasswordResetOTP)).Return(nil)
+		cache.On("Delete", mock.Anything, attemptsKey(userID, domain.PasswordResetOTP)).Return(nil)
 
-		err := otpService.VerifyOTP(context.Background(), userID, otpCode)
+		err := otpService.VerifyOTP(context.Background(), userID, domain.PasswordResetOTP, otpCode)
 		assert.NoError(t, err)
 		cache.AssertExpectations(t)
+		cache.ExpectedCalls = nil
 	})
 
-	t.Run("invalid OTP code", func(t *testing.T) {
-		cache.On("Get", mock.Anything, mock.Anything).Return([]byte(otpCode), nil)
-
-		invalidOTPCode := "6543210"
-
-		err := otpService.VerifyOTP(context.Background(), userID, invalidOTPCode)
-		assert.Error(t, err)
+	t.Run("malformed OTP code", func(t *testing.T) {
+		err := otpService.VerifyOTP(context.Background(), userID, domain.PasswordResetOTP, "6543210")
 		assert.Equal(t, domain.ErrInvalidOTP, err)
-		cache.AssertNotCalled(t, "Delete")
+		cache.AssertNotCalled(t, "Get")
 		cache.ExpectedCalls = nil
 	})
 
 	t.Run("OTP not matching stored OTP", func(t *testing.T) {
-		cache.On("Get", mock.Anything, mock.Anything).Return([]byte("654321"), nil)
+		cache.On("Get", mock.Anything, mock.Anything).Return([]byte(hashedOTP), nil)
+		cache.On("Increment", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
 
-		err := otpService.VerifyOTP(context.Background(), userID, otpCode)
-		assert.Error(t, err)
+		err := otpService.VerifyOTP(context.Background(), userID, domain.PasswordResetOTP, "654321")
 		assert.Equal(t, domain.ErrInvalidOTP, err)
 		cache.AssertNotCalled(t, "Delete")
 		cache.ExpectedCalls = nil
 	})
 
-	t.Run("cache get error", func(t *testing.T) {
-		cache.On("Get", mock.Anything, mock.Anything).Return(nil, errors.New("cache error"))
+	t.Run("OTP of another purpose", func(t *testing.T) {
+		cache.On("Get", mock.Anything, otpKey(userID, domain.PasswordResetOTP)).Return(nil, errors.New("key not found"))
 
-		err := otpService.VerifyOTP(context.Background(), userID, otpCode)
-		assert.Error(t, err)
+		err := otpService.VerifyOTP(context.Background(), userID, domain.PasswordResetOTP, otpCode)
 		assert.Equal(t, domain.ErrInvalidOTP, err)
-		cache.AssertNotCalled(t, "Delete")
+		cache.AssertNotCalled(t, "Increment")
+		cache.ExpectedCalls = nil
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		cache.On("Get", mock.Anything, mock.Anything).Return([]byte(hashedOTP), nil)
+		cache.On("Increment", mock.Anything, mock.Anything, mock.Anything).Return(int64(maxOTPAttempts+1), nil)
+		cache.On("Delete", mock.Anything, otpKey(userID, domain.PasswordResetOTP)).Return(nil)
+		cache.On("Delete", mock.Anything, attemptsKey(userID, domain.PasswordResetOTP)).Return(nil)
+
+		// Even the right code is refused once the OTP is invalidated
+		err := otpService.VerifyOTP(context.Background(), userID, domain.PasswordResetOTP, otpCode)
+		assert.Equal(t, domain.ErrOTPAttemptsExceeded, err)
+		cache.AssertExpectations(t)
 		cache.ExpectedCalls = nil
 	})
 
 	t.Run("cache delete error", func(t *testing.T) {
-		cache.On("Get", mock.Anything, mock.Anything).Return([]byte(otpCode), nil)
+		cache.On("Get", mock.Anything, mock.Anything).Return([]byte(hashedOTP), nil)
+		cache.On("Increment", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
 		cache.On("Delete", mock.Anything, mock.Anything).Return(errors.New("cache error"))
 
-		err := otpService.VerifyOTP(context.Background(), userID, otpCode)
+		err := otpService.VerifyOTP(context.Background(), userID, domain.PasswordResetOTP, otpCode)
 		assert.Error(t, err)
 		assert.Equal(t, domain.ErrInternal, err)
 		cache.AssertExpectations(t)
@@ -108,15 +144,15 @@ func TestVerifyOTP(t *testing.T) {
 	})
 }
 
-func TestCheckCacheForKey(t *testing.T) {
+func TestHasPendingOTP(t *testing.T) {
 	otpService, cache := setupOtpService()
 
 	userID, _ := uuid.NewRandom()
 
 	t.Run("exists", func(t *testing.T) {
-		cache.On("Exists", mock.Anything, mock.Anything).Return(true, nil)
+		cache.On("Exists", mock.Anything, otpKey(userID, domain.RegistrationOTP)).Return(true, nil)
 
-		exists, err := otpService.CheckCacheForKey(context.Background(), userID)
+		exists, err := otpService.HasPendingOTP(context.Background(), userID, domain.RegistrationOTP)
 		assert.NoError(t, err)
 		assert.True(t, exists)
 		cache.AssertExpectations(t)
@@ -126,7 +162,7 @@ func TestCheckCacheForKey(t *testing.T) {
 	t.Run("does not exist", func(t *testing.T) {
 		cache.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
 
-		exists, err := otpService.CheckCacheForKey(context.Background(), userID)
+		exists, err := otpService.HasPendingOTP(context.Background(), userID, domain.RegistrationOTP)
 		assert.NoError(t, err)
 		assert.False(t, exists)
 		cache.AssertExpectations(t)
@@ -136,7 +172,7 @@ func TestCheckCacheForKey(t *testing.T) {
 	t.Run("cache exists error", func(t *testing.T) {
 		cache.On("Exists", mock.Anything, mock.Anything).Return(false, errors.New("cache error"))
 
-		exists, err := otpService.CheckCacheForKey(context.Background(), userID)
+		exists, err := otpService.HasPendingOTP(context.Background(), userID, domain.RegistrationOTP)
 		assert.Error(t, err)
 		assert.Equal(t, domain.ErrInternal, err)
 		assert.False(t, exists)
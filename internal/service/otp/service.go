@@ -2,6 +2,7 @@ package otp
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/8thgencore/passfort/internal/service/adapters/cache"
 )
@@ -11,14 +12,18 @@ import (
  * and provides OTP (One-Time Password) related functionality
  */
 type OtpService struct {
-	log   *slog.Logger
-	cache cache.CacheRepository
+	log         *slog.Logger
+	cache       cache.CacheRepository
+	ttl         time.Duration
+	maxAttempts int
 }
 
 // NewOtpService creates a new OTP service instance
-func NewOtpService(log *slog.Logger, cache cache.CacheRepository) *OtpService {
+func NewOtpService(log *slog.Logger, cache cache.CacheRepository, ttl time.Duration, maxAttempts int) *OtpService {
 	return &OtpService{
 		log,
 		cache,
+		ttl,
+		maxAttempts,
 	}
 }
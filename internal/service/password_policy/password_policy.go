@@ -0,0 +1,138 @@
+package passwordpolicy
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/pkg/util"
+)
+
+// maxPasswordBytes is the longest password bcrypt hashes, it refuses longer ones
+const maxPasswordBytes = 72
+
+// characterClass is a class of characters counted by the policy
+type characterClass int
+
+const (
+	lowercase characterClass = iota
+	uppercase
+	digit
+	symbol
+)
+
+// poolSizes are the number of characters of each class, used to estimate the entropy
+var poolSizes = map[characterClass]float64{
+	lowercase: 26,
+	uppercase: 26,
+	digit:     10,
+	symbol:    33,
+}
+
+// ValidatePassword checks a new account password against the account rules
+func (svc *PasswordPolicyService) ValidatePassword(password string) error {
+	return svc.validate("password", password, svc.account, "")
+}
+
+// ValidateMasterPassword checks a new master password against the master password rules.
+// It must differ from the account password of the given hash if the rules say so.
+func (svc *PasswordPolicyService) ValidateMasterPassword(password, accountPasswordHash string) error {
+	return svc.validate("master password", password, svc.masterPassword, accountPasswordHash)
+}
+
+// validate checks a password against the rules and returns all the rules it breaks
+func (svc *PasswordPolicyService) validate(name, password string, rules config.PasswordRules, accountPasswordHash string) error {
+	var violations []string
+
+	if utf8.RuneCountInString(password) < rules.MinLength {
+		violations = append(violations, fmt.Sprintf("%s must be at least %d characters long", name, rules.MinLength))
+	}
+	if len(password) > maxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("%s must be at most %d bytes long", name, maxPasswordBytes))
+	}
+	if len(characterClasses(password)) < rules.MinCharacterClasses {
+		violations = append(violations, fmt.Sprintf("%s must use at least %d of lowercase letters, uppercase letters, digits and symbols",
+			name, rules.MinCharacterClasses))
+	}
+	if rules.Denylist && svc.isCommon(password) {
+		violations = append(violations, fmt.Sprintf("%s is too common", name))
+	} else if entropy(password) < rules.MinEntropy {
+		violations = append(violations, fmt.Sprintf("%s is too easy to guess", name))
+	}
+	if rules.DifferFromAccountPassword && accountPasswordHash != "" && len(password) <= maxPasswordBytes &&
+		util.CompareHash(password, accountPasswordHash) == nil {
+		violations = append(violations, fmt.Sprintf("%s must differ from the account password", name))
+	}
+
+	if len(violations) > 0 {
+		return &domain.PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// isCommon checks if the password is in the denylist, also with digits or symbols around it like "Password123!"
+func (svc *PasswordPolicyService) isCommon(password string) bool {
+	password = strings.ToLower(password)
+	if _, ok := svc.denylist[password]; ok {
+		return true
+	}
+
+	word := strings.TrimFunc(password, func(r rune) bool { return !unicode.IsLetter(r) })
+	_, ok := svc.denylist[word]
+	return ok && word != ""
+}
+
+// characterClasses returns the classes of the characters of the password
+func characterClasses(password string) map[characterClass]struct{} {
+	classes := make(map[characterClass]struct{})
+	for _, r := range password {
+		classes[classOf(r)] = struct{}{}
+	}
+	return classes
+}
+
+// classOf returns the class of a character, anything else than a letter or a digit is a symbol
+func classOf(r rune) characterClass {
+	switch {
+	case unicode.IsLower(r):
+		return lowercase
+	case unicode.IsUpper(r):
+		return uppercase
+	case unicode.IsDigit(r):
+		return digit
+	default:
+		return symbol
+	}
+}
+
+// entropy estimates the strength of a password in bits.
+// Each character adds the bits of the pool of the classes the password uses,
+// a character repeating the previous one or continuing a sequence like "abc" or "321" adds a single bit.
+func entropy(password string) float64 {
+	var pool float64
+	for class := range characterClasses(password) {
+		pool += poolSizes[class]
+	}
+	if pool == 0 {
+		return 0
+	}
+	bitsPerCharacter := math.Log2(pool)
+
+	var bits float64
+	var previous rune
+	for i, r := range []rune(password) {
+		if i > 0 && (r == previous || r == previous+1 || r == previous-1) {
+			bits++
+		} else {
+			bits += bitsPerCharacter
+		}
+		previous = r
+	}
+
+	return bits
+}
@@ -0,0 +1,104 @@
+package passwordpolicy_test
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	passwordpolicy "github.com/8thgencore/passfort/internal/service/password_policy"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var policyConfig = config.PasswordPolicy{
+	Account:        config.PasswordRules{MinLength: 8, MinCharacterClasses: 2, MinEntropy: 40, Denylist: true},
+	MasterPassword: config.PasswordRules{MinLength: 12, MinCharacterClasses: 3, MinEntropy: 60, Denylist: true, DifferFromAccountPassword: true},
+}
+
+// violations returns the broken rules of the policy error
+func violations(t *testing.T, err error) []string {
+	var policyErr *domain.PasswordPolicyError
+	require.True(t, errors.As(err, &policyErr), "expected a policy error, got %v", err)
+	assert.ErrorIs(t, err, domain.ErrWeakPassword)
+	return policyErr.Violations
+}
+
+func TestValidatePassword(t *testing.T) {
+	svc, err := passwordpolicy.NewPasswordPolicyService(slog.Default(), &policyConfig)
+	require.NoError(t, err)
+
+	t.Run("Accepts a strong password", func(t *testing.T) {
+		assert.NoError(t, svc.ValidatePassword("Correct-Horse-42"))
+	})
+
+	tests := []struct {
+		name      string
+		password  string
+		violation string
+	}{
+		{"Too short", "Xy7!", "password must be at least 8 characters long"},
+		{"Too long for bcrypt", strings.Repeat("Correct-Horse-42", 5), "password must be at most 72 bytes long"},
+		{"Single character class", "tqzvkwmrjdyx", "password must use at least 2 of lowercase letters, uppercase letters, digits and symbols"},
+		{"Common password", "password", "password is too common"},
+		{"Common password with suffix", "Password123!", "password is too common"},
+		{"Sequence", "abcdefgh1", "password is too easy to guess"},
+		{"Repetition", "aaaaaaaaaaa1", "password is too easy to guess"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, violations(t, svc.ValidatePassword(tt.password)), tt.violation)
+		})
+	}
+}
+
+func TestValidateMasterPassword(t *testing.T) {
+	svc, err := passwordpolicy.NewPasswordPolicyService(slog.Default(), &policyConfig)
+	require.NoError(t, err)
+
+	accountHash, err := util.HashPassword("Battery-Staple-Vault-7")
+	require.NoError(t, err)
+
+	t.Run("Accepts a strong password differing from the account password", func(t *testing.T) {
+		assert.NoError(t, svc.ValidateMasterPassword("Tangerine-Orbit-Cellar-19", accountHash))
+	})
+
+	t.Run("Refuses the account password", func(t *testing.T) {
+		assert.Equal(t, []string{"master password must differ from the account password"},
+			violations(t, svc.ValidateMasterPassword("Battery-Staple-Vault-7", accountHash)))
+	})
+
+	t.Run("Applies the stricter rules", func(t *testing.T) {
+		// Good enough for the account, not for the master password
+		require.NoError(t, svc.ValidatePassword("Correct42"))
+		assert.Equal(t, []string{
+			"master password must be at least 12 characters long",
+			"master password must use at least 3 of lowercase letters, uppercase letters, digits and symbols",
+			"master password is too easy to guess",
+		}, violations(t, svc.ValidateMasterPassword("correct42", accountHash)))
+	})
+}
+
+func TestDenylistFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Acme-Corp-2024\n\n  passfort-rocks  \n"), 0o600))
+
+	cfg := policyConfig
+	cfg.DenylistFile = path
+	svc, err := passwordpolicy.NewPasswordPolicyService(slog.Default(), &cfg)
+	require.NoError(t, err)
+
+	assert.Contains(t, violations(t, svc.ValidatePassword("acme-corp-2024")), "password is too common")
+	assert.Contains(t, violations(t, svc.ValidatePassword("Passfort-Rocks")), "password is too common")
+	// The built-in list is still used
+	assert.Contains(t, violations(t, svc.ValidatePassword("qwerty123")), "password is too common")
+
+	cfg.DenylistFile = filepath.Join(t.TempDir(), "missing.txt")
+	_, err = passwordpolicy.NewPasswordPolicyService(slog.Default(), &cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/8thgencore/passfort/internal/config"
+)
+
+// commonPasswords is the built-in denylist, one password per line
+//
+//go:embed common_passwords.txt
+var commonPasswords string
+
+/**
+ * PasswordPolicyService implements service.PasswordPolicyService interface
+ * and checks the new passwords against the configured rules
+ */
+type PasswordPolicyService struct {
+	log            *slog.Logger
+	account        config.PasswordRules
+	masterPassword config.PasswordRules
+	denylist       map[string]struct{}
+}
+
+// NewPasswordPolicyService creates a new password policy service instance.
+// The common passwords of the denylist file are added to the built-in ones.
+func NewPasswordPolicyService(log *slog.Logger, cfg *config.PasswordPolicy) (*PasswordPolicyService, error) {
+	denylist := make(map[string]struct{})
+	if err := addPasswords(denylist, strings.NewReader(commonPasswords)); err != nil {
+		return nil, fmt.Errorf("failed to read built-in password denylist: %w", err)
+	}
+
+	if cfg.DenylistFile != "" {
+		file, err := os.Open(cfg.DenylistFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open password denylist: %w", err)
+		}
+		defer file.Close()
+
+		if err := addPasswords(denylist, file); err != nil {
+			return nil, fmt.Errorf("failed to read password denylist: %w", err)
+		}
+	}
+
+	return &PasswordPolicyService{
+		log:            log,
+		account:        cfg.Account,
+		masterPassword: cfg.MasterPassword,
+		denylist:       denylist,
+	}, nil
+}
+
+// addPasswords adds the passwords of a list, one per line, to the denylist
+func addPasswords(denylist map[string]struct{}, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if password := strings.ToLower(strings.TrimSpace(scanner.Text())); password != "" {
+			denylist[password] = struct{}{}
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,127 @@
+package personalaccesstoken
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/pkg/cipherkit"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// tokenSize is the number of random bytes of a personal access token
+	tokenSize = 32
+	// usageInterval limits how often the last use of a token is written to the database
+	usageInterval = time.Minute
+)
+
+// CreatePersonalAccessToken creates a new personal access token for the user.
+// The vault encryption key of the user is stored wrapped with a key derived from the token,
+// so the token can decrypt secrets without a master password activation.
+// The token is returned only once, it is stored hashed.
+func (svc *PersonalAccessTokenService) CreatePersonalAccessToken(ctx context.Context, userID uuid.UUID, token *domain.PersonalAccessToken, encryptionKey []byte) (string, *domain.PersonalAccessToken, error) {
+	for _, collectionID := range token.CollectionIDs {
+		isPartOfCollection, err := svc.collectionStorage.IsUserPartOfCollection(ctx, userID, collectionID)
+		if err != nil {
+			svc.log.Error("Error checking if user is part of collection", "user", userID, "collection", collectionID, sl.Err(err))
+			return "", nil, domain.ErrInternal
+		}
+		if !isPartOfCollection {
+			return "", nil, domain.ErrDataNotFound
+		}
+	}
+
+	secret, err := util.GenerateRandomToken(tokenSize)
+	if err != nil {
+		svc.log.Error("Error generating personal access token:", sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+	rawToken := domain.PersonalAccessTokenPrefix + secret
+
+	token.UserID = userID
+	token.WrappedKey, err = cipherkit.Encrypt(encryptionKey, cipherkit.TokenKey(rawToken))
+	if err != nil {
+		svc.log.Error("Error wrapping encryption key:", sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+
+	tokenDAO := converter.ToPersonalAccessTokenDAO(token)
+	tokenDAO.TokenHash = util.HashToken(rawToken)
+
+	createdToken, err := svc.storage.CreatePersonalAccessToken(ctx, tokenDAO)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return "", nil, err
+		}
+		svc.log.Error("Error creating personal access token:", "userID", userID, sl.Err(err))
+		return "", nil, domain.ErrInternal
+	}
+
+	return rawToken, converter.ToPersonalAccessToken(createdToken), nil
+}
+
+// ListPersonalAccessTokens returns the personal access tokens of the user
+func (svc *PersonalAccessTokenService) ListPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	tokensDAO, err := svc.storage.ListPersonalAccessTokensByUserID(ctx, userID)
+	if err != nil {
+		svc.log.Error("Error listing personal access tokens:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	tokens := make([]domain.PersonalAccessToken, 0, len(tokensDAO))
+	for _, tokenDAO := range tokensDAO {
+		tokens = append(tokens, *converter.ToPersonalAccessToken(&tokenDAO))
+	}
+
+	return tokens, nil
+}
+
+// DeletePersonalAccessToken revokes a personal access token of the user
+func (svc *PersonalAccessTokenService) DeletePersonalAccessToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	err := svc.storage.DeletePersonalAccessToken(ctx, userID, tokenID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("Error deleting personal access token:", "tokenID", tokenID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// Authenticate verifies a personal access token and returns it with the vault encryption key of its owner
+func (svc *PersonalAccessTokenService) Authenticate(ctx context.Context, rawToken string) (*domain.PersonalAccessToken, []byte, error) {
+	tokenDAO, err := svc.storage.GetPersonalAccessTokenByHash(ctx, util.HashToken(rawToken))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, nil, domain.ErrInvalidToken
+		}
+		svc.log.Error("Error getting personal access token:", sl.Err(err))
+		return nil, nil, domain.ErrInternal
+	}
+
+	token := converter.ToPersonalAccessToken(tokenDAO)
+	if token.IsExpired() {
+		return nil, nil, domain.ErrExpiredToken
+	}
+
+	encryptionKey, err := cipherkit.Decrypt(token.WrappedKey, cipherkit.TokenKey(rawToken))
+	if err != nil {
+		svc.log.Error("Error unwrapping encryption key:", "tokenID", token.ID, sl.Err(err))
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	// The last use is only tracked to the minute, so busy CI jobs do not write on every request
+	if time.Since(token.LastUsedAt) > usageInterval {
+		if err := svc.storage.UpdatePersonalAccessTokenUsage(ctx, token.ID); err != nil {
+			svc.log.Error("Error updating personal access token usage:", "tokenID", token.ID, sl.Err(err))
+		}
+	}
+
+	return token, encryptionKey, nil
+}
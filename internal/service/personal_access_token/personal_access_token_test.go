@@ -0,0 +1,123 @@
+package personalaccesstoken_test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	personalaccesstoken "github.com/8thgencore/passfort/internal/service/personal_access_token"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPersonalAccessTokenService() (*personalaccesstoken.PersonalAccessTokenService, *mocks.PersonalAccessTokenRepository, *mocks.CollectionRepository) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	tokens := &mocks.PersonalAccessTokenRepository{}
+	collections := &mocks.CollectionRepository{}
+	svc := personalaccesstoken.NewPersonalAccessTokenService(logger, tokens, collections)
+	return svc, tokens, collections
+}
+
+// createToken creates a token through the service and returns it with the stored row
+func createToken(t *testing.T, svc *personalaccesstoken.PersonalAccessTokenService, tokens *mocks.PersonalAccessTokenRepository, userID uuid.UUID, encryptionKey []byte) (string, *dao.PersonalAccessTokenDAO) {
+	var stored *dao.PersonalAccessTokenDAO
+	tokens.On("CreatePersonalAccessToken", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, tokenDAO *dao.PersonalAccessTokenDAO) *dao.PersonalAccessTokenDAO {
+			tokenDAO.ID = uuid.New()
+			tokenDAO.CreatedAt = time.Now()
+			stored = tokenDAO
+			return tokenDAO
+		}, nil).Once()
+
+	rawToken, token, err := svc.CreatePersonalAccessToken(context.Background(), userID, &domain.PersonalAccessToken{
+		Name:   "CI",
+		Scopes: []domain.TokenScope{domain.ScopeSecretsRead},
+	}, encryptionKey)
+	require.NoError(t, err)
+	require.Equal(t, stored.ID, token.ID)
+
+	return rawToken, stored
+}
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	svc, tokens, collections := setupPersonalAccessTokenService()
+
+	userID := uuid.New()
+	encryptionKey := make([]byte, 32)
+
+	t.Run("stores the token hashed with a wrapped key", func(t *testing.T) {
+		rawToken, stored := createToken(t, svc, tokens, userID, encryptionKey)
+
+		assert.True(t, strings.HasPrefix(rawToken, domain.PersonalAccessTokenPrefix))
+		assert.Equal(t, util.HashToken(rawToken), stored.TokenHash)
+		assert.Equal(t, userID, stored.UserID)
+		assert.NotContains(t, string(stored.WrappedKey), string(encryptionKey))
+		assert.False(t, stored.ExpiresAt.Valid)
+	})
+
+	t.Run("collection of another user", func(t *testing.T) {
+		collectionID := uuid.New()
+		collections.On("IsUserPartOfCollection", mock.Anything, userID, collectionID).Return(false, nil).Once()
+
+		_, _, err := svc.CreatePersonalAccessToken(context.Background(), userID, &domain.PersonalAccessToken{
+			Name:          "CI",
+			Scopes:        []domain.TokenScope{domain.ScopeSecretsRead},
+			CollectionIDs: []uuid.UUID{collectionID},
+		}, encryptionKey)
+		assert.Equal(t, domain.ErrDataNotFound, err)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	svc, tokens, _ := setupPersonalAccessTokenService()
+
+	userID := uuid.New()
+	encryptionKey := []byte("0123456789abcdef0123456789abcdef")
+
+	t.Run("unwraps the encryption key", func(t *testing.T) {
+		rawToken, stored := createToken(t, svc, tokens, userID, encryptionKey)
+		tokens.On("GetPersonalAccessTokenByHash", mock.Anything, stored.TokenHash).Return(stored, nil).Once()
+		tokens.On("UpdatePersonalAccessTokenUsage", mock.Anything, stored.ID).Return(nil).Once()
+
+		token, key, err := svc.Authenticate(context.Background(), rawToken)
+		require.NoError(t, err)
+		assert.Equal(t, userID, token.UserID)
+		assert.Equal(t, encryptionKey, key)
+		tokens.AssertExpectations(t)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		tokens.On("GetPersonalAccessTokenByHash", mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound).Once()
+
+		_, _, err := svc.Authenticate(context.Background(), domain.PersonalAccessTokenPrefix+"unknown")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		rawToken, stored := createToken(t, svc, tokens, userID, encryptionKey)
+		stored.ExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+		tokens.On("GetPersonalAccessTokenByHash", mock.Anything, stored.TokenHash).Return(stored, nil).Once()
+
+		_, _, err := svc.Authenticate(context.Background(), rawToken)
+		assert.Equal(t, domain.ErrExpiredToken, err)
+	})
+
+	t.Run("wrapped key of another token", func(t *testing.T) {
+		_, stored := createToken(t, svc, tokens, userID, encryptionKey)
+		otherToken, _ := createToken(t, svc, tokens, userID, encryptionKey)
+		tokens.On("GetPersonalAccessTokenByHash", mock.Anything, util.HashToken(otherToken)).Return(stored, nil).Once()
+
+		_, _, err := svc.Authenticate(context.Background(), otherToken)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+	})
+}
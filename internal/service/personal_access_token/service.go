@@ -0,0 +1,30 @@
+package personalaccesstoken
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * PersonalAccessTokenService implements service.PersonalAccessTokenService interface
+ * and provides an access to the personal access token repository
+ */
+type PersonalAccessTokenService struct {
+	log               *slog.Logger
+	storage           storage.PersonalAccessTokenRepository
+	collectionStorage storage.CollectionRepository
+}
+
+// NewPersonalAccessTokenService creates a new personal access token service instance
+func NewPersonalAccessTokenService(
+	log *slog.Logger,
+	storage storage.PersonalAccessTokenRepository,
+	collectionStorage storage.CollectionRepository,
+) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		log,
+		storage,
+		collectionStorage,
+	}
+}
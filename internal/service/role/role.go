@@ -0,0 +1,212 @@
+package role
+
+import (
+	"context"
+	"slices"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+)
+
+// CreateRole creates a new custom role
+func (svc *RoleService) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	role.Permissions = normalizePermissions(role.Permissions)
+
+	createdRole, err := svc.storage.CreateRole(ctx, converter.ToRoleDAO(role))
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		svc.log.Error("Error creating role:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return converter.ToRole(createdRole), nil
+}
+
+// ListRoles returns all custom roles
+func (svc *RoleService) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	rolesDAO, err := svc.storage.ListRoles(ctx)
+	if err != nil {
+		svc.log.Error("Error listing roles:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	roles := make([]domain.Role, 0, len(rolesDAO))
+	for _, roleDAO := range rolesDAO {
+		roles = append(roles, *converter.ToRole(&roleDAO))
+	}
+
+	return roles, nil
+}
+
+// UpdateRole updates the name, the description and the permissions of a custom role.
+// The users of the role get its new permissions on their next request.
+func (svc *RoleService) UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	role.Permissions = normalizePermissions(role.Permissions)
+
+	updatedRole, err := svc.storage.UpdateRole(ctx, converter.ToRoleDAO(role))
+	if err != nil {
+		if err == domain.ErrDataNotFound || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		svc.log.Error("Error updating role:", "roleID", role.ID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.clearPermissions(ctx); err != nil {
+		return nil, err
+	}
+
+	return converter.ToRole(updatedRole), nil
+}
+
+// DeleteRole deletes a custom role, its users lose its permissions on their next request
+func (svc *RoleService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	if err := svc.storage.DeleteRole(ctx, id); err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("Error deleting role:", "roleID", id, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return svc.clearPermissions(ctx)
+}
+
+// ListUserRoles returns the custom roles assigned to a user
+func (svc *RoleService) ListUserRoles(ctx context.Context, userID uuid.UUID) ([]domain.Role, error) {
+	if _, err := svc.userStorage.GetUserByID(ctx, userID); err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	rolesDAO, err := svc.storage.ListRolesByUserID(ctx, userID)
+	if err != nil {
+		svc.log.Error("Error listing user roles:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	roles := make([]domain.Role, 0, len(rolesDAO))
+	for _, roleDAO := range rolesDAO {
+		roles = append(roles, *converter.ToRole(&roleDAO))
+	}
+
+	return roles, nil
+}
+
+// SetUserRoles replaces the custom roles assigned to a user, the user gets their new permissions on their next request
+func (svc *RoleService) SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) ([]domain.Role, error) {
+	if _, err := svc.userStorage.GetUserByID(ctx, userID); err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.storage.SetUserRoles(ctx, userID, uniqueIDs(roleIDs)); err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error setting user roles:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.cache.Delete(ctx, util.GenerateCacheKey("user_permissions", userID)); err != nil {
+		svc.log.Error("Error deleting cached permissions:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return svc.ListUserRoles(ctx, userID)
+}
+
+// SetRoleMembers replaces the users a custom role is assigned to, they get their new permissions on their next request
+func (svc *RoleService) SetRoleMembers(ctx context.Context, roleID uuid.UUID, userIDs []uuid.UUID) error {
+	if err := svc.storage.SetRoleMembers(ctx, roleID, uniqueIDs(userIDs)); err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("Error setting role members:", "roleID", roleID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return svc.clearPermissions(ctx)
+}
+
+// GetUserPermissions returns the permissions of a user, granted by their built-in role and their custom roles.
+// They are read from the database, not from the token, so changes apply without a new login.
+func (svc *RoleService) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+
+	cacheKey := util.GenerateCacheKey("user_permissions", userID)
+	cachedPermissions, err := svc.cache.Get(ctx, cacheKey)
+	if err == nil {
+		if err := util.Deserialize(cachedPermissions, &permissions); err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		return permissions, nil
+	}
+
+	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	rolesDAO, err := svc.storage.ListRolesByUserID(ctx, userID)
+	if err != nil {
+		svc.log.Error("Error listing user roles:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	permissions = append(permissions, domain.UserRoleEnum(userDAO.Role).Permissions()...)
+	for _, roleDAO := range rolesDAO {
+		permissions = append(permissions, converter.ToRole(&roleDAO).Permissions...)
+	}
+	permissions = normalizePermissions(permissions)
+
+	permissionsSerialized, err := util.Serialize(permissions)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.cache.Set(ctx, cacheKey, permissionsSerialized, 0); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return permissions, nil
+}
+
+// clearPermissions deletes the cached permissions of all users after a role changed
+func (svc *RoleService) clearPermissions(ctx context.Context) error {
+	if err := svc.cache.DeleteByPrefix(ctx, "user_permissions:*"); err != nil {
+		svc.log.Error("Error deleting cached permissions:", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// normalizePermissions sorts the permissions and removes the duplicates
+func normalizePermissions(permissions []domain.Permission) []domain.Permission {
+	permissions = slices.Clone(permissions)
+	slices.Sort(permissions)
+
+	return slices.Compact(permissions)
+}
+
+// uniqueIDs sorts the IDs and removes the duplicates
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+
+	return slices.Compact(ids)
+}
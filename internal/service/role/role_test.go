@@ -0,0 +1,173 @@
+package role_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/role"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type roleTest struct {
+	svc       *role.RoleService
+	cache     *cacheMocks.MemoryCache
+	roles     *mocks.RoleRepository
+	users     *mocks.UserRepository
+	user      *dao.UserDAO
+	userRoles []dao.RoleDAO
+}
+
+func setupRoleTest(t *testing.T) *roleTest {
+	test := &roleTest{
+		cache: cacheMocks.NewMemoryCache(),
+		roles: &mocks.RoleRepository{},
+		users: &mocks.UserRepository{},
+		user:  &dao.UserDAO{ID: uuid.New(), Email: "user@example.com", Role: string(domain.UserRole)},
+	}
+	test.users.On("GetUserByID", mock.Anything, test.user.ID).Return(
+		func(context.Context, uuid.UUID) *dao.UserDAO { copied := *test.user; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	test.users.On("GetUserByID", mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound)
+	test.roles.On("ListRolesByUserID", mock.Anything, test.user.ID).Return(
+		func(context.Context, uuid.UUID) []dao.RoleDAO { return test.userRoles },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+
+	test.svc = role.NewRoleService(slog.Default(), test.roles, test.users, test.cache)
+
+	return test
+}
+
+func TestGetUserPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Admins have all permissions", func(t *testing.T) {
+		test := setupRoleTest(t)
+		test.user.Role = string(domain.AdminRole)
+
+		permissions, err := test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, domain.Permissions, permissions)
+	})
+
+	t.Run("Users have the permissions of their custom roles", func(t *testing.T) {
+		test := setupRoleTest(t)
+
+		permissions, err := test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, permissions)
+
+		require.NoError(t, test.cache.DeleteByPrefix(ctx, ""))
+		test.userRoles = []dao.RoleDAO{
+			{ID: uuid.New(), Name: "support", Permissions: []string{"users:read", "invites:read"}},
+			{ID: uuid.New(), Name: "auditor", Permissions: []string{"users:read", "audit:read"}},
+		}
+
+		permissions, err = test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Permission{domain.AuditReadPermission, domain.InvitesReadPermission, domain.UsersReadPermission}, permissions)
+	})
+
+	t.Run("Returns error when user does not exist", func(t *testing.T) {
+		test := setupRoleTest(t)
+
+		_, err := test.svc.GetUserPermissions(ctx, uuid.New())
+		assert.Equal(t, domain.ErrDataNotFound, err)
+	})
+}
+
+func TestPermissionChanges(t *testing.T) {
+	ctx := context.Background()
+	support := dao.RoleDAO{ID: uuid.New(), Name: "support", Permissions: []string{"users:read"}}
+
+	t.Run("Assigning roles applies on the next request", func(t *testing.T) {
+		test := setupRoleTest(t)
+
+		permissions, err := test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, permissions)
+
+		test.roles.On("SetUserRoles", mock.Anything, test.user.ID, []uuid.UUID{support.ID}).Return(
+			func(context.Context, uuid.UUID, []uuid.UUID) error {
+				test.userRoles = []dao.RoleDAO{support}
+				return nil
+			},
+		).Once()
+
+		roles, err := test.svc.SetUserRoles(ctx, test.user.ID, []uuid.UUID{support.ID, support.ID})
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		assert.Equal(t, "support", roles[0].Name)
+
+		permissions, err = test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Permission{domain.UsersReadPermission}, permissions)
+		test.roles.AssertExpectations(t)
+	})
+
+	t.Run("Updating a role applies to its users on their next request", func(t *testing.T) {
+		test := setupRoleTest(t)
+		test.userRoles = []dao.RoleDAO{support}
+
+		permissions, err := test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Permission{domain.UsersReadPermission}, permissions)
+
+		updated := support
+		updated.Permissions = []string{"users:read", "users:write"}
+		test.roles.On("UpdateRole", mock.Anything, mock.Anything).Return(
+			func(context.Context, *dao.RoleDAO) *dao.RoleDAO {
+				test.userRoles = []dao.RoleDAO{updated}
+				return &updated
+			},
+			func(context.Context, *dao.RoleDAO) error { return nil },
+		).Once()
+
+		_, err = test.svc.UpdateRole(ctx, &domain.Role{
+			ID:          support.ID,
+			Name:        support.Name,
+			Permissions: []domain.Permission{domain.UsersWritePermission, domain.UsersReadPermission},
+		})
+		require.NoError(t, err)
+
+		permissions, err = test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Permission{domain.UsersReadPermission, domain.UsersWritePermission}, permissions)
+	})
+
+	t.Run("Deleting a role applies to its users on their next request", func(t *testing.T) {
+		test := setupRoleTest(t)
+		test.userRoles = []dao.RoleDAO{support}
+
+		_, err := test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+
+		test.roles.On("DeleteRole", mock.Anything, support.ID).Return(
+			func(context.Context, uuid.UUID) error { test.userRoles = nil; return nil },
+		).Once()
+
+		require.NoError(t, test.svc.DeleteRole(ctx, support.ID))
+
+		permissions, err := test.svc.GetUserPermissions(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.Empty(t, permissions)
+	})
+
+	t.Run("Returns error when assigning unknown roles", func(t *testing.T) {
+		test := setupRoleTest(t)
+		unknownID := uuid.New()
+		test.roles.On("SetUserRoles", mock.Anything, test.user.ID, []uuid.UUID{unknownID}).Return(domain.ErrDataNotFound).Once()
+
+		_, err := test.svc.SetUserRoles(ctx, test.user.ID, []uuid.UUID{unknownID})
+		assert.Equal(t, domain.ErrDataNotFound, err)
+	})
+}
@@ -0,0 +1,35 @@
+package role
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/service/adapters/cache"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * RoleService implements service.RoleService interface
+ * and provides an access to the role repository
+ * and cache service
+ */
+type RoleService struct {
+	log         *slog.Logger
+	storage     storage.RoleRepository
+	userStorage storage.UserRepository
+	cache       cache.CacheRepository
+}
+
+// NewRoleService creates a new role service instance
+func NewRoleService(
+	log *slog.Logger,
+	storage storage.RoleRepository,
+	userStorage storage.UserRepository,
+	cache cache.CacheRepository,
+) *RoleService {
+	return &RoleService{
+		log,
+		storage,
+		userStorage,
+		cache,
+	}
+}
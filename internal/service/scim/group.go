@@ -0,0 +1,165 @@
+package scim
+
+import (
+	"context"
+	"strings"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/google/uuid"
+)
+
+// ListGroups returns the page of the groups matching the filter, or of all groups without a filter,
+// with the total number of matching groups. The roles defined by the admins are not groups.
+func (svc *SCIMService) ListGroups(ctx context.Context, filter *domain.SCIMFilter, startIndex, count uint64) ([]domain.SCIMGroup, uint64, error) {
+	var rolesDAO []dao.RoleDAO
+
+	if filter != nil {
+		roleDAO, err := svc.findRole(ctx, filter)
+		if err != nil && err != domain.ErrDataNotFound {
+			return nil, 0, err
+		}
+		if roleDAO != nil {
+			rolesDAO = append(rolesDAO, *roleDAO)
+		}
+	} else {
+		var err error
+		rolesDAO, err = svc.roleStorage.ListSCIMManagedRoles(ctx)
+		if err != nil {
+			svc.log.Error("Error listing roles:", sl.Err(err))
+			return nil, 0, domain.ErrInternal
+		}
+	}
+
+	page := paginate(rolesDAO, startIndex, count)
+	groups := make([]domain.SCIMGroup, 0, len(page))
+	for _, roleDAO := range page {
+		group, err := svc.toGroup(ctx, &roleDAO)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		groups = append(groups, *group)
+	}
+
+	return groups, uint64(len(rolesDAO)), nil
+}
+
+// GetGroup returns a group by id
+func (svc *SCIMService) GetGroup(ctx context.Context, id uuid.UUID) (*domain.SCIMGroup, error) {
+	roleDAO, err := svc.getRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.toGroup(ctx, roleDAO)
+}
+
+// CreateGroup creates a custom role without permissions for the group and assigns it to the members.
+// An admin grants the permissions of the group through the roles.
+func (svc *SCIMService) CreateGroup(ctx context.Context, name string, memberIDs []uuid.UUID) (*domain.SCIMGroup, error) {
+	role, err := svc.roleService.CreateRole(ctx, &domain.Role{Name: name, SCIMManaged: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.roleService.SetRoleMembers(ctx, role.ID, memberIDs); err != nil {
+		// The group is not created with unknown members
+		if deleteErr := svc.roleService.DeleteRole(ctx, role.ID); deleteErr != nil {
+			svc.log.Error("Error deleting role of a failed group creation:", "roleID", role.ID, sl.Err(deleteErr))
+		}
+		return nil, err
+	}
+
+	svc.log.Info("Group provisioned", "role_id", role.ID)
+
+	return svc.GetGroup(ctx, role.ID)
+}
+
+// ReplaceGroup renames a group and replaces its members, the permissions of its role are kept
+func (svc *SCIMService) ReplaceGroup(ctx context.Context, id uuid.UUID, name string, memberIDs []uuid.UUID) (*domain.SCIMGroup, error) {
+	roleDAO, err := svc.getRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	role := converter.ToRole(roleDAO)
+
+	if role.Name != name {
+		role.Name = name
+		if role, err = svc.roleService.UpdateRole(ctx, role); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := svc.roleService.SetRoleMembers(ctx, id, memberIDs); err != nil {
+		return nil, err
+	}
+
+	return svc.GetGroup(ctx, id)
+}
+
+// DeleteGroup deletes the role of a group, its members lose its permissions
+func (svc *SCIMService) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	if _, err := svc.getRole(ctx, id); err != nil {
+		return err
+	}
+
+	return svc.roleService.DeleteRole(ctx, id)
+}
+
+// findRole returns the role matching a filter on the id or the displayName, which is the role name
+func (svc *SCIMService) findRole(ctx context.Context, filter *domain.SCIMFilter) (*dao.RoleDAO, error) {
+	switch strings.ToLower(filter.Attribute) {
+	case "id":
+		id, err := uuid.Parse(filter.Value)
+		if err != nil {
+			return nil, domain.ErrDataNotFound
+		}
+		return svc.getRole(ctx, id)
+	case "displayname":
+		roleDAO, err := svc.roleStorage.GetRoleByName(ctx, filter.Value)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return nil, err
+			}
+			svc.log.Error("Error getting role by name:", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+		if !roleDAO.SCIMManaged {
+			return nil, domain.ErrDataNotFound
+		}
+		return roleDAO, nil
+	default:
+		return nil, domain.ErrInvalidSCIMFilter
+	}
+}
+
+// getRole returns the role of a group by id, the roles defined by the admins are not found
+func (svc *SCIMService) getRole(ctx context.Context, id uuid.UUID) (*dao.RoleDAO, error) {
+	roleDAO, err := svc.roleStorage.GetRoleByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error getting role:", "roleID", id, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+	if !roleDAO.SCIMManaged {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return roleDAO, nil
+}
+
+// toGroup returns the group of a role with its members
+func (svc *SCIMService) toGroup(ctx context.Context, roleDAO *dao.RoleDAO) (*domain.SCIMGroup, error) {
+	memberIDs, err := svc.roleStorage.ListRoleMemberIDs(ctx, roleDAO.ID)
+	if err != nil {
+		svc.log.Error("Error listing role members:", "roleID", roleDAO.ID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.SCIMGroup{Role: *converter.ToRole(roleDAO), MemberIDs: memberIDs}, nil
+}
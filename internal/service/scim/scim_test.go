@@ -0,0 +1,260 @@
+package scim_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/role"
+	"github.com/8thgencore/passfort/internal/service/scim"
+	"github.com/8thgencore/passfort/internal/service/token"
+	"github.com/8thgencore/passfort/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type scimTest struct {
+	svc      *scim.SCIMService
+	users    *mocks.UserRepository
+	roles    *mocks.RoleRepository
+	sessions *mocks.TokenRepository
+	user     *dao.UserDAO
+}
+
+func setupSCIMTest() *scimTest {
+	log := slog.Default()
+	cache := cacheMocks.NewMemoryCache()
+
+	test := &scimTest{
+		users:    &mocks.UserRepository{},
+		roles:    &mocks.RoleRepository{},
+		sessions: &mocks.TokenRepository{},
+		user:     &dao.UserDAO{ID: uuid.New(), Name: "John", Email: "john@example.com", Role: string(domain.UserRole)},
+	}
+	test.users.On("GetUserByID", mock.Anything, test.user.ID).Return(
+		func(context.Context, uuid.UUID) *dao.UserDAO { copied := *test.user; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	test.users.On("GetUserByID", mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound)
+	test.users.On("UpdateUser", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, user *dao.UserDAO) *dao.UserDAO {
+			if user.Name != "" {
+				test.user.Name = user.Name
+			}
+			if user.Email != "" {
+				test.user.Email = user.Email
+			}
+			test.user.IsVerified = test.user.IsVerified || user.IsVerified
+			copied := *test.user
+			return &copied
+		},
+		func(context.Context, *dao.UserDAO) error { return nil },
+	)
+	test.users.On("UpdateUserStatus", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, user *dao.UserDAO) *dao.UserDAO { *test.user = *user; return user },
+		func(context.Context, *dao.UserDAO) error { return nil },
+	)
+	// The suspended users have no sessions to revoke
+	test.sessions.On("ListTokenFamiliesByUserID", mock.Anything, mock.Anything, mock.Anything).Return([]dao.TokenFamilyDAO{}, nil)
+
+	tokenService := token.NewTokenService(log, nil, "", 15*time.Minute, time.Hour, test.sessions, cache)
+	lockoutService := lockout.NewLockoutService(log, test.users, cache, nil, &config.Lockout{})
+	userService := user.NewUserService(log, test.users, cache, lockoutService, tokenService, domain.BlockOwnedCollections)
+	roleService := role.NewRoleService(log, test.roles, test.users, cache)
+	test.svc = scim.NewSCIMService(log, test.users, test.roles, cache, userService, roleService)
+
+	return test
+}
+
+func TestProvisionUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Provisions a verified user", func(t *testing.T) {
+		test := setupSCIMTest()
+		test.users.On("ProvisionUser", mock.Anything, mock.Anything).Return(
+			func(_ context.Context, user *dao.UserDAO) *dao.UserDAO {
+				test.user.Name, test.user.Email, test.user.Password = user.Name, user.Email, user.Password
+				test.user.IsVerified, test.user.SuspendedAt = user.IsVerified, user.SuspendedAt
+				copied := *test.user
+				return &copied
+			},
+			func(context.Context, *dao.UserDAO) error { return nil },
+		).Once()
+
+		created, err := test.svc.CreateUser(ctx, &domain.User{Name: "Jane", Email: "jane@example.com"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, test.user.ID, created.ID)
+		assert.True(t, created.IsVerified)
+		assert.False(t, created.IsSuspended())
+		assert.NotEmpty(t, test.user.Password)
+	})
+
+	t.Run("Provisions an inactive user suspended", func(t *testing.T) {
+		test := setupSCIMTest()
+		test.users.On("ProvisionUser", mock.Anything, mock.MatchedBy(func(user *dao.UserDAO) bool {
+			return user.IsVerified && user.SuspendedAt.Valid
+		})).Return(
+			func(_ context.Context, user *dao.UserDAO) *dao.UserDAO {
+				test.user.IsVerified, test.user.SuspendedAt = user.IsVerified, user.SuspendedAt
+				copied := *test.user
+				return &copied
+			},
+			func(context.Context, *dao.UserDAO) error { return nil },
+		).Once()
+
+		created, err := test.svc.CreateUser(ctx, &domain.User{Name: "John", Email: "john@example.com"}, false)
+		require.NoError(t, err)
+		assert.True(t, created.IsSuspended())
+		test.users.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+		test.users.AssertNotCalled(t, "UpdateUserStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Returns error when the email is taken", func(t *testing.T) {
+		test := setupSCIMTest()
+		test.users.On("ProvisionUser", mock.Anything, mock.Anything).Return(nil, domain.ErrConflictingData).Once()
+
+		_, err := test.svc.CreateUser(ctx, &domain.User{Name: "John", Email: "john@example.com"}, true)
+		assert.Equal(t, domain.ErrConflictingData, err)
+	})
+}
+
+func TestReplaceUser(t *testing.T) {
+	ctx := context.Background()
+	test := setupSCIMTest()
+
+	deactivated, err := test.svc.ReplaceUser(ctx, &domain.User{ID: test.user.ID, Name: "John", Email: "john@example.com"}, false)
+	require.NoError(t, err)
+	assert.True(t, deactivated.IsSuspended())
+
+	reactivated, err := test.svc.ReplaceUser(ctx, &domain.User{ID: test.user.ID, Name: "John Doe", Email: "john@example.com"}, true)
+	require.NoError(t, err)
+	assert.False(t, reactivated.IsSuspended())
+	assert.Equal(t, "John Doe", reactivated.Name)
+
+	require.NoError(t, test.svc.DeactivateUser(ctx, test.user.ID))
+	assert.True(t, test.user.SuspendedAt.Valid)
+
+	_, err = test.svc.ReplaceUser(ctx, &domain.User{ID: uuid.New(), Name: "Jane", Email: "jane@example.com"}, true)
+	assert.Equal(t, domain.ErrDataNotFound, err)
+}
+
+func TestListUsers(t *testing.T) {
+	ctx := context.Background()
+	test := setupSCIMTest()
+	test.users.On("GetUserByEmail", mock.Anything, test.user.Email).Return(
+		func(context.Context, string) *dao.UserDAO { copied := *test.user; return &copied },
+		func(context.Context, string) error { return nil },
+	)
+	test.users.On("GetUserByEmail", mock.Anything, mock.Anything).Return(nil, domain.ErrDataNotFound)
+
+	users, total, err := test.svc.ListUsers(ctx, &domain.SCIMFilter{Attribute: "userName", Value: test.user.Email}, 1, 100)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, test.user.ID, users[0].ID)
+
+	users, total, err = test.svc.ListUsers(ctx, &domain.SCIMFilter{Attribute: "userName", Value: "jane@example.com"}, 1, 100)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, users)
+
+	_, _, err = test.svc.ListUsers(ctx, &domain.SCIMFilter{Attribute: "title", Value: "Engineer"}, 1, 100)
+	assert.Equal(t, domain.ErrInvalidSCIMFilter, err)
+}
+
+func TestProvisionGroup(t *testing.T) {
+	ctx := context.Background()
+	group := dao.RoleDAO{ID: uuid.New(), Name: "support", SCIMManaged: true}
+
+	t.Run("Groups are roles assigned to their members", func(t *testing.T) {
+		test := setupSCIMTest()
+		test.roles.On("CreateRole", mock.Anything, mock.MatchedBy(func(role *dao.RoleDAO) bool {
+			return role.SCIMManaged
+		})).Return(&group, nil).Once()
+		test.roles.On("SetRoleMembers", mock.Anything, group.ID, []uuid.UUID{test.user.ID}).Return(nil).Once()
+		test.roles.On("GetRoleByID", mock.Anything, group.ID).Return(&group, nil)
+		test.roles.On("ListRoleMemberIDs", mock.Anything, group.ID).Return([]uuid.UUID{test.user.ID}, nil)
+
+		created, err := test.svc.CreateGroup(ctx, "support", []uuid.UUID{test.user.ID, test.user.ID})
+		require.NoError(t, err)
+		assert.Equal(t, group.ID, created.Role.ID)
+		assert.Empty(t, created.Role.Permissions)
+		assert.Equal(t, []uuid.UUID{test.user.ID}, created.MemberIDs)
+		test.roles.AssertExpectations(t)
+	})
+
+	t.Run("Deletes the role when a member does not exist", func(t *testing.T) {
+		test := setupSCIMTest()
+		test.roles.On("CreateRole", mock.Anything, mock.Anything).Return(&group, nil).Once()
+		test.roles.On("SetRoleMembers", mock.Anything, group.ID, mock.Anything).Return(domain.ErrDataNotFound).Once()
+		test.roles.On("DeleteRole", mock.Anything, group.ID).Return(nil).Once()
+
+		_, err := test.svc.CreateGroup(ctx, "support", []uuid.UUID{uuid.New()})
+		assert.Equal(t, domain.ErrDataNotFound, err)
+		test.roles.AssertExpectations(t)
+	})
+
+	t.Run("Replacing a group keeps the permissions of its role", func(t *testing.T) {
+		test := setupSCIMTest()
+		role := dao.RoleDAO{ID: group.ID, Name: "support", Permissions: []string{"users:read"}, SCIMManaged: true}
+		test.roles.On("GetRoleByID", mock.Anything, group.ID).Return(&role, nil)
+		test.roles.On("UpdateRole", mock.Anything, mock.MatchedBy(func(updated *dao.RoleDAO) bool {
+			return updated.Name == "helpdesk" && len(updated.Permissions) == 1
+		})).Return(
+			func(_ context.Context, updated *dao.RoleDAO) *dao.RoleDAO { role = *updated; return updated },
+			func(context.Context, *dao.RoleDAO) error { return nil },
+		).Once()
+		test.roles.On("SetRoleMembers", mock.Anything, group.ID, []uuid.UUID(nil)).Return(nil).Once()
+		test.roles.On("ListRoleMemberIDs", mock.Anything, group.ID).Return([]uuid.UUID{}, nil)
+
+		replaced, err := test.svc.ReplaceGroup(ctx, group.ID, "helpdesk", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "helpdesk", replaced.Role.Name)
+		assert.Equal(t, []domain.Permission{domain.UsersReadPermission}, replaced.Role.Permissions)
+		test.roles.AssertExpectations(t)
+	})
+}
+
+func TestAdminRolesAreNotGroups(t *testing.T) {
+	ctx := context.Background()
+	test := setupSCIMTest()
+	adminRole := dao.RoleDAO{ID: uuid.New(), Name: "auditors", Permissions: []string{"users:read"}}
+	group := dao.RoleDAO{ID: uuid.New(), Name: "support", SCIMManaged: true}
+	test.roles.On("GetRoleByID", mock.Anything, adminRole.ID).Return(&adminRole, nil)
+	test.roles.On("GetRoleByName", mock.Anything, adminRole.Name).Return(&adminRole, nil)
+	test.roles.On("ListSCIMManagedRoles", mock.Anything).Return([]dao.RoleDAO{group}, nil)
+	test.roles.On("ListRoleMemberIDs", mock.Anything, group.ID).Return([]uuid.UUID{}, nil)
+
+	groups, total, err := test.svc.ListGroups(ctx, nil, 1, 100)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), total)
+	require.Len(t, groups, 1)
+	assert.Equal(t, group.ID, groups[0].Role.ID)
+
+	groups, total, err = test.svc.ListGroups(ctx, &domain.SCIMFilter{Attribute: "displayName", Value: adminRole.Name}, 1, 100)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, groups)
+
+	_, err = test.svc.GetGroup(ctx, adminRole.ID)
+	assert.Equal(t, domain.ErrDataNotFound, err)
+
+	_, err = test.svc.ReplaceGroup(ctx, adminRole.ID, "helpdesk", nil)
+	assert.Equal(t, domain.ErrDataNotFound, err)
+
+	err = test.svc.DeleteGroup(ctx, adminRole.ID)
+	assert.Equal(t, domain.ErrDataNotFound, err)
+
+	test.roles.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything)
+	test.roles.AssertNotCalled(t, "SetRoleMembers", mock.Anything, mock.Anything, mock.Anything)
+	test.roles.AssertNotCalled(t, "DeleteRole", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,50 @@
+package scim
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/8thgencore/passfort/internal/service/adapters/cache"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * SCIMService implements service.SCIMService interface
+ * and provisions the users and the groups of an identity provider,
+ * the groups being custom roles
+ */
+type SCIMService struct {
+	log         *slog.Logger
+	userStorage storage.UserRepository
+	roleStorage storage.RoleRepository
+	cache       cache.CacheRepository
+	userService service.UserService
+	roleService service.RoleService
+}
+
+// NewSCIMService creates a new SCIM service instance
+func NewSCIMService(
+	log *slog.Logger,
+	userStorage storage.UserRepository,
+	roleStorage storage.RoleRepository,
+	cache cache.CacheRepository,
+	userService service.UserService,
+	roleService service.RoleService,
+) *SCIMService {
+	return &SCIMService{
+		log,
+		userStorage,
+		roleStorage,
+		cache,
+		userService,
+		roleService,
+	}
+}
+
+// paginate returns the page of the items from the 1-based start index, as SCIM counts them
+func paginate[T any](items []T, startIndex, count uint64) []T {
+	start := min(startIndex-1, uint64(len(items)))
+	end := min(start+count, uint64(len(items)))
+
+	return items[start:end]
+}
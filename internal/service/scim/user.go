@@ -0,0 +1,152 @@
+package scim
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+)
+
+// ListUsers returns the page of the users matching the filter, or of all users without a filter,
+// with the total number of matching users
+func (svc *SCIMService) ListUsers(ctx context.Context, filter *domain.SCIMFilter, startIndex, count uint64) ([]domain.User, uint64, error) {
+	if filter != nil {
+		user, err := svc.findUser(ctx, filter)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return []domain.User{}, 0, nil
+			}
+			return nil, 0, err
+		}
+
+		return paginate([]domain.User{*user}, startIndex, count), 1, nil
+	}
+
+	total, err := svc.userStorage.CountUsers(ctx)
+	if err != nil {
+		svc.log.Error("Error counting users:", sl.Err(err))
+		return nil, 0, domain.ErrInternal
+	}
+
+	usersDAO, err := svc.userStorage.ListUsers(ctx, startIndex-1, count)
+	if err != nil {
+		svc.log.Error("Error listing users:", sl.Err(err))
+		return nil, 0, domain.ErrInternal
+	}
+
+	users := make([]domain.User, 0, len(usersDAO))
+	for _, userDAO := range usersDAO {
+		users = append(users, *converter.ToUser(&userDAO))
+	}
+
+	return users, total, nil
+}
+
+// GetUser returns a user by id
+func (svc *SCIMService) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return svc.userService.GetUserByID(ctx, id)
+}
+
+// CreateUser provisions a verified account, inactive accounts are created suspended.
+// The account gets a random password, its user logs in with single sign-on or resets the password.
+func (svc *SCIMService) CreateUser(ctx context.Context, user *domain.User, active bool) (*domain.User, error) {
+	password, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	var suspendedAt sql.NullTime
+	if !active {
+		suspendedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	// The identity provider has verified the email
+	userDAO, err := svc.userStorage.ProvisionUser(ctx, &dao.UserDAO{
+		Name:        user.Name,
+		Email:       user.Email,
+		Password:    hashedPassword,
+		IsVerified:  true,
+		SuspendedAt: suspendedAt,
+	})
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		svc.log.Error("Error creating user:", sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if err := svc.cache.DeleteByPrefix(ctx, "users:*"); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	svc.log.Info("User provisioned", "user_id", userDAO.ID, "active", active)
+
+	return converter.ToUser(userDAO), nil
+}
+
+// ReplaceUser updates the name and the email of a user, and suspends or unsuspends them to match the active state
+func (svc *SCIMService) ReplaceUser(ctx context.Context, user *domain.User, active bool) (*domain.User, error) {
+	updatedUser, err := svc.userService.UpdateUser(ctx, &domain.User{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
+	})
+	if err == domain.ErrNoUpdatedData {
+		updatedUser, err = svc.userService.GetUserByID(ctx, user.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case active && updatedUser.IsSuspended():
+		return svc.userService.UnsuspendUser(ctx, user.ID)
+	case !active && !updatedUser.IsSuspended():
+		return svc.userService.SuspendUser(ctx, uuid.Nil, user.ID)
+	default:
+		return updatedUser, nil
+	}
+}
+
+// DeactivateUser suspends a deprovisioned user and revokes their sessions.
+// The account and its vault are kept, an admin deletes them once the collections are handed over.
+func (svc *SCIMService) DeactivateUser(ctx context.Context, id uuid.UUID) error {
+	_, err := svc.userService.SuspendUser(ctx, uuid.Nil, id)
+	return err
+}
+
+// findUser returns the user matching a filter on the id or the userName, which is the email
+func (svc *SCIMService) findUser(ctx context.Context, filter *domain.SCIMFilter) (*domain.User, error) {
+	switch strings.ToLower(filter.Attribute) {
+	case "id":
+		id, err := uuid.Parse(filter.Value)
+		if err != nil {
+			return nil, domain.ErrDataNotFound
+		}
+		return svc.userService.GetUserByID(ctx, id)
+	case "username", "emails.value":
+		userDAO, err := svc.userStorage.GetUserByEmail(ctx, filter.Value)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return nil, err
+			}
+			svc.log.Error("Error getting user by email:", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+		return converter.ToUser(userDAO), nil
+	default:
+		return nil, domain.ErrInvalidSCIMFilter
+	}
+}
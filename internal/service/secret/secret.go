@@ -2,6 +2,7 @@ package secret
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/8thgencore/passfort/internal/domain"
@@ -12,17 +13,33 @@ import (
 	"github.com/google/uuid"
 )
 
+// undecryptableSecretName is the name listed for the secrets whose metadata cannot be decrypted with the user's key
+const undecryptableSecretName = "Encrypted secret"
+
 // CreateSecret creates a new secret
 func (svc *SecretService) CreateSecret(ctx context.Context, userID uuid.UUID, secret *domain.Secret, encryptionKey []byte) (*domain.Secret, error) {
 	if !svc.isUserPartOfCollection(ctx, userID, secret.CollectionID) {
 		return nil, domain.ErrUnauthorized
 	}
 
+	if secret.FolderID != uuid.Nil {
+		if err := svc.checkCollectionFolder(ctx, secret.CollectionID, secret.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := domain.NormalizeTagNames(secret.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	secret.CreatedBy = userID
 	secret.UpdatedBy = userID
 	secretDAO := converter.ToSecretDAO(secret)
+	if err := svc.encryptSecretMetadata(secretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
 
-	var err error
 	switch secret.SecretType {
 	case domain.PasswordSecretType:
 		err = svc.createPasswordSecret(ctx, secret, encryptionKey, secretDAO)
@@ -44,8 +61,22 @@ func (svc *SecretService) CreateSecret(ctx context.Context, userID uuid.UUID, se
 
 	createdSecretDAO.TextSecret = secretDAO.TextSecret
 	createdSecretDAO.PasswordSecret = secretDAO.PasswordSecret
+	if err := svc.decryptSecretMetadata(createdSecretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
+	createdSecret := converter.ToSecret(createdSecretDAO)
+
+	if err := svc.updateBlindIndexes(ctx, createdSecret, encryptionKey); err != nil {
+		return nil, err
+	}
 
-	return converter.ToSecret(createdSecretDAO), nil
+	if len(tags) > 0 {
+		if err := svc.setSecretTags(ctx, userID, createdSecret, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	return createdSecret, nil
 }
 
 func (svc *SecretService) createPasswordSecret(ctx context.Context, secret *domain.Secret, encryptionKey []byte, secretDAO *dao.SecretDAO) error {
@@ -55,10 +86,11 @@ func (svc *SecretService) createPasswordSecret(ctx context.Context, secret *doma
 		return domain.ErrInternal
 	}
 
-	passwordSecretDAO := converter.ToPasswordSecretDAO(secret.PasswordSecret)
+	// The URL and login come from the secret DAO, which holds them encrypted if metadata encryption is enabled
+	passwordSecretDAO := secretDAO.PasswordSecret
 	passwordSecretDAO.Password = encryptedPassword
 
-	newSecret, err := svc.secretStorage.CreatePasswordSecret(ctx, passwordSecretDAO)
+	newSecret, err := svc.secretStorage.CreatePasswordSecret(ctx, &passwordSecretDAO)
 	if err != nil {
 		svc.log.Error("Error creating password secret:", sl.Err(err))
 		return domain.ErrInternal
@@ -87,28 +119,73 @@ func (svc *SecretService) createTextSecret(ctx context.Context, secret *domain.S
 	return nil
 }
 
-// ListSecretsByCollectionID lists secrets for a specific collection ID
-func (svc *SecretService) ListSecretsByCollectionID(ctx context.Context, userID, collectionID uuid.UUID, skip, limit uint64) ([]domain.Secret, error) {
+// ListSecretsByCollectionID lists secrets for a specific collection ID.
+// An empty folderID lists the collection root, recursive includes the secrets of nested folders.
+// A non-empty tag only lists the secrets having this tag.
+func (svc *SecretService) ListSecretsByCollectionID(ctx context.Context, userID, collectionID, folderID uuid.UUID, recursive bool, tag string, encryptionKey []byte, skip, limit uint64) ([]domain.Secret, error) {
 	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
 		return nil, domain.ErrUnauthorized
 	}
 
-	secretsDAO, err := svc.secretStorage.ListSecretsByCollectionID(ctx, collectionID, skip, limit)
+	if folderID != uuid.Nil {
+		if err := svc.checkCollectionFolder(ctx, collectionID, folderID); err != nil {
+			return nil, err
+		}
+	}
+
+	if tag != "" {
+		var err error
+		if tag, err = domain.NormalizeTagName(tag); err != nil {
+			return nil, err
+		}
+	}
+
+	secretsDAO, err := svc.secretStorage.ListSecretsByCollectionID(ctx, userID, collectionID, folderID, recursive, tag, skip, limit)
 	if err != nil {
-		svc.log.Error("Error listing secrets for collection:", "collectionID", collectionID, sl.Err(err))
+		svc.log.Error("Error listing secrets for collection:", "collectionID", collectionID, "folderID", folderID, sl.Err(err))
 		return nil, domain.ErrDataNotFound
 	}
 
-	secrets := make([]domain.Secret, 0, len(secretsDAO))
-	for _, secretDAO := range secretsDAO {
-		secrets = append(secrets, *converter.ToSecret(&secretDAO))
+	return svc.toSecrets(ctx, userID, secretsDAO, encryptionKey)
+}
+
+// SearchSecrets searches the secrets of all collections the user is a member of.
+// Encrypted metadata is not indexed, so secrets with encrypted metadata are only found with LookupSecrets.
+func (svc *SecretService) SearchSecrets(ctx context.Context, userID uuid.UUID, text string, encryptionKey []byte, skip, limit uint64) ([]domain.Secret, error) {
+	secretsDAO, err := svc.secretStorage.SearchSecrets(ctx, userID, text, skip, limit)
+	if err != nil {
+		svc.log.Error("Error searching secrets:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
 	}
 
-	return secrets, nil
+	return svc.toSecrets(ctx, userID, secretsDAO, encryptionKey)
+}
+
+// LookupSecrets finds the secrets of the user's collections whose login or URL host exactly matches the value.
+// The lookup uses blind indexes, so the value is never compared in plaintext by the database.
+func (svc *SecretService) LookupSecrets(ctx context.Context, userID uuid.UUID, field domain.BlindIndexField, value string, encryptionKey []byte, skip, limit uint64) ([]domain.Secret, error) {
+	value = domain.NormalizeBlindIndexValue(field, value)
+	if value == "" {
+		return []domain.Secret{}, nil
+	}
+
+	index := &dao.BlindIndexDAO{
+		Field: string(field),
+		Value: cipherkit.BlindIndex(encryptionKey, string(field), value),
+	}
+
+	secretsDAO, err := svc.secretStorage.LookupSecretsByBlindIndex(ctx, userID, index, skip, limit)
+	if err != nil {
+		svc.log.Error("Error looking up secrets:", "userID", userID, "field", field, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	return svc.toSecrets(ctx, userID, secretsDAO, encryptionKey)
 }
 
-// GetSecret gets a secret by ID
-func (svc *SecretService) GetSecret(ctx context.Context, userID, collectionID, secretID uuid.UUID, encryptionKey []byte) (*domain.Secret, error) {
+// GetSecret gets a secret by ID.
+// The payload of a secret flagged for a re-prompt is only decrypted if the master password was reprompted for the request.
+func (svc *SecretService) GetSecret(ctx context.Context, userID, collectionID, secretID uuid.UUID, encryptionKey []byte, reprompted bool) (*domain.Secret, error) {
 	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
 		return nil, domain.ErrUnauthorized
 	}
@@ -119,6 +196,10 @@ func (svc *SecretService) GetSecret(ctx context.Context, userID, collectionID, s
 		return nil, domain.ErrDataNotFound
 	}
 
+	if secretDAO.Reprompt && !reprompted {
+		return nil, domain.ErrMasterPasswordRepromptRequired
+	}
+
 	switch secretDAO.SecretType {
 	case dao.PasswordSecretType:
 		passwordSecretDAO, err := svc.getAndDecryptPasswordSecret(ctx, secretDAO.LinkedSecretId, encryptionKey)
@@ -137,7 +218,16 @@ func (svc *SecretService) GetSecret(ctx context.Context, userID, collectionID, s
 		return nil, domain.ErrInvalidSecretType
 	}
 
-	return converter.ToSecret(secretDAO), nil
+	if err := svc.decryptSecretMetadata(secretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
+
+	secrets := []domain.Secret{*converter.ToSecret(secretDAO)}
+	if err := svc.loadSecretTags(ctx, userID, secrets); err != nil {
+		return nil, err
+	}
+
+	return &secrets[0], nil
 }
 
 func (svc *SecretService) getAndDecryptPasswordSecret(ctx context.Context, linkedSecretID uuid.UUID, encryptionKey []byte) (*dao.PasswordSecretDAO, error) {
@@ -174,16 +264,50 @@ func (svc *SecretService) getAndDecryptTextSecret(ctx context.Context, linkedSec
 	return textSecretDAO, nil
 }
 
-// UpdateSecret updates a secret
-func (svc *SecretService) UpdateSecret(ctx context.Context, userID, collectionID uuid.UUID, secret *domain.Secret, encryptionKey []byte) (*domain.Secret, error) {
+// UpdateSecret updates a secret.
+// A nil reprompt keeps the re-prompt flag of the secret, clearing the flag requires the master password
+// to be reprompted for the request like reading the payload does.
+func (svc *SecretService) UpdateSecret(
+	ctx context.Context,
+	userID, collectionID uuid.UUID,
+	secret *domain.Secret,
+	reprompt *bool,
+	encryptionKey []byte,
+	reprompted bool,
+) (*domain.Secret, error) {
 	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
 		return nil, domain.ErrUnauthorized
 	}
 
+	currentSecretDAO, err := svc.secretStorage.GetSecretByID(ctx, secret.ID)
+	if err != nil || currentSecretDAO.CollectionID != collectionID {
+		return nil, domain.ErrDataNotFound
+	}
+
+	secret.Reprompt = currentSecretDAO.Reprompt
+	if reprompt != nil {
+		if currentSecretDAO.Reprompt && !*reprompt && !reprompted {
+			return nil, domain.ErrMasterPasswordRepromptRequired
+		}
+		secret.Reprompt = *reprompt
+	}
+
+	var tags []string
+	if secret.Tags != nil {
+		if tags, err = domain.NormalizeTagNames(secret.Tags); err != nil {
+			return nil, err
+		}
+	}
+
 	secret.UpdatedBy = userID
 	secret.UpdatedAt = time.Now()
 
-	updatedSecretDAO, err := svc.secretStorage.UpdateSecret(ctx, converter.ToSecretDAO(secret))
+	secretDAO := converter.ToSecretDAO(secret)
+	if err := svc.encryptSecretMetadata(secretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
+
+	updatedSecretDAO, err := svc.secretStorage.UpdateSecret(ctx, secretDAO)
 	if err != nil {
 		svc.log.Error("Error updating secret:", sl.Err(err))
 		return nil, domain.ErrNoUpdatedData
@@ -193,7 +317,7 @@ func (svc *SecretService) UpdateSecret(ctx context.Context, userID, collectionID
 
 	switch secret.SecretType {
 	case domain.PasswordSecretType:
-		if updatedPasswordSecret, err := svc.updatePasswordSecret(ctx, secret, encryptionKey); err != nil {
+		if updatedPasswordSecret, err := svc.updatePasswordSecret(ctx, secret, secretDAO, encryptionKey); err != nil {
 			return nil, err
 		} else {
 			updatedSecretDAO.PasswordSecret = *updatedPasswordSecret
@@ -208,10 +332,31 @@ func (svc *SecretService) UpdateSecret(ctx context.Context, userID, collectionID
 		return nil, domain.ErrInvalidSecretType
 	}
 
-	return converter.ToSecret(updatedSecretDAO), nil
+	if err := svc.decryptSecretMetadata(updatedSecretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
+
+	updatedSecrets := []domain.Secret{*converter.ToSecret(updatedSecretDAO)}
+
+	// The indexes are recomputed with the current key, this also covers the re-encryption of secrets
+	if err := svc.updateBlindIndexes(ctx, &updatedSecrets[0], encryptionKey); err != nil {
+		return nil, err
+	}
+
+	// A nil list keeps the current tags, an empty list removes them
+	if secret.Tags != nil {
+		err = svc.setSecretTags(ctx, userID, &updatedSecrets[0], tags)
+	} else {
+		err = svc.loadSecretTags(ctx, userID, updatedSecrets)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedSecrets[0], nil
 }
 
-func (svc *SecretService) updatePasswordSecret(ctx context.Context, secret *domain.Secret, encryptionKey []byte) (*dao.PasswordSecretDAO, error) {
+func (svc *SecretService) updatePasswordSecret(ctx context.Context, secret *domain.Secret, secretDAO *dao.SecretDAO, encryptionKey []byte) (*dao.PasswordSecretDAO, error) {
 	if secret.PasswordSecret == nil {
 		return nil, domain.ErrInvalidSecretType
 	}
@@ -222,11 +367,11 @@ func (svc *SecretService) updatePasswordSecret(ctx context.Context, secret *doma
 		return nil, domain.ErrInternal
 	}
 
-	passwordSecretDAO := converter.ToPasswordSecretDAO(secret.PasswordSecret)
+	passwordSecretDAO := secretDAO.PasswordSecret
 	passwordSecretDAO.ID = secret.LinkedSecretId
 	passwordSecretDAO.Password = encryptedPassword
 
-	updatedPasswordSecretDAO, err := svc.secretStorage.UpdatePasswordSecret(ctx, passwordSecretDAO)
+	updatedPasswordSecretDAO, err := svc.secretStorage.UpdatePasswordSecret(ctx, &passwordSecretDAO)
 	if err != nil {
 		svc.log.Error("Error updating password secret:", sl.Err(err))
 		return nil, domain.ErrNoUpdatedData
@@ -261,6 +406,142 @@ func (svc *SecretService) updateTextSecret(ctx context.Context, secret *domain.S
 	return updatedTextSecretDAO, nil
 }
 
+// MoveSecret moves a secret to another collection and/or folder.
+// An empty targetCollectionID keeps the secret in its collection, an empty folderID moves it to the collection root.
+func (svc *SecretService) MoveSecret(ctx context.Context, userID, collectionID, secretID, targetCollectionID, folderID uuid.UUID, encryptionKey []byte) (*domain.Secret, error) {
+	if targetCollectionID == uuid.Nil {
+		targetCollectionID = collectionID
+	}
+
+	if !svc.isUserPartOfCollection(ctx, userID, collectionID) || !svc.isUserPartOfCollection(ctx, userID, targetCollectionID) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	secretDAO, err := svc.secretStorage.GetSecretByID(ctx, secretID)
+	if err != nil || secretDAO.CollectionID != collectionID {
+		return nil, domain.ErrDataNotFound
+	}
+
+	if folderID != uuid.Nil {
+		if err := svc.checkCollectionFolder(ctx, targetCollectionID, folderID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Payloads are encrypted with the vault key of the user, which does not depend on
+	// the collection, so the linked payload is moved as is without re-encryption.
+	secretDAO.CollectionID = targetCollectionID
+	secretDAO.FolderID = folderID
+	secretDAO.UpdatedBy = userID
+	secretDAO.UpdatedAt = time.Now()
+
+	movedSecretDAO, err := svc.secretStorage.MoveSecret(ctx, collectionID, secretDAO)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error moving secret:", "secretID", secretID, "collectionID", targetCollectionID, "folderID", folderID, sl.Err(err))
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	if err := svc.decryptSecretMetadata(movedSecretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
+
+	return converter.ToSecret(movedSecretDAO), nil
+}
+
+// CopySecret copies a secret into a collection and folder, an empty targetCollectionID copies it within its collection.
+// The payload of the copy is encrypted again, so the copy never shares ciphertext with the original.
+func (svc *SecretService) CopySecret(ctx context.Context, userID, collectionID, secretID, targetCollectionID, folderID uuid.UUID, encryptionKey []byte) (*domain.Secret, error) {
+	if targetCollectionID == uuid.Nil {
+		targetCollectionID = collectionID
+	}
+
+	if !svc.isUserPartOfCollection(ctx, userID, targetCollectionID) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	// The payload is copied without being returned, so the copy does not need a re-prompt
+	secret, err := svc.GetSecret(ctx, userID, collectionID, secretID, encryptionKey, true)
+	if err != nil {
+		return nil, err
+	}
+	if secret.CollectionID != collectionID {
+		return nil, domain.ErrDataNotFound
+	}
+
+	if folderID != uuid.Nil {
+		if err := svc.checkCollectionFolder(ctx, targetCollectionID, folderID); err != nil {
+			return nil, err
+		}
+	}
+
+	secret.CollectionID = targetCollectionID
+	secret.FolderID = folderID
+	secret.CreatedBy = userID
+	secret.UpdatedBy = userID
+	secretDAO := converter.ToSecretDAO(secret)
+	if err := svc.encryptSecretMetadata(secretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
+
+	switch secret.SecretType {
+	case domain.PasswordSecretType:
+		encryptedPassword, err := cipherkit.Encrypt([]byte(secret.PasswordSecret.Password), encryptionKey)
+		if err != nil {
+			svc.log.Error("Error encrypting password secret:", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+		secretDAO.PasswordSecret.Password = encryptedPassword
+	case domain.TextSecretType:
+		encryptedText, err := cipherkit.Encrypt([]byte(secret.TextSecret.Text), encryptionKey)
+		if err != nil {
+			svc.log.Error("Error encrypting text secret:", sl.Err(err))
+			return nil, domain.ErrInternal
+		}
+		secretDAO.TextSecret.Text = encryptedText
+	default:
+		return nil, domain.ErrInvalidSecretType
+	}
+
+	copiedSecretDAO, err := svc.secretStorage.CopySecret(ctx, secretDAO)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error copying secret:", "secretID", secretID, "collectionID", targetCollectionID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	copiedSecretDAO.PasswordSecret = secretDAO.PasswordSecret
+	copiedSecretDAO.TextSecret = secretDAO.TextSecret
+	if err := svc.decryptSecretMetadata(copiedSecretDAO, encryptionKey); err != nil {
+		return nil, err
+	}
+	copiedSecret := converter.ToSecret(copiedSecretDAO)
+
+	// Return the decrypted payload like CreateSecret does
+	switch copiedSecret.SecretType {
+	case domain.PasswordSecretType:
+		copiedSecret.PasswordSecret.ID = copiedSecret.LinkedSecretId
+		copiedSecret.PasswordSecret.Password = secret.PasswordSecret.Password
+	case domain.TextSecretType:
+		copiedSecret.TextSecret.ID = copiedSecret.LinkedSecretId
+		copiedSecret.TextSecret.Text = secret.TextSecret.Text
+	}
+
+	if err := svc.updateBlindIndexes(ctx, copiedSecret, encryptionKey); err != nil {
+		return nil, err
+	}
+
+	if err := svc.setSecretTags(ctx, userID, copiedSecret, secret.Tags); err != nil {
+		return nil, err
+	}
+
+	return copiedSecret, nil
+}
+
 // DeleteSecret deletes a secret
 func (svc *SecretService) DeleteSecret(ctx context.Context, userID, collectionID, secretID uuid.UUID) error {
 	if !svc.isUserPartOfCollection(ctx, userID, collectionID) {
@@ -286,3 +567,132 @@ func (svc *SecretService) isUserPartOfCollection(ctx context.Context, userID, co
 
 	return isPartOfCollection
 }
+
+// toSecrets converts listed secrets to domain secrets with decrypted metadata and the user's tags
+func (svc *SecretService) toSecrets(ctx context.Context, userID uuid.UUID, secretsDAO []dao.SecretDAO, encryptionKey []byte) ([]domain.Secret, error) {
+	secrets := make([]domain.Secret, 0, len(secretsDAO))
+	for _, secretDAO := range secretsDAO {
+		// The metadata encrypted with the key of another member of a shared collection cannot be read,
+		// the secret is listed with a placeholder name instead of failing the whole page
+		if err := converter.DecryptSecretMetadata(&secretDAO, encryptionKey); err != nil {
+			svc.log.Warn("Listing secret with undecryptable metadata", "secretID", secretDAO.ID, sl.Err(err))
+			converter.RedactSecretMetadata(&secretDAO, undecryptableSecretName)
+		}
+		secrets = append(secrets, *converter.ToSecret(&secretDAO))
+	}
+
+	if err := svc.loadSecretTags(ctx, userID, secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// encryptSecretMetadata encrypts the metadata of a secret if metadata encryption is enabled
+func (svc *SecretService) encryptSecretMetadata(secretDAO *dao.SecretDAO, encryptionKey []byte) error {
+	if !svc.encryptMetadata {
+		return nil
+	}
+
+	if err := converter.EncryptSecretMetadata(secretDAO, encryptionKey); err != nil {
+		svc.log.Error("Error encrypting secret metadata:", "secretID", secretDAO.ID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// decryptSecretMetadata decrypts the metadata of a secret.
+// It is done whether or not metadata encryption is enabled, so secrets encrypted before it was disabled stay readable.
+func (svc *SecretService) decryptSecretMetadata(secretDAO *dao.SecretDAO, encryptionKey []byte) error {
+	if err := converter.DecryptSecretMetadata(secretDAO, encryptionKey); err != nil {
+		svc.log.Error("Error decrypting secret metadata:", "secretID", secretDAO.ID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// setSecretTags replaces the tags of a secret with the given normalized tag names
+func (svc *SecretService) setSecretTags(ctx context.Context, userID uuid.UUID, secret *domain.Secret, tags []string) error {
+	if err := svc.tagStorage.SetSecretTags(ctx, userID, secret.ID, tags); err != nil {
+		svc.log.Error("Error setting secret tags:", "secretID", secret.ID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	secret.Tags = append([]string(nil), tags...)
+	sort.Strings(secret.Tags)
+
+	return nil
+}
+
+// loadSecretTags fills the tags the user has put on the given secrets
+func (svc *SecretService) loadSecretTags(ctx context.Context, userID uuid.UUID, secrets []domain.Secret) error {
+	secretIDs := make([]uuid.UUID, 0, len(secrets))
+	for _, secret := range secrets {
+		secretIDs = append(secretIDs, secret.ID)
+	}
+
+	tags, err := svc.tagStorage.ListTagNamesBySecretIDs(ctx, userID, secretIDs)
+	if err != nil {
+		svc.log.Error("Error listing secret tags:", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	for i := range secrets {
+		secrets[i].Tags = tags[secrets[i].ID]
+	}
+
+	return nil
+}
+
+// updateBlindIndexes recomputes the blind indexes of a secret with the given key.
+// Only password secrets have indexed fields, the indexes of other secrets are cleared.
+func (svc *SecretService) updateBlindIndexes(ctx context.Context, secret *domain.Secret, encryptionKey []byte) error {
+	var indexes []dao.BlindIndexDAO
+
+	if secret.SecretType == domain.PasswordSecretType && secret.PasswordSecret != nil {
+		values := map[domain.BlindIndexField]string{
+			domain.LoginBlindIndex:   secret.PasswordSecret.Login,
+			domain.URLHostBlindIndex: secret.PasswordSecret.URL,
+		}
+
+		for field, value := range values {
+			value = domain.NormalizeBlindIndexValue(field, value)
+			if value == "" {
+				continue
+			}
+
+			indexes = append(indexes, dao.BlindIndexDAO{
+				SecretID: secret.ID,
+				Field:    string(field),
+				Value:    cipherkit.BlindIndex(encryptionKey, string(field), value),
+			})
+		}
+	}
+
+	if err := svc.secretStorage.SetBlindIndexes(ctx, secret.ID, indexes); err != nil {
+		svc.log.Error("Error updating blind indexes:", "secretID", secret.ID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// checkCollectionFolder checks that the folder exists and belongs to the given collection
+func (svc *SecretService) checkCollectionFolder(ctx context.Context, collectionID, folderID uuid.UUID) error {
+	folderDAO, err := svc.folderStorage.GetFolderByID(ctx, folderID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("Error getting folder:", "folderID", folderID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if folderDAO.CollectionID != collectionID {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,304 @@
+package secret_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/secret"
+	"github.com/8thgencore/passfort/pkg/cipherkit"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSecretReprompt(t *testing.T) {
+	ctx := context.Background()
+	userID, collectionID := uuid.New(), uuid.New()
+	encryptionKey := make([]byte, 32)
+
+	encryptedText, err := cipherkit.Encrypt([]byte("root:hunter2"), encryptionKey)
+	require.NoError(t, err)
+
+	secretDAO := &dao.SecretDAO{
+		ID:             uuid.New(),
+		CollectionID:   collectionID,
+		SecretType:     dao.TextSecretType,
+		Name:           "Root credentials",
+		LinkedSecretId: uuid.New(),
+		Reprompt:       true,
+	}
+
+	secrets := &mocks.SecretRepository{}
+	secrets.On("GetSecretByID", mock.Anything, secretDAO.ID).Return(
+		func(context.Context, uuid.UUID) *dao.SecretDAO { copied := *secretDAO; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	secrets.On("GetTextSecretByID", mock.Anything, secretDAO.LinkedSecretId).Return(
+		func(context.Context, uuid.UUID) *dao.TextSecretDAO {
+			return &dao.TextSecretDAO{ID: secretDAO.LinkedSecretId, Text: encryptedText}
+		},
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+
+	collections := &mocks.CollectionRepository{}
+	collections.On("IsUserPartOfCollection", mock.Anything, userID, collectionID).Return(true, nil)
+
+	tags := &mocks.TagRepository{}
+	tags.On("ListTagNamesBySecretIDs", mock.Anything, mock.Anything, mock.Anything).Return(map[uuid.UUID][]string{}, nil)
+
+	svc := secret.NewSecretService(slog.Default(), secrets, collections, &mocks.FolderRepository{}, tags, nil, nil, false)
+
+	t.Run("Refuses a flagged secret without a re-prompt", func(t *testing.T) {
+		_, err := svc.GetSecret(ctx, userID, collectionID, secretDAO.ID, encryptionKey, false)
+		assert.Equal(t, domain.ErrMasterPasswordRepromptRequired, err)
+		secrets.AssertNotCalled(t, "GetTextSecretByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Returns a flagged secret with a re-prompt", func(t *testing.T) {
+		got, err := svc.GetSecret(ctx, userID, collectionID, secretDAO.ID, encryptionKey, true)
+		require.NoError(t, err)
+		assert.True(t, got.Reprompt)
+		assert.Equal(t, "root:hunter2", got.TextSecret.Text)
+	})
+
+	t.Run("Returns an unflagged secret without a re-prompt", func(t *testing.T) {
+		secretDAO.Reprompt = false
+
+		got, err := svc.GetSecret(ctx, userID, collectionID, secretDAO.ID, encryptionKey, false)
+		require.NoError(t, err)
+		assert.False(t, got.Reprompt)
+		assert.Equal(t, "root:hunter2", got.TextSecret.Text)
+	})
+}
+
+func TestUpdateSecretReprompt(t *testing.T) {
+	ctx := context.Background()
+	userID, collectionID := uuid.New(), uuid.New()
+	encryptionKey := make([]byte, 32)
+
+	secretDAO := &dao.SecretDAO{
+		ID:             uuid.New(),
+		CollectionID:   collectionID,
+		SecretType:     dao.TextSecretType,
+		Name:           "Root credentials",
+		LinkedSecretId: uuid.New(),
+		Reprompt:       true,
+	}
+
+	secrets := &mocks.SecretRepository{}
+	secrets.On("GetSecretByID", mock.Anything, secretDAO.ID).Return(
+		func(context.Context, uuid.UUID) *dao.SecretDAO { copied := *secretDAO; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	secrets.On("UpdateSecret", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, updated *dao.SecretDAO) *dao.SecretDAO {
+			secretDAO.Reprompt = updated.Reprompt
+			copied := *updated
+			copied.LinkedSecretId = secretDAO.LinkedSecretId
+			return &copied
+		},
+		func(context.Context, *dao.SecretDAO) error { return nil },
+	)
+	secrets.On("UpdateTextSecret", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, text *dao.TextSecretDAO) *dao.TextSecretDAO { return text },
+		func(context.Context, *dao.TextSecretDAO) error { return nil },
+	)
+	secrets.On("SetBlindIndexes", mock.Anything, secretDAO.ID, mock.Anything).Return(nil)
+
+	collections := &mocks.CollectionRepository{}
+	collections.On("IsUserPartOfCollection", mock.Anything, userID, collectionID).Return(true, nil)
+
+	tags := &mocks.TagRepository{}
+	tags.On("ListTagNamesBySecretIDs", mock.Anything, mock.Anything, mock.Anything).Return(map[uuid.UUID][]string{}, nil)
+
+	svc := secret.NewSecretService(slog.Default(), secrets, collections, &mocks.FolderRepository{}, tags, nil, nil, false)
+
+	update := func() *domain.Secret {
+		return &domain.Secret{
+			ID:         secretDAO.ID,
+			Name:       "Root credentials",
+			SecretType: domain.TextSecretType,
+			TextSecret: &domain.TextSecret{Text: "root:hunter3"},
+		}
+	}
+	unflag := false
+
+	t.Run("Keeps the flag when the update omits it", func(t *testing.T) {
+		got, err := svc.UpdateSecret(ctx, userID, collectionID, update(), nil, encryptionKey, false)
+		require.NoError(t, err)
+		assert.True(t, got.Reprompt)
+		assert.True(t, secretDAO.Reprompt)
+	})
+
+	t.Run("Refuses to clear the flag without a re-prompt", func(t *testing.T) {
+		_, err := svc.UpdateSecret(ctx, userID, collectionID, update(), &unflag, encryptionKey, false)
+		assert.Equal(t, domain.ErrMasterPasswordRepromptRequired, err)
+		assert.True(t, secretDAO.Reprompt)
+	})
+
+	t.Run("Clears the flag with a re-prompt", func(t *testing.T) {
+		got, err := svc.UpdateSecret(ctx, userID, collectionID, update(), &unflag, encryptionKey, true)
+		require.NoError(t, err)
+		assert.False(t, got.Reprompt)
+		assert.False(t, secretDAO.Reprompt)
+	})
+}
+
+func TestListSecretsWithMixedKeys(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	userKey, memberKey := make([]byte, 32), make([]byte, 32)
+	memberKey[0] = 1
+
+	ownSecret := dao.SecretDAO{ID: uuid.New(), SecretType: dao.TextSecretType, Name: "Wi-Fi", Description: "Office"}
+	require.NoError(t, converter.EncryptSecretMetadata(&ownSecret, userKey))
+	memberSecret := dao.SecretDAO{ID: uuid.New(), SecretType: dao.TextSecretType, Name: "Payroll", Description: "Bank"}
+	require.NoError(t, converter.EncryptSecretMetadata(&memberSecret, memberKey))
+
+	secrets := &mocks.SecretRepository{}
+	secrets.On("SearchSecrets", mock.Anything, userID, "", uint64(1), uint64(10)).Return([]dao.SecretDAO{ownSecret, memberSecret}, nil)
+
+	tags := &mocks.TagRepository{}
+	tags.On("ListTagNamesBySecretIDs", mock.Anything, userID, mock.Anything).Return(map[uuid.UUID][]string{}, nil)
+
+	svc := secret.NewSecretService(slog.Default(), secrets, &mocks.CollectionRepository{}, &mocks.FolderRepository{}, tags, nil, nil, true)
+
+	got, err := svc.SearchSecrets(ctx, userID, "", userKey, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Wi-Fi", got[0].Name)
+	assert.Equal(t, "Office", got[0].Description)
+	assert.Equal(t, memberSecret.ID, got[1].ID)
+	assert.Equal(t, "Encrypted secret", got[1].Name)
+	assert.Empty(t, got[1].Description)
+}
+
+func TestReencryptSecretsTask(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	oldKey, newKey := make([]byte, 32), make([]byte, 32)
+	newKey[0] = 1
+
+	// More than a page of collections, the last one holding more than two pages of secrets
+	collectionsDAO := make([]dao.CollectionDAO, 101)
+	for i := range collectionsDAO {
+		collectionsDAO[i] = dao.CollectionDAO{ID: uuid.New()}
+	}
+	collectionID := collectionsDAO[100].ID
+
+	secretsDAO := make([]dao.SecretDAO, 205)
+	passwords := make(map[uuid.UUID]dao.PasswordSecretDAO)
+	for i := range secretsDAO {
+		password, err := cipherkit.Encrypt([]byte("hunter2"), oldKey)
+		require.NoError(t, err)
+
+		secretsDAO[i] = dao.SecretDAO{
+			ID:             uuid.New(),
+			CollectionID:   collectionID,
+			SecretType:     dao.PasswordSecretType,
+			Name:           fmt.Sprintf("Secret %d", i),
+			LinkedSecretId: uuid.New(),
+			PasswordSecret: dao.PasswordSecretDAO{URL: "https://example.com", Login: fmt.Sprintf("user%d", i)},
+		}
+		require.NoError(t, converter.EncryptSecretMetadata(&secretsDAO[i], oldKey))
+
+		passwordDAO := secretsDAO[i].PasswordSecret
+		passwordDAO.ID, passwordDAO.Password = secretsDAO[i].LinkedSecretId, password
+		passwords[passwordDAO.ID] = passwordDAO
+	}
+	byID := make(map[uuid.UUID]*dao.SecretDAO)
+	for i := range secretsDAO {
+		byID[secretsDAO[i].ID] = &secretsDAO[i]
+	}
+	indexes := make(map[uuid.UUID][]dao.BlindIndexDAO)
+
+	page := func(skip, limit uint64, size int) (uint64, uint64) {
+		return min(skip, uint64(size)), min(skip+limit, uint64(size))
+	}
+
+	collections := &mocks.CollectionRepository{}
+	collections.On("ListCollectionsByUserID", mock.Anything, userID, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, _ uuid.UUID, skip, limit uint64) []dao.CollectionDAO {
+			from, to := page(skip, limit, len(collectionsDAO))
+			return collectionsDAO[from:to]
+		},
+		func(context.Context, uuid.UUID, uint64, uint64) error { return nil },
+	)
+	collections.On("IsUserPartOfCollection", mock.Anything, userID, mock.Anything).Return(true, nil)
+
+	secrets := &mocks.SecretRepository{}
+	secrets.On("ListSecretsByCollectionID", mock.Anything, userID, mock.Anything, uuid.Nil, true, "", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, _, id, _ uuid.UUID, _ bool, _ string, skip, limit uint64) []dao.SecretDAO {
+			if id != collectionID {
+				return nil
+			}
+			from, to := page(skip, limit, len(secretsDAO))
+			return append([]dao.SecretDAO(nil), secretsDAO[from:to]...)
+		},
+		func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID, bool, string, uint64, uint64) error { return nil },
+	)
+	secrets.On("GetSecretByID", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, id uuid.UUID) *dao.SecretDAO { copied := *byID[id]; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	secrets.On("GetPasswordSecretByID", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, id uuid.UUID) *dao.PasswordSecretDAO { copied := passwords[id]; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	secrets.On("UpdateSecret", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, updated *dao.SecretDAO) *dao.SecretDAO {
+			stored := byID[updated.ID]
+			stored.Name, stored.Description, stored.PasswordSecret = updated.Name, updated.Description, updated.PasswordSecret
+			copied := *stored
+			return &copied
+		},
+		func(context.Context, *dao.SecretDAO) error { return nil },
+	)
+	secrets.On("UpdatePasswordSecret", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, updated *dao.PasswordSecretDAO) *dao.PasswordSecretDAO {
+			passwords[updated.ID] = *updated
+			copied := *updated
+			return &copied
+		},
+		func(context.Context, *dao.PasswordSecretDAO) error { return nil },
+	)
+	secrets.On("SetBlindIndexes", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, id uuid.UUID, secretIndexes []dao.BlindIndexDAO) error {
+			indexes[id] = secretIndexes
+			return nil
+		},
+	)
+
+	tags := &mocks.TagRepository{}
+	tags.On("ListTagNamesBySecretIDs", mock.Anything, userID, mock.Anything).Return(map[uuid.UUID][]string{}, nil)
+
+	svc := secret.NewSecretService(slog.Default(), secrets, collections, &mocks.FolderRepository{}, tags, nil, nil, true)
+
+	payload, err := json.Marshal(secret.ReencryptSecretsPayload{UserID: userID, OldEncryptionKey: oldKey, NewEncryptionKey: newKey})
+	require.NoError(t, err)
+	require.NoError(t, svc.HandleReencryptSecretsTask(ctx, asynq.NewTask(secret.TypeReencryptSecrets, payload)))
+
+	for _, i := range []int{0, 10, 204} {
+		secretDAO := *byID[secretsDAO[i].ID]
+		require.NoError(t, converter.DecryptSecretMetadata(&secretDAO, newKey), "secret %d", i)
+		assert.Equal(t, fmt.Sprintf("Secret %d", i), secretDAO.Name)
+		assert.Equal(t, fmt.Sprintf("user%d", i), secretDAO.PasswordSecret.Login)
+
+		password, err := cipherkit.Decrypt(passwords[secretDAO.LinkedSecretId].Password, newKey)
+		require.NoError(t, err, "secret %d", i)
+		assert.Equal(t, "hunter2", string(password))
+
+		login := cipherkit.BlindIndex(newKey, string(domain.LoginBlindIndex), domain.NormalizeBlindIndexValue(domain.LoginBlindIndex, secretDAO.PasswordSecret.Login))
+		assert.Contains(t, indexes[secretDAO.ID], dao.BlindIndexDAO{SecretID: secretDAO.ID, Field: string(domain.LoginBlindIndex), Value: login})
+	}
+	assert.Len(t, indexes, len(secretsDAO))
+}
@@ -16,8 +16,11 @@ type SecretService struct {
 	log               *slog.Logger
 	secretStorage     storage.SecretRepository
 	collectionStorage storage.CollectionRepository
+	folderStorage     storage.FolderRepository
+	tagStorage        storage.TagRepository
 	cache             cache.CacheRepository
 	asynqClient       *asynq.Client
+	encryptMetadata   bool
 }
 
 // NewSecretService creates a new secret service instance
@@ -25,14 +28,20 @@ func NewSecretService(
 	log *slog.Logger,
 	secretStorage storage.SecretRepository,
 	collectionStorage storage.CollectionRepository,
+	folderStorage storage.FolderRepository,
+	tagStorage storage.TagRepository,
 	cache cache.CacheRepository,
 	asynqClient *asynq.Client,
+	encryptMetadata bool,
 ) *SecretService {
 	return &SecretService{
 		log,
 		secretStorage,
 		collectionStorage,
+		folderStorage,
+		tagStorage,
 		cache,
 		asynqClient,
+		encryptMetadata,
 	}
 }
@@ -16,6 +16,9 @@ const (
 	TypeReencryptSecrets = "reencrypt:secrets"
 )
 
+// reencryptPageSize is the number of collections or secrets fetched at once by the re-encryption
+const reencryptPageSize = 100
+
 // Payload structure for reencrypt secrets task
 type ReencryptSecretsPayload struct {
 	UserID           uuid.UUID
@@ -52,40 +55,53 @@ func (svc *SecretService) HandleReencryptSecretsTask(ctx context.Context, t *asy
 		return fmt.Errorf("unmarshal payload: %v", err)
 	}
 
-	collections, err := svc.collectionStorage.ListCollectionsByUserID(ctx, p.UserID, 1, 10)
-	if err != nil {
-		svc.log.Error("Error fetching collections for user:", "userID", p.UserID, sl.Err(err))
-		return domain.ErrDataNotFound
-	}
+	// All the pages are handled, a secret left out would keep the metadata and the indexes of the old key
+	for skip := uint64(0); ; skip += reencryptPageSize {
+		collections, err := svc.collectionStorage.ListCollectionsByUserID(ctx, p.UserID, skip, reencryptPageSize)
+		if err != nil {
+			svc.log.Error("Error fetching collections for user:", "userID", p.UserID, sl.Err(err))
+			return domain.ErrDataNotFound
+		}
 
-	for _, collection := range collections {
-		if err := svc.reencryptCollectionSecrets(ctx, collection.ID, p.UserID, p.OldEncryptionKey, p.NewEncryptionKey); err != nil {
-			return err
+		for _, collection := range collections {
+			if err := svc.reencryptCollectionSecrets(ctx, collection.ID, p.UserID, p.OldEncryptionKey, p.NewEncryptionKey); err != nil {
+				return err
+			}
 		}
-	}
 
-	return nil
+		if len(collections) < reencryptPageSize {
+			return nil
+		}
+	}
 }
 
 func (svc *SecretService) reencryptCollectionSecrets(ctx context.Context, collectionID, userID uuid.UUID, oldEncryptionKey, newEncryptionKey []byte) error {
-	secretsDAO, err := svc.secretStorage.ListSecretsByCollectionID(ctx, collectionID, 1, 10)
-	if err != nil {
-		svc.log.Error("Error listing secrets for collection", "collectionID", collectionID, sl.Err(err))
-		return domain.ErrDataNotFound
-	}
-
-	for _, secretDAO := range secretsDAO {
-		secret, err := svc.GetSecret(ctx, userID, secretDAO.CollectionID, secretDAO.ID, oldEncryptionKey)
+	for skip := uint64(0); ; skip += reencryptPageSize {
+		secretsDAO, err := svc.secretStorage.ListSecretsByCollectionID(ctx, userID, collectionID, uuid.Nil, true, "", skip, reencryptPageSize)
 		if err != nil {
-			svc.log.Error("Error getting secret by ID", "secretID", secretDAO.ID, "error", err)
+			svc.log.Error("Error listing secrets for collection", "collectionID", collectionID, sl.Err(err))
 			return domain.ErrDataNotFound
 		}
 
-		if _, err := svc.UpdateSecret(ctx, userID, secretDAO.CollectionID, secret, newEncryptionKey); err != nil {
-			svc.log.Error("Error updating secret:", "secretID", secretDAO.ID, sl.Err(err))
-			return err
+		for _, secretDAO := range secretsDAO {
+			// The master password was just changed, which is a fresh proof for the flagged secrets
+			secret, err := svc.GetSecret(ctx, userID, secretDAO.CollectionID, secretDAO.ID, oldEncryptionKey, true)
+			if err != nil {
+				svc.log.Error("Error getting secret by ID", "secretID", secretDAO.ID, "error", err)
+				return domain.ErrDataNotFound
+			}
+
+			// Only the payload changes, keep the tags and the re-prompt flag as they are
+			secret.Tags = nil
+
+			if _, err := svc.UpdateSecret(ctx, userID, secretDAO.CollectionID, secret, nil, newEncryptionKey, true); err != nil {
+				svc.log.Error("Error updating secret:", "secretID", secretDAO.ID, sl.Err(err))
+				return err
+			}
 		}
-	}
 
-	return nil
+		if len(secretsDAO) < reencryptPageSize {
+			return nil
+		}
+	}
 }
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * SessionService implements service.SessionService interface
+ * and provides an access to the refresh token families of the users
+ */
+type SessionService struct {
+	log             *slog.Logger
+	storage         storage.TokenRepository
+	tokenService    service.TokenService
+	refreshTokenTTL time.Duration
+}
+
+// NewSessionService creates a new session service instance.
+// A session without a refresh for longer than the refresh token TTL has expired.
+func NewSessionService(
+	log *slog.Logger,
+	storage storage.TokenRepository,
+	tokenService service.TokenService,
+	refreshTokenTTL time.Duration,
+) *SessionService {
+	return &SessionService{
+		log,
+		storage,
+		tokenService,
+		refreshTokenTTL,
+	}
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/google/uuid"
+)
+
+// ListSessions returns the active sessions of the user, most recently used first
+func (svc *SessionService) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]domain.Session, error) {
+	familiesDAO, err := svc.storage.ListTokenFamiliesByUserID(ctx, userID, time.Now().Add(-svc.refreshTokenTTL))
+	if err != nil {
+		svc.log.Error("Error listing sessions:", "userID", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	sessions := make([]domain.Session, 0, len(familiesDAO))
+	for _, familyDAO := range familiesDAO {
+		session := converter.ToSession(&familyDAO)
+		session.Current = session.ID == currentSessionID
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs out a session of the user, its access and refresh tokens stop working
+func (svc *SessionService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	familyDAO, err := svc.storage.GetTokenFamilyByID(ctx, sessionID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		svc.log.Error("Error getting session:", "sessionID", sessionID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	// Sessions of other users are reported as missing
+	if familyDAO.UserID != userID || familyDAO.RevokedAt.Valid {
+		return domain.ErrDataNotFound
+	}
+
+	return svc.tokenService.RevokeTokenFamily(ctx, sessionID)
+}
+
+// RevokeAllSessions signs out all sessions of the user, including the one making the request
+func (svc *SessionService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	sessions, err := svc.ListSessions(ctx, userID, uuid.Nil)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := svc.tokenService.RevokeTokenFamily(ctx, session.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package stepup
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/service"
+	"github.com/8thgencore/passfort/internal/service/adapters/cache"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * StepUpService implements service.StepUpService interface
+ * and keeps track of the sessions whose user authenticated recently
+ */
+type StepUpService struct {
+	log          *slog.Logger
+	userStorage  storage.UserRepository
+	tokenStorage storage.TokenRepository
+	cache        cache.CacheRepository
+	mfa          service.MFAService
+	lockout      service.LockoutService
+	maxAge       time.Duration
+}
+
+// NewStepUpService creates a new step-up authentication service instance
+func NewStepUpService(
+	log *slog.Logger,
+	userStorage storage.UserRepository,
+	tokenStorage storage.TokenRepository,
+	cache cache.CacheRepository,
+	mfaService service.MFAService,
+	lockoutService service.LockoutService,
+	maxAge time.Duration,
+) *StepUpService {
+	return &StepUpService{
+		log:          log,
+		userStorage:  userStorage,
+		tokenStorage: tokenStorage,
+		cache:        cache,
+		mfa:          mfaService,
+		lockout:      lockoutService,
+		maxAge:       maxAge,
+	}
+}
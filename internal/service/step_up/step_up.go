@@ -0,0 +1,97 @@
+package stepup
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+)
+
+// StepUp confirms the identity of the user of the session with the password or a TOTP or backup code.
+// The session may then perform sensitive operations until the returned time.
+func (svc *StepUpService) StepUp(ctx context.Context, userID, sessionID uuid.UUID, password, code string, client *domain.ClientInfo) (time.Time, error) {
+	userDAO, err := svc.userStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return time.Time{}, err
+		}
+		svc.log.Error("failed to get the user", sl.Err(err))
+		return time.Time{}, domain.ErrInternal
+	}
+
+	if err := svc.lockout.Check(ctx, domain.StepUpAction, userDAO.Email, client.IPAddress); err != nil {
+		return time.Time{}, err
+	}
+
+	if password != "" {
+		if err := util.CompareHash(password, userDAO.Password); err != nil {
+			return time.Time{}, svc.failAttempt(ctx, userDAO.Email, client, domain.ErrInvalidCredentials)
+		}
+	} else {
+		if err := svc.mfa.VerifyCode(ctx, userID, code); err != nil {
+			if err != domain.ErrInvalidMFACode {
+				return time.Time{}, err
+			}
+			return time.Time{}, svc.failAttempt(ctx, userDAO.Email, client, err)
+		}
+	}
+
+	if err := svc.lockout.Succeed(ctx, domain.StepUpAction, userDAO.Email); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	cacheKey := util.GenerateCacheKey("step_up", sessionID)
+	if err := svc.cache.Set(ctx, cacheKey, []byte(now.Format(time.RFC3339)), svc.maxAge); err != nil {
+		svc.log.Error("failed to store recent authentication", sl.Err(err))
+		return time.Time{}, domain.ErrInternal
+	}
+
+	return now.Add(svc.maxAge), nil
+}
+
+// CheckRecentAuthentication checks if the user of the request authenticated recently,
+// by logging in to the session or confirming the password or second factor with StepUp.
+// Personal access tokens cannot authenticate again, so they are refused.
+func (svc *StepUpService) CheckRecentAuthentication(ctx context.Context, claims *domain.UserClaims) error {
+	if claims.PersonalAccessToken != nil {
+		return domain.ErrStepUpRequired
+	}
+
+	exists, err := svc.cache.Exists(ctx, util.GenerateCacheKey("step_up", claims.FamilyID))
+	if err != nil {
+		svc.log.Error("failed to check recent authentication", sl.Err(err))
+		return domain.ErrInternal
+	}
+	if exists {
+		return nil
+	}
+
+	// A session started by a login counts as recent for a while, the session of an approved device does not
+	familyDAO, err := svc.tokenStorage.GetTokenFamilyByID(ctx, claims.FamilyID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrStepUpRequired
+		}
+		svc.log.Error("failed to get the session", sl.Err(err))
+		return domain.ErrInternal
+	}
+	if familyDAO.UserID != claims.UserID || !familyDAO.AuthenticatedAt.Valid || time.Since(familyDAO.AuthenticatedAt.Time) > svc.maxAge {
+		return domain.ErrStepUpRequired
+	}
+
+	return nil
+}
+
+// failAttempt counts a failed step-up and returns its error,
+// or the error refusing the next attempts once the account or the client has to wait
+func (svc *StepUpService) failAttempt(ctx context.Context, email string, client *domain.ClientInfo, err error) error {
+	if lockErr := svc.lockout.Fail(ctx, domain.StepUpAction, email, client.IPAddress); lockErr != nil {
+		return lockErr
+	}
+
+	return err
+}
@@ -0,0 +1,171 @@
+package stepup_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/mfa"
+	stepup "github.com/8thgencore/passfort/internal/service/step_up"
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	password   = "Correct-Horse-42"
+	backupCode = "abcde-fghij"
+)
+
+var client = &domain.ClientInfo{DeviceName: "Firefox", UserAgent: "Mozilla/5.0", IPAddress: "10.0.0.1"}
+
+type stepUpTest struct {
+	svc      *stepup.StepUpService
+	user     *dao.UserDAO
+	session  *dao.TokenFamilyDAO
+	sessions *mocks.TokenRepository
+}
+
+func setupStepUpTest(t *testing.T) *stepUpTest {
+	log := slog.Default()
+	cache := cacheMocks.NewMemoryCache()
+
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	test := &stepUpTest{
+		user:     &dao.UserDAO{ID: uuid.New(), Email: "user@example.com", Password: hashedPassword, IsVerified: true},
+		sessions: &mocks.TokenRepository{},
+	}
+	// Logged in an hour ago
+	test.session = &dao.TokenFamilyDAO{ID: uuid.New(), UserID: test.user.ID, AuthenticatedAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}}
+	test.sessions.On("GetTokenFamilyByID", mock.Anything, test.session.ID).Return(
+		func(context.Context, uuid.UUID) *dao.TokenFamilyDAO { return test.session },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+
+	users := &mocks.UserRepository{}
+	users.On("GetUserByID", mock.Anything, test.user.ID).Return(test.user, nil)
+	users.On("GetUserByEmail", mock.Anything, test.user.Email).Return(test.user, nil)
+
+	mfaStorage := &mocks.MFARepository{}
+	mfaStorage.On("GetTOTPByUserID", mock.Anything, test.user.ID).Return(&dao.TOTPDAO{UserID: test.user.ID, Enabled: true}, nil)
+	mfaStorage.On("UpdateTOTPSecret", mock.Anything, test.user.ID, mock.Anything).Return(nil)
+	mfaStorage.On("UseBackupCode", mock.Anything, test.user.ID, util.HashToken(backupCode)).Return(nil).Once()
+	mfaStorage.On("UseBackupCode", mock.Anything, test.user.ID, mock.Anything).Return(domain.ErrDataNotFound)
+
+	lockoutService := lockout.NewLockoutService(log, users, cache, nil, &config.Lockout{
+		AttemptWindow:      15 * time.Minute,
+		FreeAttempts:       3,
+		BaseDelay:          time.Second,
+		MaxAccountAttempts: 10,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: 24 * time.Hour,
+		MaxIPAttempts:      100,
+	})
+	mfaService := mfa.NewMFAService(log, mfaStorage, users, cache, lockoutService, "passfort", make([]byte, 32))
+	test.svc = stepup.NewStepUpService(log, users, test.sessions, cache, mfaService, lockoutService, 5*time.Minute)
+
+	return test
+}
+
+func (st *stepUpTest) claims() *domain.UserClaims {
+	return &domain.UserClaims{ID: uuid.New(), UserID: st.user.ID, Role: domain.UserRole, Type: domain.AccessToken, FamilyID: st.session.ID}
+}
+
+func TestCheckRecentAuthentication(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Accepts a recent login", func(t *testing.T) {
+		test := setupStepUpTest(t)
+		test.session.AuthenticatedAt.Time = time.Now().Add(-time.Minute)
+
+		assert.NoError(t, test.svc.CheckRecentAuthentication(ctx, test.claims()))
+	})
+
+	t.Run("Refuses a new session without a login", func(t *testing.T) {
+		test := setupStepUpTest(t)
+		test.session.CreatedAt = time.Now()
+		test.session.AuthenticatedAt = sql.NullTime{}
+
+		assert.Equal(t, domain.ErrStepUpRequired, test.svc.CheckRecentAuthentication(ctx, test.claims()))
+	})
+
+	t.Run("Refuses an old login", func(t *testing.T) {
+		test := setupStepUpTest(t)
+
+		assert.Equal(t, domain.ErrStepUpRequired, test.svc.CheckRecentAuthentication(ctx, test.claims()))
+	})
+
+	t.Run("Refuses personal access tokens", func(t *testing.T) {
+		test := setupStepUpTest(t)
+		test.session.AuthenticatedAt.Time = time.Now()
+
+		claims := test.claims()
+		claims.PersonalAccessToken = &domain.PersonalAccessToken{ID: uuid.New(), UserID: test.user.ID}
+		assert.Equal(t, domain.ErrStepUpRequired, test.svc.CheckRecentAuthentication(ctx, claims))
+	})
+}
+
+func TestStepUp(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Confirming the password allows the session only", func(t *testing.T) {
+		test := setupStepUpTest(t)
+
+		expiresAt, err := test.svc.StepUp(ctx, test.user.ID, test.session.ID, password, "", client)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), expiresAt, time.Second)
+
+		assert.NoError(t, test.svc.CheckRecentAuthentication(ctx, test.claims()))
+
+		otherSession := test.claims()
+		otherSession.FamilyID = uuid.New()
+		test.sessions.On("GetTokenFamilyByID", mock.Anything, otherSession.FamilyID).Return(nil, domain.ErrDataNotFound)
+		assert.Equal(t, domain.ErrStepUpRequired, test.svc.CheckRecentAuthentication(ctx, otherSession))
+	})
+
+	t.Run("Confirming a backup code", func(t *testing.T) {
+		test := setupStepUpTest(t)
+
+		_, err := test.svc.StepUp(ctx, test.user.ID, test.session.ID, "", "ABCDE-FGHIJ", client)
+		require.NoError(t, err)
+		assert.NoError(t, test.svc.CheckRecentAuthentication(ctx, test.claims()))
+	})
+
+	t.Run("Wrong password or code", func(t *testing.T) {
+		test := setupStepUpTest(t)
+
+		_, err := test.svc.StepUp(ctx, test.user.ID, test.session.ID, "wrong-password", "", client)
+		assert.Equal(t, domain.ErrInvalidCredentials, err)
+
+		_, err = test.svc.StepUp(ctx, test.user.ID, test.session.ID, "", "zzzzz-zzzzz", client)
+		assert.Equal(t, domain.ErrInvalidMFACode, err)
+
+		assert.Equal(t, domain.ErrStepUpRequired, test.svc.CheckRecentAuthentication(ctx, test.claims()))
+	})
+
+	t.Run("Failed attempts are limited", func(t *testing.T) {
+		test := setupStepUpTest(t)
+
+		for range 4 {
+			_, _ = test.svc.StepUp(ctx, test.user.ID, test.session.ID, "wrong-password", "", client)
+		}
+
+		// Even the right password waits
+		_, err := test.svc.StepUp(ctx, test.user.ID, test.session.ID, password, "", client)
+		var retryErr *domain.RetryAfterError
+		assert.True(t, errors.As(err, &retryErr), "expected a retry error, got %v", err)
+	})
+}
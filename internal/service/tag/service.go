@@ -0,0 +1,24 @@
+package tag
+
+import (
+	"log/slog"
+
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+)
+
+/**
+ * TagService implements service.TagService interface
+ * and provides an access to the tag repository
+ */
+type TagService struct {
+	log     *slog.Logger
+	storage storage.TagRepository
+}
+
+// NewTagService creates a new tag service instance
+func NewTagService(log *slog.Logger, storage storage.TagRepository) *TagService {
+	return &TagService{
+		log,
+		storage,
+	}
+}
@@ -0,0 +1,117 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/google/uuid"
+)
+
+// CreateTag creates a new tag for the user
+func (svc *TagService) CreateTag(ctx context.Context, userID uuid.UUID, name string) (*domain.Tag, error) {
+	name, err := domain.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tagDAO, err := svc.storage.CreateTag(ctx, &dao.TagDAO{UserID: userID, Name: name})
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		svc.log.Error("Error creating tag", "user", userID, sl.Err(err))
+		return nil, domain.ErrDataNotAdded
+	}
+
+	return converter.ToTag(tagDAO), nil
+}
+
+// ListTags lists the tags of the user
+func (svc *TagService) ListTags(ctx context.Context, userID uuid.UUID) ([]domain.Tag, error) {
+	tagsDAO, err := svc.storage.ListTagsByUserID(ctx, userID)
+	if err != nil {
+		svc.log.Error("Error listing tags", "user", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	tags := make([]domain.Tag, 0, len(tagsDAO))
+	for _, tagDAO := range tagsDAO {
+		tags = append(tags, *converter.ToTag(&tagDAO))
+	}
+
+	return tags, nil
+}
+
+// GetTagCloud returns the tags of the user with the number of secrets using them
+func (svc *TagService) GetTagCloud(ctx context.Context, userID uuid.UUID) ([]domain.TagCount, error) {
+	tagCountsDAO, err := svc.storage.GetTagCloud(ctx, userID)
+	if err != nil {
+		svc.log.Error("Error getting tag cloud", "user", userID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	tagCounts := make([]domain.TagCount, 0, len(tagCountsDAO))
+	for _, tagCountDAO := range tagCountsDAO {
+		tagCounts = append(tagCounts, *converter.ToTagCount(&tagCountDAO))
+	}
+
+	return tagCounts, nil
+}
+
+// UpdateTag renames a tag of the user
+func (svc *TagService) UpdateTag(ctx context.Context, userID, tagID uuid.UUID, name string) (*domain.Tag, error) {
+	name, err := domain.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := svc.getUserTag(ctx, userID, tagID); err != nil {
+		return nil, err
+	}
+
+	tagDAO, err := svc.storage.UpdateTag(ctx, &dao.TagDAO{ID: tagID, Name: name})
+	if err != nil {
+		if err == domain.ErrDataNotFound || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		svc.log.Error("Error updating tag", "tag", tagID, sl.Err(err))
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	return converter.ToTag(tagDAO), nil
+}
+
+// DeleteTag deletes a tag of the user and detaches it from all secrets
+func (svc *TagService) DeleteTag(ctx context.Context, userID, tagID uuid.UUID) error {
+	if _, err := svc.getUserTag(ctx, userID, tagID); err != nil {
+		return err
+	}
+
+	if err := svc.storage.DeleteTag(ctx, tagID); err != nil {
+		svc.log.Error("Error deleting tag", "tag", tagID, sl.Err(err))
+		return domain.ErrDataNotDeleted
+	}
+
+	return nil
+}
+
+// getUserTag gets a tag and checks that it belongs to the user
+func (svc *TagService) getUserTag(ctx context.Context, userID, tagID uuid.UUID) (*dao.TagDAO, error) {
+	tagDAO, err := svc.storage.GetTagByID(ctx, tagID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error getting tag", "tag", tagID, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+
+	if tagDAO.UserID != userID {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return tagDAO, nil
+}
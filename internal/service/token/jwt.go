@@ -2,42 +2,186 @@ package token
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"errors"
 
 	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
 	"github.com/8thgencore/passfort/pkg/util"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 // GenerateToken generates a new JWT token pair based on the provided user claims.
-func (svc *TokenService) GenerateToken(userID uuid.UUID, role domain.UserRoleEnum) (string, string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+// Each call starts a new session with its refresh token family, which is rotated by RotateToken.
+// The user just logged in, so the session counts as recently authenticated for the step-up.
+func (svc *TokenService) GenerateToken(ctx context.Context, userID uuid.UUID, role domain.UserRoleEnum, client *domain.ClientInfo) (string, string, error) {
+	return svc.generateToken(ctx, userID, role, client, sql.NullTime{Time: time.Now(), Valid: true})
+}
+
+// GenerateDeviceToken generates a new JWT token pair of a device approved from another session.
+// The user did not authenticate on the device, so its session has to step up for sensitive operations.
+func (svc *TokenService) GenerateDeviceToken(ctx context.Context, userID uuid.UUID, role domain.UserRoleEnum, client *domain.ClientInfo) (string, string, error) {
+	return svc.generateToken(ctx, userID, role, client, sql.NullTime{})
+}
+
+// generateToken starts a new session with its refresh token family and returns its token pair
+func (svc *TokenService) generateToken(
+	ctx context.Context,
+	userID uuid.UUID,
+	role domain.UserRoleEnum,
+	client *domain.ClientInfo,
+	authenticatedAt sql.NullTime,
+) (string, string, error) {
+	refreshTokenID, err := uuid.NewRandom()
+	if err != nil {
+		return "", "", domain.ErrTokenCreation
+	}
+
+	deviceName := client.DeviceName
+	if deviceName == "" {
+		deviceName = util.DeviceName(client.UserAgent)
+	}
+
+	familyDAO, err := svc.storage.CreateTokenFamily(ctx, &dao.TokenFamilyDAO{
+		UserID:          userID,
+		CurrentTokenID:  refreshTokenID,
+		DeviceName:      deviceName,
+		UserAgent:       client.UserAgent,
+		IPAddress:       client.IPAddress,
+		AuthenticatedAt: authenticatedAt,
+	})
+	if err != nil {
+		svc.log.Error("Error creating refresh token family:", "userID", userID, sl.Err(err))
+		return "", "", domain.ErrTokenCreation
+	}
+
+	return svc.signTokenPair(userID, role, familyDAO.ID, refreshTokenID)
+}
+
+// RotateToken exchanges a refresh token for a new token pair of the same family.
+// A refresh token can be exchanged only once, presenting it again revokes the whole family.
+func (svc *TokenService) RotateToken(ctx context.Context, claims *domain.UserClaims, role domain.UserRoleEnum, client *domain.ClientInfo) (string, string, error) {
+	if claims.Type != domain.RefreshToken {
+		return "", "", domain.ErrInvalidRefreshToken
+	}
+
+	refreshTokenID, err := uuid.NewRandom()
+	if err != nil {
+		return "", "", domain.ErrTokenCreation
+	}
+
+	err = svc.storage.RotateTokenFamily(ctx, claims.FamilyID, claims.ID, refreshTokenID, client.IPAddress)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return "", "", svc.handleRefreshTokenReuse(ctx, claims)
+		}
+		svc.log.Error("Error rotating refresh token family:", "familyID", claims.FamilyID, sl.Err(err))
+		return "", "", domain.ErrInternal
+	}
+
+	return svc.signTokenPair(claims.UserID, role, claims.FamilyID, refreshTokenID)
+}
+
+// handleRefreshTokenReuse revokes the family of a refresh token that is not the current one anymore.
+// Either the token was stolen or the legitimate user holds a stolen family, so neither may continue.
+func (svc *TokenService) handleRefreshTokenReuse(ctx context.Context, claims *domain.UserClaims) error {
+	familyDAO, err := svc.storage.GetTokenFamilyByID(ctx, claims.FamilyID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidRefreshToken
+		}
+		svc.log.Error("Error getting refresh token family:", "familyID", claims.FamilyID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	if familyDAO.RevokedAt.Valid || familyDAO.UserID != claims.UserID {
+		return domain.ErrInvalidRefreshToken
+	}
+
+	svc.log.Warn("Refresh token reuse detected, revoking the token family",
+		"userID", claims.UserID, "familyID", claims.FamilyID)
+
+	if err := svc.RevokeTokenFamily(ctx, claims.FamilyID); err != nil {
+		return err
+	}
+
+	return domain.ErrRefreshTokenReused
+}
+
+// RevokeTokenFamily revokes a refresh token family with the access tokens issued with it
+func (svc *TokenService) RevokeTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := svc.storage.RevokeTokenFamily(ctx, familyID); err != nil {
+		svc.log.Error("Error revoking refresh token family:", "familyID", familyID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	// Access tokens are not checked against the database, the revocation is cached as long as they are valid
+	cacheKey := util.GenerateCacheKey("token_family", familyID)
+	if err := svc.cache.Set(ctx, cacheKey, []byte(familyID.String()), svc.accessTokenTTL); err != nil {
+		svc.log.Error("Error caching revoked token family:", sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// RevokeUserTokenFamilies revokes the refresh token families of a user except the given one, signing out its other sessions
+func (svc *TokenService) RevokeUserTokenFamilies(ctx context.Context, userID, exceptFamilyID uuid.UUID) error {
+	familiesDAO, err := svc.storage.ListTokenFamiliesByUserID(ctx, userID, time.Now().Add(-svc.refreshTokenTTL))
+	if err != nil {
+		svc.log.Error("Error listing refresh token families:", "userID", userID, sl.Err(err))
+		return domain.ErrInternal
+	}
+
+	for _, familyDAO := range familiesDAO {
+		if familyDAO.ID == exceptFamilyID {
+			continue
+		}
+		if err := svc.RevokeTokenFamily(ctx, familyDAO.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-	tokenID, err := uuid.NewRandom()
+// signTokenPair signs an access token and a refresh token of a token family.
+// The tokens have distinct IDs and types, so one cannot be used in place of the other.
+func (svc *TokenService) signTokenPair(userID uuid.UUID, role domain.UserRoleEnum, familyID, refreshTokenID uuid.UUID) (string, string, error) {
+	accessTokenID, err := uuid.NewRandom()
 	if err != nil {
 		return "", "", domain.ErrTokenCreation
 	}
 
 	now := time.Now()
-	claims := token.Claims.(jwt.MapClaims)
-	claims["exp"] = now.Add(svc.accessTokenTTL).Unix()
-	claims["iat"] = now.Unix()
-	claims["id"] = tokenID
-	claims["user_id"] = userID
-	claims["role"] = role
 
-	accessToken, err := token.SignedString([]byte(svc.signingKey))
+	accessToken, err := svc.signToken(jwt.MapClaims{
+		"exp":       now.Add(svc.accessTokenTTL).Unix(),
+		"iat":       now.Unix(),
+		"id":        accessTokenID,
+		"user_id":   userID,
+		"role":      role,
+		"type":      domain.AccessToken,
+		"family_id": familyID,
+	})
 	if err != nil {
 		return "", "", errors.New("failed to sign access token")
 	}
 
-	claims["exp"] = now.Add(svc.refreshTokenTTL).Unix()
-
-	refreshToken, err := token.SignedString([]byte(svc.signingKey))
+	refreshToken, err := svc.signToken(jwt.MapClaims{
+		"exp":       now.Add(svc.refreshTokenTTL).Unix(),
+		"iat":       now.Unix(),
+		"id":        refreshTokenID,
+		"user_id":   userID,
+		"role":      role,
+		"type":      domain.RefreshToken,
+		"family_id": familyID,
+	})
 	if err != nil {
 		return "", "", errors.New("failed to sign refresh token")
 	}
@@ -45,16 +189,47 @@ func (svc *TokenService) GenerateToken(userID uuid.UUID, role domain.UserRoleEnu
 	return accessToken, refreshToken, nil
 }
 
-// ParseUserClaims parses the access token and returns the user claims.
-func (svc *TokenService) ParseUserClaims(accessToken string) (*domain.UserClaims, error) {
-	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+// signToken signs the claims with the current signing key, named by the kid header
+func (svc *TokenService) signToken(claims jwt.MapClaims) (string, error) {
+	key := svc.keyring.currentKey()
+	if key == nil {
+		return "", errors.New("no signing key")
+	}
+
+	token := jwt.NewWithClaims(signingMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.PrivateKey)
+}
+
+// verificationKey returns the key verifying a token, the one named by its kid header.
+// HS256 tokens without kid are verified with the legacy key, if it is still configured.
+func (svc *TokenService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if svc.legacyKey == "" {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(svc.signingKey), nil
-	})
+		return []byte(svc.legacyKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := svc.keyring.VerificationKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+	if token.Method != signingMethod(key.Algorithm) {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
+
+// ParseUserClaims parses the access token and returns the user claims.
+func (svc *TokenService) ParseUserClaims(accessToken string) (*domain.UserClaims, error) {
+	token, err := jwt.Parse(accessToken, svc.verificationKey,
+		jwt.WithValidMethods([]string{string(domain.RS256), string(domain.EdDSA), jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
-		svc.log.Debug("Error parsing access token: %v", err)
+		svc.log.Debug("Error parsing access token", sl.Err(err))
 		return nil, domain.ErrExpiredToken
 	}
 
@@ -66,13 +241,13 @@ func (svc *TokenService) ParseUserClaims(accessToken string) (*domain.UserClaims
 
 	tokenID, err := uuid.Parse(fmt.Sprintf("%v", claims["id"]))
 	if err != nil {
-		svc.log.Debug("Error parsing token ID: %v", err)
+		svc.log.Debug("Error parsing token ID", sl.Err(err))
 		return nil, domain.ErrInvalidToken
 	}
 
 	userID, err := uuid.Parse(fmt.Sprintf("%v", claims["user_id"]))
 	if err != nil {
-		svc.log.Debug("Error parsing user ID: %v", err)
+		svc.log.Debug("Error parsing user ID", sl.Err(err))
 		return nil, domain.ErrInvalidToken
 	}
 
@@ -83,14 +258,28 @@ func (svc *TokenService) ParseUserClaims(accessToken string) (*domain.UserClaims
 	}
 	role, err := domain.ParseUserRoleEnum(roleStr)
 	if err != nil {
-		svc.log.Debug("Error parsing user role: %v", err)
+		svc.log.Debug("Error parsing user role", sl.Err(err))
+		return nil, domain.ErrInvalidToken
+	}
+
+	tokenType, err := domain.ParseTokenEnum(fmt.Sprintf("%v", claims["type"]))
+	if err != nil {
+		svc.log.Debug("Error parsing token type", sl.Err(err))
+		return nil, domain.ErrInvalidToken
+	}
+
+	familyID, err := uuid.Parse(fmt.Sprintf("%v", claims["family_id"]))
+	if err != nil {
+		svc.log.Debug("Error parsing token family ID", sl.Err(err))
 		return nil, domain.ErrInvalidToken
 	}
 
 	return &domain.UserClaims{
-		ID:     tokenID,
-		UserID: userID,
-		Role:   role,
+		ID:       tokenID,
+		UserID:   userID,
+		Role:     role,
+		Type:     tokenType,
+		FamilyID: familyID,
 	}, nil
 }
 
@@ -110,14 +299,25 @@ func (svc *TokenService) RevokeToken(ctx context.Context, tokenID uuid.UUID) err
 	return nil
 }
 
-// CheckJWTTokenRevoked checks if the JWT token is revoked.
-func (svc *TokenService) CheckJWTTokenRevoked(ctx context.Context, tokenID uuid.UUID) (bool, error) {
-	cacheKey := util.GenerateCacheKey("token", tokenID)
+// ListVerificationKeys returns the public keys verifying the tokens, published as the JWKS
+func (svc *TokenService) ListVerificationKeys() []domain.SigningKey {
+	return svc.keyring.VerificationKeys()
+}
 
-	exists, err := svc.cache.Exists(ctx, cacheKey)
-	if err != nil {
-		return false, errors.New("failed to check if token is revoked")
+// CheckJWTTokenRevoked checks if the JWT token or its token family is revoked.
+func (svc *TokenService) CheckJWTTokenRevoked(ctx context.Context, claims *domain.UserClaims) (bool, error) {
+	for _, cacheKey := range []string{
+		util.GenerateCacheKey("token", claims.ID),
+		util.GenerateCacheKey("token_family", claims.FamilyID),
+	} {
+		exists, err := svc.cache.Exists(ctx, cacheKey)
+		if err != nil {
+			return false, errors.New("failed to check if token is revoked")
+		}
+		if exists {
+			return true, nil
+		}
 	}
 
-	return exists, nil
+	return false, nil
 }
@@ -2,6 +2,7 @@ package token_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -9,68 +10,178 @@ import (
 	"log/slog"
 
 	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
 	"github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	storageMocks "github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
 	"github.com/8thgencore/passfort/internal/service/token"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var (
 	cache           = new(mocks.CacheRepository)
+	storage         = new(storageMocks.TokenRepository)
 	log             = slog.Default()
 	signingKey      = "test-signing-key"
 	accessTokenTTL  = 15 * time.Minute
 	refreshTokenTTL = 7 * 24 * time.Hour
+	client          = &domain.ClientInfo{UserAgent: "Go-http-client/1.1", IPAddress: "127.0.0.1"}
 )
 
-func newTokenService() *token.TokenService {
-	return token.NewTokenService(log, signingKey, accessTokenTTL, refreshTokenTTL, cache)
+// newTokenService creates a token service accepting the tokens of the legacy signing key
+func newTokenService(t *testing.T) *token.TokenService {
+	keyring := newKeyring(t, &memorySigningKeys{}, domain.EdDSA, refreshTokenTTL)
+	return token.NewTokenService(log, keyring, signingKey, accessTokenTTL, refreshTokenTTL, storage, cache)
+}
+
+// expectTokenFamily expects a new refresh token family to be created
+func expectTokenFamily(familyID uuid.UUID) {
+	storage.On("CreateTokenFamily", mock.Anything, mock.Anything).Return(&dao.TokenFamilyDAO{ID: familyID}, nil).Once()
 }
 
 func TestGenerateToken(t *testing.T) {
-	ts := newTokenService()
+	ts := newTokenService(t)
 
 	t.Run("Successfully generates token pair", func(t *testing.T) {
 		userID := uuid.New()
 		role := domain.UserRole
+		expectTokenFamily(uuid.New())
 
-		accessToken, refreshToken, err := ts.GenerateToken(userID, role)
+		accessToken, refreshToken, err := ts.GenerateToken(context.Background(), userID, role, client)
 
 		assert.NoError(t, err)
 		assert.NotEmpty(t, accessToken)
 		assert.NotEmpty(t, refreshToken)
 
-		// Verify the tokens
+		// Verify the tokens with the published key, as other services do
+		keys := ts.ListVerificationKeys()
+		require.Len(t, keys, 1)
+		publicKey := func(token *jwt.Token) (interface{}, error) {
+			assert.Equal(t, keys[0].ID, token.Header["kid"])
+			return keys[0].PublicKey, nil
+		}
+
 		accessTokenClaims := jwt.MapClaims{}
-		_, err = jwt.ParseWithClaims(accessToken, accessTokenClaims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(signingKey), nil
-		})
+		_, err = jwt.ParseWithClaims(accessToken, accessTokenClaims, publicKey, jwt.WithValidMethods([]string{"EdDSA"}))
 		assert.NoError(t, err)
 
 		refreshTokenClaims := jwt.MapClaims{}
-		_, err = jwt.ParseWithClaims(refreshToken, refreshTokenClaims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(signingKey), nil
-		})
+		_, err = jwt.ParseWithClaims(refreshToken, refreshTokenClaims, publicKey, jwt.WithValidMethods([]string{"EdDSA"}))
+		assert.NoError(t, err)
+
+		// The tokens differ in ID and type, but share the token family
+		assert.NotEqual(t, accessTokenClaims["id"], refreshTokenClaims["id"])
+		assert.Equal(t, string(domain.AccessToken), accessTokenClaims["type"])
+		assert.Equal(t, string(domain.RefreshToken), refreshTokenClaims["type"])
+		assert.Equal(t, accessTokenClaims["family_id"], refreshTokenClaims["family_id"])
+	})
+
+	t.Run("Returns error when token family cannot be created", func(t *testing.T) {
+		storage.On("CreateTokenFamily", mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		_, _, err := ts.GenerateToken(context.Background(), uuid.New(), domain.UserRole, client)
+		assert.Equal(t, domain.ErrTokenCreation, err)
+	})
+}
+
+func TestRotateToken(t *testing.T) {
+	ts := newTokenService(t)
+
+	userID := uuid.New()
+	role := domain.UserRole
+
+	newRefreshClaims := func(t *testing.T) *domain.UserClaims {
+		familyID := uuid.New()
+		expectTokenFamily(familyID)
+
+		_, refreshToken, err := ts.GenerateToken(context.Background(), userID, role, client)
 		assert.NoError(t, err)
+
+		claims, err := ts.ParseUserClaims(refreshToken)
+		assert.NoError(t, err)
+		assert.Equal(t, familyID, claims.FamilyID)
+		return claims
+	}
+
+	t.Run("Successfully rotates refresh token", func(t *testing.T) {
+		claims := newRefreshClaims(t)
+		storage.On("RotateTokenFamily", mock.Anything, claims.FamilyID, claims.ID, mock.Anything, client.IPAddress).Return(nil).Once()
+
+		accessToken, refreshToken, err := ts.RotateToken(context.Background(), claims, role, client)
+		assert.NoError(t, err)
+
+		accessClaims, err := ts.ParseUserClaims(accessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AccessToken, accessClaims.Type)
+
+		refreshClaims, err := ts.ParseUserClaims(refreshToken)
+		assert.NoError(t, err)
+		assert.Equal(t, claims.FamilyID, refreshClaims.FamilyID)
+		assert.NotEqual(t, claims.ID, refreshClaims.ID)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("Revokes token family when refresh token is reused", func(t *testing.T) {
+		claims := newRefreshClaims(t)
+		storage.On("RotateTokenFamily", mock.Anything, claims.FamilyID, claims.ID, mock.Anything, client.IPAddress).Return(domain.ErrDataNotFound).Once()
+		storage.On("GetTokenFamilyByID", mock.Anything, claims.FamilyID).Return(&dao.TokenFamilyDAO{
+			ID:             claims.FamilyID,
+			UserID:         userID,
+			CurrentTokenID: uuid.New(),
+		}, nil).Once()
+		storage.On("RevokeTokenFamily", mock.Anything, claims.FamilyID).Return(nil).Once()
+		cache.On("Set", mock.Anything, mock.Anything, mock.Anything, accessTokenTTL).Return(nil).Once()
+
+		_, _, err := ts.RotateToken(context.Background(), claims, role, client)
+		assert.Equal(t, domain.ErrRefreshTokenReused, err)
+		storage.AssertExpectations(t)
+		cache.AssertExpectations(t)
+		cache.ExpectedCalls = nil
+	})
+
+	t.Run("Returns error when token family is revoked", func(t *testing.T) {
+		claims := newRefreshClaims(t)
+		storage.On("RotateTokenFamily", mock.Anything, claims.FamilyID, claims.ID, mock.Anything, client.IPAddress).Return(domain.ErrDataNotFound).Once()
+		storage.On("GetTokenFamilyByID", mock.Anything, claims.FamilyID).Return(&dao.TokenFamilyDAO{
+			ID:        claims.FamilyID,
+			UserID:    userID,
+			RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil).Once()
+
+		_, _, err := ts.RotateToken(context.Background(), claims, role, client)
+		assert.Equal(t, domain.ErrInvalidRefreshToken, err)
+		storage.AssertNotCalled(t, "RevokeTokenFamily", mock.Anything, claims.FamilyID)
+	})
+
+	t.Run("Returns error when access token is used", func(t *testing.T) {
+		claims := newRefreshClaims(t)
+		claims.Type = domain.AccessToken
+
+		_, _, err := ts.RotateToken(context.Background(), claims, role, client)
+		assert.Equal(t, domain.ErrInvalidRefreshToken, err)
 	})
 }
 
 func TestParseUserClaims(t *testing.T) {
-	ts := newTokenService()
+	ts := newTokenService(t)
 
 	t.Run("Successfully parses user claims", func(t *testing.T) {
 		userID := uuid.New()
 		role := domain.UserRole
 
-		accessToken, _, err := ts.GenerateToken(userID, role)
+		expectTokenFamily(uuid.New())
+
+		accessToken, _, err := ts.GenerateToken(context.Background(), userID, role, client)
 		assert.NoError(t, err)
 
 		claims, err := ts.ParseUserClaims(accessToken)
 		assert.NoError(t, err)
 		assert.Equal(t, userID, claims.UserID)
 		assert.Equal(t, role, claims.Role)
+		assert.Equal(t, domain.AccessToken, claims.Type)
 	})
 
 	t.Run("Returns error when token parsing fails", func(t *testing.T) {
@@ -80,6 +191,38 @@ func TestParseUserClaims(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("Accepts tokens of the legacy signing key", func(t *testing.T) {
+		accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"id":        uuid.New(),
+			"user_id":   uuid.New(),
+			"role":      domain.UserRole,
+			"type":      domain.AccessToken,
+			"family_id": uuid.New(),
+		}).SignedString([]byte(signingKey))
+		require.NoError(t, err)
+
+		_, err = ts.ParseUserClaims(accessToken)
+		assert.NoError(t, err)
+
+		// Without the legacy key, HS256 tokens are rejected
+		keyring := newKeyring(t, &memorySigningKeys{}, domain.EdDSA, refreshTokenTTL)
+		withoutLegacyKey := token.NewTokenService(log, keyring, "", accessTokenTTL, refreshTokenTTL, storage, cache)
+		_, err = withoutLegacyKey.ParseUserClaims(accessToken)
+		assert.Equal(t, domain.ErrExpiredToken, err)
+	})
+
+	t.Run("Returns error when token is signed with an unknown key", func(t *testing.T) {
+		other := token.NewTokenService(log, newKeyring(t, &memorySigningKeys{}, domain.EdDSA, refreshTokenTTL), "",
+			accessTokenTTL, refreshTokenTTL, storage, cache)
+		expectTokenFamily(uuid.New())
+
+		accessToken, _, err := other.GenerateToken(context.Background(), uuid.New(), domain.UserRole, client)
+		require.NoError(t, err)
+
+		_, err = ts.ParseUserClaims(accessToken)
+		assert.Equal(t, domain.ErrExpiredToken, err)
+	})
+
 	t.Run("Returns error when token claims are not a map", func(t *testing.T) {
 		token := jwt.New(jwt.SigningMethodHS256)
 		token.Claims = jwt.MapClaims{}
@@ -129,7 +272,7 @@ func TestParseUserClaims(t *testing.T) {
 }
 
 func TestRevokeToken(t *testing.T) {
-	ts := newTokenService()
+	ts := newTokenService(t)
 
 	t.Run("Successfully revokes token", func(t *testing.T) {
 		tokenID := uuid.New()
@@ -157,14 +300,14 @@ func TestRevokeToken(t *testing.T) {
 }
 
 func TestCheckJWTTokenRevoked(t *testing.T) {
-	ts := newTokenService()
+	ts := newTokenService(t)
 
 	t.Run("Token is revoked", func(t *testing.T) {
-		tokenID := uuid.New()
+		claims := &domain.UserClaims{ID: uuid.New(), FamilyID: uuid.New()}
 
 		cache.On("Exists", mock.Anything, mock.Anything).Return(true, nil)
 
-		revoked, err := ts.CheckJWTTokenRevoked(context.Background(), tokenID)
+		revoked, err := ts.CheckJWTTokenRevoked(context.Background(), claims)
 		assert.NoError(t, err)
 		assert.True(t, revoked)
 
@@ -173,11 +316,11 @@ func TestCheckJWTTokenRevoked(t *testing.T) {
 	})
 
 	t.Run("Token is not revoked", func(t *testing.T) {
-		tokenID := uuid.New()
+		claims := &domain.UserClaims{ID: uuid.New(), FamilyID: uuid.New()}
 
 		cache.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
 
-		revoked, err := ts.CheckJWTTokenRevoked(context.Background(), tokenID)
+		revoked, err := ts.CheckJWTTokenRevoked(context.Background(), claims)
 
 		assert.NoError(t, err)
 		assert.False(t, revoked)
@@ -186,12 +329,26 @@ func TestCheckJWTTokenRevoked(t *testing.T) {
 		cache.ExpectedCalls = nil
 	})
 
+	t.Run("Token family is revoked", func(t *testing.T) {
+		claims := &domain.UserClaims{ID: uuid.New(), FamilyID: uuid.New()}
+
+		cache.On("Exists", mock.Anything, "token:"+claims.ID.String()).Return(false, nil)
+		cache.On("Exists", mock.Anything, "token_family:"+claims.FamilyID.String()).Return(true, nil)
+
+		revoked, err := ts.CheckJWTTokenRevoked(context.Background(), claims)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		cache.AssertExpectations(t)
+		cache.ExpectedCalls = nil
+	})
+
 	t.Run("Returns error when cache exists check fails", func(t *testing.T) {
-		tokenID := uuid.New()
+		claims := &domain.UserClaims{ID: uuid.New(), FamilyID: uuid.New()}
 
 		cache.On("Exists", mock.Anything, mock.Anything).Return(false, errors.New("failed to check cache"))
 
-		_, _ = ts.CheckJWTTokenRevoked(context.Background(), tokenID)
+		_, _ = ts.CheckJWTTokenRevoked(context.Background(), claims)
 		assert.Error(t, domain.ErrInternal)
 
 		cache.AssertExpectations(t)
@@ -0,0 +1,294 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// keyringRefreshInterval is how often the keys are reloaded, picking up the keys rotated by other instances
+	keyringRefreshInterval = time.Minute
+	// keyringMissReloadInterval limits the reloads caused by tokens signed with unknown keys
+	keyringMissReloadInterval = 10 * time.Second
+	// rsaKeySize is the size in bits of the generated RSA keys
+	rsaKeySize = 2048
+)
+
+// Keyring holds the keys signing and verifying the JWT tokens.
+// The keys are shared by the instances through the database, one of them rotates the signing key on schedule.
+type Keyring struct {
+	log              *slog.Logger
+	storage          storage.SigningKeyRepository
+	algorithm        domain.SigningAlgorithm
+	rotationInterval time.Duration
+	verificationTTL  time.Duration
+
+	mu         sync.RWMutex
+	signingKey *domain.SigningKey
+	keys       map[string]*domain.SigningKey
+	loadedAt   time.Time
+}
+
+// NewKeyring creates a new keyring signing with keys of the algorithm, each replaced after the rotation interval.
+// The verification TTL is the lifetime of the longest token, a retired key verifies tokens for that long.
+func NewKeyring(
+	log *slog.Logger,
+	storage storage.SigningKeyRepository,
+	algorithm domain.SigningAlgorithm,
+	rotationInterval time.Duration,
+	verificationTTL time.Duration,
+) *Keyring {
+	return &Keyring{
+		log:              log,
+		storage:          storage,
+		algorithm:        algorithm,
+		rotationInterval: rotationInterval,
+		verificationTTL:  verificationTTL,
+		keys:             make(map[string]*domain.SigningKey),
+	}
+}
+
+// Load loads the keys from the database, the first signing key is generated if there is none
+func (k *Keyring) Load(ctx context.Context) error {
+	if err := k.reload(ctx); err != nil {
+		return err
+	}
+
+	if k.currentKey() == nil {
+		return k.Rotate(ctx)
+	}
+
+	return nil
+}
+
+// Run reloads the keys and rotates the signing key once it is older than the rotation interval, until the context is done
+func (k *Keyring) Run(ctx context.Context) {
+	ticker := time.NewTicker(keyringRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.reload(ctx); err != nil {
+				k.log.Error("Error reloading signing keys", sl.Err(err))
+				continue
+			}
+
+			current := k.currentKey()
+			if current != nil && time.Since(current.CreatedAt) < k.rotationInterval {
+				continue
+			}
+			if err := k.Rotate(ctx); err != nil {
+				k.log.Error("Error rotating signing key", sl.Err(err))
+			}
+		}
+	}
+}
+
+// Rotate replaces the signing key with a new one, the retired key still verifies the tokens it signed.
+// If another instance has rotated the key in the meantime, its key is used instead.
+func (k *Keyring) Rotate(ctx context.Context) error {
+	keyDAO, err := k.generateKey()
+	if err != nil {
+		return err
+	}
+
+	current := k.currentKey()
+	if current == nil {
+		_, err = k.storage.CreateSigningKey(ctx, keyDAO)
+	} else {
+		_, err = k.storage.RotateSigningKey(ctx, uuid.MustParse(current.ID), time.Now().Add(k.verificationTTL), keyDAO)
+	}
+	if err != nil && err != domain.ErrConflictingData {
+		return err
+	}
+	if err == nil {
+		k.log.Info("Rotated signing key", "algorithm", k.algorithm)
+	}
+
+	if err := k.reload(ctx); err != nil {
+		return err
+	}
+	if k.currentKey() == nil {
+		return fmt.Errorf("no signing key after rotation")
+	}
+
+	return nil
+}
+
+// VerificationKey returns the key named by the kid header of a token.
+// The keys are reloaded for an unknown key, it may have been rotated by another instance.
+func (k *Keyring) VerificationKey(kid string) (*domain.SigningKey, bool) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	loadedAt := k.loadedAt
+	k.mu.RUnlock()
+
+	if ok || time.Since(loadedAt) < keyringMissReloadInterval {
+		return key, ok
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := k.reload(ctx); err != nil {
+		k.log.Error("Error reloading signing keys", sl.Err(err))
+		return nil, false
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok = k.keys[kid]
+	return key, ok
+}
+
+// VerificationKeys returns the public keys of the signing key and the retired keys that have not expired, newest first
+func (k *Keyring) VerificationKeys() []domain.SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]domain.SigningKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		publicKey := *key
+		publicKey.PrivateKey = nil
+		keys = append(keys, publicKey)
+	}
+	slices.SortFunc(keys, func(a, b domain.SigningKey) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return keys
+}
+
+// currentKey returns the key signing new tokens, nil if there is none
+func (k *Keyring) currentKey() *domain.SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.signingKey
+}
+
+// reload replaces the keys with the keys of the database
+func (k *Keyring) reload(ctx context.Context) error {
+	keysDAO, err := k.storage.ListSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*domain.SigningKey, len(keysDAO))
+	var signingKey *domain.SigningKey
+	for _, keyDAO := range keysDAO {
+		key, err := toSigningKey(&keyDAO)
+		if err != nil {
+			k.log.Error("Error parsing signing key", "kid", keyDAO.ID, sl.Err(err))
+			continue
+		}
+
+		keys[key.ID] = key
+		if !keyDAO.RetiredAt.Valid && signingKey == nil {
+			signingKey = key
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = keys
+	k.signingKey = signingKey
+	k.loadedAt = time.Now()
+
+	return nil
+}
+
+// generateKey generates a key pair of the algorithm of the keyring
+func (k *Keyring) generateKey() (*dao.SigningKeyDAO, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch k.algorithm {
+	case domain.RS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case domain.EdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", k.algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	privateKeyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dao.SigningKeyDAO{
+		Algorithm:  string(k.algorithm),
+		PrivateKey: privateKeyDER,
+		PublicKey:  publicKeyDER,
+	}, nil
+}
+
+// toSigningKey parses a stored key pair
+func toSigningKey(keyDAO *dao.SigningKeyDAO) (*domain.SigningKey, error) {
+	privateKey, err := x509.ParsePKCS8PrivateKey(keyDAO.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(keyDAO.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm := domain.SigningAlgorithm(keyDAO.Algorithm)
+	if signingMethod(algorithm) == nil {
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+
+	return &domain.SigningKey{
+		ID:         keyDAO.ID.String(),
+		Algorithm:  algorithm,
+		PrivateKey: signer,
+		PublicKey:  publicKey,
+		CreatedAt:  keyDAO.CreatedAt,
+		RetiredAt:  keyDAO.RetiredAt.Time,
+		ExpiresAt:  keyDAO.ExpiresAt.Time,
+	}, nil
+}
+
+// signingMethod returns the JWT signing method of the algorithm, nil if it is not supported
+func signingMethod(algorithm domain.SigningAlgorithm) jwt.SigningMethod {
+	switch algorithm {
+	case domain.RS256:
+		return jwt.SigningMethodRS256
+	case domain.EdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return nil
+	}
+}
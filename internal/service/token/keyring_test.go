@@ -0,0 +1,169 @@
+package token_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/internal/service/token"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySigningKeys is an in-memory storage.SigningKeyRepository shared by the keyrings of several instances
+type memorySigningKeys struct {
+	mu   sync.Mutex
+	keys []dao.SigningKeyDAO
+}
+
+func (m *memorySigningKeys) CreateSigningKey(_ context.Context, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.keys {
+		if !stored.RetiredAt.Valid {
+			return nil, domain.ErrConflictingData
+		}
+	}
+
+	return m.insert(key), nil
+}
+
+func (m *memorySigningKeys) RotateSigningKey(_ context.Context, currentID uuid.UUID, verifyUntil time.Time, key *dao.SigningKeyDAO) (*dao.SigningKeyDAO, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := slices.IndexFunc(m.keys, func(stored dao.SigningKeyDAO) bool {
+		return stored.ID == currentID && !stored.RetiredAt.Valid
+	})
+	if i < 0 {
+		return nil, domain.ErrConflictingData
+	}
+	m.keys[i].RetiredAt.Time, m.keys[i].RetiredAt.Valid = time.Now(), true
+	m.keys[i].ExpiresAt.Time, m.keys[i].ExpiresAt.Valid = verifyUntil, true
+
+	return m.insert(key), nil
+}
+
+func (m *memorySigningKeys) ListSigningKeys(_ context.Context) ([]dao.SigningKeyDAO, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []dao.SigningKeyDAO
+	for _, key := range slices.Backward(m.keys) {
+		if !key.RetiredAt.Valid || key.ExpiresAt.Time.After(time.Now()) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func (m *memorySigningKeys) insert(key *dao.SigningKeyDAO) *dao.SigningKeyDAO {
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	m.keys = append(m.keys, *key)
+	return key
+}
+
+// newKeyring creates a loaded keyring of the algorithm with the keys of the storage
+func newKeyring(t *testing.T, keys *memorySigningKeys, algorithm domain.SigningAlgorithm, verificationTTL time.Duration) *token.Keyring {
+	keyring := token.NewKeyring(log, keys, algorithm, 24*time.Hour, verificationTTL)
+	require.NoError(t, keyring.Load(context.Background()))
+	return keyring
+}
+
+// tokenKeyID returns the kid header of the token
+func tokenKeyID(t *testing.T, tokenString string) string {
+	parsed, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+	return parsed.Header["kid"].(string)
+}
+
+func TestKeyringLoad(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Generates the first signing key once", func(t *testing.T) {
+		keys := &memorySigningKeys{}
+		newKeyring(t, keys, domain.EdDSA, time.Hour)
+
+		// Another instance loads the same key
+		keyring := newKeyring(t, keys, domain.EdDSA, time.Hour)
+		assert.Len(t, keys.keys, 1)
+		assert.Len(t, keyring.VerificationKeys(), 1)
+	})
+
+	t.Run("Rejects an unsupported algorithm", func(t *testing.T) {
+		keyring := token.NewKeyring(log, &memorySigningKeys{}, "HS256", 24*time.Hour, time.Hour)
+		assert.Error(t, keyring.Load(ctx))
+	})
+}
+
+func TestKeyringRotate(t *testing.T) {
+	ctx := context.Background()
+
+	for _, algorithm := range []domain.SigningAlgorithm{domain.EdDSA, domain.RS256} {
+		t.Run("Old tokens stay valid after rotation with "+string(algorithm), func(t *testing.T) {
+			keys := &memorySigningKeys{}
+			keyring := newKeyring(t, keys, algorithm, time.Hour)
+			ts := token.NewTokenService(log, keyring, "", accessTokenTTL, refreshTokenTTL, storage, cache)
+
+			expectTokenFamily(uuid.New())
+			oldToken, _, err := ts.GenerateToken(ctx, uuid.New(), domain.UserRole, client)
+			require.NoError(t, err)
+
+			require.NoError(t, keyring.Rotate(ctx))
+
+			expectTokenFamily(uuid.New())
+			newToken, _, err := ts.GenerateToken(ctx, uuid.New(), domain.UserRole, client)
+			require.NoError(t, err)
+			assert.NotEqual(t, tokenKeyID(t, oldToken), tokenKeyID(t, newToken))
+
+			_, err = ts.ParseUserClaims(oldToken)
+			assert.NoError(t, err)
+			_, err = ts.ParseUserClaims(newToken)
+			assert.NoError(t, err)
+
+			published := ts.ListVerificationKeys()
+			require.Len(t, published, 2)
+			assert.Equal(t, tokenKeyID(t, newToken), published[0].ID)
+			assert.Equal(t, algorithm, published[0].Algorithm)
+			assert.Nil(t, published[0].PrivateKey)
+		})
+	}
+
+	t.Run("Expired keys no longer verify", func(t *testing.T) {
+		keys := &memorySigningKeys{}
+		keyring := newKeyring(t, keys, domain.EdDSA, -time.Second)
+		ts := token.NewTokenService(log, keyring, "", accessTokenTTL, refreshTokenTTL, storage, cache)
+
+		expectTokenFamily(uuid.New())
+		oldToken, _, err := ts.GenerateToken(ctx, uuid.New(), domain.UserRole, client)
+		require.NoError(t, err)
+
+		require.NoError(t, keyring.Rotate(ctx))
+
+		_, err = ts.ParseUserClaims(oldToken)
+		assert.Equal(t, domain.ErrExpiredToken, err)
+		assert.Len(t, ts.ListVerificationKeys(), 1)
+	})
+
+	t.Run("Uses the key rotated by another instance", func(t *testing.T) {
+		keys := &memorySigningKeys{}
+		keyring := newKeyring(t, keys, domain.EdDSA, time.Hour)
+		other := newKeyring(t, keys, domain.EdDSA, time.Hour)
+
+		require.NoError(t, other.Rotate(ctx))
+		require.NoError(t, keyring.Rotate(ctx))
+
+		// The second rotation lost the race, a single key was added
+		assert.Len(t, keys.keys, 2)
+		assert.Equal(t, other.VerificationKeys()[0].ID, keyring.VerificationKeys()[0].ID)
+	})
+}
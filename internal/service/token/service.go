@@ -5,30 +5,38 @@ import (
 	"time"
 
 	"github.com/8thgencore/passfort/internal/service/adapters/cache"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage"
 )
 
 // TokenService handles operations related to tokens.
 type TokenService struct {
 	log             *slog.Logger
-	signingKey      string
+	keyring         *Keyring
+	legacyKey       string
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
+	storage         storage.TokenRepository
 	cache           cache.CacheRepository
 }
 
 // New creates a new instance of TokenService.
+// The tokens are signed with the keyring, the legacy key only verifies the HS256 tokens issued before it.
 func NewTokenService(
 	log *slog.Logger,
-	signingKey string,
+	keyring *Keyring,
+	legacyKey string,
 	accessTokenTTL time.Duration,
 	refreshTokenTTL time.Duration,
+	storage storage.TokenRepository,
 	cache cache.CacheRepository,
 ) *TokenService {
 	return &TokenService{
 		log:             log,
-		signingKey:      signingKey,
+		keyring:         keyring,
+		legacyKey:       legacyKey,
 		accessTokenTTL:  accessTokenTTL,
 		refreshTokenTTL: refreshTokenTTL,
+		storage:         storage,
 		cache:           cache,
 	}
 }
@@ -3,6 +3,8 @@ package user
 import (
 	"log/slog"
 
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/service"
 	"github.com/8thgencore/passfort/internal/service/adapters/cache"
 	"github.com/8thgencore/passfort/internal/service/adapters/storage"
 )
@@ -13,19 +15,28 @@ import (
  * and cache service
  */
 type UserService struct {
-	log     *slog.Logger
-	storage storage.UserRepository
-	cache   cache.CacheRepository
+	log                    *slog.Logger
+	storage                storage.UserRepository
+	cache                  cache.CacheRepository
+	lockout                service.LockoutService
+	tokenService           service.TokenService
+	ownedCollectionsPolicy domain.OwnedCollectionsPolicy
 }
 
 // NewUserService creates a new user service instance
 func NewUserService(log *slog.Logger,
 	storage storage.UserRepository,
 	cache cache.CacheRepository,
+	lockoutService service.LockoutService,
+	tokenService service.TokenService,
+	ownedCollectionsPolicy domain.OwnedCollectionsPolicy,
 ) *UserService {
 	return &UserService{
 		log,
 		storage,
 		cache,
+		lockoutService,
+		tokenService,
+		ownedCollectionsPolicy,
 	}
 }
@@ -2,9 +2,13 @@ package user
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/8thgencore/passfort/internal/domain"
 	"github.com/8thgencore/passfort/internal/repository/storage/postgres/converter"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	"github.com/8thgencore/passfort/pkg/logger/sl"
 	"github.com/8thgencore/passfort/pkg/util"
 	"github.com/google/uuid"
 )
@@ -115,7 +119,7 @@ func (svc *UserService) UpdateUser(ctx context.Context, user *domain.User) (*dom
 		return nil, domain.ErrInternal
 	}
 
-	serializedUser, err := util.Serialize(user)
+	serializedUser, err := util.Serialize(updatedUser)
 	if err != nil {
 		return nil, domain.ErrInternal
 	}
@@ -128,11 +132,21 @@ func (svc *UserService) UpdateUser(ctx context.Context, user *domain.User) (*dom
 		return nil, domain.ErrInternal
 	}
 
+	// The built-in role grants permissions
+	if updatedUser.Role != existingUser.Role {
+		if err = svc.cache.Delete(ctx, util.GenerateCacheKey("user_permissions", user.ID)); err != nil {
+			return nil, domain.ErrInternal
+		}
+	}
+
 	return updatedUser, nil
 }
 
-// DeleteUser deletes a user by ID
-func (svc *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+// DeleteUser deletes a user by ID.
+// The collections owned by the user are handled according to the configured policy in the same transaction,
+// with the transfer policy they are given to the admin deleting the user
+// and with the delete policy the collections shared with other members are kept.
+func (svc *UserService) DeleteUser(ctx context.Context, adminID, id uuid.UUID) error {
 	_, err := svc.storage.GetUserByID(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
@@ -141,6 +155,15 @@ func (svc *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 		return domain.ErrInternal
 	}
 
+	if err = svc.storage.DeleteUser(ctx, id, svc.ownedCollectionsPolicy, adminID); err != nil {
+		switch err {
+		case domain.ErrUserOwnsCollections, domain.ErrUserOwnsSharedCollections, domain.ErrDataNotFound:
+			return err
+		}
+		svc.log.Error("Error deleting user", "user", id, "policy", svc.ownedCollectionsPolicy, sl.Err(err))
+		return domain.ErrInternal
+	}
+
 	cacheKey := util.GenerateCacheKey("user", id)
 
 	if err = svc.cache.Delete(ctx, cacheKey); err != nil {
@@ -151,5 +174,141 @@ func (svc *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 		return domain.ErrInternal
 	}
 
-	return svc.storage.DeleteUser(ctx, id)
+	if err = svc.cache.Delete(ctx, util.GenerateCacheKey("user_permissions", id)); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// UnlockUser ends the lockouts of a user after too many failed attempts
+func (svc *UserService) UnlockUser(ctx context.Context, id uuid.UUID) error {
+	userDAO, err := svc.storage.GetUserByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if err := svc.lockout.Unlock(ctx, userDAO.Email); err != nil {
+		return err
+	}
+
+	svc.log.Info("User unlocked", "user_id", id)
+
+	return nil
+}
+
+// GetUserStatus returns the verification, the suspension, the forced actions and the lockouts of a user
+func (svc *UserService) GetUserStatus(ctx context.Context, id uuid.UUID) (*domain.UserStatus, error) {
+	userDAO, err := svc.storage.GetUserByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	locks, err := svc.lockout.ListLocks(ctx, userDAO.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserStatus{
+		User:  converter.ToUser(userDAO),
+		Locks: locks,
+	}, nil
+}
+
+// SuspendUser suspends a user, who cannot log in until unsuspended.
+// All the sessions of the user are revoked, their personal access tokens are refused while suspended.
+func (svc *UserService) SuspendUser(ctx context.Context, adminID, id uuid.UUID) (*domain.User, error) {
+	if adminID == id {
+		return nil, domain.ErrSuspendOwnAccount
+	}
+
+	user, err := svc.updateUserStatus(ctx, id, func(userDAO *dao.UserDAO) {
+		if !userDAO.SuspendedAt.Valid {
+			userDAO.SuspendedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.tokenService.RevokeUserTokenFamilies(ctx, id, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	svc.log.Info("User suspended", "user_id", id, "admin_id", adminID)
+
+	return user, nil
+}
+
+// UnsuspendUser lets a suspended user log in again
+func (svc *UserService) UnsuspendUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := svc.updateUserStatus(ctx, id, func(userDAO *dao.UserDAO) {
+		userDAO.SuspendedAt = sql.NullTime{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	svc.log.Info("User unsuspended", "user_id", id)
+
+	return user, nil
+}
+
+// ForcePasswordReset makes a user change their password before using their account again
+func (svc *UserService) ForcePasswordReset(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return svc.updateUserStatus(ctx, id, func(userDAO *dao.UserDAO) {
+		userDAO.ForcePasswordReset = true
+	})
+}
+
+// ForceMFAEnrollment makes a user enable a second factor before using their account again
+func (svc *UserService) ForceMFAEnrollment(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return svc.updateUserStatus(ctx, id, func(userDAO *dao.UserDAO) {
+		userDAO.ForceMFAEnrollment = true
+	})
+}
+
+// updateUserStatus applies a change of the suspension or the forced actions of a user.
+// The cached user is updated right away, the authentication checks it on each request.
+func (svc *UserService) updateUserStatus(ctx context.Context, id uuid.UUID, update func(userDAO *dao.UserDAO)) (*domain.User, error) {
+	userDAO, err := svc.storage.GetUserByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	update(userDAO)
+
+	updatedUserDAO, err := svc.storage.UpdateUserStatus(ctx, userDAO)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		svc.log.Error("Error updating user status", "user", id, sl.Err(err))
+		return nil, domain.ErrInternal
+	}
+	user := converter.ToUser(updatedUserDAO)
+
+	serializedUser, err := util.Serialize(user)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if err = svc.cache.Set(ctx, util.GenerateCacheKey("user", id), serializedUser, 0); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if err = svc.cache.DeleteByPrefix(ctx, "users:*"); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return user, nil
 }
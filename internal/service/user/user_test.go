@@ -0,0 +1,164 @@
+package user_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/passfort/internal/config"
+	"github.com/8thgencore/passfort/internal/domain"
+	"github.com/8thgencore/passfort/internal/repository/storage/postgres/dao"
+	cacheMocks "github.com/8thgencore/passfort/internal/service/adapters/cache/mocks"
+	"github.com/8thgencore/passfort/internal/service/adapters/storage/mocks"
+	"github.com/8thgencore/passfort/internal/service/lockout"
+	"github.com/8thgencore/passfort/internal/service/token"
+	"github.com/8thgencore/passfort/internal/service/user"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type userTest struct {
+	svc      *user.UserService
+	lockout  *lockout.LockoutService
+	users    *mocks.UserRepository
+	sessions *mocks.TokenRepository
+	user     *dao.UserDAO
+	adminID  uuid.UUID
+}
+
+func setupUserTest() *userTest {
+	log := slog.Default()
+	cache := cacheMocks.NewMemoryCache()
+
+	test := &userTest{
+		sessions: &mocks.TokenRepository{},
+		user:     &dao.UserDAO{ID: uuid.New(), Email: "user@example.com", IsVerified: true, Role: string(domain.UserRole)},
+		adminID:  uuid.New(),
+	}
+
+	users := &mocks.UserRepository{}
+	test.users = users
+	users.On("GetUserByID", mock.Anything, test.user.ID).Return(
+		func(context.Context, uuid.UUID) *dao.UserDAO { copied := *test.user; return &copied },
+		func(context.Context, uuid.UUID) error { return nil },
+	)
+	// The lockout notice is not sent
+	users.On("GetUserByEmail", mock.Anything, test.user.Email).Return(nil, domain.ErrDataNotFound)
+	users.On("UpdateUserStatus", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, user *dao.UserDAO) *dao.UserDAO { *test.user = *user; return user },
+		func(context.Context, *dao.UserDAO) error { return nil },
+	)
+
+	tokenService := token.NewTokenService(log, nil, "", 15*time.Minute, time.Hour, test.sessions, cache)
+	test.lockout = lockout.NewLockoutService(log, users, cache, nil, &config.Lockout{
+		AttemptWindow:      15 * time.Minute,
+		FreeAttempts:       3,
+		BaseDelay:          time.Second,
+		MaxAccountAttempts: 10,
+		LockoutDuration:    15 * time.Minute,
+		MaxLockoutDuration: 24 * time.Hour,
+		MaxIPAttempts:      100,
+	})
+	test.svc = user.NewUserService(log, users, cache, test.lockout, tokenService, domain.BlockOwnedCollections)
+
+	return test
+}
+
+func TestSuspendUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Suspends the user and revokes their sessions", func(t *testing.T) {
+		test := setupUserTest()
+		sessionID := uuid.New()
+		test.sessions.On("ListTokenFamiliesByUserID", mock.Anything, test.user.ID, mock.Anything).Return([]dao.TokenFamilyDAO{
+			{ID: sessionID, UserID: test.user.ID},
+		}, nil).Once()
+		test.sessions.On("RevokeTokenFamily", mock.Anything, sessionID).Return(nil).Once()
+
+		suspended, err := test.svc.SuspendUser(ctx, test.adminID, test.user.ID)
+		require.NoError(t, err)
+		assert.True(t, suspended.IsSuspended())
+		test.sessions.AssertExpectations(t)
+
+		// The authentication reads the cached user
+		cached, err := test.svc.GetUserByID(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.True(t, cached.IsSuspended())
+
+		unsuspended, err := test.svc.UnsuspendUser(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.False(t, unsuspended.IsSuspended())
+
+		cached, err = test.svc.GetUserByID(ctx, test.user.ID)
+		require.NoError(t, err)
+		assert.False(t, cached.IsSuspended())
+	})
+
+	t.Run("Refuses to suspend the own account", func(t *testing.T) {
+		test := setupUserTest()
+
+		_, err := test.svc.SuspendUser(ctx, test.user.ID, test.user.ID)
+		assert.Equal(t, domain.ErrSuspendOwnAccount, err)
+		assert.False(t, test.user.SuspendedAt.Valid)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Applies the owned collections policy with the deletion", func(t *testing.T) {
+		test := setupUserTest()
+		test.users.On("DeleteUser", mock.Anything, test.user.ID, domain.BlockOwnedCollections, test.adminID).Return(nil).Once()
+
+		require.NoError(t, test.svc.DeleteUser(ctx, test.adminID, test.user.ID))
+		test.users.AssertCalled(t, "DeleteUser", mock.Anything, test.user.ID, domain.BlockOwnedCollections, test.adminID)
+	})
+
+	t.Run("Returns error when the user owns shared collections", func(t *testing.T) {
+		test := setupUserTest()
+		test.users.On("DeleteUser", mock.Anything, test.user.ID, mock.Anything, test.adminID).
+			Return(domain.ErrUserOwnsSharedCollections).Once()
+
+		err := test.svc.DeleteUser(ctx, test.adminID, test.user.ID)
+		assert.Equal(t, domain.ErrUserOwnsSharedCollections, err)
+	})
+}
+
+func TestForcedActions(t *testing.T) {
+	ctx := context.Background()
+	test := setupUserTest()
+
+	_, err := test.svc.ForcePasswordReset(ctx, test.user.ID)
+	require.NoError(t, err)
+	forced, err := test.svc.ForceMFAEnrollment(ctx, test.user.ID)
+	require.NoError(t, err)
+
+	assert.True(t, forced.ForcePasswordReset)
+	assert.True(t, forced.ForceMFAEnrollment)
+	assert.Equal(t, domain.ErrPasswordResetRequired, forced.PendingActionError())
+}
+
+func TestGetUserStatus(t *testing.T) {
+	ctx := context.Background()
+	test := setupUserTest()
+
+	for range 10 {
+		_ = test.lockout.Fail(ctx, domain.LoginAction, test.user.Email, "")
+	}
+
+	status, err := test.svc.GetUserStatus(ctx, test.user.ID)
+	require.NoError(t, err)
+	assert.True(t, status.User.IsVerified)
+	require.Len(t, status.Locks, 1)
+	assert.Equal(t, domain.LoginAction, status.Locks[0].Action)
+	assert.True(t, status.Locks[0].Locked)
+
+	require.NoError(t, test.svc.UnlockUser(ctx, test.user.ID))
+
+	status, err = test.svc.GetUserStatus(ctx, test.user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, status.Locks)
+}
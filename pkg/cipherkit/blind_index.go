@@ -0,0 +1,23 @@
+package cipherkit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// blindIndexContext separates the blind index subkey from other uses of the same key
+var blindIndexContext = []byte("passfort blind index v1")
+
+// BlindIndex computes an HMAC-SHA256 of a field value that allows exact-match lookups
+// without storing the value. The HMAC key is derived from the given key, so the index
+// reveals nothing about the key used to encrypt the value.
+func BlindIndex(key []byte, field, value string) []byte {
+	subKey := hmacSHA256(key, blindIndexContext)
+	return hmacSHA256(subKey, []byte(field+"\x00"+value))
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
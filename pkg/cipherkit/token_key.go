@@ -0,0 +1,10 @@
+package cipherkit
+
+// tokenKeyContext separates the token key from the hash the token is looked up by
+var tokenKeyContext = []byte("passfort token key v1")
+
+// TokenKey derives an encryption key from a random token, e.g. to wrap the vault key of its owner.
+// A token is high-entropy unlike a password, so no slow key derivation is needed.
+func TokenKey(token string) []byte {
+	return hmacSHA256([]byte(token), tokenKeyContext)
+}
@@ -0,0 +1,12 @@
+package cipherkit
+
+// totpKeyContext separates the TOTP encryption key from other uses of the same server secret
+var totpKeyContext = []byte("passfort totp key v1")
+
+// MinTOTPSecretLength is the length under which a server secret is too easy to guess to encrypt the TOTP secrets
+const MinTOTPSecretLength = 32
+
+// TOTPKey derives the key encrypting the stored TOTP secrets from a server secret of the configuration
+func TOTPKey(secret string) []byte {
+	return hmacSHA256([]byte(secret), totpKeyContext)
+}
@@ -1,15 +1,20 @@
 package util
 
 import (
+	"crypto/rand"
 	"fmt"
-	"math/rand"
+	"math/big"
 )
 
 // GenerateOTP generates a six-digit one-time password (OTP) code as a string.
-// The code consists of random digits between 000000 and 999999.
-func GenerateOTP() string {
-	code := rand.Intn(1000000)
-	return fmt.Sprintf("%06d", code)
+// The code consists of random digits between 000000 and 999999, drawn from a cryptographically secure source.
+func GenerateOTP() (string, error) {
+	code, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", code.Int64()), nil
 }
 
 // ValidateOTP validates if the provided OTP code is a six-digit numeric string.
@@ -9,7 +9,8 @@ import (
 
 func TestGenerateOTP(t *testing.T) {
 	t.Run("Successfully generates OTP", func(t *testing.T) {
-		otp := util.GenerateOTP()
+		otp, err := util.GenerateOTP()
+		assert.NoError(t, err)
 		assert.Len(t, otp, 6)
 		assert.True(t, util.ValidateOTP(otp))
 	})
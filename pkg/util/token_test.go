@@ -0,0 +1,43 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateRandomToken(t *testing.T) {
+	t.Run("Successfully generates different tokens", func(t *testing.T) {
+		token1, err := util.GenerateRandomToken(32)
+		assert.NoError(t, err)
+		assert.Len(t, token1, 43)
+
+		token2, err := util.GenerateRandomToken(32)
+		assert.NoError(t, err)
+		assert.NotEqual(t, token1, token2)
+	})
+}
+
+func TestHashToken(t *testing.T) {
+	t.Run("Same token gives the same hash", func(t *testing.T) {
+		assert.Equal(t, util.HashToken("token"), util.HashToken("token"))
+		assert.NotEqual(t, util.HashToken("token"), util.HashToken("other"))
+		assert.Len(t, util.HashToken("token"), 64)
+	})
+}
+
+func TestBackupCode(t *testing.T) {
+	t.Run("Successfully generates a backup code", func(t *testing.T) {
+		code, err := util.GenerateBackupCode()
+		assert.NoError(t, err)
+		assert.Len(t, code, 11)
+		assert.Equal(t, "-", code[5:6])
+		assert.Equal(t, code, util.NormalizeBackupCode(code))
+	})
+
+	t.Run("Normalizes a typed backup code", func(t *testing.T) {
+		assert.Equal(t, "k3f9x-2mq7p", util.NormalizeBackupCode("K3F9X 2MQ7P"))
+		assert.Equal(t, "k3f9x-2mq7p", util.NormalizeBackupCode("k3f9x2mq7p"))
+	})
+}
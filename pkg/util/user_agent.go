@@ -0,0 +1,58 @@
+package util
+
+import "strings"
+
+// userAgentToken maps a token found in a user agent to a readable name
+type userAgentToken struct {
+	token string
+	name  string
+}
+
+// userAgentBrowsers are matched in order, as most browsers also name the engines they are based on
+var userAgentBrowsers = []userAgentToken{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"PostmanRuntime/", "Postman"},
+	{"curl/", "curl"},
+}
+
+// userAgentSystems are matched in order, Android and ChromeOS also name Linux
+var userAgentSystems = []userAgentToken{
+	{"Windows", "Windows"},
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"CrOS", "ChromeOS"},
+	{"Mac OS X", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// DeviceName derives a readable device name such as "Chrome on macOS" from a user agent
+func DeviceName(userAgent string) string {
+	browser := matchUserAgent(userAgent, userAgentBrowsers)
+	system := matchUserAgent(userAgent, userAgentSystems)
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
+
+func matchUserAgent(userAgent string, tokens []userAgentToken) string {
+	for _, token := range tokens {
+		if strings.Contains(userAgent, token.token) {
+			return token.name
+		}
+	}
+
+	return ""
+}
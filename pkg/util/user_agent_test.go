@@ -0,0 +1,53 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/8thgencore/passfort/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceName(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		expected  string
+	}{
+		{
+			name:      "Chrome on macOS",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/128.0.0.0 Safari/537.36",
+			expected:  "Chrome on macOS",
+		},
+		{
+			name:      "Edge on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/128.0.0.0 Safari/537.36 Edg/128.0.0.0",
+			expected:  "Edge on Windows",
+		},
+		{
+			name:      "Safari on iOS",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			expected:  "Safari on iOS",
+		},
+		{
+			name:      "Firefox on Android",
+			userAgent: "Mozilla/5.0 (Android 14; Mobile; rv:129.0) Gecko/129.0 Firefox/129.0",
+			expected:  "Firefox on Android",
+		},
+		{
+			name:      "Command line client",
+			userAgent: "curl/8.7.1",
+			expected:  "curl",
+		},
+		{
+			name:      "Unknown user agent",
+			userAgent: "",
+			expected:  "Unknown device",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, util.DeviceName(tt.userAgent))
+		})
+	}
+}